@@ -24,7 +24,6 @@ import (
 	"github.com/amazechain/amc/common/transaction"
 	"github.com/amazechain/amc/common/types"
 	"github.com/holiman/uint256"
-	"math/big"
 )
 
 var (
@@ -116,7 +115,7 @@ type ContractFilterer interface {
 // DeployBackend wraps the operations needed by WaitMined and WaitDeployed.
 type DeployBackend interface {
 	TransactionReceipt(ctx context.Context, txHash types.Hash) (*block.Receipt, error)
-	CodeAt(ctx context.Context, account types.Address, blockNumber *big.Int) ([]byte, error)
+	CodeAt(ctx context.Context, account types.Address, blockNumber *uint256.Int) ([]byte, error)
 }
 
 // ContractBackend defines the methods needed to work with contracts on a read-write basis.
@@ -0,0 +1,568 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package backends
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/amazechain/amc"
+	"github.com/amazechain/amc/accounts/abi/bind"
+	"github.com/amazechain/amc/common"
+	"github.com/amazechain/amc/common/block"
+	"github.com/amazechain/amc/common/hexutil"
+	"github.com/amazechain/amc/common/transaction"
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/conf"
+	"github.com/amazechain/amc/internal"
+	"github.com/amazechain/amc/internal/api"
+	"github.com/amazechain/amc/internal/api/filters"
+	mvm_types "github.com/amazechain/amc/internal/avm/types"
+	"github.com/amazechain/amc/internal/consensus"
+	"github.com/amazechain/amc/internal/consensus/apos"
+	"github.com/amazechain/amc/internal/consensus/misc"
+	"github.com/amazechain/amc/internal/txspool"
+	vm2 "github.com/amazechain/amc/internal/vm"
+	"github.com/amazechain/amc/modules"
+	event "github.com/amazechain/amc/modules/event/v2"
+	"github.com/amazechain/amc/modules/rawdb"
+	"github.com/amazechain/amc/modules/rpc/jsonrpc"
+	"github.com/amazechain/amc/modules/state"
+	"github.com/amazechain/amc/params"
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+)
+
+// This nil assignment ensures at compile time that SimulatedBackend implements bind.ContractBackend.
+var _ bind.ContractBackend = (*SimulatedBackend)(nil)
+
+var errBlockNumberUnsupported = errors.New("simulatedBackend cannot access blocks other than the latest block")
+
+// SimulatedChainConfig is the chain configuration of the simulated backend,
+// every fork up to London is active from genesis.
+var SimulatedChainConfig = &params.ChainConfig{
+	ChainID:               big.NewInt(1337),
+	Consensus:             params.Faker,
+	HomesteadBlock:        big.NewInt(0),
+	TangerineWhistleBlock: big.NewInt(0),
+	SpuriousDragonBlock:   big.NewInt(0),
+	ByzantiumBlock:        big.NewInt(0),
+	ConstantinopleBlock:   big.NewInt(0),
+	PetersburgBlock:       big.NewInt(0),
+	IstanbulBlock:         big.NewInt(0),
+	MuirGlacierBlock:      big.NewInt(0),
+	BerlinBlock:           big.NewInt(0),
+	LondonBlock:           big.NewInt(0),
+	ArrowGlacierBlock:     big.NewInt(0),
+	GrayGlacierBlock:      big.NewInt(0),
+}
+
+// SimulatedBackend implements bind.ContractBackend, simulating a blockchain in
+// the background. Its main purpose is to allow for easy testing of contract
+// bindings without a live node. Transactions are kept in the pool until
+// Commit seals them into a new block.
+type SimulatedBackend struct {
+	db     kv.RwDB
+	bc     common.IBlockChain
+	pool   *txspool.TxsPool
+	engine consensus.Engine
+	api    *api.API
+	config *params.ChainConfig
+
+	mu         sync.Mutex
+	timeOffset uint64 // seconds added to the timestamp of the next block
+
+	logsFeed event.Feed
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewSimulatedBackend creates a new binding backend using an in-memory database
+// and a genesis block holding the given allocations.
+func NewSimulatedBackend(alloc []conf.Allocate, gasLimit uint64) (*SimulatedBackend, error) {
+	modules.AmcInit()
+	kv.ChaindataTablesCfg = modules.AmcTableCfg
+	db := memdb.New("")
+
+	genesis := &internal.GenesisBlock{
+		GenesisBlockConfig: &conf.GenesisBlockConfig{
+			Config:   SimulatedChainConfig,
+			GasLimit: gasLimit,
+			Engine:   &conf.ConsensusConfig{EngineName: "APosEngine", GasCeil: gasLimit},
+			Alloc:    alloc,
+		},
+	}
+	var genesisBlock *block.Block
+	if err := db.Update(context.Background(), func(tx kv.RwTx) error {
+		var err error
+		genesisBlock, _, err = genesis.Write(tx)
+		return err
+	}); nil != err {
+		db.Close()
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	engine := apos.NewFaker()
+	bc, err := internal.NewBlockChain(ctx, genesisBlock, engine, nil, db, nil, SimulatedChainConfig)
+	if nil != err {
+		cancel()
+		db.Close()
+		return nil, err
+	}
+	poolConfig := txspool.DefaultTxPoolConfig
+	poolConfig.Journal = ""
+	pool, err := txspool.NewTxsPool(ctx, poolConfig, bc)
+	if nil != err {
+		cancel()
+		db.Close()
+		return nil, err
+	}
+
+	return &SimulatedBackend{
+		db:     db,
+		bc:     bc,
+		pool:   pool.(*txspool.TxsPool),
+		engine: engine,
+		api:    api.NewAPI(nil, nil, nil, bc, db, engine, pool, nil, nil, SimulatedChainConfig),
+		config: SimulatedChainConfig,
+		ctx:    ctx,
+		cancel: cancel,
+	}, nil
+}
+
+// Close terminates the underlying blockchain's update loop.
+func (b *SimulatedBackend) Close() error {
+	b.pool.Stop()
+	b.cancel()
+	b.db.Close()
+	return nil
+}
+
+// Blockchain returns the underlying blockchain.
+func (b *SimulatedBackend) Blockchain() common.IBlockChain {
+	return b.bc
+}
+
+// Commit seals the pending transactions into a new block on top of the current
+// head and returns its hash.
+func (b *SimulatedBackend) Commit() (types.Hash, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	parent := b.bc.CurrentBlock()
+	newBlock, receipts, err := b.buildBlock(parent.Header().(*block.Header))
+	if nil != err {
+		return types.Hash{}, err
+	}
+
+	hash := newBlock.Hash()
+	var logs []*block.Log
+	for i, receipt := range receipts {
+		receipt.BlockHash = hash
+		receipt.BlockNumber = newBlock.Number64()
+		receipt.TransactionIndex = uint(i)
+		for _, l := range receipt.Logs {
+			l.BlockHash = hash
+			l.BlockNumber = newBlock.Number64()
+		}
+		logs = append(logs, receipt.Logs...)
+	}
+	if err := b.bc.WriteBlockWithState(newBlock, receipts); nil != err {
+		return types.Hash{}, err
+	}
+	b.timeOffset = 0
+	b.pool.ResetHead(parent)
+
+	for _, l := range logs {
+		b.logsFeed.Send(l)
+	}
+	return hash, nil
+}
+
+// buildBlock executes the pending transactions on top of parent, the same way
+// the miner does, and writes the resulting state.
+func (b *SimulatedBackend) buildBlock(parent *block.Header) (block.IBlock, []*block.Receipt, error) {
+	header := &block.Header{
+		ParentHash: parent.Hash(),
+		Number:     new(uint256.Int).AddUint64(parent.Number, 1),
+		GasLimit:   parent.GasLimit,
+		Time:       parent.Time + 10 + b.timeOffset,
+		Difficulty: uint256.NewInt(0),
+		BaseFee:    uint256.NewInt(0),
+	}
+	if b.config.IsLondon(header.Number.Uint64()) {
+		header.BaseFee, _ = uint256.FromBig(misc.CalcBaseFee(b.config, parent))
+	}
+	if err := b.engine.Prepare(b.bc, header); nil != err {
+		return nil, nil, err
+	}
+
+	pending, err := b.pool.GetTransaction()
+	if nil != err {
+		return nil, nil, err
+	}
+
+	tx, err := b.db.BeginRw(b.ctx)
+	if nil != err {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	stateWriter := state.NewPlainStateWriter(tx, tx, header.Number.Uint64())
+	ibs := state.New(state.NewPlainStateReader(tx))
+	commitment, err := internal.OpenStateCommitment(tx, b.config, header)
+	if nil != err {
+		return nil, nil, err
+	}
+	ibs.SetStateCommitment(commitment)
+	getHeader := func(hash types.Hash, number uint64) *block.Header {
+		return rawdb.ReadHeader(tx, hash, number)
+	}
+
+	var (
+		txs      []*transaction.Transaction
+		receipts []*block.Receipt
+		gasPool  = new(common.GasPool).AddGas(header.GasLimit)
+		noop     = state.NewNoopWriter()
+	)
+	for _, txn := range pending {
+		ibs.Prepare(txn.Hash(), types.Hash{}, len(txs))
+		snap, gasSnap := ibs.Snapshot(), gasPool.Gas()
+		receipt, _, err := internal.ApplyTransaction(b.config, internal.GetHashFn(header, getHeader), b.engine, &header.Coinbase, gasPool, ibs, noop, header, txn, &header.GasUsed, vm2.Config{})
+		if nil != err {
+			ibs.RevertToSnapshot(snap)
+			gasPool = new(common.GasPool).AddGas(gasSnap)
+			continue
+		}
+		txs = append(txs, txn)
+		receipts = append(receipts, receipt)
+	}
+
+	newBlock, _, _, err := internal.FinalizeBlockExecution(tx, b.engine, header, txs, stateWriter, b.config, ibs, receipts, nil, true, b.config.IsBeijing(header.Number.Uint64()), nil)
+	if nil != err {
+		return nil, nil, err
+	}
+	if err := tx.Commit(); nil != err {
+		return nil, nil, err
+	}
+	return newBlock, receipts, nil
+}
+
+// AdjustTime adds a time shift to the next block. It must be called before
+// Commit and is reset once the block is sealed.
+func (b *SimulatedBackend) AdjustTime(adjustment time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.timeOffset += uint64(adjustment / time.Second)
+}
+
+// CodeAt returns the code associated with a certain account in the blockchain.
+func (b *SimulatedBackend) CodeAt(ctx context.Context, contract types.Address, blockNumber *uint256.Int) ([]byte, error) {
+	ibs, closer, err := b.stateByNumber(ctx, blockNumber)
+	if nil != err {
+		return nil, err
+	}
+	defer closer()
+	return ibs.GetCode(contract), nil
+}
+
+// BalanceAt returns the wei balance of a certain account in the blockchain.
+func (b *SimulatedBackend) BalanceAt(ctx context.Context, contract types.Address, blockNumber *uint256.Int) (*uint256.Int, error) {
+	ibs, closer, err := b.stateByNumber(ctx, blockNumber)
+	if nil != err {
+		return nil, err
+	}
+	defer closer()
+	return ibs.GetBalance(contract).Clone(), nil
+}
+
+// NonceAt returns the nonce of a certain account in the blockchain.
+func (b *SimulatedBackend) NonceAt(ctx context.Context, contract types.Address, blockNumber *uint256.Int) (uint64, error) {
+	ibs, closer, err := b.stateByNumber(ctx, blockNumber)
+	if nil != err {
+		return 0, err
+	}
+	defer closer()
+	return ibs.GetNonce(contract), nil
+}
+
+// StorageAt returns the value of key in the storage of an account in the blockchain.
+func (b *SimulatedBackend) StorageAt(ctx context.Context, contract types.Address, key types.Hash, blockNumber *uint256.Int) ([]byte, error) {
+	ibs, closer, err := b.stateByNumber(ctx, blockNumber)
+	if nil != err {
+		return nil, err
+	}
+	defer closer()
+	var value uint256.Int
+	ibs.GetState(contract, &key, &value)
+	return value.PaddedBytes(32), nil
+}
+
+// stateByNumber opens the state of the latest block. Only the latest block is
+// supported, as the simulated chain does not keep history readers around.
+func (b *SimulatedBackend) stateByNumber(ctx context.Context, blockNumber *uint256.Int) (*state.IntraBlockState, func(), error) {
+	if blockNumber != nil && blockNumber.Cmp(b.bc.CurrentBlock().Number64()) != 0 {
+		return nil, nil, errBlockNumberUnsupported
+	}
+	tx, err := b.db.BeginRo(ctx)
+	if nil != err {
+		return nil, nil, err
+	}
+	return state.New(state.NewPlainStateReader(tx)), tx.Rollback, nil
+}
+
+// TransactionReceipt returns the receipt of a transaction.
+func (b *SimulatedBackend) TransactionReceipt(ctx context.Context, txHash types.Hash) (*block.Receipt, error) {
+	var (
+		blockHash types.Hash
+		index     uint64
+		txn       *transaction.Transaction
+	)
+	if err := b.db.View(ctx, func(tx kv.Tx) error {
+		var err error
+		txn, blockHash, _, index, err = rawdb.ReadTransactionByHash(tx, txHash)
+		return err
+	}); nil != err {
+		return nil, err
+	}
+	if txn == nil {
+		return nil, amazechain.NotFound
+	}
+	receipts, err := b.bc.GetReceipts(blockHash)
+	if nil != err {
+		return nil, err
+	}
+	if uint64(len(receipts)) <= index {
+		return nil, amazechain.NotFound
+	}
+	return receipts[index], nil
+}
+
+// HeaderByNumber returns a block header from the current canonical chain. If
+// number is nil, the latest known header is returned.
+func (b *SimulatedBackend) HeaderByNumber(ctx context.Context, number *uint256.Int) (*block.Header, error) {
+	if number == nil || number.Cmp(b.bc.CurrentBlock().Number64()) == 0 {
+		return b.bc.CurrentBlock().Header().(*block.Header), nil
+	}
+	header := b.bc.GetHeaderByNumber(number)
+	if header == nil {
+		return nil, amazechain.NotFound
+	}
+	return header.(*block.Header), nil
+}
+
+// PendingCodeAt returns the code associated with an account. Pending
+// transactions are not executed until Commit, so this is the latest code.
+func (b *SimulatedBackend) PendingCodeAt(ctx context.Context, contract types.Address) ([]byte, error) {
+	return b.CodeAt(ctx, contract, nil)
+}
+
+// PendingNonceAt returns the next nonce of an account, taking the transactions
+// waiting in the pool into account.
+func (b *SimulatedBackend) PendingNonceAt(ctx context.Context, account types.Address) (uint64, error) {
+	return b.pool.Nonce(account), nil
+}
+
+// CallContract executes a contract call against the latest block.
+func (b *SimulatedBackend) CallContract(ctx context.Context, call amazechain.CallMsg, blockNumber *uint256.Int) ([]byte, error) {
+	if blockNumber != nil && blockNumber.Cmp(b.bc.CurrentBlock().Number64()) != 0 {
+		return nil, errBlockNumberUnsupported
+	}
+	return b.callContract(ctx, call)
+}
+
+// PendingCallContract executes a contract call against the latest block,
+// pending transactions are not visible until Commit.
+func (b *SimulatedBackend) PendingCallContract(ctx context.Context, call amazechain.CallMsg) ([]byte, error) {
+	return b.callContract(ctx, call)
+}
+
+func (b *SimulatedBackend) callContract(ctx context.Context, call amazechain.CallMsg) ([]byte, error) {
+	res, err := api.DoCall(ctx, b.api, toCallArgs(call), jsonrpc.BlockNumberOrHashWithNumber(jsonrpc.LatestBlockNumber), nil, 0, b.api.RPCGasCap())
+	if nil != err {
+		return nil, err
+	}
+	if len(res.Revert()) > 0 {
+		return nil, fmt.Errorf("%w: %s", res.Err, hexutil.Encode(res.Revert()))
+	}
+	return res.Return(), res.Err
+}
+
+// SuggestGasPrice returns the base fee of the next block.
+func (b *SimulatedBackend) SuggestGasPrice(ctx context.Context) (*uint256.Int, error) {
+	if b.config.IsLondon(b.bc.CurrentBlock().Number64().Uint64() + 1) {
+		baseFee, _ := uint256.FromBig(misc.CalcBaseFee(b.config, b.bc.CurrentBlock().Header().(*block.Header)))
+		return baseFee, nil
+	}
+	return uint256.NewInt(1), nil
+}
+
+// SuggestGasTipCap returns a fixed tip of 1 wei, the simulated chain has no
+// competition for block space.
+func (b *SimulatedBackend) SuggestGasTipCap(ctx context.Context) (*uint256.Int, error) {
+	return uint256.NewInt(1), nil
+}
+
+// EstimateGas executes the requested code against the latest block and
+// returns the lowest gas limit it succeeds with.
+func (b *SimulatedBackend) EstimateGas(ctx context.Context, call amazechain.CallMsg) (uint64, error) {
+	gas, err := api.DoEstimateGas(ctx, b.api, toCallArgs(call), jsonrpc.BlockNumberOrHashWithNumber(jsonrpc.LatestBlockNumber), b.api.RPCGasCap())
+	return uint64(gas), err
+}
+
+// SendTransaction adds a signed transaction to the pool, it is included in the
+// next block sealed by Commit.
+func (b *SimulatedBackend) SendTransaction(ctx context.Context, tx *transaction.Transaction) error {
+	sender, err := transaction.Sender(transaction.LatestSignerForChainID(b.config.ChainID), tx)
+	if nil != err {
+		return fmt.Errorf("invalid transaction: %v", err)
+	}
+	tx.SetFrom(sender)
+	return b.pool.AddLocal(tx)
+}
+
+// FilterLogs executes a log filter operation, blocking during execution and
+// returning all the results in one batch.
+func (b *SimulatedBackend) FilterLogs(ctx context.Context, query amazechain.FilterQuery) ([]block.Log, error) {
+	var filter *filters.Filter
+	if query.BlockHash != nil {
+		filter = filters.NewBlockFilter(b.api, *query.BlockHash, query.Addresses, query.Topics)
+	} else {
+		from := int64(0)
+		if query.FromBlock != nil {
+			from = int64(query.FromBlock.Uint64())
+		}
+		to := int64(-1)
+		if query.ToBlock != nil {
+			to = int64(query.ToBlock.Uint64())
+		}
+		filter = filters.NewRangeFilter(b.api, from, to, query.Addresses, query.Topics)
+	}
+	logs, err := filter.Logs(ctx)
+	if nil != err {
+		return nil, err
+	}
+	res := make([]block.Log, len(logs))
+	for i, l := range logs {
+		res[i] = *l
+	}
+	return res, nil
+}
+
+// SubscribeFilterLogs creates a background log filtering operation, returning
+// a subscription immediately, which can be used to stream the logs of the
+// blocks sealed by Commit.
+func (b *SimulatedBackend) SubscribeFilterLogs(ctx context.Context, query amazechain.FilterQuery, ch chan<- block.Log) (amazechain.Subscription, error) {
+	sink := make(chan *block.Log)
+	sub := b.logsFeed.Subscribe(sink)
+
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case l := <-sink:
+				if !matchLog(l, query) {
+					continue
+				}
+				select {
+				case ch <- *l:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// matchLog reports whether the log satisfies the address and topic criteria
+// of the query.
+func matchLog(l *block.Log, query amazechain.FilterQuery) bool {
+	if len(query.Addresses) > 0 {
+		found := false
+		for _, addr := range query.Addresses {
+			if addr == l.Address {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(query.Topics) > len(l.Topics) {
+		return false
+	}
+	for i, sub := range query.Topics {
+		if len(sub) == 0 {
+			continue
+		}
+		match := false
+		for _, topic := range sub {
+			if l.Topics[i] == topic {
+				match = true
+				break
+			}
+		}
+		if !match {
+			return false
+		}
+	}
+	return true
+}
+
+// toCallArgs converts a contract call message into the RPC call arguments.
+func toCallArgs(call amazechain.CallMsg) api.TransactionArgs {
+	data := hexutil.Bytes(call.Data)
+	args := api.TransactionArgs{
+		From: mvm_types.FromAmcAddress(&call.From),
+		Data: &data,
+	}
+	if call.To != nil {
+		args.To = mvm_types.FromAmcAddress(call.To)
+	}
+	if call.Gas != 0 {
+		gas := hexutil.Uint64(call.Gas)
+		args.Gas = &gas
+	}
+	if call.GasPrice != nil {
+		args.GasPrice = (*hexutil.Big)(call.GasPrice.ToBig())
+	}
+	if call.GasFeeCap != nil {
+		args.MaxFeePerGas = (*hexutil.Big)(call.GasFeeCap.ToBig())
+	}
+	if call.GasTipCap != nil {
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(call.GasTipCap.ToBig())
+	}
+	if call.Value != nil {
+		args.Value = (*hexutil.Big)(call.Value.ToBig())
+	}
+	return args
+}
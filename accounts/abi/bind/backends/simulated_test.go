@@ -0,0 +1,120 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package backends
+
+import (
+	"context"
+	"encoding/hex"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/amazechain/amc"
+	"github.com/amazechain/amc/accounts/abi"
+	"github.com/amazechain/amc/accounts/abi/bind"
+	"github.com/amazechain/amc/common/crypto"
+	"github.com/amazechain/amc/common/hexutil"
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/conf"
+	"github.com/holiman/uint256"
+)
+
+func loadDeposit(t *testing.T) (abi.ABI, []byte) {
+	abiJSON, err := os.ReadFile("../../../../contracts/deposit/abi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := abi.JSON(strings.NewReader(string(abiJSON)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bin, err := os.ReadFile("../../../../contracts/deposit/bytecode.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed, hexutil.MustDecode("0x" + strings.TrimSpace(string(bin)))
+}
+
+func TestSimulatedBackendDeposit(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	sim, err := NewSimulatedBackend([]conf.Allocate{{Address: "AMC" + hex.EncodeToString(addr[:]), Balance: "1000000000000000000000"}}, 30_000_000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sim.Close()
+
+	auth, err := bind.NewKeyedTransactorWithChainID(key, SimulatedChainConfig.ChainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, bin := loadDeposit(t)
+	contractAddr, tx, contract, err := bind.DeployContract(auth, parsed, bin, sim, uint64(86400), uint64(10), uint64(10), uint64(10))
+	if err != nil {
+		t.Fatalf("deploy: %v", err)
+	}
+	if _, err := sim.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	receipt, err := sim.TransactionReceipt(context.Background(), tx.Hash())
+	if err != nil {
+		t.Fatalf("receipt: %v", err)
+	}
+	if receipt.ContractAddress != contractAddr || receipt.Status != 1 {
+		t.Fatalf("unexpected receipt: address %v, status %d", receipt.ContractAddress, receipt.Status)
+	}
+	if code, err := sim.CodeAt(context.Background(), contractAddr, nil); err != nil || len(code) == 0 {
+		t.Fatalf("no code at %v: %v", contractAddr, err)
+	}
+
+	hundred := new(uint256.Int).Mul(uint256.NewInt(100), uint256.NewInt(1e18))
+	limit := func() uint64 {
+		var out []interface{}
+		if err := contract.Call(nil, &out, "getDepositLimit", hundred); err != nil {
+			t.Fatalf("call: %v", err)
+		}
+		return out[0].(uint64)
+	}
+	if have := limit(); have != 10 {
+		t.Fatalf("limit mismatch: have %d, want 10", have)
+	}
+
+	if _, err := contract.Transact(auth, "addDepositLimit", hundred, uint64(5)); err != nil {
+		t.Fatalf("transact: %v", err)
+	}
+	if have := limit(); have != 10 {
+		t.Fatalf("pending transaction visible before commit: have %d", have)
+	}
+	if _, err := sim.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if have := limit(); have != 15 {
+		t.Fatalf("limit mismatch: have %d, want 15", have)
+	}
+
+	logs, err := sim.FilterLogs(context.Background(), amazechain.FilterQuery{
+		Addresses: []types.Address{contractAddr},
+		Topics:    [][]types.Hash{{parsed.Events["OwnershipTransferred"].ID}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 1 {
+		t.Fatalf("ownership logs: have %d, want 1", len(logs))
+	}
+}
@@ -254,7 +254,7 @@ func (c *BoundContract) createDynamicTx(opts *TransactOpts, contract *types.Addr
 	// Estimate FeeCap
 	gasFeeCap := opts.GasFeeCap
 	if gasFeeCap == nil {
-		gasFeeCap = gasTipCap.Add(gasTipCap, head.BaseFee.Mod(head.BaseFee, uint256.NewInt(basefeeWiggleMultiplier)))
+		gasFeeCap = new(uint256.Int).Add(gasTipCap, new(uint256.Int).Mul(head.BaseFee, uint256.NewInt(basefeeWiggleMultiplier)))
 	}
 	if gasFeeCap.Cmp(gasTipCap) < 0 {
 		return nil, fmt.Errorf("maxFeePerGas (%v) < maxPriorityFeePerGas (%v)", gasFeeCap, gasTipCap)
@@ -263,7 +263,7 @@ func (c *BoundContract) createDynamicTx(opts *TransactOpts, contract *types.Addr
 	gasLimit := opts.GasLimit
 	if opts.GasLimit == 0 {
 		var err error
-		gasLimit, err = c.estimateGasLimit(opts, contract, input, uint256.NewInt(0), gasTipCap, gasFeeCap, value)
+		gasLimit, err = c.estimateGasLimit(opts, contract, input, nil, gasTipCap, gasFeeCap, value)
 		if err != nil {
 			return nil, err
 		}
@@ -307,7 +307,7 @@ func (c *BoundContract) createLegacyTx(opts *TransactOpts, contract *types.Addre
 	gasLimit := opts.GasLimit
 	if opts.GasLimit == 0 {
 		var err error
-		gasLimit, err = c.estimateGasLimit(opts, contract, input, gasPrice, uint256.NewInt(0), uint256.NewInt(0), value)
+		gasLimit, err = c.estimateGasLimit(opts, contract, input, gasPrice, nil, nil, value)
 		if err != nil {
 			return nil, err
 		}
@@ -375,7 +375,7 @@ func (c *BoundContract) transact(opts *TransactOpts, contract *types.Address, in
 	} else {
 
 		// Only query for basefee if gasPrice not specified
-		if head, errHead := c.transactor.HeaderByNumber(ensureContext(opts.Context), uint256.NewInt(0) /* latest */); errHead != nil {
+		if head, errHead := c.transactor.HeaderByNumber(ensureContext(opts.Context), nil /* latest */); errHead != nil {
 			return nil, errHead
 		} else if head.BaseFee != nil {
 			rawTx, err = c.createDynamicTx(opts, contract, input, head)
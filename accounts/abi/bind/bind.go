@@ -305,13 +305,18 @@ var bindType = map[Lang]func(kind abi.Type, structs map[string]*tmplStruct) stri
 func bindBasicTypeGo(kind abi.Type) string {
 	switch kind.T {
 	case abi.AddressTy:
-		return "common.Address"
+		return "types.Address"
 	case abi.IntTy, abi.UintTy:
 		parts := regexp.MustCompile(`(u)?int([0-9]*)`).FindStringSubmatch(kind.String())
 		switch parts[2] {
 		case "8", "16", "32", "64":
 			return fmt.Sprintf("%sint%s", parts[1], parts[2])
 		}
+		// Wide unsigned integers use the chain's native 256 bit type, signed
+		// ones need the sign and stay big integers.
+		if kind.T == abi.UintTy {
+			return "*uint256.Int"
+		}
 		return "*big.Int"
 	case abi.FixedBytesTy:
 		return fmt.Sprintf("[%d]byte", kind.Size)
@@ -435,7 +440,7 @@ func bindTopicTypeGo(kind abi.Type, structs map[string]*tmplStruct) string {
 	// We only convert stringS and bytes to hash, still need to deal with
 	// array(both fixed-size and dynamic-size) and struct.
 	if bound == "string" || bound == "[]byte" {
-		bound = "common.Hash"
+		bound = "types.Hash"
 	}
 	return bound
 }
@@ -0,0 +1,56 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package bind
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestBindDeposit generates the binding of the deposit contract and checks it
+// uses the AmazeChain address, transaction and uint256 types.
+func TestBindDeposit(t *testing.T) {
+	abiJSON, err := os.ReadFile("../../../contracts/deposit/abi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bin, err := os.ReadFile("../../../contracts/deposit/bytecode.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, err := Bind([]string{"Deposit"}, []string{string(abiJSON)}, []string{strings.TrimSpace(string(bin))}, nil, "deposit", LangGo, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to generate binding: %v", err)
+	}
+	for _, want := range []string{
+		"func DeployDeposit(auth *bind.TransactOpts, backend bind.ContractBackend, _depositLockingTime uint64, _fiftyDepositLimit uint64, _oneHundredDepositLimit uint64, _fiveHundredDepositLimit uint64) (types.Address, *transaction.Transaction, *Deposit, error)",
+		"func (_Deposit *DepositCaller) GetDepositCount(opts *bind.CallOpts) (*uint256.Int, error)",
+		"func (_Deposit *DepositCaller) DepositsOf(opts *bind.CallOpts, payee types.Address) (*uint256.Int, error)",
+		"func (_Deposit *DepositFilterer) WatchOwnershipTransferred(opts *bind.WatchOpts, sink chan<- *DepositOwnershipTransferred, previousOwner []types.Address, newOwner []types.Address) (event.Subscription, error)",
+		"func (_Deposit *DepositFilterer) ParseOwnershipTransferred(log block.Log) (*DepositOwnershipTransferred, error)",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("binding is missing %q", want)
+		}
+	}
+	for _, unwanted := range []string{"common.Address", "*big.Int", "types.Log"} {
+		if strings.Contains(code, unwanted) {
+			t.Errorf("binding still references %q", unwanted)
+		}
+	}
+}
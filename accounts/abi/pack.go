@@ -22,6 +22,7 @@ import (
 	"github.com/amazechain/amc/common"
 	"github.com/amazechain/amc/common/math"
 	"github.com/amazechain/amc/common/types"
+	"github.com/holiman/uint256"
 	"math/big"
 	"reflect"
 )
@@ -78,6 +79,9 @@ func packNum(value reflect.Value) []byte {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		return math.U256Bytes(big.NewInt(value.Int()))
 	case reflect.Ptr:
+		if v, ok := value.Interface().(*uint256.Int); ok {
+			return v.PaddedBytes(32)
+		}
 		return math.U256Bytes(new(big.Int).Set(value.Interface().(*big.Int)))
 	default:
 		panic("abi: fatal error")
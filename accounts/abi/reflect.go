@@ -22,6 +22,8 @@ import (
 	"math/big"
 	"reflect"
 	"strings"
+
+	"github.com/holiman/uint256"
 )
 
 // ConvertType converts an interface of a runtime type into a interface of the
@@ -51,9 +53,9 @@ func ConvertType(in interface{}, proto interface{}) interface{} {
 }
 
 // indirect recursively dereferences the value until it either gets the value
-// or finds a big.Int
+// or finds a big.Int or uint256.Int
 func indirect(v reflect.Value) reflect.Value {
-	if v.Kind() == reflect.Ptr && v.Elem().Type() != reflect.TypeOf(big.Int{}) {
+	if v.Kind() == reflect.Ptr && v.Elem().Type() != reflect.TypeOf(big.Int{}) && v.Elem().Type() != reflect.TypeOf(uint256.Int{}) {
 		return indirect(v.Elem())
 	}
 	return v
@@ -102,6 +104,8 @@ func mustArrayToByteSlice(value reflect.Value) reflect.Value {
 func set(dst, src reflect.Value) error {
 	dstType, srcType := dst.Type(), src.Type()
 	switch {
+	case dstType == reflect.TypeOf(&uint256.Int{}) && srcType == reflect.TypeOf(&big.Int{}) && dst.CanSet():
+		return setUint256(dst, src.Interface().(*big.Int))
 	case dstType.Kind() == reflect.Interface && dst.Elem().IsValid() && (dst.Elem().Type().Kind() == reflect.Ptr || dst.Elem().CanSet()):
 		return set(dst.Elem(), src)
 	case dstType.Kind() == reflect.Ptr && dstType.Elem() != reflect.TypeOf(big.Int{}):
@@ -120,6 +124,20 @@ func set(dst, src reflect.Value) error {
 	return nil
 }
 
+// setUint256 assigns a decoded big integer to a *uint256.Int destination,
+// rejecting values the 256 bit unsigned type cannot hold.
+func setUint256(dst reflect.Value, src *big.Int) error {
+	if src.Sign() < 0 {
+		return fmt.Errorf("abi: cannot unmarshal negative %v in to %v", src, dst.Type())
+	}
+	v, overflow := uint256.FromBig(src)
+	if overflow {
+		return fmt.Errorf("abi: cannot unmarshal %v in to %v, overflow", src, dst.Type())
+	}
+	dst.Set(reflect.ValueOf(v))
+	return nil
+}
+
 // setSlice attempts to assign src to dst when slices are not assignable by default
 // e.g. src: [][]byte -> dst: [][15]byte
 // setSlice ignores if we cannot copy all of src' elements.
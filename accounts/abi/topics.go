@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"github.com/amazechain/amc/common/crypto"
 	"github.com/amazechain/amc/common/types"
+	"github.com/holiman/uint256"
 	"math/big"
 	"reflect"
 )
@@ -42,6 +43,8 @@ func MakeTopics(query ...[]interface{}) ([][]types.Hash, error) {
 			case *big.Int:
 				blob := rule.Bytes()
 				copy(topic[types.HashLength-len(blob):], blob)
+			case *uint256.Int:
+				topic = rule.Bytes32()
 			case bool:
 				if rule {
 					topic[types.HashLength-1] = 1
@@ -113,17 +116,18 @@ func genIntType(rule int64, size uint) []byte {
 // ParseTopics converts the indexed topic fields into actual log field values.
 func ParseTopics(out interface{}, fields Arguments, topics []types.Hash) error {
 	return parseTopicWithSetter(fields, topics,
-		func(arg Argument, reconstr interface{}) {
+		func(arg Argument, reconstr interface{}) error {
 			field := reflect.ValueOf(out).Elem().FieldByName(ToCamelCase(arg.Name))
-			field.Set(reflect.ValueOf(reconstr))
+			return set(field, reflect.ValueOf(reconstr))
 		})
 }
 
 // ParseTopicsIntoMap converts the indexed topic field-value pairs into map key-value pairs.
 func ParseTopicsIntoMap(out map[string]interface{}, fields Arguments, topics []types.Hash) error {
 	return parseTopicWithSetter(fields, topics,
-		func(arg Argument, reconstr interface{}) {
+		func(arg Argument, reconstr interface{}) error {
 			out[arg.Name] = reconstr
+			return nil
 		})
 }
 
@@ -132,7 +136,7 @@ func ParseTopicsIntoMap(out map[string]interface{}, fields Arguments, topics []t
 //
 // Note, dynamic types cannot be reconstructed since they get mapped to Keccak256
 // hashes as the topic value!
-func parseTopicWithSetter(fields Arguments, topics []types.Hash, setter func(Argument, interface{})) error {
+func parseTopicWithSetter(fields Arguments, topics []types.Hash, setter func(Argument, interface{}) error) error {
 	// Sanity check that the fields and topics match up
 	if len(fields) != len(topics) {
 		return errors.New("topic/field count mismatch")
@@ -165,7 +169,9 @@ func parseTopicWithSetter(fields Arguments, topics []types.Hash, setter func(Arg
 			}
 		}
 		// Use the setter function to store the value
-		setter(arg, reconstr)
+		if err := setter(arg, reconstr); err != nil {
+			return err
+		}
 	}
 
 	return nil
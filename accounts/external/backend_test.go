@@ -0,0 +1,185 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package external
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/amazechain/amc/accounts"
+	"github.com/amazechain/amc/common/crypto"
+	"github.com/amazechain/amc/common/hexutil"
+	"github.com/amazechain/amc/common/transaction"
+	"github.com/amazechain/amc/common/types"
+	mvm_types "github.com/amazechain/amc/internal/avm/types"
+	"github.com/amazechain/amc/internal/consensus/apos"
+	"github.com/amazechain/amc/modules/rpc/jsonrpc"
+	"github.com/holiman/uint256"
+)
+
+// mockSigner implements the account_ namespace of clef in process.
+type mockSigner struct {
+	key     *ecdsa.PrivateKey
+	chainID *big.Int
+	tamper  bool // bump the nonce of transactions before signing them
+}
+
+func (m *mockSigner) Version() string { return "6.0.0" }
+
+func (m *mockSigner) List() []types.Address {
+	return []types.Address{crypto.PubkeyToAddress(m.key.PublicKey)}
+}
+
+func (m *mockSigner) SignData(mimeType string, addr types.Address, data hexutil.Bytes) (hexutil.Bytes, error) {
+	if addr != crypto.PubkeyToAddress(m.key.PublicKey) {
+		return nil, errors.New("unknown account")
+	}
+	sig, err := crypto.Sign(crypto.Keccak256(data), m.key)
+	if err != nil {
+		return nil, err
+	}
+	sig[crypto.RecoveryIDOffset] += 27 // clef returns ethereum-type signatures
+	return sig, nil
+}
+
+func (m *mockSigner) SignTransaction(args sendTxArgs) (*signTransactionResult, error) {
+	nonce := uint64(args.Nonce)
+	if m.tamper {
+		nonce++
+	}
+	to := mvm_types.FromAmcAddress(args.To)
+	var inner mvm_types.TxData
+	if args.MaxFeePerGas != nil {
+		inner = &mvm_types.DynamicFeeTx{
+			ChainID:    args.ChainID.ToInt(),
+			Nonce:      nonce,
+			GasTipCap:  args.MaxPriorityFeePerGas.ToInt(),
+			GasFeeCap:  args.MaxFeePerGas.ToInt(),
+			Gas:        uint64(args.Gas),
+			To:         to,
+			Value:      args.Value.ToInt(),
+			Data:       *args.Data,
+			AccessList: *args.AccessList,
+		}
+	} else {
+		inner = &mvm_types.LegacyTx{
+			Nonce:    nonce,
+			GasPrice: args.GasPrice.ToInt(),
+			Gas:      uint64(args.Gas),
+			To:       to,
+			Value:    args.Value.ToInt(),
+			Data:     *args.Data,
+		}
+	}
+	signed, err := mvm_types.SignNewTx(m.key, mvm_types.LatestSignerForChainID(m.chainID), inner)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return &signTransactionResult{Raw: raw}, nil
+}
+
+func newTestSigner(t *testing.T, mock *mockSigner) *ExternalSigner {
+	server := jsonrpc.NewServer()
+	if err := server.RegisterName("account", mock); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Stop)
+	signer, err := newExternalSigner(jsonrpc.DialInProc(server), "mock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer
+}
+
+// Tests that block seals can be signed through the external signer with the
+// signer function the consensus engines expect.
+func TestExternalSignerSignData(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	signer := newTestSigner(t, &mockSigner{key: key, chainID: big.NewInt(97)})
+
+	account := accounts.Account{Address: addr}
+	if !signer.Contains(account) {
+		t.Fatalf("signer does not contain %s", addr)
+	}
+	var signFn apos.SignerFn = signer.SignData
+	data := []byte("header")
+	sig, err := signFn(account, accounts.MimetypeClique, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sig[crypto.RecoveryIDOffset] > 1 {
+		t.Fatalf("recovery id not normalized: %d", sig[crypto.RecoveryIDOffset])
+	}
+	pub, err := crypto.SigToPub(crypto.Keccak256(data), sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have := crypto.PubkeyToAddress(*pub); have != addr {
+		t.Fatalf("signer mismatch: have %s, want %s", have, addr)
+	}
+
+	other, _ := crypto.GenerateKey()
+	if _, err := signFn(accounts.Account{Address: crypto.PubkeyToAddress(other.PublicKey)}, accounts.MimetypeClique, data); err == nil {
+		t.Fatal("signed with an unknown account")
+	}
+}
+
+// Tests that transactions signed externally match the ones signed locally, and
+// that a signer modifying the transaction is detected.
+func TestExternalSignerSignTx(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	chainID := big.NewInt(97)
+	to := types.HexToAddress("0x000000000000000000000000000000000000dead")
+	account := accounts.Account{Address: addr}
+
+	txs := []*transaction.Transaction{
+		transaction.NewTx(&transaction.LegacyTx{Nonce: 1, GasPrice: uint256.NewInt(2), Gas: 21000, To: &to, Value: uint256.NewInt(3)}),
+		transaction.NewTx(&transaction.DynamicFeeTx{ChainID: uint256.NewInt(97), Nonce: 2, GasTipCap: uint256.NewInt(1), GasFeeCap: uint256.NewInt(5), Gas: 30000, To: &to, Value: uint256.NewInt(0), Data: []byte{0x01}}),
+	}
+	signer := newTestSigner(t, &mockSigner{key: key, chainID: chainID})
+	for _, tx := range txs {
+		signed, err := signer.SignTx(account, tx, chainID)
+		if err != nil {
+			t.Fatalf("type %d: sign failed: %v", tx.Type(), err)
+		}
+		local, err := transaction.SignTx(tx, transaction.LatestSignerForChainID(chainID), key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have, want := signed.Hash(), local.Hash(); have != want {
+			t.Fatalf("type %d: hash mismatch: have %s, want %s", tx.Type(), have, want)
+		}
+		if have := *signed.From(); have != addr {
+			t.Fatalf("type %d: sender mismatch: have %s, want %s", tx.Type(), have, addr)
+		}
+	}
+
+	tampering := newTestSigner(t, &mockSigner{key: key, chainID: chainID, tamper: true})
+	for _, tx := range txs {
+		if _, err := tampering.SignTx(account, tx, chainID); err == nil {
+			t.Fatalf("type %d: modified transaction accepted", tx.Type())
+		}
+	}
+}
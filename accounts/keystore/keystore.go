@@ -34,6 +34,7 @@ import (
 
 	"github.com/amazechain/amc/accounts"
 	"github.com/amazechain/amc/common/crypto"
+	"github.com/amazechain/amc/common/crypto/bls"
 	"github.com/amazechain/amc/common/transaction"
 	"github.com/amazechain/amc/common/types"
 	event "github.com/amazechain/amc/modules/event/v2"
@@ -288,6 +289,21 @@ func (ks *KeyStore) SignTx(a accounts.Account, tx *transaction.Transaction, chai
 	return transaction.SignTx(tx, signer, unlockedKey.PrivateKey)
 }
 
+// BLSSecretKey returns the BLS key of an unlocked account, derived from its private
+// key the same way the deposit tooling derives the key a verifier deposits with.
+func (ks *KeyStore) BLSSecretKey(a accounts.Account) (bls.SecretKey, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	unlockedKey, found := ks.unlocked[a.Address]
+	if !found {
+		return nil, ErrLocked
+	}
+	var seed [32]byte
+	copy(seed[:], crypto.FromECDSA(unlockedKey.PrivateKey))
+	return bls.SecretKeyFromRandom32Byte(seed)
+}
+
 // SignHashWithPassphrase signs hash if the private key matching the given address
 // can be decrypted with the given passphrase. The produced signature is in the
 // [R || S || V] format where V is 0 or 1.
@@ -29,6 +29,7 @@ import (
 
 	"github.com/amazechain/amc/accounts"
 	"github.com/amazechain/amc/common/crypto"
+	"github.com/amazechain/amc/common/crypto/bls"
 	"github.com/amazechain/amc/common/types"
 	"github.com/amazechain/amc/internal/avm/common"
 	event "github.com/amazechain/amc/modules/event/v2"
@@ -86,6 +87,41 @@ func TestSign(t *testing.T) {
 	}
 }
 
+func TestBLSSecretKey(t *testing.T) {
+	_, ks := tmpKeyStore(t, true)
+
+	// Verifiers deposit the BLS key seeded with their raw private key
+	seed := "2d09d9f4e166f35a4ab0a2edd599e2a23bbe86b312b2e05b34d9fbe5693b1e48"
+	priv, err := crypto.HexToECDSA(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pass := "foo"
+	a1, err := ks.ImportECDSA(priv, pass)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ks.BLSSecretKey(a1); err != ErrLocked {
+		t.Fatalf("expected ErrLocked, got %v", err)
+	}
+	if err := ks.Unlock(a1, pass); err != nil {
+		t.Fatal(err)
+	}
+	key, err := ks.BLSSecretKey(a1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ikm [32]byte
+	copy(ikm[:], common.FromHex(seed))
+	want, err := bls.SecretKeyFromRandom32Byte(ikm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !key.PublicKey().Equals(want.PublicKey()) {
+		t.Errorf("BLS key mismatch")
+	}
+}
+
 func TestSignWithPassphrase(t *testing.T) {
 	_, ks := tmpKeyStore(t, true)
 
@@ -0,0 +1,189 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/amazechain/amc/accounts/abi/bind"
+	"github.com/amazechain/amc/common/crypto"
+	"github.com/amazechain/amc/internal/avm/common/compiler"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	abigenABIFlag = &cli.StringFlag{
+		Name:  "abi",
+		Usage: "Path to the contract ABI json to bind, - for STDIN",
+	}
+	abigenBinFlag = &cli.StringFlag{
+		Name:  "bin",
+		Usage: "Path to the contract bytecode (generate deploy method)",
+	}
+	abigenTypeFlag = &cli.StringFlag{
+		Name:  "type",
+		Usage: "Go struct name for the binding (default = package name)",
+	}
+	abigenSolFlag = &cli.StringFlag{
+		Name:  "sol",
+		Usage: "Path to the contract Solidity source to build and bind",
+	}
+	abigenSolcFlag = &cli.StringFlag{
+		Name:  "solc",
+		Usage: "Solidity compiler to use if source builds are requested",
+		Value: "solc",
+	}
+	abigenExcFlag = &cli.StringFlag{
+		Name:  "exc",
+		Usage: "Comma separated types to exclude from binding",
+	}
+	abigenPkgFlag = &cli.StringFlag{
+		Name:     "pkg",
+		Usage:    "Package name to generate the binding into",
+		Required: true,
+	}
+	abigenOutFlag = &cli.StringFlag{
+		Name:  "out",
+		Usage: "Output file for the generated binding (default = stdout)",
+	}
+
+	abigenCommand = &cli.Command{
+		Name:      "abigen",
+		Usage:     "Generate Go bindings for a contract",
+		ArgsUsage: "",
+		Action:    abigen,
+		Flags: []cli.Flag{
+			abigenABIFlag,
+			abigenBinFlag,
+			abigenTypeFlag,
+			abigenSolFlag,
+			abigenSolcFlag,
+			abigenExcFlag,
+			abigenPkgFlag,
+			abigenOutFlag,
+		},
+		Description: `
+The abigen command generates a Go binding from a contract ABI, optionally with
+its bytecode for a deploy method, or from Solidity sources compiled with solc.
+The binding uses the AmazeChain address, transaction and uint256 types and runs
+against any bind.ContractBackend, such as a node over RPC or the in-memory
+backends.SimulatedBackend.`,
+	}
+)
+
+func abigen(ctx *cli.Context) error {
+	var (
+		abis    []string
+		bins    []string
+		types   []string
+		sigs    []map[string]string
+		libs    = make(map[string]string)
+		aliases = make(map[string]string)
+	)
+	if sol := ctx.String(abigenSolFlag.Name); sol != "" {
+		exclude := make(map[string]bool)
+		for _, kind := range strings.Split(ctx.String(abigenExcFlag.Name), ",") {
+			exclude[strings.ToLower(strings.TrimSpace(kind))] = true
+		}
+		contracts, err := compiler.CompileSolidity(ctx.String(abigenSolcFlag.Name), sol)
+		if err != nil {
+			return fmt.Errorf("failed to build Solidity contract: %w", err)
+		}
+		for name, contract := range contracts {
+			nameParts := strings.Split(name, ":")
+			typeName := nameParts[len(nameParts)-1]
+			if exclude[strings.ToLower(typeName)] {
+				continue
+			}
+			abi, err := json.Marshal(contract.Info.AbiDefinition)
+			if err != nil {
+				return fmt.Errorf("failed to parse ABIs from compiler output: %w", err)
+			}
+			abis = append(abis, string(abi))
+			bins = append(bins, contract.Code)
+			sigs = append(sigs, contract.Hashes)
+			types = append(types, typeName)
+
+			// Derive the library placeholder which is a 34 character prefix of the
+			// hex encoding of the keccak256 hash of the fully qualified library name.
+			libPattern := crypto.Keccak256Hash([]byte(name)).String()[2:36]
+			libs[libPattern] = typeName
+		}
+	} else {
+		input := ctx.String(abigenABIFlag.Name)
+		if input == "" {
+			return errors.New("either --abi or --sol must be specified")
+		}
+		abi, err := readABIInput(input)
+		if err != nil {
+			return err
+		}
+		abis = append(abis, string(abi))
+
+		var bin []byte
+		if binFile := ctx.String(abigenBinFlag.Name); binFile != "" {
+			if bin, err = os.ReadFile(binFile); err != nil {
+				return fmt.Errorf("failed to read input bytecode: %w", err)
+			}
+			if strings.Contains(string(bin), "//") {
+				return errors.New("contract has additional library references, please use other mode(e.g. --sol) to generate the binding")
+			}
+		}
+		bins = append(bins, strings.TrimSpace(string(bin)))
+
+		kind := ctx.String(abigenTypeFlag.Name)
+		if kind == "" {
+			kind = ctx.String(abigenPkgFlag.Name)
+		}
+		types = append(types, kind)
+	}
+
+	code, err := bind.Bind(types, abis, bins, sigs, ctx.String(abigenPkgFlag.Name), bind.LangGo, libs, aliases)
+	if err != nil {
+		return fmt.Errorf("failed to generate ABI binding: %w", err)
+	}
+	if out := ctx.String(abigenOutFlag.Name); out != "" {
+		if err := os.WriteFile(out, []byte(code), 0600); err != nil {
+			return fmt.Errorf("failed to write ABI binding: %w", err)
+		}
+		return nil
+	}
+	fmt.Printf("%s\n", code)
+	return nil
+}
+
+// readABIInput loads the ABI json from a file, or from STDIN for "-".
+func readABIInput(input string) ([]byte, error) {
+	var (
+		abi []byte
+		err error
+	)
+	if input == "-" {
+		abi, err = io.ReadAll(os.Stdin)
+	} else {
+		abi, err = os.ReadFile(input)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read input ABI: %w", err)
+	}
+	return abi, nil
+}
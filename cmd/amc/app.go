@@ -53,6 +53,7 @@ func appRun(ctx *cli.Context) error {
 		if len(privateKey) > 0 {
 			DefaultConfig.NetworkCfg.LocalPeerKey = privateKey
 		}
+		DefaultConfig.TxPoolCfg.Locals = txpoolLocals.Value()
 	}
 
 	log.Init(DefaultConfig.NodeCfg, DefaultConfig.LoggerCfg)
@@ -183,7 +184,11 @@ func unlockAccounts(ctx *cli.Context, stack *node.Node, cfg *conf.Config) {
 	if !cfg.NodeCfg.InsecureUnlockAllowed && cfg.NodeCfg.ExtRPCEnabled() {
 		utils.Fatalf("Account unlock with HTTP access is forbidden!")
 	}
-	ks := stack.AccountManager().Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
+	backends := stack.AccountManager().Backends(keystore.KeyStoreType)
+	if len(backends) == 0 {
+		utils.Fatalf("Keystore is not available, accounts of the external signer can not be unlocked")
+	}
+	ks := backends[0].(*keystore.KeyStore)
 	passwords := MakePasswordList(ctx)
 	for i, account := range unlocks {
 		unlockAccount(ks, account, i, passwords)
@@ -29,6 +29,7 @@ var (
 	listenAddress = cli.NewStringSlice()
 	bootstraps    = cli.NewStringSlice()
 	cfgFile       string
+	txpoolLocals  = cli.NewStringSlice()
 )
 
 var rootCmd = []*cli.Command{
@@ -72,6 +73,18 @@ var nodeFlg = []cli.Flag{
 		Value:       "",
 		Destination: &DefaultConfig.NodeCfg.NodePrivate,
 	},
+	&cli.StringFlag{
+		Name:        "syncmode",
+		Usage:       `Blockchain sync mode ("full" or "snap")`,
+		Value:       "full",
+		Destination: &DefaultConfig.NodeCfg.SyncMode,
+	},
+	&cli.DurationFlag{
+		Name:        "slowblock",
+		Usage:       "Log the processing stage breakdown of blocks taking longer than this (0 disables)",
+		Value:       DefaultConfig.NodeCfg.SlowBlockThreshold,
+		Destination: &DefaultConfig.NodeCfg.SlowBlockThreshold,
+	},
 }
 
 var rpcFlags = []cli.Flag{
@@ -102,6 +115,31 @@ var rpcFlags = []cli.Flag{
 		Destination: &DefaultConfig.NodeCfg.HTTPPort,
 	},
 
+	&cli.BoolFlag{
+		Name:        "authrpc",
+		Usage:       "Enable the JWT authenticated HTTP json-rpc server serving the admin namespace",
+		Value:       false,
+		Destination: &DefaultConfig.NodeCfg.AuthRPC,
+	},
+	&cli.StringFlag{
+		Name:        "authrpc.addr",
+		Usage:       "Listening address for authenticated APIs",
+		Value:       DefaultConfig.NodeCfg.AuthHost,
+		Destination: &DefaultConfig.NodeCfg.AuthHost,
+	},
+	&cli.StringFlag{
+		Name:        "authrpc.port",
+		Usage:       "Listening port for authenticated APIs",
+		Value:       DefaultConfig.NodeCfg.AuthPort,
+		Destination: &DefaultConfig.NodeCfg.AuthPort,
+	},
+	&cli.StringFlag{
+		Name:        "authrpc.jwtsecret",
+		Usage:       "Path to a JWT secret to use for authenticated RPC endpoints (generated if missing)",
+		Value:       DefaultConfig.NodeCfg.JWTSecret,
+		Destination: &DefaultConfig.NodeCfg.JWTSecret,
+	},
+
 	&cli.BoolFlag{
 		Name:        "ws",
 		Usage:       "Enable the WS-RPC server",
@@ -266,6 +304,11 @@ var (
 		TakesFile:   true,
 		Destination: &DefaultConfig.NodeCfg.KeyStoreDir,
 	}
+	ExternalSignerFlag = &cli.StringFlag{
+		Name:        "account.signer",
+		Usage:       "External signer (url or path to ipc file)",
+		Destination: &DefaultConfig.NodeCfg.ExternalSigner,
+	}
 	InsecureUnlockAllowedFlag = &cli.BoolFlag{
 		Name:        "account.allow.insecure.unlock",
 		Usage:       "Allow insecure account unlocking when account-related RPCs are exposed by http",
@@ -279,6 +322,18 @@ var (
 		Usage: "Enable metrics collection and reporting",
 	}
 
+	MetricsHTTPFlag = &cli.StringFlag{
+		Name:        "metrics.addr",
+		Usage:       "Enable stand-alone metrics HTTP server listening interface (serves Prometheus /metrics)",
+		Destination: &DefaultConfig.MetricsCfg.HTTP,
+	}
+	MetricsPortFlag = &cli.IntFlag{
+		Name:        "metrics.port",
+		Usage:       "Metrics HTTP server listening port",
+		Value:       DefaultConfig.MetricsCfg.Port,
+		Destination: &DefaultConfig.MetricsCfg.Port,
+	}
+
 	MetricsEnableInfluxDBFlag = &cli.BoolFlag{
 		Name:        "metrics.influxdb",
 		Usage:       "Enable metrics export/push to an external InfluxDB database",
@@ -336,6 +391,96 @@ var (
 		Usage:       "InfluxDB organization name (v2 only)",
 		Destination: &DefaultConfig.MetricsCfg.InfluxDBOrganization,
 	}
+
+	TxPoolLocalsFlag = &cli.StringSliceFlag{
+		Name:        "txpool.locals",
+		Usage:       "Comma separated accounts to treat as locals (no flush, priority inclusion)",
+		Destination: txpoolLocals,
+	}
+	TxPoolNoLocalsFlag = &cli.BoolFlag{
+		Name:        "txpool.nolocals",
+		Usage:       "Disables price exemptions for locally submitted transactions",
+		Destination: &DefaultConfig.TxPoolCfg.NoLocals,
+	}
+	TxPoolJournalFlag = &cli.StringFlag{
+		Name:        "txpool.journal",
+		Usage:       "Disk journal for local transaction to survive node restarts (relative to the data dir)",
+		Value:       DefaultConfig.TxPoolCfg.Journal,
+		Destination: &DefaultConfig.TxPoolCfg.Journal,
+	}
+	TxPoolRejournalFlag = &cli.DurationFlag{
+		Name:        "txpool.rejournal",
+		Usage:       "Time interval to regenerate the local transaction journal",
+		Value:       DefaultConfig.TxPoolCfg.Rejournal,
+		Destination: &DefaultConfig.TxPoolCfg.Rejournal,
+	}
+	TxPoolPriceLimitFlag = &cli.Uint64Flag{
+		Name:        "txpool.pricelimit",
+		Usage:       "Minimum gas price limit to enforce for acceptance into the pool",
+		Value:       DefaultConfig.TxPoolCfg.PriceLimit,
+		Destination: &DefaultConfig.TxPoolCfg.PriceLimit,
+	}
+	TxPoolPriceBumpFlag = &cli.Uint64Flag{
+		Name:        "txpool.pricebump",
+		Usage:       "Price bump percentage to replace an already existing transaction",
+		Value:       DefaultConfig.TxPoolCfg.PriceBump,
+		Destination: &DefaultConfig.TxPoolCfg.PriceBump,
+	}
+	TxPoolAccountSlotsFlag = &cli.Uint64Flag{
+		Name:        "txpool.accountslots",
+		Usage:       "Minimum number of executable transaction slots guaranteed per account",
+		Value:       DefaultConfig.TxPoolCfg.AccountSlots,
+		Destination: &DefaultConfig.TxPoolCfg.AccountSlots,
+	}
+	TxPoolGlobalSlotsFlag = &cli.Uint64Flag{
+		Name:        "txpool.globalslots",
+		Usage:       "Maximum number of executable transaction slots for all accounts",
+		Value:       DefaultConfig.TxPoolCfg.GlobalSlots,
+		Destination: &DefaultConfig.TxPoolCfg.GlobalSlots,
+	}
+	TxPoolAccountQueueFlag = &cli.Uint64Flag{
+		Name:        "txpool.accountqueue",
+		Usage:       "Maximum number of non-executable transaction slots permitted per account",
+		Value:       DefaultConfig.TxPoolCfg.AccountQueue,
+		Destination: &DefaultConfig.TxPoolCfg.AccountQueue,
+	}
+	TxPoolGlobalQueueFlag = &cli.Uint64Flag{
+		Name:        "txpool.globalqueue",
+		Usage:       "Maximum number of non-executable transaction slots for all accounts",
+		Value:       DefaultConfig.TxPoolCfg.GlobalQueue,
+		Destination: &DefaultConfig.TxPoolCfg.GlobalQueue,
+	}
+	TxPoolLifetimeFlag = &cli.DurationFlag{
+		Name:        "txpool.lifetime",
+		Usage:       "Maximum amount of time non-executable transaction are queued",
+		Value:       DefaultConfig.TxPoolCfg.Lifetime,
+		Destination: &DefaultConfig.TxPoolCfg.Lifetime,
+	}
+
+	PruneFlag = &cli.StringFlag{
+		Name:        "prune",
+		Usage:       `History kept by the node: "archive" keeps everything, "full" only the last prune.* blocks`,
+		Value:       DefaultConfig.DatabaseCfg.Prune,
+		Destination: &DefaultConfig.DatabaseCfg.Prune,
+	}
+	PruneHistoryFlag = &cli.Uint64Flag{
+		Name:        "prune.history",
+		Usage:       "Number of recent blocks whose state history is kept in full prune mode (0 keeps all)",
+		Value:       DefaultConfig.DatabaseCfg.PruneHistory,
+		Destination: &DefaultConfig.DatabaseCfg.PruneHistory,
+	}
+	PruneReceiptsFlag = &cli.Uint64Flag{
+		Name:        "prune.receipts",
+		Usage:       "Number of recent blocks whose receipts are kept in full prune mode (0 keeps all)",
+		Value:       DefaultConfig.DatabaseCfg.PruneReceipts,
+		Destination: &DefaultConfig.DatabaseCfg.PruneReceipts,
+	}
+	PruneLogsFlag = &cli.Uint64Flag{
+		Name:        "prune.logs",
+		Usage:       "Number of recent blocks whose logs are kept in full prune mode (0 keeps all)",
+		Value:       DefaultConfig.DatabaseCfg.PruneLogs,
+		Destination: &DefaultConfig.DatabaseCfg.PruneLogs,
+	}
 )
 
 var (
@@ -348,10 +493,13 @@ var (
 		LightKDFFlag,
 		InsecureUnlockAllowedFlag,
 		UnlockedAccountFlag,
+		ExternalSignerFlag,
 	}
 
 	metricsFlags = []cli.Flag{
 		MetricsEnabledFlag,
+		MetricsHTTPFlag,
+		MetricsPortFlag,
 		MetricsEnableInfluxDBFlag,
 		MetricsInfluxDBEndpointFlag,
 		MetricsInfluxDBTokenFlag,
@@ -363,4 +511,25 @@ var (
 		MetricsInfluxDBUsernameFlag,
 		MetricsInfluxDBDatabaseFlag,
 	}
+
+	txpoolFlags = []cli.Flag{
+		TxPoolLocalsFlag,
+		TxPoolNoLocalsFlag,
+		TxPoolJournalFlag,
+		TxPoolRejournalFlag,
+		TxPoolPriceLimitFlag,
+		TxPoolPriceBumpFlag,
+		TxPoolAccountSlotsFlag,
+		TxPoolGlobalSlotsFlag,
+		TxPoolAccountQueueFlag,
+		TxPoolGlobalQueueFlag,
+		TxPoolLifetimeFlag,
+	}
+
+	pruneFlags = []cli.Flag{
+		PruneFlag,
+		PruneHistoryFlag,
+		PruneReceiptsFlag,
+		PruneLogsFlag,
+	}
 )
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"github.com/amazechain/amc/params"
 	"math/big"
+	"time"
 
 	"github.com/amazechain/amc/conf"
 )
@@ -36,7 +37,12 @@ var DefaultConfig = conf.Config{
 		HTTPHost:    "127.0.0.1",
 		HTTPPort:    "8545",
 		IPCPath:     "amc.ipc",
+		AuthHost:    "127.0.0.1",
+		AuthPort:    "8551",
+		JWTSecret:   "jwtsecret",
 		Miner:       false,
+
+		SlowBlockThreshold: 2 * time.Second,
 	},
 	NetworkCfg: conf.NetWorkConfig{
 		Bootstrapped: true,
@@ -65,13 +71,33 @@ var DefaultConfig = conf.Config{
 		IsMem:      false,
 		MaxDB:      100,
 		MaxReaders: 1000,
+
+		Prune:         conf.PruneArchive,
+		PruneHistory:  90000,
+		PruneReceipts: 90000,
+		PruneLogs:     90000,
 	},
 	MetricsCfg: conf.MetricsConfig{
+		Port:                 6061,
 		InfluxDBEndpoint:     "",
 		InfluxDBToken:        "",
 		InfluxDBBucket:       "",
 		InfluxDBOrganization: "",
 	},
+	TxPoolCfg: conf.TxPoolConfig{
+		Journal:   "transactions.journal",
+		Rejournal: time.Hour,
+
+		PriceLimit: 1,
+		PriceBump:  10,
+
+		AccountSlots: 16,
+		GlobalSlots:  4096 + 1024,
+		AccountQueue: 64,
+		GlobalQueue:  1024,
+
+		Lifetime: 3 * time.Hour,
+	},
 
 	GenesisBlockCfg: ReadGenesis("allocs/genesis.json"),
 	GPO:             conf.FullNodeGPO,
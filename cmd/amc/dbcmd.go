@@ -0,0 +1,210 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/amazechain/amc/internal"
+	"github.com/amazechain/amc/internal/node"
+	"github.com/amazechain/amc/modules/rawdb"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	verifyFromFlag = &cli.Uint64Flag{
+		Name:  "from",
+		Usage: "Number of the first block to verify",
+	}
+	verifyToFlag = &cli.Uint64Flag{
+		Name:  "to",
+		Usage: "Number of the last block to verify (default: current head)",
+	}
+	reexecFromFlag = &cli.Uint64Flag{
+		Name:  "from",
+		Usage: "Number of the first block to re-execute",
+		Value: 1,
+	}
+	reexecToFlag = &cli.Uint64Flag{
+		Name:  "to",
+		Usage: "Number of the last block to re-execute (default: current head)",
+	}
+	verifyRepairFlag = &cli.BoolFlag{
+		Name:  "repair",
+		Usage: "Rebuild the inconsistent entries that can be derived from other tables",
+	}
+
+	dbCommand = &cli.Command{
+		Name:        "db",
+		Usage:       "Low level database operations",
+		ArgsUsage:   "",
+		Description: ``,
+		Subcommands: []*cli.Command{
+			{
+				Name:      "verify",
+				Usage:     "Check the chain tables of the database for inconsistencies",
+				ArgsUsage: "",
+				Action:    verifyDatabase,
+				Flags: []cli.Flag{
+					DataDirFlag,
+					verifyFromFlag,
+					verifyToFlag,
+					verifyRepairFlag,
+				},
+				Description: `
+Walks the canonical blocks and checks the headers, hash to number mappings,
+parent hashes, bodies, transactions, senders, receipts, transaction lookups and
+history indices against each other. The database is opened read-only, so the
+check can run against the database of a running node or a copy of it.
+
+With --repair the hash to number mappings, transaction lookups, max transaction
+ids, senders and history indices found inconsistent are rebuilt. Repairing
+writes to the database and requires the node to be stopped.`,
+			},
+			{
+				Name:      "reexec",
+				Usage:     "Re-execute historical blocks and compare the results with the stored ones",
+				ArgsUsage: "",
+				Action:    reexecBlocks,
+				Flags: []cli.Flag{
+					DataDirFlag,
+					reexecFromFlag,
+					reexecToFlag,
+				},
+				Description: `
+Executes the canonical blocks in the given range again on top of the historical
+state of their parents, and compares the gas used, receipts and state root with
+the ones stored. The first block that diverges is reported with the accounts
+and storage slots whose post state differs. Nothing is written to the database;
+the state history of the blocks must not be pruned.`,
+			},
+		},
+	}
+)
+
+func verifyDatabase(ctx *cli.Context) error {
+	db, err := node.OpenDatabaseReadonly(&DefaultConfig, nil, kv.ChainDB.String())
+	if err != nil {
+		return err
+	}
+	var issues []*internal.DBIssue
+	err = db.View(ctx.Context, func(tx kv.Tx) error {
+		to := ctx.Uint64(verifyToFlag.Name)
+		if !ctx.IsSet(verifyToFlag.Name) {
+			head := rawdb.ReadHeaderNumber(tx, rawdb.ReadHeadBlockHash(tx))
+			if head == nil {
+				return fmt.Errorf("database has no head block")
+			}
+			to = *head
+		}
+		from := ctx.Uint64(verifyFromFlag.Name)
+		if from > to {
+			return fmt.Errorf("first block %d is after the last block %d", from, to)
+		}
+		fmt.Printf("verifying blocks %d to %d\n", from, to)
+		return internal.VerifyDatabase(ctx.Context, tx, from, to, func(issue *internal.DBIssue) {
+			fmt.Println(issue)
+			issues = append(issues, issue)
+		})
+	})
+	db.Close()
+	if err != nil {
+		return err
+	}
+	if len(issues) == 0 {
+		fmt.Println("no inconsistencies found")
+		return nil
+	}
+
+	var repairable []*internal.DBIssue
+	for _, issue := range issues {
+		if issue.Repairable() {
+			repairable = append(repairable, issue)
+		}
+	}
+	if !ctx.Bool(verifyRepairFlag.Name) || len(repairable) == 0 {
+		return fmt.Errorf("found %d inconsistencies, %d of them repairable", len(issues), len(repairable))
+	}
+
+	rwDB, err := node.OpenDatabase(&DefaultConfig, nil, kv.ChainDB.String())
+	if err != nil {
+		return err
+	}
+	defer rwDB.Close()
+	if err := rwDB.Update(ctx.Context, func(tx kv.RwTx) error {
+		for _, issue := range repairable {
+			if err := issue.Repair(tx); err != nil {
+				return fmt.Errorf("repair of %v failed: %w", issue, err)
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	fmt.Printf("repaired %d of %d inconsistencies\n", len(repairable), len(issues))
+	if len(repairable) < len(issues) {
+		return fmt.Errorf("%d inconsistencies cannot be repaired", len(issues)-len(repairable))
+	}
+	return nil
+}
+
+func reexecBlocks(ctx *cli.Context) error {
+	stack, err := node.NewNode(ctx.Context, &DefaultConfig)
+	if err != nil {
+		return err
+	}
+	defer stack.Close()
+
+	blockChain, ok := stack.BlockChain().(*internal.BlockChain)
+	if !ok {
+		return fmt.Errorf("unsupported blockchain %T", stack.BlockChain())
+	}
+	from, to := ctx.Uint64(reexecFromFlag.Name), ctx.Uint64(reexecToFlag.Name)
+	if !ctx.IsSet(reexecToFlag.Name) {
+		to = blockChain.CurrentBlock().Number64().Uint64()
+	}
+	if from > to {
+		return fmt.Errorf("first block %d is after the last block %d", from, to)
+	}
+
+	start, logged := time.Now(), time.Now()
+	divergence, err := blockChain.Reexec(ctx.Context, from, to, func(number uint64) {
+		if time.Since(logged) > 8*time.Second {
+			fmt.Printf("re-executed blocks %d to %d\n", from, number)
+			logged = time.Now()
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if divergence == nil {
+		fmt.Printf("re-executed blocks %d to %d in %v, no divergence\n", from, to, time.Since(start).Round(time.Millisecond))
+		return nil
+	}
+	fmt.Printf("block %d (%s) diverges:\n", divergence.Number, divergence.Hash)
+	for _, reason := range divergence.Reasons {
+		fmt.Printf("  %s\n", reason)
+	}
+	if len(divergence.Diffs) > 0 {
+		fmt.Println("state diff:")
+		for _, diff := range divergence.Diffs {
+			fmt.Printf("  %s\n", diff)
+		}
+	}
+	return fmt.Errorf("block %d diverges", divergence.Number)
+}
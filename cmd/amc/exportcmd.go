@@ -18,13 +18,18 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/amazechain/amc/cmd/utils"
 	"github.com/amazechain/amc/common/account"
 	common "github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/conf"
 	"github.com/amazechain/amc/internal/node"
 	"github.com/amazechain/amc/log"
 	"github.com/amazechain/amc/modules"
+	"github.com/amazechain/amc/modules/rawdb"
+	"github.com/amazechain/amc/modules/state"
 	"github.com/amazechain/amc/params"
 	"github.com/amazechain/amc/turbo/backup"
 	"github.com/holiman/uint256"
@@ -36,12 +41,61 @@ import (
 )
 
 var (
+	exportFromFlag = &cli.Uint64Flag{
+		Name:  "from",
+		Usage: "Number of the first block to export",
+	}
+	exportToFlag = &cli.Uint64Flag{
+		Name:  "to",
+		Usage: "Number of the last block to export (default: current head)",
+	}
+	exportBlockFlag = &cli.Uint64Flag{
+		Name:  "block",
+		Usage: "Number of the block to dump the state of (default: current head)",
+	}
+	chainFormatFlag = &cli.StringFlag{
+		Name:  "format",
+		Usage: "Block encoding of the chain file (rlp or pb)",
+		Value: utils.FormatRLP,
+	}
+
 	exportCommand = &cli.Command{
 		Name:        "export",
 		Usage:       "Export AmazeChain data",
 		ArgsUsage:   "",
 		Description: ``,
 		Subcommands: []*cli.Command{
+			{
+				Name:      "chain",
+				Usage:     "Export canonical blocks into a gzip compressed file",
+				ArgsUsage: "<filename>",
+				Action:    exportChain,
+				Flags: []cli.Flag{
+					DataDirFlag,
+					exportFromFlag,
+					exportToFlag,
+					chainFormatFlag,
+				},
+				Description: `
+Exports the canonical blocks in the given range, including the senders of their
+transactions and their verifiers and rewards. The file can be loaded into
+another node with the import command.`,
+			},
+			{
+				Name:      "state",
+				Usage:     "Dump the world state of a block into a genesis file",
+				ArgsUsage: "<filename>",
+				Action:    exportState,
+				Flags: []cli.Flag{
+					DataDirFlag,
+					exportBlockFlag,
+				},
+				Description: `
+Writes the balances, nonces, code and storage of every account after the given
+block as the allocations of a genesis file with the chain and engine settings of
+this node. Passing the file to "amc init --genesis" starts a network that forks
+the state of this chain.`,
+			},
 			{
 				Name:      "txs",
 				Usage:     "Export All AmazeChain Transactions",
@@ -87,6 +141,85 @@ var (
 	}
 )
 
+func exportChain(ctx *cli.Context) error {
+	if ctx.Args().Len() != 1 {
+		return fmt.Errorf("this command requires an argument")
+	}
+	stack, err := node.NewNode(ctx.Context, &DefaultConfig)
+	if err != nil {
+		return err
+	}
+	defer stack.Close()
+
+	blockChain := stack.BlockChain()
+	last := blockChain.CurrentBlock().Number64().Uint64()
+	if ctx.IsSet(exportToFlag.Name) {
+		if to := ctx.Uint64(exportToFlag.Name); to < last {
+			last = to
+		}
+	}
+	return utils.ExportChain(ctx.Context, blockChain, ctx.Args().First(), ctx.String(chainFormatFlag.Name), ctx.Uint64(exportFromFlag.Name), last)
+}
+
+func exportState(ctx *cli.Context) error {
+	if ctx.Args().Len() != 1 {
+		return fmt.Errorf("this command requires an argument")
+	}
+	stack, err := node.NewNode(ctx.Context, &DefaultConfig)
+	if err != nil {
+		return err
+	}
+	defer stack.Close()
+
+	roTX, err := stack.Database().BeginRo(ctx.Context)
+	if err != nil {
+		return err
+	}
+	defer roTX.Rollback()
+
+	header := rawdb.ReadCurrentHeader(roTX)
+	if header == nil {
+		return fmt.Errorf("cannot get current block")
+	}
+	if ctx.IsSet(exportBlockFlag.Name) {
+		number := ctx.Uint64(exportBlockFlag.Name)
+		if header = rawdb.ReadHeaderByNumber(roTX, number); header == nil {
+			return fmt.Errorf("block %d not found", number)
+		}
+	}
+	number := header.Number64().Uint64()
+	// the state of a block is rebuilt from the history of the following blocks
+	if err := rawdb.CheckPruned(roTX, rawdb.PruneHistory, number+1); err != nil {
+		return err
+	}
+
+	genesis := *DefaultConfig.GenesisBlockCfg
+	genesis.Timestamp = header.Time
+	genesis.Alloc = nil
+	if err := state.DumpState(roTX, number, func(acc *state.DumpAccount) error {
+		genesis.Alloc = append(genesis.Alloc, conf.Allocate{
+			Address: "AMC" + acc.Address.Hex()[2:],
+			Balance: acc.Balance.Dec(),
+			Code:    acc.Code,
+			Storage: acc.Storage,
+			Nonce:   acc.Nonce,
+		})
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(&genesis, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(ctx.Args().First(), data, 0644); err != nil {
+		return err
+	}
+	log.Info("Exported state", "block", number, "hash", header.Hash(), "accounts", len(genesis.Alloc), "file", ctx.Args().First())
+	return nil
+}
+
 func exportTransactions(ctx *cli.Context) error {
 
 	stack, err := node.NewNode(ctx.Context, &DefaultConfig)
@@ -0,0 +1,55 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/amazechain/amc/cmd/utils"
+	"github.com/amazechain/amc/internal/node"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	importCommand = &cli.Command{
+		Name:      "import",
+		Usage:     "Import blocks from a chain export file",
+		ArgsUsage: "<filename>",
+		Action:    importChain,
+		Flags: []cli.Flag{
+			DataDirFlag,
+			chainFormatFlag,
+		},
+		Description: `
+The import command inserts the blocks of a file written by "export chain" with
+full validation, so a new node can be seeded from a file instead of syncing from
+its peers. Blocks the node already has are skipped.`,
+	}
+)
+
+func importChain(ctx *cli.Context) error {
+	if ctx.Args().Len() != 1 {
+		return fmt.Errorf("this command requires an argument")
+	}
+	stack, err := node.NewNode(ctx.Context, &DefaultConfig)
+	if err != nil {
+		return err
+	}
+	defer stack.Close()
+
+	return utils.ImportChain(ctx.Context, stack.BlockChain(), ctx.Args().First(), ctx.String(chainFormatFlag.Name))
+}
@@ -0,0 +1,71 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/amazechain/amc/conf"
+	"github.com/amazechain/amc/internal/node"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	genesisFlag = &cli.StringFlag{
+		Name:     "genesis",
+		Usage:    "Genesis file of the network, e.g. a state dump of another chain",
+		Required: true,
+	}
+
+	initCommand = &cli.Command{
+		Name:      "init",
+		Usage:     "Initialise a new database with a custom genesis block",
+		ArgsUsage: "",
+		Action:    initGenesis,
+		Flags: []cli.Flag{
+			DataDirFlag,
+			genesisFlag,
+		},
+		Description: `
+The init command writes the genesis block of the given genesis file into an
+empty data directory. The node keeps using this genesis, with its chain and
+engine settings, on later starts. Files written by "amc export state" start a
+network that forks the state of another chain.`,
+	}
+)
+
+func initGenesis(ctx *cli.Context) error {
+	data, err := os.ReadFile(ctx.String(genesisFlag.Name))
+	if err != nil {
+		return err
+	}
+	genesis := new(conf.GenesisBlockConfig)
+	if err := json.Unmarshal(data, genesis); err != nil {
+		return fmt.Errorf("invalid genesis file: %w", err)
+	}
+	if genesis.Config == nil || genesis.Engine == nil {
+		return fmt.Errorf("genesis file lacks the chain config or engine settings")
+	}
+	block, err := node.InitGenesis(ctx.Context, &DefaultConfig, genesis)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("wrote genesis block %s with %d accounts\n", block.Hash(), len(genesis.Alloc))
+	return nil
+}
@@ -38,8 +38,10 @@ func main() {
 	flags = append(flags, settingFlag...)
 	flags = append(flags, accountFlag...)
 	flags = append(flags, metricsFlags...)
+	flags = append(flags, txpoolFlags...)
+	flags = append(flags, pruneFlags...)
 
-	rootCmd = append(rootCmd, walletCommand, accountCommand, exportCommand)
+	rootCmd = append(rootCmd, walletCommand, accountCommand, initCommand, exportCommand, importCommand, rewindCommand, dbCommand, abigenCommand)
 	commands := rootCmd
 
 	app := &cli.App{
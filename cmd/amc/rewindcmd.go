@@ -0,0 +1,76 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/amazechain/amc/common/block"
+	"github.com/amazechain/amc/internal"
+	"github.com/amazechain/amc/internal/node"
+	"github.com/amazechain/amc/modules/rawdb"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	rewindToFlag = &cli.Uint64Flag{
+		Name:     "to",
+		Usage:    "Number of the block to make the new head",
+		Required: true,
+	}
+
+	rewindCommand = &cli.Command{
+		Name:      "rewind",
+		Usage:     "Rewind the chain and its state to an earlier block",
+		ArgsUsage: "",
+		Action:    rewindChain,
+		Flags: []cli.Flag{
+			DataDirFlag,
+			rewindToFlag,
+		},
+		Description: `
+The rewind command unwinds the state of an offline node to the given block using
+the state history, and drops the later blocks with their receipts, logs and
+indices. The node syncs the dropped blocks again on the next start.`,
+	}
+)
+
+func rewindChain(ctx *cli.Context) error {
+	db, engine, err := node.OpenChainDatabase(ctx.Context, &DefaultConfig)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	target := ctx.Uint64(rewindToFlag.Name)
+	var (
+		current uint64
+		head    *block.Block
+	)
+	if err := db.Update(ctx.Context, func(tx kv.RwTx) error {
+		if n := rawdb.ReadHeaderNumber(tx, rawdb.ReadHeadBlockHash(tx)); n != nil {
+			current = *n
+		}
+		head, err = internal.Rewind(ctx.Context, tx, DefaultConfig.GenesisBlockCfg.Config, engine, target)
+		return err
+	}); err != nil {
+		return fmt.Errorf("rewind from %d to %d failed: %w", current, target, err)
+	}
+	fmt.Printf("rewound from %d to %d (%s)\n", current, head.Number64().Uint64(), head.Hash())
+	return nil
+}
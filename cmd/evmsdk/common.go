@@ -27,7 +27,6 @@ import (
 	"github.com/amazechain/amc/common/crypto"
 	"github.com/amazechain/amc/common/crypto/ecies"
 	"github.com/holiman/uint256"
-	"golang.org/x/crypto/sha3"
 	"io"
 	"io/ioutil"
 	"net/http"
@@ -41,8 +40,10 @@ import (
 	"time"
 
 	commTyp "github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/internal/verifier"
 	"github.com/amazechain/amc/log"
 	"github.com/amazechain/amc/modules/state"
+	"github.com/amazechain/amc/params"
 	"github.com/go-kit/kit/transport/http/jsonrpc"
 	"github.com/gorilla/websocket"
 
@@ -422,7 +423,7 @@ func (e *EvmEngine) verificationTaskBg() error {
 		return err
 	}
 	simpleLog("init websocket")
-	wssvr, err := NewWebSocketService(e.ServerUri, e.Account)
+	wssvr, err := NewWebSocketService(e.ServerUri, e.Account, e.PrivKey)
 	if err != nil {
 		return err
 	}
@@ -620,22 +621,12 @@ func (e *EvmEngine) vertify(in []byte) ([]byte, error) {
 		return nil, err
 	}
 
-	if bean.Entire.Header == nil {
-		return nil, errors.New("nil pointer found")
-	}
-	// before state verify
-	var hash commTyp.Hash
-	hasher := sha3.NewLegacyKeccak256()
-	state.EncodeBeforeState(hasher, bean.Entire.Snap.Items, bean.Codes)
-	hasher.(crypto.KeccakState).Read(hash[:])
-	if bean.Entire.Header.MixDigest != hash {
-		simpleLog("misMatch state hash", "want", bean.Entire.Header.MixDigest, "get", hash, "block", bean.Entire.Header.Number.Uint64())
-		return nil, errors.New("state verify failed")
+	stateRoot, err := verifier.Verify(e.ctx, params.AmazeChainConfig, &bean)
+	if err != nil {
+		simpleLog("verify block error,err=", err)
+		return nil, err
 	}
 
-	entirecode := state.EntireCode(bean)
-	stateRoot := verify(e.ctx, &entirecode)
-
 	res := AggSign{}
 	//stateroot
 	copy(res.StateRoot[:], stateRoot[:])
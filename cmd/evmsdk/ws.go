@@ -17,21 +17,26 @@
 package evmsdk
 
 import (
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"runtime"
 
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/internal/api"
 	"github.com/gorilla/websocket"
 )
 
 type WebSocketService struct {
 	addr      string
 	acc       string
+	token     string
 	readConn  *websocket.Conn
 	writeConn *websocket.Conn
 }
 
-func NewWebSocketService(addr, acc string) (*WebSocketService, error) {
+func NewWebSocketService(addr, acc, privKey string) (*WebSocketService, error) {
 	simpleLog("init ws read conn")
 	readConn, readResp, err := websocket.DefaultDialer.Dial(addr, nil)
 	if err != nil {
@@ -64,14 +69,72 @@ func NewWebSocketService(addr, acc string) (*WebSocketService, error) {
 	writeConn.SetPongHandler(func(appData string) error { simpleLog("write pong", appData); return nil })
 	writeConn.SetPingHandler(func(appData string) error { simpleLog("write ping", appData); return nil })
 	simpleLog("init dial ws done")
+	token, err := login(readConn, acc, privKey)
+	if err != nil {
+		simpleLog("login error,err=%+v", err)
+		return nil, err
+	}
 	return &WebSocketService{
 		addr:      addr,
 		readConn:  readConn,
 		writeConn: writeConn,
 		acc:       acc,
+		token:     token,
 	}, nil
 }
 
+// call sends a JSON-RPC request over conn and waits for its result.
+func call(conn *websocket.Conn, method string, params ...interface{}) (json.RawMessage, error) {
+	req := &JSONRPCRequest{JsonRpc: "2.0", Method: method, ID: 1}
+	for _, p := range params {
+		raw, err := json.Marshal(p)
+		if err != nil {
+			return nil, err
+		}
+		req.Params = append(req.Params, raw)
+	}
+	if err := conn.WriteJSON(req); err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := conn.ReadJSON(&resp); err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("%s: %s", method, resp.Error.Message)
+	}
+	return resp.Result, nil
+}
+
+// login answers the node's challenge for the account with its BLS key and
+// returns the session token.
+func login(conn *websocket.Conn, acc, privKey string) (string, error) {
+	addr := types.HexToAddress(acc)
+	res, err := call(conn, "eth_verifierChallenge", addr)
+	if err != nil {
+		return "", err
+	}
+	var nonce types.Hash
+	if err := json.Unmarshal(res, &nonce); err != nil {
+		return "", err
+	}
+	hash := api.ChallengeHash(addr, nonce)
+	sig, err := BlsSign(privKey, hex.EncodeToString(hash[:]))
+	if err != nil {
+		return "", err
+	}
+	if res, err = call(conn, "eth_verifierLogin", addr, nonce, "0x"+sig.(string)); err != nil {
+		return "", err
+	}
+	var token string
+	return token, json.Unmarshal(res, &token)
+}
+
 func (ws *WebSocketService) Chans(pubk string) (<-chan []byte, chan<- []byte, error) {
 	simpleLog("ping read conn")
 	err := ws.readConn.PingHandler()("")
@@ -85,7 +148,7 @@ func (ws *WebSocketService) Chans(pubk string) (<-chan []byte, chan<- []byte, er
 	"method": "eth_subscribe",
 	"params": [
 	"minedBlock",
-	"` + ws.acc + `"
+	"` + ws.token + `"
 	],
 	"id": 1
 }`
@@ -191,9 +254,10 @@ func (ws *WebSocketService) unwrapJSONRPCRequest(in []byte) ([]byte, error) {
 		JsonRpc: "2.0",
 		Method:  "eth_submitSign",
 		ID:      1,
-		Params:  make([]json.RawMessage, 1),
+		Params:  make([]json.RawMessage, 2),
 	}
 	d.Params[0] = in
+	d.Params[1], _ = json.Marshal(ws.token)
 	return json.Marshal(d)
 
 }
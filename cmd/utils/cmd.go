@@ -0,0 +1,233 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/amazechain/amc/common"
+	"github.com/amazechain/amc/common/block"
+	"github.com/amazechain/amc/internal/avm/rlp"
+	"github.com/amazechain/amc/log"
+	"github.com/holiman/uint256"
+)
+
+// Chain export formats.
+const (
+	// FormatRLP stores each block as an RLP list.
+	FormatRLP = "rlp"
+	// FormatPB stores each block as a uvarint length prefixed protobuf message.
+	FormatPB = "pb"
+)
+
+const (
+	importBatchSize = 2500
+	maxPBBlockSize  = 64 * 1024 * 1024
+	progressEvery   = 8 * time.Second
+)
+
+// ExportChain streams the canonical blocks first to last into a gzip compressed
+// file in the given format. The blocks keep the senders of their transactions
+// and their verifiers and rewards.
+func ExportChain(ctx context.Context, bc common.IBlockChain, fn, format string, first, last uint64) error {
+	if format != FormatRLP && format != FormatPB {
+		return fmt.Errorf("unknown export format %q", format)
+	}
+	if first > last {
+		return fmt.Errorf("export range %d-%d is empty", first, last)
+	}
+	log.Info("Exporting blockchain", "file", fn, "format", format, "first", first, "last", last)
+
+	fh, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+	w := gzip.NewWriter(fh)
+
+	var (
+		start  = time.Now()
+		report = time.Now()
+	)
+	for nr := first; nr <= last; nr++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		b, err := bc.GetBlockByNumber(uint256.NewInt(nr))
+		if err != nil {
+			return err
+		}
+		if b == nil {
+			return fmt.Errorf("export failed on #%d: not found", nr)
+		}
+		if err := writeBlock(w, b.(*block.Block), format); err != nil {
+			return fmt.Errorf("export failed on #%d: %w", nr, err)
+		}
+		if time.Since(report) > progressEvery {
+			log.Info("Exporting blocks", "exported", nr-first+1, "total", last-first+1, "elapsed", time.Since(start))
+			report = time.Now()
+		}
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	log.Info("Exported blockchain", "file", fn, "blocks", last-first+1, "elapsed", time.Since(start))
+	return nil
+}
+
+func writeBlock(w io.Writer, b *block.Block, format string) error {
+	if format == FormatRLP {
+		return rlp.Encode(w, b)
+	}
+	data, err := b.Marshal()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(binary.AppendUvarint(nil, uint64(len(data)))); err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// blockReader decodes the blocks of an export file one at a time.
+type blockReader struct {
+	r      *bufio.Reader
+	stream *rlp.Stream
+	format string
+}
+
+func (br *blockReader) next() (*block.Block, error) {
+	b := new(block.Block)
+	if br.format == FormatRLP {
+		if err := br.stream.Decode(b); err != nil {
+			return nil, err
+		}
+		return b, nil
+	}
+	size, err := binary.ReadUvarint(br.r)
+	if err != nil {
+		return nil, err
+	}
+	if size > maxPBBlockSize {
+		return nil, fmt.Errorf("block of %d bytes exceeds the limit", size)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(br.r, data); err != nil {
+		return nil, err
+	}
+	if err := b.Unmarshal(data); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// ImportChain feeds the blocks of an export file through the full block
+// validation of InsertChain. The file may be gzip compressed. Blocks the chain
+// already has are skipped, so an interrupted import can be restarted.
+func ImportChain(ctx context.Context, bc common.IBlockChain, fn, format string) error {
+	if format != FormatRLP && format != FormatPB {
+		return fmt.Errorf("unknown import format %q", format)
+	}
+	log.Info("Importing blockchain", "file", fn, "format", format)
+
+	fh, err := os.Open(fn)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	var r io.Reader = bufio.NewReader(fh)
+	if magic, err := r.(*bufio.Reader).Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		if r, err = gzip.NewReader(r); err != nil {
+			return err
+		}
+	}
+	br := &blockReader{r: bufio.NewReader(r), format: format}
+	br.stream = rlp.NewStream(br.r, 0)
+
+	var (
+		start    = time.Now()
+		imported int
+		txs      int
+		batch    = make([]block.IBlock, 0, importBatchSize)
+	)
+	for done := false; !done; {
+		batch = batch[:0]
+		for len(batch) < importBatchSize {
+			b, err := br.next()
+			if errors.Is(err, io.EOF) {
+				done = true
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("block %d: failed to parse: %w", imported+len(batch), err)
+			}
+			// The genesis block is not inserted, but it has to match ours
+			if b.Number64().IsZero() {
+				if b.Hash() != bc.GenesisBlock().Hash() {
+					return fmt.Errorf("genesis mismatch: file has %v, chain has %v", b.Hash(), bc.GenesisBlock().Hash())
+				}
+				continue
+			}
+			batch = append(batch, b)
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		missing := missingBlocks(bc, batch)
+		if len(missing) == 0 {
+			if len(batch) > 0 {
+				log.Info("Skipping batch as all blocks present", "first", batch[0].Number64(), "last", batch[len(batch)-1].Number64())
+			}
+			continue
+		}
+		if n, err := bc.InsertChain(missing); err != nil {
+			if n < len(missing) {
+				return fmt.Errorf("invalid block %d: %w", missing[n].Number64().Uint64(), err)
+			}
+			return err
+		}
+		head := missing[len(missing)-1]
+		for _, b := range missing {
+			txs += len(b.Transactions())
+		}
+		imported += len(missing)
+		log.Info("Imported blocks", "number", head.Number64().Uint64(), "hash", head.Hash(), "blocks", imported, "txs", txs, "elapsed", time.Since(start))
+	}
+	log.Info("Imported blockchain", "file", fn, "blocks", imported, "txs", txs, "head", bc.CurrentBlock().Number64().Uint64(), "elapsed", time.Since(start))
+	return nil
+}
+
+// missingBlocks returns the blocks of the batch from the first one the chain
+// does not have yet.
+func missingBlocks(bc common.IBlockChain, batch []block.IBlock) []block.IBlock {
+	for i, b := range batch {
+		if !bc.HasBlock(b.Hash(), b.Number64().Uint64()) {
+			return batch[i:]
+		}
+	}
+	return nil
+}
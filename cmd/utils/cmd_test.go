@@ -0,0 +1,67 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/amazechain/amc/common/block"
+	"github.com/amazechain/amc/common/transaction"
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/internal/avm/rlp"
+	"github.com/holiman/uint256"
+)
+
+// Tests that blocks written in either export format read back unchanged.
+func TestExportFormats(t *testing.T) {
+	var blocks []*block.Block
+	parent := types.Hash{}
+	for i := uint64(1); i <= 3; i++ {
+		from, to := types.Address{byte(i)}, types.Address{0xff}
+		header := &block.Header{ParentHash: parent, Number: uint256.NewInt(i), Difficulty: uint256.NewInt(2), BaseFee: uint256.NewInt(0)}
+		txs := []*transaction.Transaction{transaction.NewTransaction(i, from, &to, uint256.NewInt(i), 21000, uint256.NewInt(1), nil)}
+		b := block.NewBlockFromStorage(header.Hash(), header, &block.Body{Txs: txs, Rewards: []*block.Reward{{Address: from, Amount: uint256.NewInt(i)}}})
+		blocks = append(blocks, b)
+		parent = b.Hash()
+	}
+	for _, format := range []string{FormatRLP, FormatPB} {
+		var buf bytes.Buffer
+		for _, b := range blocks {
+			if err := writeBlock(&buf, b, format); err != nil {
+				t.Fatalf("%s: write: %v", format, err)
+			}
+		}
+		br := &blockReader{r: bufio.NewReader(&buf), format: format}
+		br.stream = rlp.NewStream(br.r, 0)
+		for i, want := range blocks {
+			have, err := br.next()
+			if err != nil {
+				t.Fatalf("%s: block %d: %v", format, i, err)
+			}
+			if have.Hash() != want.Hash() || *have.Transactions()[0].From() != *want.Transactions()[0].From() {
+				t.Fatalf("%s: block %d mismatch", format, i)
+			}
+		}
+		if _, err := br.next(); !errors.Is(err, io.EOF) {
+			t.Fatalf("%s: have %v after the last block, want EOF", format, err)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/amazechain/amc/conf"
+	"github.com/amazechain/amc/params"
+	"gopkg.in/yaml.v2"
+)
+
+// Config is the configuration file of amc-verifier.
+type Config struct {
+	// Nodes are the websocket RPC endpoints of the mining nodes to verify for.
+	Nodes []string `json:"nodes" yaml:"nodes"`
+	// DataDir keeps the record of signed blocks and the logs.
+	DataDir string `json:"data_dir" yaml:"data_dir"`
+
+	// KeyStore is the keystore directory holding the key of Account, the deposit
+	// address. Its BLS key is derived from the account key.
+	KeyStore     string `json:"keystore" yaml:"keystore"`
+	Account      string `json:"account" yaml:"account"`
+	PasswordFile string `json:"password_file" yaml:"password_file"`
+
+	// Reconnection delays to a node, doubled after each failed attempt.
+	MinBackoff time.Duration `json:"min_backoff" yaml:"min_backoff"`
+	MaxBackoff time.Duration `json:"max_backoff" yaml:"max_backoff"`
+
+	// Genesis is the genesis file of the chain as given to amc init, the chain
+	// configuration of the verified blocks is read from it. Empty for the
+	// AmazeChain main network.
+	Genesis string `json:"genesis" yaml:"genesis"`
+
+	// Metrics is the listen address of the /metrics endpoint, empty to disable it.
+	Metrics string `json:"metrics" yaml:"metrics"`
+
+	Logger conf.LoggerConfig `json:"logger" yaml:"logger"`
+}
+
+var defaultConfig = Config{
+	DataDir:    "./verifier",
+	MinBackoff: time.Second,
+	MaxBackoff: time.Minute,
+	Logger: conf.LoggerConfig{
+		LogFile:    "verifier.log",
+		Level:      "info",
+		MaxSize:    10,
+		MaxBackups: 10,
+		MaxAge:     30,
+	},
+}
+
+// loadConfig reads the configuration file over the defaults.
+func loadConfig(file string) (*Config, error) {
+	cfg := defaultConfig
+	fd, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+	if err := yaml.NewDecoder(bufio.NewReader(fd)).Decode(&cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, cfg.validate()
+}
+
+// chainConfig returns the chain configuration of the genesis file.
+func (c *Config) chainConfig() (*params.ChainConfig, error) {
+	if c.Genesis == "" {
+		return params.AmazeChainConfig, nil
+	}
+	data, err := os.ReadFile(c.Genesis)
+	if err != nil {
+		return nil, err
+	}
+	genesis := new(conf.GenesisBlockConfig)
+	if err := json.Unmarshal(data, genesis); err != nil {
+		return nil, fmt.Errorf("invalid genesis file %s: %w", c.Genesis, err)
+	}
+	if genesis.Config == nil {
+		return nil, fmt.Errorf("genesis file %s has no chain config", c.Genesis)
+	}
+	return genesis.Config, nil
+}
+
+func (c *Config) validate() error {
+	switch {
+	case len(c.Nodes) == 0:
+		return errors.New("no nodes configured")
+	case c.KeyStore == "" || c.Account == "":
+		return errors.New("keystore and account are required")
+	case c.MinBackoff <= 0 || c.MaxBackoff < c.MinBackoff:
+		return errors.New("invalid reconnection backoff")
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+// amc-verifier re-executes the blocks mined by AmazeChain nodes and signs their
+// state roots with the key of a deposit address.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/amazechain/amc/conf"
+	"github.com/amazechain/amc/internal/metrics/prometheus"
+	"github.com/amazechain/amc/log"
+	"github.com/amazechain/amc/params"
+	"github.com/rcrowley/go-metrics"
+	"github.com/urfave/cli/v2"
+)
+
+var configFlag = &cli.StringFlag{
+	Name:     "config",
+	Usage:    "Verifier configuration file",
+	Required: true,
+}
+
+func main() {
+	app := &cli.App{
+		Name:    "amc-verifier",
+		Usage:   "AmazeChain block verifier",
+		Flags:   []cli.Flag{configFlag},
+		Version: params.VersionWithCommit(params.GitCommit, ""),
+		Action:  run,
+	}
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx *cli.Context) error {
+	cfg, err := loadConfig(ctx.String(configFlag.Name))
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(cfg.DataDir, 0700); err != nil {
+		return err
+	}
+	log.Init(conf.NodeConfig{DataDir: cfg.DataDir}, cfg.Logger)
+
+	var password string
+	if cfg.PasswordFile != "" {
+		data, err := os.ReadFile(cfg.PasswordFile)
+		if err != nil {
+			return err
+		}
+		password = strings.TrimRight(strings.SplitN(string(data), "\n", 2)[0], "\r")
+	}
+	signed, err := openSignedRecord(filepath.Join(cfg.DataDir, "signed"))
+	if err != nil {
+		return err
+	}
+	v, err := NewVerifier(cfg, password, signed)
+	if err != nil {
+		return err
+	}
+	if cfg.Metrics != "" {
+		prometheus.Setup(cfg.Metrics, metrics.DefaultRegistry)
+	}
+
+	c, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	log.Info("Starting verifier", "account", v.account.Address, "nodes", len(cfg.Nodes))
+	v.Run(c)
+	log.Info("Verifier stopped")
+	return nil
+}
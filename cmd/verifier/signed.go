@@ -0,0 +1,153 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/amazechain/amc/common/types"
+)
+
+// signedWindow is the number of heights below the highest one signed whose
+// signed blocks are remembered.
+const signedWindow = 1024
+
+const signedEntryLength = 8 + types.HashLength // block_num_u64 + state root
+
+// errConflictingRoot is returned when another state root is to be signed at a
+// block number already signed.
+var errConflictingRoot = errors.New("conflicting state root")
+
+// signedRecord persists the state roots signed per block height, so that a
+// restarted verifier or one connected to several nodes knows which block of a
+// height it signed.
+type signedRecord struct {
+	mu     sync.Mutex
+	path   string
+	signed map[uint64]types.Hash
+	last   uint64
+}
+
+func openSignedRecord(path string) (*signedRecord, error) {
+	r := &signedRecord{path: path, signed: make(map[uint64]types.Hash)}
+	data, err := os.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+		return r, nil
+	case err != nil:
+		return nil, err
+	case len(data)%signedEntryLength != 0:
+		return nil, fmt.Errorf("corrupted signed record %s", path)
+	}
+	for ; len(data) > 0; data = data[signedEntryLength:] {
+		number := binary.BigEndian.Uint64(data)
+		r.signed[number] = types.BytesToHash(data[8:signedEntryLength])
+		if number > r.last {
+			r.last = number
+		}
+	}
+	return r, nil
+}
+
+// Last returns the highest block number signed.
+func (r *signedRecord) Last() (uint64, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.last, len(r.signed) > 0
+}
+
+// Signed returns the state root signed at the block number, if any.
+func (r *signedRecord) Signed(number uint64) (types.Hash, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	root, ok := r.signed[number]
+	return root, ok
+}
+
+// Expired reports whether the block number is too far below the highest one
+// signed to tell which block of it was signed.
+func (r *signedRecord) Expired(number uint64) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.signed) > 0 && number+signedWindow <= r.last
+}
+
+// Mark records the state root as signed at the block number. Another root signed
+// there before is never replaced, errConflictingRoot is returned instead. The
+// record is synced to disk before Mark returns.
+func (r *signedRecord) Mark(number uint64, root types.Hash) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if signed, ok := r.signed[number]; ok {
+		if signed != root {
+			return fmt.Errorf("%w: block %d signed with root %s", errConflictingRoot, number, signed)
+		}
+		return nil
+	}
+	last := r.last
+	if number > last {
+		last = number
+	}
+	data := make([]byte, 0, (len(r.signed)+1)*signedEntryLength)
+	entry := func(number uint64, root types.Hash) {
+		data = binary.BigEndian.AppendUint64(data, number)
+		data = append(data, root[:]...)
+	}
+	for n, signed := range r.signed {
+		if n+signedWindow > last {
+			entry(n, signed)
+		}
+	}
+	entry(number, root)
+	if err := writeFileSync(r.path, data); err != nil {
+		return err
+	}
+	for n := range r.signed {
+		if n+signedWindow <= last {
+			delete(r.signed, n)
+		}
+	}
+	r.signed[number], r.last = root, last
+	return nil
+}
+
+// writeFileSync atomically replaces the file with data.
+func writeFileSync(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
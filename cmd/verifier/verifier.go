@@ -0,0 +1,233 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/amazechain/amc/accounts"
+	"github.com/amazechain/amc/accounts/keystore"
+	"github.com/amazechain/amc/common/crypto/bls"
+	"github.com/amazechain/amc/common/hexutil"
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/internal/api"
+	"github.com/amazechain/amc/internal/verifier"
+	"github.com/amazechain/amc/log"
+	"github.com/amazechain/amc/modules/rpc/jsonrpc"
+	"github.com/amazechain/amc/modules/state"
+	"github.com/amazechain/amc/params"
+	"github.com/rcrowley/go-metrics"
+)
+
+var (
+	connectedCounter = metrics.GetOrRegisterCounter("verifier/nodes/connected", nil)
+	reconnectCounter = metrics.GetOrRegisterCounter("verifier/nodes/reconnects", nil)
+	receivedCounter  = metrics.GetOrRegisterCounter("verifier/blocks/received", nil)
+	signedCounter    = metrics.GetOrRegisterCounter("verifier/blocks/signed", nil)
+	skippedCounter   = metrics.GetOrRegisterCounter("verifier/blocks/skipped", nil)
+	failedCounter    = metrics.GetOrRegisterCounter("verifier/blocks/failed", nil)
+	lastSignedGauge  = metrics.GetOrRegisterGauge("verifier/blocks/last", nil)
+	verifyTimer      = metrics.GetOrRegisterTimer("verifier/verify", nil)
+)
+
+// Verifier re-executes the blocks mined by the configured nodes and signs their
+// state roots with the deposited key.
+type Verifier struct {
+	cfg         *Config
+	chainConfig *params.ChainConfig
+
+	ks      *keystore.KeyStore
+	account accounts.Account
+	key     bls.SecretKey
+	signed  *signedRecord
+}
+
+// NewVerifier unlocks the deposit account in the keystore and loads the chain
+// configuration of the verified blocks.
+func NewVerifier(cfg *Config, password string, signed *signedRecord) (*Verifier, error) {
+	if !types.IsHexAddress(cfg.Account) {
+		return nil, fmt.Errorf("invalid account %q", cfg.Account)
+	}
+	ks := keystore.NewKeyStore(cfg.KeyStore, keystore.StandardScryptN, keystore.StandardScryptP)
+	account, err := ks.Find(accounts.Account{Address: types.HexToAddress(cfg.Account)})
+	if err != nil {
+		return nil, err
+	}
+	if err := ks.Unlock(account, password); err != nil {
+		return nil, err
+	}
+	key, err := ks.BLSSecretKey(account)
+	if err != nil {
+		return nil, err
+	}
+	if last, ok := signed.Last(); ok {
+		lastSignedGauge.Update(int64(last))
+	}
+	chainConfig, err := cfg.chainConfig()
+	if err != nil {
+		return nil, err
+	}
+	return &Verifier{
+		cfg:         cfg,
+		chainConfig: chainConfig,
+		ks:          ks,
+		account:     account,
+		key:         key,
+		signed:      signed,
+	}, nil
+}
+
+// Run verifies for all the nodes until ctx is done.
+func (v *Verifier) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, node := range v.cfg.Nodes {
+		wg.Add(1)
+		go func(node string) {
+			defer wg.Done()
+			v.loop(ctx, node)
+		}(node)
+	}
+	wg.Wait()
+}
+
+// loop keeps a session with the node, reconnecting with exponential backoff.
+func (v *Verifier) loop(ctx context.Context, node string) {
+	backoff := v.cfg.MinBackoff
+	for {
+		err := v.session(ctx, node, func() { backoff = v.cfg.MinBackoff })
+		if ctx.Err() != nil {
+			return
+		}
+		log.Warn("Verifier disconnected", "node", node, "err", err, "retry", backoff)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		reconnectCounter.Inc(1)
+		if backoff *= 2; backoff > v.cfg.MaxBackoff {
+			backoff = v.cfg.MaxBackoff
+		}
+	}
+}
+
+// session logs in to the node, subscribes to its mined blocks and signs them
+// until the connection fails.
+func (v *Verifier) session(ctx context.Context, node string, connected func()) error {
+	client, err := jsonrpc.DialWebsocket(ctx, node, "")
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	token, err := v.login(ctx, client)
+	if err != nil {
+		return err
+	}
+	entires := make(chan *state.EntireCode, 16)
+	sub, err := client.Subscribe(ctx, "eth", entires, "minedBlock", token)
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+
+	connected()
+	connectedCounter.Inc(1)
+	defer connectedCounter.Dec(1)
+	log.Info("Verifier connected", "node", node, "account", v.account.Address)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-sub.Err():
+			return err
+		case entire := <-entires:
+			if err := v.sign(ctx, client, token, entire); err != nil {
+				failedCounter.Inc(1)
+				log.Warn("Failed to sign mined block", "node", node, "err", err)
+			}
+		}
+	}
+}
+
+// login answers the node's challenge with the account key and returns the
+// session token.
+func (v *Verifier) login(ctx context.Context, client *jsonrpc.Client) (string, error) {
+	var nonce types.Hash
+	if err := client.CallContext(ctx, &nonce, "eth_verifierChallenge", v.account.Address); err != nil {
+		return "", err
+	}
+	hash := api.ChallengeHash(v.account.Address, nonce)
+	sig, err := v.ks.SignHash(v.account, hash[:])
+	if err != nil {
+		return "", err
+	}
+	var token string
+	err = client.CallContext(ctx, &token, "eth_verifierLogin", v.account.Address, nonce, hexutil.Bytes(sig))
+	return token, err
+}
+
+// sign verifies the block and submits the signature of its state root. A block
+// is signed again for every node pushing it, but no other block is ever signed at
+// a height already signed.
+func (v *Verifier) sign(ctx context.Context, client *jsonrpc.Client, token string, entire *state.EntireCode) error {
+	if entire.Entire.Header == nil {
+		return fmt.Errorf("missing block header")
+	}
+	number := entire.Entire.Header.Number.Uint64()
+	receivedCounter.Inc(1)
+	if v.signed.Expired(number) {
+		skippedCounter.Inc(1)
+		log.Debug("Skipping old block number", "number", number)
+		return nil
+	}
+
+	start := time.Now()
+	root, err := verifier.Verify(ctx, v.chainConfig, entire)
+	verifyTimer.UpdateSince(start)
+	if err != nil {
+		return err
+	}
+
+	if err := v.signed.Mark(number, root); errors.Is(err, errConflictingRoot) {
+		skippedCounter.Inc(1)
+		log.Warn("Skipping another block at a signed height", "number", number, "root", root, "err", err)
+		return nil
+	} else if err != nil {
+		return err
+	}
+	sign := api.AggSign{
+		Number:    number,
+		StateRoot: root,
+		Address:   v.account.Address,
+	}
+	copy(sign.Sign[:], v.key.Sign(root[:]).Marshal())
+	if err := client.CallContext(ctx, nil, "eth_submitSign", sign, token); err != nil {
+		return err
+	}
+	signedCounter.Inc(1)
+	if last, ok := v.signed.Last(); ok {
+		lastSignedGauge.Update(int64(last))
+	}
+	log.Info("Signed mined block", "number", number, "root", root, "elapsed", time.Since(start))
+	return nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/params"
+)
+
+// Tests that the signed record keeps the block signed per height, also after it
+// is reopened, and forgets the heights far below the highest one.
+func TestSignedRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "signed")
+	r, err := openSignedRecord(path)
+	if err != nil {
+		t.Fatalf("open failed: %v", err)
+	}
+	if _, ok := r.Last(); ok {
+		t.Fatal("empty record has a signed block")
+	}
+	rootA, rootB := types.Hash{0x0a}, types.Hash{0x0b}
+	for _, mark := range []struct {
+		number uint64
+		root   types.Hash
+	}{{10, rootA}, {10, rootA}, {11, rootA}, {9, rootB}} {
+		if err := r.Mark(mark.number, mark.root); err != nil {
+			t.Fatalf("mark %d: %v", mark.number, err)
+		}
+	}
+	if err := r.Mark(11, rootB); !errors.Is(err, errConflictingRoot) {
+		t.Fatalf("conflicting mark: have %v, want %v", err, errConflictingRoot)
+	}
+
+	r, err = openSignedRecord(path)
+	if err != nil {
+		t.Fatalf("reopen failed: %v", err)
+	}
+	if last, ok := r.Last(); !ok || last != 11 {
+		t.Fatalf("last signed: have %d, want 11", last)
+	}
+	for number, want := range map[uint64]types.Hash{9: rootB, 10: rootA, 11: rootA} {
+		if root, ok := r.Signed(number); !ok || root != want {
+			t.Fatalf("block %d: have %s (%v), want %s", number, root, ok, want)
+		}
+	}
+	if _, ok := r.Signed(12); ok {
+		t.Fatal("unsigned block 12 recorded")
+	}
+
+	if err := r.Mark(10+signedWindow, rootA); err != nil {
+		t.Fatal(err)
+	}
+	if !r.Expired(10) || r.Expired(11) {
+		t.Fatal("wrong heights expired")
+	}
+	if r, err = openSignedRecord(path); err != nil {
+		t.Fatalf("reopen failed: %v", err)
+	}
+	if _, ok := r.Signed(10); ok {
+		t.Fatal("expired block 10 kept")
+	}
+	if _, ok := r.Signed(11); !ok {
+		t.Fatal("block 11 dropped")
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "verifier.yaml")
+	data := "nodes:\n  - ws://127.0.0.1:20013\n  - ws://10.0.0.2:20013\nkeystore: ./keystore\naccount: \"0x588639773bc6f163aa262245cda746c120676431\"\nmax_backoff: 30s\n"
+	if err := os.WriteFile(file, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := loadConfig(file)
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if len(cfg.Nodes) != 2 || cfg.MaxBackoff != 30*time.Second || cfg.MinBackoff != defaultConfig.MinBackoff {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+	if chainConfig, err := cfg.chainConfig(); err != nil || chainConfig != params.AmazeChainConfig {
+		t.Fatalf("chain config without genesis: have %v (%v), want the main network's", chainConfig, err)
+	}
+	cfg.Genesis = filepath.Join(t.TempDir(), "genesis.json")
+	if err := os.WriteFile(cfg.Genesis, []byte(`{"config": {"chainId": 1337}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if chainConfig, err := cfg.chainConfig(); err != nil || chainConfig.ChainID.Uint64() != 1337 {
+		t.Fatalf("chain config of the genesis file: have %v (%v), want chain id 1337", chainConfig, err)
+	}
+
+	if err := os.WriteFile(file, []byte("keystore: ./keystore\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadConfig(file); err == nil {
+		t.Fatal("config without nodes accepted")
+	}
+}
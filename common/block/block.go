@@ -17,14 +17,18 @@
 package block
 
 import (
+	"bytes"
 	"fmt"
 	"github.com/amazechain/amc/api/protocol/types_pb"
+	"github.com/amazechain/amc/common/crypto"
 	"github.com/amazechain/amc/common/hashing"
 	"github.com/amazechain/amc/common/transaction"
 	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/internal/avm/rlp"
 	"github.com/amazechain/amc/utils"
 	"github.com/golang/protobuf/proto"
 	"github.com/holiman/uint256"
+	"io"
 	"sync/atomic"
 	"time"
 )
@@ -83,6 +87,34 @@ func (r *Reward) FromProtoMessage(pbReward *types_pb.Reward) *Reward {
 	return r
 }
 
+// Verifiers implements hashing.DerivableList for the verifiers of a body.
+type Verifiers []*Verify
+
+func (vs Verifiers) Len() int { return len(vs) }
+
+func (vs Verifiers) EncodeIndex(i int, w *bytes.Buffer) {
+	w.Write(vs[i].Address[:])
+	w.Write(vs[i].PublicKey[:])
+}
+
+// Rewards implements hashing.DerivableList for the rewards of a body.
+type Rewards []*Reward
+
+func (rs Rewards) Len() int { return len(rs) }
+
+func (rs Rewards) EncodeIndex(i int, w *bytes.Buffer) {
+	amount := rs[i].Amount.Bytes32()
+	w.Write(rs[i].Address[:])
+	w.Write(amount[:])
+}
+
+// CalcVerifierHash returns the header commitment to the verifiers and rewards
+// of a body.
+func CalcVerifierHash(verifiers []*Verify, rewards []*Reward) types.Hash {
+	v, r := hashing.DeriveSha(Verifiers(verifiers)), hashing.DeriveSha(Rewards(rewards))
+	return crypto.Keccak256Hash(v[:], r[:])
+}
+
 func (b *Block) Transactions() []*transaction.Transaction {
 	if b.body != nil {
 		return b.body.Transactions()
@@ -246,6 +278,101 @@ func (b *Block) FromProtoMessage(message proto.Message) error {
 	return nil
 }
 
+// extHeader is the RLP encoding of a header. Header itself cannot be encoded
+// directly as its signature follows the optional fields.
+type extHeader struct {
+	ParentHash   types.Hash
+	Coinbase     types.Address
+	Root         types.Hash
+	TxHash       types.Hash
+	ReceiptHash  types.Hash
+	Bloom        Bloom
+	Difficulty   *uint256.Int
+	Number       *uint256.Int
+	GasLimit     uint64
+	GasUsed      uint64
+	Time         uint64
+	MixDigest    types.Hash
+	Nonce        BlockNonce
+	Extra        []byte
+	Signature    types.Signature
+	BaseFee      *uint256.Int
+	VerifierHash *types.Hash `rlp:"nil"`
+}
+
+// extblock is the RLP encoding of a block, including the senders of its
+// transactions and its verifiers and rewards.
+type extblock struct {
+	Header    *extHeader
+	Txs       []*transaction.Transaction
+	Verifiers []*Verify
+	Rewards   []*Reward
+}
+
+// EncodeRLP implements rlp.Encoder.
+func (b *Block) EncodeRLP(w io.Writer) error {
+	h := b.header
+	return rlp.Encode(w, &extblock{
+		Header: &extHeader{
+			ParentHash:   h.ParentHash,
+			Coinbase:     h.Coinbase,
+			Root:         h.Root,
+			TxHash:       h.TxHash,
+			ReceiptHash:  h.ReceiptHash,
+			Bloom:        h.Bloom,
+			Difficulty:   h.Difficulty,
+			Number:       h.Number,
+			GasLimit:     h.GasLimit,
+			GasUsed:      h.GasUsed,
+			Time:         h.Time,
+			MixDigest:    h.MixDigest,
+			Nonce:        h.Nonce,
+			Extra:        h.Extra,
+			Signature:    h.Signature,
+			BaseFee:      h.BaseFee,
+			VerifierHash: h.VerifierHash,
+		},
+		Txs:       b.body.Txs,
+		Verifiers: b.body.Verifiers,
+		Rewards:   b.body.Rewards,
+	})
+}
+
+// DecodeRLP implements rlp.Decoder.
+func (b *Block) DecodeRLP(s *rlp.Stream) error {
+	var eb extblock
+	if err := s.Decode(&eb); err != nil {
+		return err
+	}
+	h := eb.Header
+	header := &Header{
+		ParentHash:   h.ParentHash,
+		Coinbase:     h.Coinbase,
+		Root:         h.Root,
+		TxHash:       h.TxHash,
+		ReceiptHash:  h.ReceiptHash,
+		Bloom:        h.Bloom,
+		Difficulty:   h.Difficulty,
+		Number:       h.Number,
+		GasLimit:     h.GasLimit,
+		GasUsed:      h.GasUsed,
+		Time:         h.Time,
+		MixDigest:    h.MixDigest,
+		Nonce:        h.Nonce,
+		Signature:    h.Signature,
+		BaseFee:      h.BaseFee,
+		VerifierHash: h.VerifierHash,
+	}
+	// match the protobuf decoding, which the header hash depends on
+	if len(h.Extra) > 0 {
+		header.Extra = h.Extra
+	}
+	b.header = header
+	b.body = &Body{Txs: eb.Txs, Verifiers: eb.Verifiers, Rewards: eb.Rewards}
+	b.ReceiveAt = time.Now()
+	return nil
+}
+
 func (b *Block) SendersToTxs(senders []types.Address) {
 	//todo
 }
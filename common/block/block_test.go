@@ -20,8 +20,12 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"github.com/amazechain/amc/api/protocol/types_pb"
+	"github.com/amazechain/amc/common/transaction"
+	"github.com/amazechain/amc/common/types"
 	"github.com/amazechain/amc/internal/avm/rlp"
 	"github.com/golang/protobuf/proto"
+	"github.com/holiman/uint256"
+	"strings"
 	"testing"
 )
 
@@ -160,3 +164,101 @@ func BenchmarkJsonDecode(b *testing.B) {
 		_ = json.Unmarshal(bytes, &exB)
 	}
 }
+
+func TestVerifierHash(t *testing.T) {
+	legacy := &Header{Number: uint256.NewInt(1), Difficulty: uint256.NewInt(2), BaseFee: uint256.NewInt(0), Root: types.Hash{3}, Extra: []byte{4}}
+
+	// Headers before the fork keep their encoding
+	var pb types_pb.Header
+	if err := proto.Unmarshal(mustMarshal(t, legacy), &pb); err != nil {
+		t.Fatal(err)
+	}
+	if pb.VerifierHash != nil {
+		t.Fatalf("verifier hash encoded before the fork")
+	}
+	if data, _ := json.Marshal(legacy); strings.Contains(string(data), "verifiersRoot") {
+		t.Fatalf("verifier hash covered by the header hash before the fork")
+	}
+
+	verifiers := []*Verify{{Address: types.Address{1}, PublicKey: types.PublicKey{2}}}
+	rewards := []*Reward{{Address: types.Address{1}, Amount: uint256.NewInt(3)}}
+	hash := CalcVerifierHash(verifiers, rewards)
+	header := CopyHeader(legacy)
+	header.VerifierHash = &hash
+	if header.Hash() == legacy.Hash() {
+		t.Fatalf("verifier hash not covered by the header hash")
+	}
+
+	var decoded Header
+	if err := decoded.Unmarshal(mustMarshal(t, header)); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.VerifierHash == nil || *decoded.VerifierHash != hash || decoded.Hash() != header.Hash() {
+		t.Fatalf("verifier hash lost in encoding")
+	}
+
+	// Substituted lists change the commitment
+	for i, alt := range []types.Hash{
+		CalcVerifierHash([]*Verify{{Address: types.Address{9}, PublicKey: types.PublicKey{2}}}, rewards),
+		CalcVerifierHash([]*Verify{{Address: types.Address{1}, PublicKey: types.PublicKey{9}}}, rewards),
+		CalcVerifierHash(verifiers, []*Reward{{Address: types.Address{1}, Amount: uint256.NewInt(4)}}),
+		CalcVerifierHash(nil, rewards),
+		CalcVerifierHash(verifiers, nil),
+	} {
+		if alt == hash {
+			t.Errorf("substitution %d: verifier hash unchanged", i)
+		}
+	}
+}
+
+// Tests that blocks survive the RLP encoding of chain exports with their hash,
+// senders, verifiers and rewards.
+func TestBlockRLP(t *testing.T) {
+	verifiers := []*Verify{{Address: types.Address{1}, PublicKey: types.PublicKey{2}}}
+	rewards := []*Reward{{Address: types.Address{1}, Amount: uint256.NewInt(3)}}
+	hash := CalcVerifierHash(verifiers, rewards)
+	header := &Header{Number: uint256.NewInt(1), Difficulty: uint256.NewInt(2), BaseFee: uint256.NewInt(0), Root: types.Hash{3}, VerifierHash: &hash}
+
+	from, to := types.Address{5}, types.Address{6}
+	txs := []*transaction.Transaction{
+		transaction.NewTransaction(0, from, &to, uint256.NewInt(7), 21000, uint256.NewInt(1), nil),
+		transaction.NewTx(&transaction.DynamicFeeTx{ChainID: uint256.NewInt(1), Nonce: 1, GasTipCap: uint256.NewInt(1), GasFeeCap: uint256.NewInt(2), Gas: 21000, To: &to, From: &from, Data: []byte{8}}),
+	}
+	b := NewBlockFromStorage(header.Hash(), header, &Body{Txs: txs, Verifiers: verifiers, Rewards: rewards})
+	legacy := &Header{Number: uint256.NewInt(2), Difficulty: uint256.NewInt(2), Extra: []byte{4}}
+	empty := NewBlockFromStorage(legacy.Hash(), legacy, &Body{})
+
+	for i, want := range []*Block{b, empty} {
+		enc, err := rlp.EncodeToBytes(want)
+		if err != nil {
+			t.Fatalf("block %d: encode: %v", i, err)
+		}
+		var have Block
+		if err := rlp.DecodeBytes(enc, &have); err != nil {
+			t.Fatalf("block %d: decode: %v", i, err)
+		}
+		if have.Hash() != want.Hash() {
+			t.Fatalf("block %d: hash mismatch: have %v, want %v", i, have.Hash(), want.Hash())
+		}
+		if len(have.Transactions()) != len(want.Transactions()) {
+			t.Fatalf("block %d: have %d txs, want %d", i, len(have.Transactions()), len(want.Transactions()))
+		}
+		for j, tx := range have.Transactions() {
+			if tx.Hash() != want.Transactions()[j].Hash() || *tx.From() != *want.Transactions()[j].From() {
+				t.Errorf("block %d: tx %d mismatch", i, j)
+			}
+		}
+		haveBody, wantBody := have.Body().(*Body), want.Body().(*Body)
+		if CalcVerifierHash(haveBody.Verifiers, haveBody.Rewards) != CalcVerifierHash(wantBody.Verifiers, wantBody.Rewards) {
+			t.Errorf("block %d: verifiers or rewards mismatch", i)
+		}
+	}
+}
+
+func mustMarshal(t *testing.T, h *Header) []byte {
+	data, err := h.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
@@ -74,6 +74,10 @@ type Header struct {
 	// BaseFee was added by EIP-1559 and is ignored in legacy headers.
 	BaseFee *uint256.Int `json:"baseFeePerGas" rlp:"optional"`
 
+	// VerifierHash commits to the verifiers and rewards of the body after the
+	// verifier commitment fork and is nil before it.
+	VerifierHash *types.Hash `json:"verifiersRoot,omitempty" rlp:"optional"`
+
 	hash atomic.Value
 
 	Signature types.Signature `json:"signature"`
@@ -119,7 +123,7 @@ func (h Header) Hash() types.Hash {
 }
 
 func (h *Header) ToProtoMessage() proto.Message {
-	return &types_pb.Header{
+	pbHeader := &types_pb.Header{
 		ParentHash:  utils.ConvertHashToH256(h.ParentHash),
 		Coinbase:    utils.ConvertAddressToH160(h.Coinbase),
 		Root:        utils.ConvertHashToH256(h.Root),
@@ -137,6 +141,10 @@ func (h *Header) ToProtoMessage() proto.Message {
 		Bloom:       utils.ConvertBytesToH2048(h.Bloom.Bytes()),
 		MixDigest:   utils.ConvertHashToH256(h.MixDigest),
 	}
+	if h.VerifierHash != nil {
+		pbHeader.VerifierHash = utils.ConvertHashToH256(*h.VerifierHash)
+	}
+	return pbHeader
 }
 
 func (h *Header) FromProtoMessage(message proto.Message) error {
@@ -165,6 +173,10 @@ func (h *Header) FromProtoMessage(message proto.Message) error {
 	h.Signature = utils.ConvertH768ToSignature(pbHeader.Signature)
 	h.Bloom = utils.ConvertH2048ToBloom(pbHeader.Bloom)
 	h.MixDigest = utils.ConvertH256ToHash(pbHeader.MixDigest)
+	if pbHeader.VerifierHash != nil {
+		verifierHash := types.Hash(utils.ConvertH256ToHash(pbHeader.VerifierHash))
+		h.VerifierHash = &verifierHash
+	}
 	return nil
 }
 
@@ -197,6 +209,10 @@ func CopyHeader(h *Header) *Header {
 	if h.BaseFee != nil {
 		cpy.BaseFee = uint256.NewInt(0).SetBytes(h.BaseFee.Bytes())
 	}
+	if h.VerifierHash != nil {
+		verifierHash := *h.VerifierHash
+		cpy.VerifierHash = &verifierHash
+	}
 
 	if len(h.Extra) > 0 {
 		cpy.Extra = make([]byte, len(h.Extra))
@@ -39,6 +39,8 @@ type IBlockChain interface {
 	IHeaderChain
 	Config() *params.ChainConfig
 	CurrentBlock() block.IBlock
+	CurrentSafeBlock() block.IHeader
+	CurrentFinalizedBlock() block.IHeader
 	Blocks() []block.IBlock
 	Start() error
 	GenesisBlock() block.IBlock
@@ -65,6 +67,9 @@ type IBlockChain interface {
 	Quit() <-chan struct{}
 
 	WriteBlockWithState(block block.IBlock, receipts []*block.Receipt) error
+
+	InsertBlocksWithoutState(chain []block.IBlock) (int, error)
+	SnapSyncCommitHead(hash types.Hash) error
 }
 
 type IMiner interface {
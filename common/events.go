@@ -54,6 +54,10 @@ type ChainHighestBlock struct {
 	Block    block.Block
 	Inserted bool
 }
+
+// FinalizedHeadEvent is posted when the finalized block advances
+type FinalizedHeadEvent struct{ Header *block.Header }
+
 type MinedEntireEvent struct {
 	Entire state.EntireCode
 }
@@ -36,9 +36,18 @@ type IDownloader interface {
 	Start() error
 	Close() error
 	IsDownloading() bool
+	Progress() SyncProgress
 	ConnHandler([]byte, peer.ID) error
 }
 
+// SyncProgress gives progress indications when the node is synchronising
+// with the network.
+type SyncProgress struct {
+	StartingBlock uint64 // Block number where sync began
+	CurrentBlock  uint64 // Current block number where sync is at
+	HighestBlock  uint64 // Highest alleged block number in the chain
+}
+
 type ConnHandler func([]byte, peer.ID) error
 
 type ProtocolHandshakeFn func(peer IPeer, genesisHash types.Hash, currentHeight *uint256.Int) (Peer, bool)
@@ -48,10 +57,12 @@ type INetwork interface {
 	WriterMessage(messageType message.MessageType, payload []byte, peer peer.ID) error
 	//BroadcastMessage(messageType message.MessageType, payload []byte) (int, error)
 	SetHandler(message.MessageType, ConnHandler) error
+	AddPeer(info peer.AddrInfo) error
 	ClosePeer(id peer.ID) error
 	Start() error
 	Host() host.Host
 	PeerCount() int
+	Peers() PeerSet
 	Bootstrapped() bool
 }
 
@@ -19,9 +19,11 @@ package transaction
 import (
 	"bytes"
 	"fmt"
+	"github.com/amazechain/amc/internal/avm/rlp"
 	"github.com/amazechain/amc/utils"
 	"github.com/golang/protobuf/proto"
 	"github.com/holiman/uint256"
+	"io"
 	"math/big"
 	"sync/atomic"
 	"time"
@@ -249,6 +251,58 @@ func (tx *Transaction) ToProtoMessage() proto.Message {
 	return &pbTx
 }
 
+// EncodeRLP implements rlp.Encoder. Legacy transactions are encoded as a list,
+// typed transactions as a byte string holding the type followed by the list.
+// Unlike the signing hash the encoding carries the sender.
+func (tx *Transaction) EncodeRLP(w io.Writer) error {
+	if tx.Type() == LegacyTxType {
+		return rlp.Encode(w, tx.inner)
+	}
+	var buf bytes.Buffer
+	buf.WriteByte(tx.Type())
+	if err := rlp.Encode(&buf, tx.inner); err != nil {
+		return err
+	}
+	return rlp.Encode(w, buf.Bytes())
+}
+
+// DecodeRLP implements rlp.Decoder.
+func (tx *Transaction) DecodeRLP(s *rlp.Stream) error {
+	kind, _, err := s.Kind()
+	if err != nil {
+		return err
+	}
+	if kind == rlp.List {
+		var inner LegacyTx
+		if err := s.Decode(&inner); err != nil {
+			return err
+		}
+		tx.setDecoded(&inner, 0)
+		return nil
+	}
+	b, err := s.Bytes()
+	if err != nil {
+		return err
+	}
+	if len(b) == 0 {
+		return fmt.Errorf("empty typed transaction")
+	}
+	var inner TxData
+	switch b[0] {
+	case AccessListTxType:
+		inner = new(AccessListTx)
+	case DynamicFeeTxType:
+		inner = new(DynamicFeeTx)
+	default:
+		return fmt.Errorf("unsupported transaction type %d", b[0])
+	}
+	if err := rlp.DecodeBytes(b[1:], inner); err != nil {
+		return err
+	}
+	tx.setDecoded(inner, 0)
+	return nil
+}
+
 func (tx *Transaction) setDecoded(inner TxData, size int) {
 	tx.inner = inner
 	tx.time = time.Now()
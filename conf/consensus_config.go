@@ -46,4 +46,8 @@ type APosConfig struct {
 	RewardLimit        *big.Int `json:"rewardLimit" yaml:"rewardLimit"`
 
 	DepositContract string `json:"depositContract" yaml:"depositContract"` // Deposit contract
+
+	// Share of the active depositors that must sign a block, 2/3 by default
+	QuorumNumerator   uint64 `json:"quorumNumerator" yaml:"quorumNumerator"`
+	QuorumDenominator uint64 `json:"quorumDenominator" yaml:"quorumDenominator"`
 }
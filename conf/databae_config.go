@@ -25,4 +25,18 @@ type DatabaseConfig struct {
 	IsMem      bool     `json:"memory" yaml:"memory"`
 	MaxDB      uint64   `json:"max_db" yaml:"max_db"`
 	MaxReaders uint64   `json:"max_readers" yaml:"max_readers"`
+
+	// Prune is "archive" to keep the whole history, or "full" to keep only the
+	// last PruneHistory blocks of state history, PruneReceipts blocks of receipts
+	// and PruneLogs blocks of logs. A zero distance keeps that class in full.
+	Prune         string `json:"prune" yaml:"prune"`
+	PruneHistory  uint64 `json:"prune_history" yaml:"prune_history"`
+	PruneReceipts uint64 `json:"prune_receipts" yaml:"prune_receipts"`
+	PruneLogs     uint64 `json:"prune_logs" yaml:"prune_logs"`
 }
+
+// Prune modes
+const (
+	PruneArchive = "archive"
+	PruneFull    = "full"
+)
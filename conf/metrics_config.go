@@ -17,6 +17,9 @@
 package conf
 
 type MetricsConfig struct {
+	HTTP string `json:"http" yaml:"http"`
+	Port int    `json:"port" yaml:"port"`
+
 	EnableInfluxDB       bool   `json:"enable_influx_db" yaml:"enable_influx_db"`
 	InfluxDBEndpoint     string `json:"influx_db_endpoint" yaml:"influx_db_endpoint"`
 	InfluxDBDatabase     string `json:"influx_db_database" yaml:"influx_db_database"`
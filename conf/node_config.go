@@ -19,6 +19,7 @@ package conf
 import (
 	"os"
 	"path/filepath"
+	"time"
 )
 
 const (
@@ -36,6 +37,20 @@ type NodeConfig struct {
 	IPCPath     string `json:"ipc_path" yaml:"ipc_path"`
 	DataDir     string `json:"data_dir" yaml:"data_dir"`
 	Miner       bool   `json:"miner" yaml:"miner"`
+	SyncMode    string `json:"sync_mode" yaml:"sync_mode"`
+
+	// AuthRPC enables the JWT authenticated HTTP endpoint, which additionally
+	// serves the admin namespace. JWTSecret is the path of the hex encoded
+	// secret; a relative path is resolved against DataDir and a new secret is
+	// generated there if the file does not exist.
+	AuthRPC   bool   `json:"authrpc" yaml:"authrpc"`
+	AuthHost  string `json:"authrpc_host" yaml:"authrpc_host"`
+	AuthPort  string `json:"authrpc_port" yaml:"authrpc_port"`
+	JWTSecret string `json:"jwt_secret" yaml:"jwt_secret"`
+
+	// SlowBlockThreshold is the processing time above which the stage breakdown
+	// of an imported or mined block is logged as a warning. Zero disables it.
+	SlowBlockThreshold time.Duration `json:"slow_block_threshold" yaml:"slow_block_threshold"`
 
 	// KeyStoreDir is the file system folder that contains private keys. The directory can
 	// be specified as a relative path, in which case it is resolved relative to the
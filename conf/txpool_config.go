@@ -0,0 +1,36 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package conf
+
+import "time"
+
+type TxPoolConfig struct {
+	Locals    []string      `json:"locals" yaml:"locals"`
+	NoLocals  bool          `json:"no_locals" yaml:"no_locals"`
+	Journal   string        `json:"journal" yaml:"journal"`
+	Rejournal time.Duration `json:"rejournal" yaml:"rejournal"`
+
+	PriceLimit uint64 `json:"price_limit" yaml:"price_limit"`
+	PriceBump  uint64 `json:"price_bump" yaml:"price_bump"`
+
+	AccountSlots uint64 `json:"account_slots" yaml:"account_slots"`
+	GlobalSlots  uint64 `json:"global_slots" yaml:"global_slots"`
+	AccountQueue uint64 `json:"account_queue" yaml:"account_queue"`
+	GlobalQueue  uint64 `json:"global_queue" yaml:"global_queue"`
+
+	Lifetime time.Duration `json:"lifetime" yaml:"lifetime"`
+}
@@ -17,10 +17,16 @@
 package deposit
 
 import (
+	"context"
 	"github.com/amazechain/amc/common/crypto/bls"
 	"github.com/amazechain/amc/common/hexutil"
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/modules"
+	"github.com/amazechain/amc/modules/rawdb"
 	"github.com/amazechain/amc/params"
 	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
 	"testing"
 )
 
@@ -62,6 +68,58 @@ func TestUint256(t *testing.T) {
 	t.Logf("100 AMT uint256 bytes:%s, hex Bytes: %s", hexutil.Encode(amt100Uint256.Bytes()), hexutil.Encode(amt100Hex))
 }
 
+// Tests that deposits earn the rewards of the highest tier of the schedule they reach.
+func TestGetDepositInfo(t *testing.T) {
+	modules.AmcInit()
+	kv.ChaindataTablesCfg = modules.AmcTableCfg
+	db := memdb.New(t.TempDir())
+	defer db.Close()
+	tx, err := db.BeginRw(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	key, err := bls.RandKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pub types.PublicKey
+	pub.SetBytes(key.PublicKey().Marshal())
+
+	tests := []struct {
+		deposit  uint64 // AMT
+		perBlock uint64 // wei, 0 without rewards
+		perEpoch uint64
+	}{
+		{10, 0, 0},
+		{50, 25000000000000, 12500000000000000},
+		{75, 0, 0},
+		{100, 333333333333334, 33333333333333400},
+		{500, 2083333333333334, 208333333333333400},
+	}
+	for _, tt := range tests {
+		addr := types.Address{byte(tt.deposit)}
+		amount := new(uint256.Int).Mul(uint256.NewInt(params.AMT), uint256.NewInt(tt.deposit))
+		if err := rawdb.PutDeposit(tx, addr, pub, *amount); err != nil {
+			t.Fatal(err)
+		}
+		info := GetDepositInfo(tx, addr, params.DefaultDepositSchedule)
+		if tt.perBlock == 0 {
+			if info != nil {
+				t.Errorf("deposit of %d AMT: unexpected rewards %v", tt.deposit, info.RewardPerBlock)
+			}
+			continue
+		}
+		if info == nil {
+			t.Fatalf("deposit of %d AMT: no rewards", tt.deposit)
+		}
+		if info.RewardPerBlock.Uint64() != tt.perBlock || info.MaxRewardPerEpoch.Uint64() != tt.perEpoch {
+			t.Errorf("deposit of %d AMT: have %v/%v, want %d/%d", tt.deposit, info.RewardPerBlock, info.MaxRewardPerEpoch, tt.perBlock, tt.perEpoch)
+		}
+	}
+}
+
 //func TestPrivateKey(t *testing.T) {
 //	private, _ := hexutil.Decode("0xde4b76c3dca3d8e10aea7644f77b316a68a6476fbd119d441ead5c6131aa42a7")
 //
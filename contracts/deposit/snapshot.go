@@ -0,0 +1,99 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+package deposit
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/amazechain/amc/common/block"
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/modules/rawdb"
+	"github.com/amazechain/amc/params"
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+)
+
+// ErrUnknownDeposits is returned when the depositors after a block are not known.
+var ErrUnknownDeposits = errors.New("unknown deposits")
+
+// ReadDeposits returns the depositors after the block with the given hash and
+// number. The Deposit table stands in for the head block if no snapshot was
+// recorded for it, as for the blocks written before snapshots or without state.
+func ReadDeposits(tx kv.Tx, hash types.Hash, number uint64) (rawdb.Deposits, error) {
+	deposits, ok, err := rawdb.ReadDepositSnapshot(tx, hash, number)
+	if err != nil || ok {
+		return deposits, err
+	}
+	if rawdb.ReadHeadBlockHash(tx) == hash {
+		return rawdb.ReadDeposits(tx)
+	}
+	return nil, fmt.Errorf("%w: block %d %x", ErrUnknownDeposits, number, hash)
+}
+
+// ApplyBlock returns the depositors after the block, given the depositors before
+// it and its receipts. A deposit event of the contract registers its sender with
+// the deposited key and amount, unless the signature over the amount is invalid
+// or the amount is below the minimum deposit of the schedule in force at the
+// block, and a withdrawal event removes the sender.
+func ApplyBlock(parent rawdb.Deposits, contract types.Address, schedule *params.DepositSchedule, b block.IBlock, receipts block.Receipts) (rawdb.Deposits, error) {
+	deposits := make(rawdb.Deposits, len(parent))
+	for addr, d := range parent {
+		deposits[addr] = d
+	}
+	txs := b.Transactions()
+	for i, receipt := range receipts {
+		if receipt == nil {
+			continue
+		}
+		for _, l := range receipt.Logs {
+			if l.Address != contract || len(l.Topics) == 0 || (l.Topics[0] != depositEventSignature && l.Topics[0] != withdrawnSignature) {
+				continue
+			}
+			if i >= len(txs) || txs[i].From() == nil {
+				return nil, fmt.Errorf("deposit event of unknown sender in block %d", b.Number64().Uint64())
+			}
+			sender := *txs[i].From()
+			if l.Topics[0] == withdrawnSignature {
+				delete(deposits, sender)
+				continue
+			}
+			pub, amount, ok := verifiedDeposit(l.Data)
+			if !ok || !accepted(schedule, amount) {
+				continue
+			}
+			deposits[sender] = &rawdb.Depositor{PublicKey: pub, Amount: amount}
+		}
+	}
+	return deposits, nil
+}
+
+// Verifiers returns the depositors whose deposit reaches the minimum deposit of
+// the schedule, who verify blocks and make up the signing quorum.
+func Verifiers(deposits rawdb.Deposits, schedule *params.DepositSchedule) rawdb.Deposits {
+	verifiers := make(rawdb.Deposits, len(deposits))
+	for addr, d := range deposits {
+		if accepted(schedule, d.Amount) {
+			verifiers[addr] = d
+		}
+	}
+	return verifiers
+}
+
+// accepted returns whether the amount reaches the minimum deposit of the schedule.
+func accepted(schedule *params.DepositSchedule, amount *uint256.Int) bool {
+	return amount != nil && amount.ToBig().Cmp(schedule.MinDeposit) >= 0
+}
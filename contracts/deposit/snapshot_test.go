@@ -0,0 +1,166 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+package deposit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/amazechain/amc/accounts/abi"
+	"github.com/amazechain/amc/common/block"
+	"github.com/amazechain/amc/common/crypto/bls"
+	"github.com/amazechain/amc/common/transaction"
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/modules"
+	"github.com/amazechain/amc/modules/rawdb"
+	"github.com/amazechain/amc/params"
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+)
+
+// depositLog returns a deposit event of the contract, signed with the key unless
+// it is forged.
+func depositLog(t *testing.T, contract types.Address, key bls.SecretKey, amount *uint256.Int, forged bool) *block.Log {
+	contractAbi, err := abi.JSON(bytes.NewReader(depositAbiCode))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := key.Sign(amount.Bytes())
+	if forged {
+		sig = key.Sign([]byte("forged"))
+	}
+	data, err := contractAbi.Events["DepositEvent"].Inputs.Pack(key.PublicKey().Marshal(), amount.ToBig(), sig.Marshal())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &block.Log{Address: contract, Topics: []types.Hash{depositEventSignature}, Data: data}
+}
+
+// Tests that the depositors after a block follow the deposit and withdrawal
+// events of its receipts, and are read back from the snapshot of the block.
+func TestApplyBlock(t *testing.T) {
+	modules.AmcInit()
+	kv.ChaindataTablesCfg = modules.AmcTableCfg
+	db := memdb.New(t.TempDir())
+	defer db.Close()
+
+	var (
+		contract = types.Address{0xc0}
+		amount   = new(uint256.Int).Mul(uint256.NewInt(50), uint256.NewInt(params.AMT))
+		addrs    = []types.Address{{0x01}, {0x02}, {0x03}, {0x04}}
+		keys     = make([]bls.SecretKey, len(addrs))
+	)
+	for i := range keys {
+		var err error
+		if keys[i], err = bls.RandKey(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var pub0 types.PublicKey
+	pub0.SetBytes(keys[0].PublicKey().Marshal())
+	parent := rawdb.Deposits{
+		addrs[0]: {PublicKey: pub0, Amount: amount},
+	}
+
+	txs := make([]*transaction.Transaction, len(addrs))
+	for i := range addrs {
+		txs[i] = transaction.NewTransaction(uint64(i), addrs[i], &contract, uint256.NewInt(0), 0, uint256.NewInt(0), nil)
+	}
+	receipts := block.Receipts{
+		// The first depositor withdraws
+		{Logs: []*block.Log{{Address: contract, Topics: []types.Hash{withdrawnSignature}}}},
+		// The second one deposits
+		{Logs: []*block.Log{depositLog(t, contract, keys[1], amount, false)}},
+		// The third one forges the signature, and another contract emits the event
+		{Logs: []*block.Log{depositLog(t, contract, keys[2], amount, true), depositLog(t, types.Address{0xc1}, keys[2], amount, false)}},
+		// The fourth one deposits less than the minimum
+		{Logs: []*block.Log{depositLog(t, contract, keys[3], new(uint256.Int).Sub(amount, uint256.NewInt(1)), false)}},
+	}
+	b := block.NewBlock(&block.Header{Number: uint256.NewInt(1)}, txs)
+	deposits, err := ApplyBlock(parent, contract, params.DefaultDepositSchedule, b, receipts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deposits) != 1 || deposits[addrs[1]] == nil || !deposits[addrs[1]].Amount.Eq(amount) {
+		t.Fatalf("unexpected depositors %v", deposits)
+	}
+	if _, ok := parent[addrs[0]]; !ok {
+		t.Fatalf("depositors before the block modified")
+	}
+	// A schedule raising the minimum deposit leaves the depositor out of the verifiers.
+	raised := &params.DepositSchedule{MinDeposit: new(big.Int).Add(amount.ToBig(), big.NewInt(1))}
+	if verifiers := Verifiers(deposits, params.DefaultDepositSchedule); len(verifiers) != 1 {
+		t.Fatalf("unexpected verifiers %v", verifiers)
+	}
+	if verifiers := Verifiers(deposits, raised); len(verifiers) != 0 {
+		t.Fatalf("unexpected verifiers %v below the minimum deposit", verifiers)
+	}
+
+	if err := db.Update(context.Background(), func(tx kv.RwTx) error {
+		if err := rawdb.WriteDepositSnapshot(tx, b.Hash(), 1, deposits); err != nil {
+			return err
+		}
+		have, err := ReadDeposits(tx, b.Hash(), 1)
+		if err != nil {
+			return err
+		}
+		if len(have) != 1 || have[addrs[1]].PublicKey != deposits[addrs[1]].PublicKey || !have[addrs[1]].Amount.Eq(amount) {
+			t.Errorf("snapshot mismatch: have %v, want %v", have, deposits)
+		}
+
+		// Without a snapshot, the Deposit table only stands in for the head
+		other := types.Hash{0x01}
+		if _, err := ReadDeposits(tx, other, 1); !errors.Is(err, ErrUnknownDeposits) {
+			t.Errorf("unknown block: have error %v, want %v", err, ErrUnknownDeposits)
+		}
+		if err := rawdb.PutDeposit(tx, addrs[0], pub0, *amount); err != nil {
+			return err
+		}
+		rawdb.WriteHeadBlockHash(tx, other)
+		head, err := ReadDeposits(tx, other, 1)
+		if err != nil {
+			return err
+		}
+		if len(head) != 1 || head[addrs[0]] == nil {
+			t.Errorf("head depositors %v, want the Deposit table", head)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Tests that the Deposit table is not rebuilt once the logs it is rebuilt from
+// have been pruned.
+func TestUnwindDepositsPruned(t *testing.T) {
+	modules.AmcInit()
+	kv.ChaindataTablesCfg = modules.AmcTableCfg
+	db := memdb.New(t.TempDir())
+	defer db.Close()
+
+	if err := db.Update(context.Background(), func(tx kv.RwTx) error {
+		if err := rawdb.WritePrunedTo(tx, rawdb.PruneLogs, 10); err != nil {
+			return err
+		}
+		return UnwindDeposits(tx, params.AmazeChainConfig, types.Address{0xc0}, 20)
+	}); !errors.Is(err, rawdb.ErrHistoryPruned) {
+		t.Fatalf("have error %v, want %v", err, rawdb.ErrHistoryPruned)
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package deposit
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/amazechain/amc/common/block"
+	"github.com/amazechain/amc/common/crypto/bls"
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/modules"
+	"github.com/amazechain/amc/modules/rawdb"
+	"github.com/amazechain/amc/params"
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+)
+
+// UnwindDeposits reverts the Deposit table to its state after block target. The
+// entries of every depositor with events in later blocks are dropped and rebuilt
+// from their last deposit or withdrawal at or before target, deposits below the
+// minimum deposit in force at their block being ignored. It has to run before the
+// later blocks and their receipts are removed, and fails with ErrHistoryPruned
+// once transaction logs have been pruned.
+func UnwindDeposits(tx kv.RwTx, config *params.ChainConfig, contract types.Address, target uint64) error {
+	// The last event of a depositor may be in any block.
+	if err := rawdb.CheckPruned(tx, rawdb.PruneLogs, 0); err != nil {
+		return err
+	}
+	pending := make(map[types.Address]struct{})
+	if err := walkDepositEvents(tx, contract, target+1, math.MaxUint64, func(sender types.Address, _ *block.Log) error {
+		pending[sender] = struct{}{}
+		return nil
+	}); err != nil {
+		return err
+	}
+	for addr := range pending {
+		if err := rawdb.DeleteDeposit(tx, addr); err != nil {
+			return err
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	// Blocks covered by the log index are looked up in the contract's bitmap,
+	// the rest are scanned.
+	progress, err := rawdb.ReadLogIndexProgress(tx)
+	if err != nil {
+		return err
+	}
+	blocks := make([]uint64, 0)
+	for n := target; n > progress; n-- {
+		blocks = append(blocks, n)
+	}
+	if progress > target {
+		progress = target
+	}
+	indexed, err := rawdb.ReadLogIndex(tx, modules.LogAddressIndex, contract.Bytes(), 0, progress)
+	if err != nil {
+		return err
+	}
+	numbers := indexed.ToArray()
+	for i := len(numbers) - 1; i >= 0; i-- {
+		blocks = append(blocks, uint64(numbers[i]))
+	}
+
+	// The newest event of a depositor decides its entry.
+	for _, n := range blocks {
+		var events []depositEvent
+		if err := walkDepositEvents(tx, contract, n, n, func(sender types.Address, l *block.Log) error {
+			events = append(events, depositEvent{sender, l})
+			return nil
+		}); err != nil {
+			return err
+		}
+		for i := len(events) - 1; i >= 0 && len(pending) > 0; i-- {
+			sender, l := events[i].sender, events[i].log
+			if _, ok := pending[sender]; !ok {
+				continue
+			}
+			if l.Topics[0] == withdrawnSignature {
+				delete(pending, sender)
+				continue
+			}
+			pub, amount, ok := verifiedDeposit(l.Data)
+			if !ok || !accepted(config.DepositScheduleAt(n), amount) {
+				continue
+			}
+			if err := rawdb.PutDeposit(tx, sender, pub, *amount); err != nil {
+				return err
+			}
+			delete(pending, sender)
+		}
+		if len(pending) == 0 {
+			break
+		}
+	}
+	return nil
+}
+
+type depositEvent struct {
+	sender types.Address
+	log    *block.Log
+}
+
+// walkDepositEvents calls fn with the sender of every deposit and withdrawal event
+// emitted by the contract in the canonical blocks [from, to], in order.
+func walkDepositEvents(tx kv.Tx, contract types.Address, from, to uint64, fn func(sender types.Address, l *block.Log) error) error {
+	c, err := tx.Cursor(modules.Log)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	var current *block.Block
+	for k, v, err := c.Seek(modules.EncodeBlockNumber(from)); k != nil; k, v, err = c.Next() {
+		if err != nil {
+			return err
+		}
+		number, txIndex := binary.BigEndian.Uint64(k[:8]), binary.BigEndian.Uint32(k[8:])
+		if number > to {
+			break
+		}
+		var logs block.Logs
+		if err := logs.Unmarshal(v); err != nil {
+			return fmt.Errorf("logs unmarshal failed for block %d: %w", number, err)
+		}
+		for _, l := range logs {
+			if l.Address != contract || len(l.Topics) == 0 || (l.Topics[0] != depositEventSignature && l.Topics[0] != withdrawnSignature) {
+				continue
+			}
+			if current == nil || current.Number64().Uint64() != number {
+				if current, err = rawdb.ReadBlockByNumber(tx, number); err != nil {
+					return err
+				}
+				if current == nil {
+					return fmt.Errorf("missing canonical block %d", number)
+				}
+			}
+			txs := current.Transactions()
+			if int(txIndex) >= len(txs) {
+				return fmt.Errorf("log of missing transaction %d in block %d", txIndex, number)
+			}
+			if err := fn(*txs[txIndex].From(), l); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// verifiedDeposit decodes a deposit event, reporting whether its BLS signature over
+// the amount is valid, as deposits with invalid signatures are not registered.
+func verifiedDeposit(data []byte) (types.PublicKey, *uint256.Int, bool) {
+	pb, amount, sig, err := UnpackDepositLogData(data)
+	if err != nil {
+		return types.PublicKey{}, nil, false
+	}
+	signature, err := bls.SignatureFromBytes(sig)
+	if err != nil {
+		return types.PublicKey{}, nil, false
+	}
+	publicKey, err := bls.PublicKeyFromBytes(pb)
+	if err != nil || !signature.Verify(publicKey, amount.Bytes()) {
+		return types.PublicKey{}, nil, false
+	}
+	var pub types.PublicKey
+	pub.SetBytes(publicKey.Marshal())
+	return pub, amount, true
+}
@@ -18,8 +18,8 @@ package api
 
 import (
 	"context"
-	"encoding/hex"
-	"fmt"
+	"sync"
+
 	"github.com/amazechain/amc/common"
 	"github.com/amazechain/amc/common/block"
 	"github.com/amazechain/amc/common/crypto"
@@ -32,18 +32,26 @@ import (
 	event "github.com/amazechain/amc/modules/event/v2"
 	"github.com/amazechain/amc/modules/rawdb"
 	"github.com/amazechain/amc/modules/state"
+	"github.com/amazechain/amc/params"
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"golang.org/x/crypto/sha3"
 )
 
 var sigChannel = make(chan AggSign, 10)
 
-var validVerifers = map[string]string{
-	"AMC4541Fc1CCB4e042a3BaDFE46904F9D22d127B682": "157aee59b889a8a9e3ecec11e4f79f6c065e3d21c6da2222b916c54f8c820d9c",
-	"AMC9BA336835422BAeFc537d75642959d2a866500a3": "00121edadf6e723f2fe8c23d2359f57a7058986a1b8458d23eb29db7204afea7",
-	"AMCf13d680bA12717fE27d33caB983c5C755Ff74358": "5de474bbf3fee5dfda9047287f596c6e6c0271876305357e399fffba6a5f9a9f",
-	"AMCd1ff88affe38dfb65c621706dff6468ecd418bff": "4c1ad066cc2971c94a8aca6a7d3e4bdd86891922c5b1d39e6d0da8cad9262be4",
-	"AMCb9e94477f5f88b5e8da2e97e8506d6e4fcf04e5b": "2c02dd3cf600af9a8567e5cc5ff158c1b89e1f3ea21bff61f505d141a96a60ee",
+// The BLS key this node signs the blocks it mines with, if it is a depositor.
+var (
+	verifierLock    sync.RWMutex
+	verifierAddress types.Address
+	verifierKey     bls.SecretKey
+)
+
+// SetVerifier sets the deposited address and BLS key MachineVerify signs mined
+// blocks with.
+func SetVerifier(addr types.Address, key bls.SecretKey) {
+	verifierLock.Lock()
+	defer verifierLock.Unlock()
+	verifierAddress, verifierKey = addr, key
 }
 
 //type WithCodeAndHash struct {
@@ -187,26 +195,44 @@ func (s *AggSign) Check(root types.Hash) bool {
 	return sig.Verify(pub, s.StateRoot[:])
 }
 
-func DepositInfo(db kv.RwDB, key types.Address) *deposit.Info {
+func DepositInfo(db kv.RwDB, schedule *params.DepositSchedule, key types.Address) *deposit.Info {
 	var info *deposit.Info
 	_ = db.View(context.Background(), func(tx kv.Tx) error {
-		info = deposit.GetDepositInfo(tx, key)
+		info = deposit.GetDepositInfo(tx, key, schedule)
 		return nil
 	})
 	return info
 }
 
-func IsDeposit(db kv.RwDB, addr types.Address) (bool, error) {
+// IsDeposit reports whether the address deposited at least the minimum deposit
+// of the schedule.
+func IsDeposit(db kv.RwDB, schedule *params.DepositSchedule, addr types.Address) (bool, error) {
 	tx, err := db.BeginRo(context.Background())
 	if nil != err {
 		return false, err
 	}
 	defer tx.Rollback()
 
-	return rawdb.IsDeposit(tx, addr), nil
+	return isDeposit(tx, schedule, addr)
+}
+
+// isDeposit is IsDeposit within tx.
+func isDeposit(tx kv.Tx, schedule *params.DepositSchedule, addr types.Address) (bool, error) {
+	if !rawdb.IsDeposit(tx, addr) {
+		return false, nil
+	}
+	_, amount, err := rawdb.GetDeposit(tx, addr)
+	if nil != err {
+		return false, err
+	}
+	return amount.ToBig().Cmp(schedule.MinDeposit) >= 0, nil
 }
 
-func SignMerge(ctx context.Context, header *block.Header, depositNum uint64) (types.Signature, []*block.Verify, error) {
+// SignMerge collects the verifier signatures of the header's state root until ctx
+// is done and aggregates them. Only signatures of the given depositors made with
+// their deposited keys are accepted, any if deposits is nil, and at least quorum
+// of them are required.
+func SignMerge(ctx context.Context, deposits rawdb.Deposits, header *block.Header, quorum uint64) (types.Signature, []*block.Verify, error) {
 	aggrSigns := make([]bls.Signature, 0)
 	verifiers := make([]*block.Verify, 0)
 	uniq := make(map[types.Address]struct{})
@@ -225,6 +251,11 @@ LOOP:
 				continue
 			}
 
+			if d, ok := deposits[s.Address]; deposits != nil && (!ok || d.PublicKey != s.PublicKey) {
+				log.Tracef("discard sign: %s is not a depositor with this key", s.Address)
+				continue
+			}
+
 			if !s.Check(header.Root) {
 				log.Tracef("discard sign: sign check failed! %v", s)
 				continue
@@ -244,13 +275,14 @@ LOOP:
 			break LOOP
 		}
 	}
-	// todo enough sigs check
-	// 1
-	// uint64(len(aggrSigns)) < depositNum/2
-	// uint64(len(aggrSigns)) < 7
-	if uint64(len(aggrSigns)) < 1 {
+	if uint64(len(aggrSigns)) < quorum {
+		log.Warn("not enough verifier signatures", "number", header.Number.Uint64(), "have", len(aggrSigns), "want", quorum)
 		return types.Signature{}, nil, consensus.ErrNotEnoughSign
 	}
+	// Without depositors there is nobody to sign
+	if len(aggrSigns) == 0 {
+		return types.Signature{}, verifiers, nil
+	}
 
 	aggS := blst.AggregateSignatures(aggrSigns)
 	var aggSign types.Signature
@@ -263,63 +295,39 @@ func MachineVerify(ctx context.Context) error {
 	blocksSub := event.GlobalFeed.Subscribe(entire)
 	defer blocksSub.Unsubscribe()
 
-	errs := make(chan error)
-	defer close(errs)
-
 	for {
 		select {
 		case b := <-entire:
 			log.Tracef("machine verify accept entire, number: %d", b.Entire.Entire.Header.Number.Uint64())
-			for k, s := range validVerifers {
-				go func(seckey string, address string) {
-					// recover private key
-					sByte, err := hex.DecodeString(seckey)
-					if nil != err {
-						errs <- err
-						return
-					}
-					var addr types.Address
-					if !addr.DecodeString(address) {
-						errs <- fmt.Errorf("unvalid address")
-						return
-					}
-
-					// before state verify
-					var hash types.Hash
-					hasher := sha3.NewLegacyKeccak256()
-					state.EncodeBeforeState(hasher, b.Entire.Entire.Snap.Items, b.Entire.Codes)
-					hasher.(crypto.KeccakState).Read(hash[:])
-					if b.Entire.Entire.Header.MixDigest != hash {
-						log.Warn("misMatch before state hash", "want:", b.Entire.Entire.Header.MixDigest, "get:", hash, b.Entire.Entire.Header.Number.Uint64())
-						return
-					}
-
-					// publicKey
-					var bs [32]byte
-					copy(bs[:], sByte)
-					pri, err := bls.SecretKeyFromRandom32Byte(bs)
-					if nil != err {
-						errs <- err
-						return
-					}
-
-					// Signature
-					sign := pri.Sign(b.Entire.Entire.Header.Root[:])
-					tmp := AggSign{Number: b.Entire.Entire.Header.Number.Uint64()}
-					copy(tmp.StateRoot[:], b.Entire.Entire.Header.Root[:])
-					copy(tmp.Sign[:], sign.Marshal())
-					copy(tmp.PublicKey[:], pri.PublicKey().Marshal())
-					tmp.Address = addr
-					// send res
-					sigChannel <- tmp
-					//log.Tracef("send verify sign, %+v", tmp)
-				}(s, k)
+			verifierLock.RLock()
+			addr, pri := verifierAddress, verifierKey
+			verifierLock.RUnlock()
+			if pri == nil {
+				continue
 			}
+			go func() {
+				// before state verify
+				var hash types.Hash
+				hasher := sha3.NewLegacyKeccak256()
+				state.EncodeBeforeState(hasher, b.Entire.Entire.Snap.Items, b.Entire.Codes)
+				hasher.(crypto.KeccakState).Read(hash[:])
+				if b.Entire.Entire.Header.MixDigest != hash {
+					log.Warn("misMatch before state hash", "want:", b.Entire.Entire.Header.MixDigest, "get:", hash, b.Entire.Entire.Header.Number.Uint64())
+					return
+				}
+
+				// Signature
+				sign := pri.Sign(b.Entire.Entire.Header.Root[:])
+				tmp := AggSign{Number: b.Entire.Entire.Header.Number.Uint64()}
+				copy(tmp.StateRoot[:], b.Entire.Entire.Header.Root[:])
+				copy(tmp.Sign[:], sign.Marshal())
+				copy(tmp.PublicKey[:], pri.PublicKey().Marshal())
+				tmp.Address = addr
+				// send res
+				sigChannel <- tmp
+			}()
 		case <-ctx.Done():
 			return nil
-		case err := <-errs:
-			return err
 		}
 	}
-	return nil
 }
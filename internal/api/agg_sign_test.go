@@ -0,0 +1,277 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/amazechain/amc/common"
+	"github.com/amazechain/amc/common/account"
+	"github.com/amazechain/amc/common/block"
+	"github.com/amazechain/amc/common/crypto/bls"
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/internal"
+	"github.com/amazechain/amc/internal/api"
+	"github.com/amazechain/amc/internal/verifier"
+	"github.com/amazechain/amc/modules"
+	event "github.com/amazechain/amc/modules/event/v2"
+	"github.com/amazechain/amc/modules/rawdb"
+	"github.com/amazechain/amc/modules/rpc/jsonrpc"
+	"github.com/amazechain/amc/modules/state"
+	"github.com/amazechain/amc/params"
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+)
+
+// testChain is the block chain seen by the verifier API in tests.
+type testChain struct {
+	common.IBlockChain
+	head block.IBlock
+}
+
+func (c *testChain) CurrentBlock() block.IBlock { return c.head }
+
+func (c *testChain) CurrentFinalizedBlock() block.IHeader { return nil }
+
+// mineBlock executes a block crediting rewards on top of parent the way the miner
+// does, and returns the block as the miner hands it to MinedBlock.
+func mineBlock(t *testing.T, db kv.RwDB, config *params.ChainConfig, parent *block.Header, rewards []*block.Reward) (*block.Header, *state.EntireCode) {
+	tx, err := db.BeginRw(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	number := parent.Number.Uint64() + 1
+	header := &block.Header{
+		ParentHash: parent.Hash(),
+		Number:     uint256.NewInt(number),
+		GasLimit:   parent.GasLimit,
+		Time:       parent.Time + 8,
+		Difficulty: uint256.NewInt(2),
+		BaseFee:    uint256.NewInt(0),
+	}
+	ibs := state.New(state.NewPlainStateReader(tx))
+	commitment, err := internal.OpenWitnessStateCommitment(tx, config, header)
+	if err != nil {
+		t.Fatalf("open state commitment: %v", err)
+	}
+	ibs.SetStateCommitment(commitment)
+	ibs.BeginWriteSnapshot()
+	ibs.BeginWriteCodes()
+	for _, reward := range rewards {
+		if !ibs.Exist(reward.Address) {
+			ibs.CreateAccount(reward.Address, false)
+		}
+		ibs.AddBalance(reward.Address, reward.Amount)
+	}
+	ibs.SoftFinalise()
+	header.Root = ibs.IntermediateRoot()
+	header.MixDigest = ibs.BeforeStateRoot()
+	if err := ibs.CommitBlock(config.Rules(number), state.NewPlainStateWriter(tx, tx, number)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := commitment.Commit(tx); err != nil {
+		t.Fatal(err)
+	}
+	rawdb.WriteHeader(tx, header)
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	return header, &state.EntireCode{
+		Entire:    state.Entire{Header: block.CopyHeader(header), Snap: ibs.Snap()},
+		Headers:   []*block.Header{parent},
+		Rewards:   rewards,
+		StateRoot: commitment.Root(),
+		TrieNodes: commitment.Witness(),
+	}
+}
+
+// receive decodes a block pushed by MinedBlock.
+func receive(t *testing.T, data []byte) *state.EntireCode {
+	entire := new(state.EntireCode)
+	if err := json.Unmarshal(data, entire); err != nil {
+		t.Fatal(err)
+	}
+	return entire
+}
+
+// Tests that blocks past the state commitment fork are sealed with the signatures
+// of verifiers re-executing them on the trie witness they are pushed with.
+func TestSealWithExternalSigns(t *testing.T) {
+	modules.AmcInit()
+	kv.ChaindataTablesCfg = modules.AmcTableCfg
+	db := memdb.New(t.TempDir())
+	defer db.Close()
+
+	config := *params.AmazeChainConfig
+	config.StateCommitmentBlock = big.NewInt(1)
+	config.VerifierQuorumBlock = big.NewInt(0)
+
+	var (
+		deposit, _ = uint256.FromBig(new(big.Int).Mul(big.NewInt(50), big.NewInt(params.AMT)))
+		contract   = types.Address{0xc0}
+		keys       = make([]bls.SecretKey, 2)
+		addrs      = []types.Address{{0x01}, {0x02}}
+	)
+	if err := db.Update(context.Background(), func(tx kv.RwTx) error {
+		w := state.NewPlainStateWriter(tx, tx, 0)
+		empty := account.NewAccount()
+		for i := range keys {
+			key, err := bls.RandKey()
+			if err != nil {
+				return err
+			}
+			keys[i] = key
+			var pub types.PublicKey
+			pub.SetBytes(key.PublicKey().Marshal())
+			if err := rawdb.PutDeposit(tx, addrs[i], pub, *deposit); err != nil {
+				return err
+			}
+			acc := account.NewAccount()
+			acc.Initialised = true
+			acc.Balance.SetUint64(uint64(i + 1))
+			if err := w.UpdateAccountData(addrs[i], &empty, &acc); err != nil {
+				return err
+			}
+		}
+		acc := account.NewAccount()
+		acc.Initialised = true
+		acc.Incarnation = 1
+		if err := w.UpdateAccountData(contract, &empty, &acc); err != nil {
+			return err
+		}
+		for i := byte(1); i <= 20; i++ {
+			if err := w.WriteAccountStorage(contract, 1, &types.Hash{i}, new(uint256.Int), uint256.NewInt(uint64(i))); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var deposits rawdb.Deposits
+	if err := db.View(context.Background(), func(tx kv.Tx) error {
+		var err error
+		deposits, err = rawdb.ReadDeposits(tx)
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	chain := &testChain{head: block.NewBlock(&block.Header{Number: uint256.NewInt(0)}, nil)}
+	s := api.NewBlockChainAPI(api.NewAPI(nil, nil, nil, chain, db, nil, nil, nil, nil, &config))
+	tokens := make([]string, len(keys))
+	for i, key := range keys {
+		nonce, err := s.VerifierChallenge(addrs[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		hash := api.ChallengeHash(addrs[i], nonce)
+		if tokens[i], err = s.VerifierLogin(context.Background(), addrs[i], nonce, key.Sign(hash[:]).Marshal()); err != nil {
+			t.Fatalf("login of %s: %v", addrs[i], err)
+		}
+	}
+
+	// the verifiers get the mined blocks from the subscription
+	server := jsonrpc.NewServer()
+	if err := server.RegisterName("eth", s); err != nil {
+		t.Fatal(err)
+	}
+	defer server.Stop()
+	client := jsonrpc.DialInProc(server)
+	defer client.Close()
+	mined := make(chan json.RawMessage, 4)
+	sub, err := client.Subscribe(context.Background(), "eth", mined, "minedBlock", tokens[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+
+	// block 1 activates the state commitment, block 2 is applied on its trie
+	parent := &block.Header{Number: uint256.NewInt(0), GasLimit: 30000000, Difficulty: uint256.NewInt(2), BaseFee: uint256.NewInt(0)}
+	for _, rewards := range [][]*block.Reward{
+		{{Address: addrs[0], Amount: uint256.NewInt(100)}, {Address: types.Address{0x03}, Amount: uint256.NewInt(7)}},
+		{{Address: addrs[1], Amount: uint256.NewInt(200)}, {Address: types.Address{0x04}, Amount: uint256.NewInt(9)}},
+	} {
+		header, entire := mineBlock(t, db, &config, parent, rewards)
+		number := header.Number.Uint64()
+		// the subscription starts listening asynchronously
+		for event.GlobalFeed.Send(common.MinedEntireEvent{Entire: *entire}) == 0 {
+			time.Sleep(10 * time.Millisecond)
+		}
+		var pushed []byte
+		select {
+		case pushed = <-mined:
+		case err := <-sub.Err():
+			t.Fatalf("block %d: subscription failed: %v", number, err)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("block %d: not pushed", number)
+		}
+		if receive(t, pushed).Entire.Header.Root != (types.Hash{}) {
+			t.Fatalf("block %d: state root pushed to the verifiers", number)
+		}
+
+		for i, key := range keys {
+			root, err := verifier.Verify(context.Background(), &config, receive(t, pushed))
+			if err != nil {
+				t.Fatalf("block %d: verify failed: %v", number, err)
+			}
+			if root != header.Root {
+				t.Fatalf("block %d: verified root %s, header %s", number, root, header.Root)
+			}
+			sign := api.AggSign{Number: number, StateRoot: root, Address: addrs[i]}
+			copy(sign.Sign[:], key.Sign(root[:]).Marshal())
+			if err := s.SubmitSign(sign, tokens[i]); err != nil {
+				t.Fatalf("block %d: submit sign of %s: %v", number, addrs[i], err)
+			}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		aggSign, verifiers, err := api.SignMerge(ctx, deposits, header, uint64(len(keys)))
+		cancel()
+		if err != nil {
+			t.Fatalf("block %d: seal failed: %v", number, err)
+		}
+		pubs := make([]bls.PublicKey, len(verifiers))
+		for i, v := range verifiers {
+			if pubs[i], err = bls.PublicKeyFromBytes(v.PublicKey[:]); err != nil {
+				t.Fatal(err)
+			}
+		}
+		sig, err := bls.SignatureFromBytes(aggSign[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(verifiers) != len(keys) || !sig.FastAggregateVerify(pubs, header.Root) {
+			t.Fatalf("block %d: invalid aggregated signature of %d verifiers", number, len(verifiers))
+		}
+
+		// the root can not be rebuilt without the witness
+		entire = receive(t, pushed)
+		entire.TrieNodes = nil
+		if _, err := verifier.Verify(context.Background(), &config, entire); err == nil {
+			t.Fatalf("block %d: verified without a state witness", number)
+		}
+		parent = header
+	}
+}
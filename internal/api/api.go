@@ -23,6 +23,7 @@ import (
 	"github.com/amazechain/amc/conf"
 	"github.com/amazechain/amc/internal"
 	"github.com/amazechain/amc/internal/api/filters"
+	"github.com/amazechain/amc/internal/tracers/logger"
 	vm2 "github.com/amazechain/amc/internal/vm"
 	"github.com/amazechain/amc/internal/vm/evmtypes"
 	event "github.com/amazechain/amc/modules/event/v2"
@@ -52,6 +53,7 @@ import (
 	"github.com/amazechain/amc/modules/rawdb"
 	"github.com/amazechain/amc/modules/rpc/jsonrpc"
 	"github.com/amazechain/amc/params"
+	"github.com/amazechain/amc/signer/core/apitypes"
 	"github.com/libp2p/go-libp2p/core/peer"
 )
 
@@ -77,6 +79,8 @@ type API struct {
 	chainConfig    *params.ChainConfig
 
 	gpo *Oracle
+
+	verifiers *verifierSessions
 }
 
 // NewAPI creates a new protocol API.
@@ -92,6 +96,7 @@ func NewAPI(pubsub common.IPubSub, p2pserver common.INetwork, peers map[peer.ID]
 		downloader:     downloader,
 		accountManager: accountManager,
 		chainConfig:    config,
+		verifiers:      newVerifierSessions(),
 	}
 }
 
@@ -128,6 +133,12 @@ func (api *API) Apis() []jsonrpc.API {
 		}, {
 			Namespace: "eth",
 			Service:   filters.NewFilterAPI(api, 5*time.Minute),
+		}, {
+			Namespace: "eth",
+			Service:   NewAccountAPI(api.accountManager),
+		}, {
+			Namespace: "personal",
+			Service:   NewPersonalAccountAPI(api, nonceLock),
 		},
 	}
 }
@@ -155,20 +166,24 @@ func (n *API) GetEvm(ctx context.Context, msg internal.Message, ibs evmtypes.Int
 	return vm2.NewEVM(context, txContext, ibs, n.GetChainConfig(), *vmConfig), vmError, nil
 }
 
-func (n *API) State(tx kv.Tx, blockNrOrHash jsonrpc.BlockNumberOrHash) evmtypes.IntraBlockState {
+func (n *API) State(tx kv.Tx, blockNrOrHash jsonrpc.BlockNumberOrHash) (evmtypes.IntraBlockState, error) {
 
 	_, blockHash, err := rpchelper.GetCanonicalBlockNumber(blockNrOrHash, tx)
 	if err != nil {
-		return nil
+		return nil, nil
 	}
 
 	blockNr := rawdb.ReadHeaderNumber(tx, blockHash)
 	if nil == blockNr {
-		return nil
+		return nil, nil
+	}
+	// the state of a block is rebuilt from the history of the following blocks
+	if err := rawdb.CheckPruned(tx, rawdb.PruneHistory, *blockNr+1); err != nil {
+		return nil, err
 	}
 
 	stateReader := state.NewPlainState(tx, *blockNr+1)
-	return state.New(stateReader)
+	return state.New(stateReader), nil
 }
 
 func (n *API) GetChainConfig() *params.ChainConfig {
@@ -265,6 +280,28 @@ func (s *AmcAPI) FeeHistory(ctx context.Context, blockCount jsonrpc.DecimalOrHex
 	return results, nil
 }
 
+// Syncing returns false in case the node is currently not syncing with the network. It can be up-to-date or has not
+// yet received the latest block headers from its pears. In case it is synchronizing:
+// - startingBlock: block number this node started to synchronize from
+// - currentBlock:  block number this node is currently importing
+// - highestBlock:  block number of the highest block header this node has received from peers
+func (s *AmcAPI) Syncing() (interface{}, error) {
+	downloader := s.api.Downloader()
+	if downloader == nil || !downloader.IsDownloading() {
+		return false, nil
+	}
+	progress := downloader.Progress()
+	// Return not syncing if the synchronisation already completed
+	if progress.CurrentBlock >= progress.HighestBlock {
+		return false, nil
+	}
+	return map[string]interface{}{
+		"startingBlock": hexutil.Uint64(progress.StartingBlock),
+		"currentBlock":  hexutil.Uint64(progress.CurrentBlock),
+		"highestBlock":  hexutil.Uint64(progress.HighestBlock),
+	}, nil
+}
+
 // TxPoolAPI offers and API for the transaction pool. It only operates on data that is non confidential.
 type TxPoolAPI struct {
 	api *API
@@ -314,17 +351,17 @@ func (s *TxPoolAPI) Inspect() map[string]map[string]map[string]string {
 // AccountAPI provides an API to access accounts managed by this node.
 // It offers only methods that can retrieve accounts.
 type AccountAPI struct {
+	am *accounts.Manager
 }
 
 // NewAccountAPI creates a new AccountAPI.
-func NewAccountAPI() *AccountAPI {
-	return &AccountAPI{}
+func NewAccountAPI(am *accounts.Manager) *AccountAPI {
+	return &AccountAPI{am: am}
 }
 
 // Accounts returns the collection of accounts this node manages.
 func (s *AccountAPI) Accounts() []types.Address {
-	//return s.am.Accounts()
-	return nil
+	return s.am.Accounts()
 }
 
 // BlockChainAPI provides an API to access Ethereum blockchain data.
@@ -350,7 +387,10 @@ func (s *BlockChainAPI) GetBalance(ctx context.Context, address mvm_common.Addre
 	}
 	defer tx.Rollback()
 
-	state := s.api.State(tx, blockNrOrHash)
+	state, err := s.api.State(tx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
 	if state == nil {
 		return nil, nil
 	}
@@ -372,7 +412,10 @@ func (s *BlockChainAPI) GetCode(ctx context.Context, address mvm_common.Address,
 	}
 	defer tx.Rollback()
 
-	state := s.api.State(tx, blockNrOrHash)
+	state, err := s.api.State(tx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
 	if state == nil {
 		return nil, nil
 	}
@@ -390,7 +433,10 @@ func (s *BlockChainAPI) GetStorageAt(ctx context.Context, address types.Address,
 	}
 	defer tx.Rollback()
 
-	state := s.api.State(tx, blockNrOrHash)
+	state, err := s.api.State(tx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
 	if state == nil {
 		return nil, nil
 	}
@@ -442,6 +488,68 @@ type StorageResult struct {
 	Proof []string     `json:"proof"`
 }
 
+// GetProof returns the Merkle proofs of an account and of some of its storage slots
+// against the state root of the given block. Proofs are only available for blocks
+// from the state commitment fork on.
+func (s *BlockChainAPI) GetProof(ctx context.Context, address types.Address, storageKeys []string, blockNrOrHash jsonrpc.BlockNumberOrHash) (*AccountResult, error) {
+	tx, err := s.api.db.BeginRo(ctx)
+	if nil != err {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	_, blockHash, err := rpchelper.GetCanonicalBlockNumber(blockNrOrHash, tx)
+	if nil != err {
+		return nil, err
+	}
+	blockNr := rawdb.ReadHeaderNumber(tx, blockHash)
+	if nil == blockNr {
+		return nil, fmt.Errorf("header for hash %x not found", blockHash)
+	}
+	if !s.api.GetChainConfig().IsStateCommitment(*blockNr) {
+		return nil, fmt.Errorf("state commitment is not active at block %d", *blockNr)
+	}
+	header := rawdb.ReadHeader(tx, blockHash, *blockNr)
+	if nil == header {
+		return nil, fmt.Errorf("header for hash %x not found", blockHash)
+	}
+
+	keys := make([]types.Hash, len(storageKeys))
+	for i, key := range storageKeys {
+		keys[i] = types.HexToHash(key)
+	}
+	proof, err := state.ProveAccount(tx, header.Root, address, keys)
+	if nil != err {
+		return nil, err
+	}
+
+	result := &AccountResult{
+		Address:      address,
+		AccountProof: encodeProof(proof.Proof),
+		Balance:      (*hexutil.Big)(proof.Balance.ToBig()),
+		CodeHash:     proof.CodeHash,
+		Nonce:        hexutil.Uint64(proof.Nonce),
+		StorageHash:  proof.StorageHash,
+		StorageProof: make([]StorageResult, len(proof.StorageProof)),
+	}
+	for i, sp := range proof.StorageProof {
+		result.StorageProof[i] = StorageResult{
+			Key:   storageKeys[i],
+			Value: (*hexutil.Big)(sp.Value.ToBig()),
+			Proof: encodeProof(sp.Proof),
+		}
+	}
+	return result, nil
+}
+
+func encodeProof(proof [][]byte) []string {
+	enc := make([]string, len(proof))
+	for i, node := range proof {
+		enc[i] = hexutil.Encode(node)
+	}
+	return enc
+}
+
 // // OverrideAccount indicates the overriding fields of account during the execution
 // // of a message call.
 // // Note, state and stateDiff can't be specified at the same time. If state is
@@ -548,11 +656,7 @@ func DoCall(ctx context.Context, api *API, args TransactionArgs, blockNrOrHash j
 	var header block.IHeader
 	var err error
 	if blockNr, ok := blockNrOrHash.Number(); ok {
-		if blockNr < jsonrpc.EarliestBlockNumber {
-			header = api.BlockChain().CurrentBlock().Header()
-		} else {
-			header = api.BlockChain().GetHeaderByNumber(uint256.NewInt(uint64(blockNr.Int64())))
-		}
+		header, err = HeaderByNumber(blockNr, api)
 	}
 	if hash, ok := blockNrOrHash.Hash(); ok {
 		header, err = api.BlockChain().GetHeaderByHash(hash)
@@ -569,7 +673,10 @@ func DoCall(ctx context.Context, api *API, args TransactionArgs, blockNrOrHash j
 
 	//reader := state.NewPlainStateReader(tx)
 	//ibs := state.New(reader)
-	ibs := api.State(tx, blockNrOrHash)
+	ibs, err := api.State(tx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
 	if ibs == nil {
 		return nil, errors.New("cannot load state")
 	}
@@ -674,6 +781,37 @@ func (s *BlockChainAPI) Call(ctx context.Context, args TransactionArgs, blockNrO
 	return result.Return(), result.Err
 }
 
+var (
+	errSafeNotFound      = errors.New("safe block not found")
+	errFinalizedNotFound = errors.New("finalized block not found")
+)
+
+// finalityHeader returns the header the safe or finalized tag currently
+// points at.
+func finalityHeader(bc common.IBlockChain, number jsonrpc.BlockNumber) (block.IHeader, error) {
+	if number == jsonrpc.SafeBlockNumber {
+		if header := bc.CurrentSafeBlock(); header != nil {
+			return header, nil
+		}
+		return nil, errSafeNotFound
+	}
+	if header := bc.CurrentFinalizedBlock(); header != nil {
+		return header, nil
+	}
+	return nil, errFinalizedNotFound
+}
+
+// HeaderByNumber resolves a block number or tag to a canonical header.
+func HeaderByNumber(number jsonrpc.BlockNumber, n *API) (block.IHeader, error) {
+	switch number {
+	case jsonrpc.PendingBlockNumber, jsonrpc.LatestBlockNumber:
+		return n.BlockChain().CurrentBlock().Header(), nil
+	case jsonrpc.SafeBlockNumber, jsonrpc.FinalizedBlockNumber:
+		return finalityHeader(n.BlockChain(), number)
+	}
+	return n.BlockChain().GetHeaderByNumber(uint256.NewInt(uint64(number))), nil
+}
+
 func BlockByNumber(ctx context.Context, number jsonrpc.BlockNumber, n *API) (block.IBlock, error) {
 	// todo
 	// Pending block is only known by the miner
@@ -686,6 +824,13 @@ func BlockByNumber(ctx context.Context, number jsonrpc.BlockNumber, n *API) (blo
 		iblock := n.BlockChain().CurrentBlock()
 		return iblock, nil
 	}
+	if number == jsonrpc.SafeBlockNumber || number == jsonrpc.FinalizedBlockNumber {
+		header, err := finalityHeader(n.BlockChain(), number)
+		if err != nil {
+			return nil, err
+		}
+		return n.BlockChain().GetBlock(header.Hash(), header.Number64().Uint64()), nil
+	}
 	iblock, err := n.BlockChain().GetBlockByNumber(uint256.NewInt(uint64(number)))
 	if err != nil {
 		return nil, err
@@ -813,7 +958,10 @@ func DoEstimateGas(ctx context.Context, n *API, args TransactionArgs, blockNrOrH
 			return 0, err
 		}
 		defer tx.Rollback()
-		statedb := n.State(tx, blockNrOrHash)
+		statedb, err := n.State(tx, blockNrOrHash)
+		if err != nil {
+			return 0, err
+		}
 		if statedb == nil {
 			return 0, errors.New("cannot load stateDB")
 		}
@@ -919,12 +1067,7 @@ func (s *BlockChainAPI) GetBlockByNumber(ctx context.Context, number jsonrpc.Blo
 		err   error
 	)
 	// header
-	if number == jsonrpc.LatestBlockNumber {
-		block = s.api.BlockChain().CurrentBlock()
-		err = nil
-	} else {
-		block, err = s.api.BlockChain().GetBlockByNumber(uint256.NewInt(uint64(number.Int64())))
-	}
+	block, err = BlockByNumber(ctx, number, s.api)
 
 	if block != nil && err == nil {
 		response, err := RPCMarshalBlock(block, s.api.BlockChain(), true, fullTx)
@@ -950,17 +1093,175 @@ func (s *BlockChainAPI) GetBlockByHash(ctx context.Context, hash mvm_common.Hash
 	return nil, err
 }
 
-func (s *BlockChainAPI) MinedBlock(ctx context.Context, address types.Address) (*jsonrpc.Subscription, error) {
+// GetHeaderByNumber returns the requested canonical block header.
+//   - When blockNr is -1 the chain head is returned.
+//   - When blockNr is -2 the pending chain head is returned.
+func (s *BlockChainAPI) GetHeaderByNumber(ctx context.Context, number jsonrpc.BlockNumber) (map[string]interface{}, error) {
+	header, err := HeaderByNumber(number, s.api)
+	if header != nil && err == nil {
+		response := RPCMarshalHeader(header)
+		if number == jsonrpc.PendingBlockNumber {
+			// Pending header need to nil out a few fields
+			for _, field := range []string{"hash", "nonce", "miner"} {
+				response[field] = nil
+			}
+		}
+		return response, err
+	}
+	return nil, err
+}
+
+// GetHeaderByHash returns the requested header by hash.
+func (s *BlockChainAPI) GetHeaderByHash(ctx context.Context, hash mvm_common.Hash) map[string]interface{} {
+	header, _ := s.api.BlockChain().GetHeaderByHash(mvm_types.ToAmcHash(hash))
+	if header != nil {
+		return RPCMarshalHeader(header)
+	}
+	return nil
+}
+
+// GetBlockReceipts returns the receipts of all transactions in the given block.
+func (s *BlockChainAPI) GetBlockReceipts(ctx context.Context, blockNrOrHash jsonrpc.BlockNumberOrHash) ([]map[string]interface{}, error) {
+	iblock, err := BlockByNumberOrHash(ctx, blockNrOrHash, s.api)
+	if iblock == nil || err != nil {
+		// When the block doesn't exist, the RPC method should return JSON null
+		// as per specification.
+		return nil, nil
+	}
+	receipts, err := s.api.BlockChain().GetReceipts(iblock.Hash())
+	if err != nil {
+		return nil, err
+	}
+	txs := iblock.Transactions()
+	if len(txs) != len(receipts) {
+		return nil, fmt.Errorf("receipts length mismatch: %d vs %d", len(txs), len(receipts))
+	}
+	result := make([]map[string]interface{}, len(receipts))
+	for i, receipt := range receipts {
+		result[i] = marshalReceipt(receipt, txs[i], iblock.Hash(), iblock.Number64().Uint64(), uint64(i), iblock.Header())
+	}
+	return result, nil
+}
+
+// accessListResult returns an optional accesslist
+// It's the result of the `debug_createAccessList` RPC call.
+// It contains an error if the transaction itself failed.
+type accessListResult struct {
+	Accesslist *mvm_types.AccessList `json:"accessList"`
+	Error      string                `json:"error,omitempty"`
+	GasUsed    hexutil.Uint64        `json:"gasUsed"`
+}
+
+// CreateAccessList creates an EIP-2930 type AccessList for the given transaction.
+// Reexec and BlockNrOrHash can be specified to create the accessList on top of a certain state.
+func (s *BlockChainAPI) CreateAccessList(ctx context.Context, args TransactionArgs, blockNrOrHash *jsonrpc.BlockNumberOrHash) (*accessListResult, error) {
+	bNrOrHash := jsonrpc.BlockNumberOrHashWithNumber(jsonrpc.PendingBlockNumber)
+	if blockNrOrHash != nil {
+		bNrOrHash = *blockNrOrHash
+	}
+	acl, gasUsed, vmerr, err := AccessList(ctx, s.api, bNrOrHash, args)
+	if err != nil {
+		return nil, err
+	}
+	result := &accessListResult{Accesslist: &acl, GasUsed: hexutil.Uint64(gasUsed)}
+	if vmerr != nil {
+		result.Error = vmerr.Error()
+	}
+	return result, nil
+}
+
+// AccessList creates an access list for the given transaction.
+// If the accesslist creation fails an error is returned.
+// If the transaction itself fails, an vmErr is returned.
+func AccessList(ctx context.Context, api *API, blockNrOrHash jsonrpc.BlockNumberOrHash, args TransactionArgs) (acl mvm_types.AccessList, gasUsed uint64, vmErr error, err error) {
+	// Retrieve the execution context
+	iblock, err := BlockByNumberOrHash(ctx, blockNrOrHash, api)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	if iblock == nil {
+		return nil, 0, nil, errors.New("block not found")
+	}
+	header := iblock.Header()
+	// If the gas amount is not set, default to RPC gas cap.
+	if args.Gas == nil {
+		tmp := hexutil.Uint64(rpcGasCap)
+		args.Gas = &tmp
+	}
+
+	// Ensure any missing fields are filled, extract the recipient and input data
+	if err := args.setDefaults(ctx, api); err != nil {
+		return nil, 0, nil, err
+	}
+	var to types.Address
+	if args.To != nil {
+		to = *mvm_types.ToAmcAddress(args.To)
+	} else {
+		to = crypto.CreateAddress(args.from(), uint64(*args.Nonce))
+	}
+	// Retrieve the precompiles since they don't need to be added to the access list
+	precompiles := vm2.ActivePrecompiles(api.GetChainConfig().Rules(header.Number64().Uint64()))
+
+	tx, err := api.db.BeginRo(ctx)
+	if nil != err {
+		return nil, 0, nil, err
+	}
+	defer tx.Rollback()
+
+	// Create an initial tracer
+	prevTracer := logger.NewAccessListTracer(nil, args.from(), to, precompiles)
+	if args.AccessList != nil {
+		prevTracer = logger.NewAccessListTracer(mvm_types.ToAmcAccessList(*args.AccessList), args.from(), to, precompiles)
+	}
+	for {
+		// Retrieve the current access list to expand
+		accessList := mvm_types.FromAmcAccessList(prevTracer.AccessList())
+		log.Trace("Creating access list", "input", accessList)
+
+		// Copy the original db so we don't modify it
+		statedb, err := api.State(tx, blockNrOrHash)
+		if err != nil {
+			return nil, 0, nil, err
+		}
+		if statedb == nil {
+			return nil, 0, nil, errors.New("cannot load state")
+		}
+
+		// Set the accesslist to the last al
+		args.AccessList = &accessList
+		msg, err := args.ToMessage(rpcGasCap, header.BaseFee64().ToBig())
+		if err != nil {
+			return nil, 0, nil, err
+		}
+
+		// Apply the transaction with the access list tracer
+		tracer := logger.NewAccessListTracer(mvm_types.ToAmcAccessList(accessList), args.from(), to, precompiles)
+		evm, _, err := api.GetEvm(ctx, msg, statedb, header, &vm2.Config{Tracer: tracer, Debug: true, NoBaseFee: true})
+		if err != nil {
+			return nil, 0, nil, err
+		}
+		res, err := internal.ApplyMessage(evm, msg, new(common.GasPool).AddGas(msg.Gas()), true, false)
+		if err != nil {
+			return nil, 0, nil, fmt.Errorf("failed to apply transaction: %v err: %v", args.toTransaction().Hash(), err)
+		}
+		if tracer.Equal(prevTracer) {
+			return accessList, res.UsedGas, res.Err, nil
+		}
+		prevTracer = tracer
+	}
+}
+
+// MinedBlock pushes the blocks mined by this node to the verifier of the session
+// token obtained with VerifierLogin.
+func (s *BlockChainAPI) MinedBlock(ctx context.Context, token string) (*jsonrpc.Subscription, error) {
 	notifier, supported := jsonrpc.NotifierFromContext(ctx)
 	if !supported {
 		return &jsonrpc.Subscription{}, jsonrpc.ErrNotificationsUnsupported
 	}
 
-	if is, err := IsDeposit(s.api.db, address); nil != err || !is {
-		if nil != err {
-			log.Errorf("IsDeposit(%s) failed, err= %v", address, err)
-		}
-		return &jsonrpc.Subscription{}, fmt.Errorf("unauthed address: %s", address)
+	address, err := s.sessionVerifier(token)
+	if nil != err {
+		return &jsonrpc.Subscription{}, err
 	}
 
 	rpcSub := notifier.CreateSubscription()
@@ -970,6 +1271,13 @@ func (s *BlockChainAPI) MinedBlock(ctx context.Context, address types.Address) (
 		for {
 			select {
 			case b := <-entire:
+				s.api.verifiers.push(b.Entire.Entire.Header.Number.Uint64(), b.Entire.Entire.Header.Root)
+				// Stop pushing to a verifier that withdrew its deposit
+				if _, err := s.sessionVerifier(token); nil != err {
+					log.Debug("Ending mined block subscription", "addr", address, "err", err)
+					blocksSub.Unsubscribe()
+					return
+				}
 				var pushData state.EntireCode
 				pushData.Entire = b.Entire.Entire.Clone()
 				pushData.Entire.Header.Root = types.Hash{}
@@ -977,6 +1285,8 @@ func (s *BlockChainAPI) MinedBlock(ctx context.Context, address types.Address) (
 				pushData.Codes = b.Entire.Codes
 				pushData.Rewards = b.Entire.Rewards
 				pushData.CoinBase = b.Entire.CoinBase
+				pushData.StateRoot = b.Entire.StateRoot
+				pushData.TrieNodes = b.Entire.TrieNodes
 				log.Trace("send mining block", "addr", address, "blockNr", b.Entire.Entire.Header.Number.Hex(), "blockTime", time.Unix(int64(b.Entire.Entire.Header.Time), 0).Format(time.RFC3339))
 				notifier.Notify(rpcSub.ID, pushData)
 			case <-rpcSub.Err():
@@ -991,12 +1301,42 @@ func (s *BlockChainAPI) MinedBlock(ctx context.Context, address types.Address) (
 	return rpcSub, nil
 }
 
-func (s *BlockChainAPI) SubmitSign(sign AggSign) error {
-	info := DepositInfo(s.api.db, sign.Address)
+// SubmitSign hands the state root signature of a mined block to the miner. The
+// signer is the verifier of the session token and the block one pushed to the
+// verifiers. A repeated signature is dropped, and at a finalized height only the
+// canonical block can be signed.
+func (s *BlockChainAPI) SubmitSign(sign AggSign, token string) error {
+	address, err := s.sessionVerifier(token)
+	if nil != err {
+		return err
+	}
+	if sign.Address != address {
+		verifierUnauthedCounter.Inc(1)
+		return fmt.Errorf("session of %s can not sign for %s", address, sign.Address)
+	}
+	info := DepositInfo(s.api.db, s.api.chainConfig.DepositScheduleAt(sign.Number), sign.Address)
 	if nil == info {
 		return fmt.Errorf("unauthed address: %s", sign.Address)
 	}
 	sign.PublicKey.SetBytes(info.PublicKey.Bytes())
+	if !sign.Check(sign.StateRoot) {
+		verifierUnauthedCounter.Inc(1)
+		return fmt.Errorf("invalid sign of block %d by %s", sign.Number, sign.Address)
+	}
+	if !s.api.verifiers.wasPushed(sign.Number, sign.StateRoot) {
+		return fmt.Errorf("no mined block %d with state root %s", sign.Number, sign.StateRoot)
+	}
+	// A finalized height only takes the signature of its canonical block
+	if final := s.api.BlockChain().CurrentFinalizedBlock(); final != nil && sign.Number <= final.Number64().Uint64() {
+		header, ok := s.api.BlockChain().GetHeaderByNumber(uint256.NewInt(sign.Number)).(*block.Header)
+		if !ok || header.Root != sign.StateRoot {
+			verifierDuplicateCounter.Inc(1)
+			return fmt.Errorf("sign of block %d by %s conflicts with the finalized block", sign.Number, sign.Address)
+		}
+	}
+	if !s.api.verifiers.markSigned(sign.Address, sign.Number, sign.StateRoot) {
+		return nil
+	}
 	go func() {
 		sigChannel <- sign
 	}()
@@ -1030,7 +1370,10 @@ func (s *TransactionAPI) GetTransactionCount(ctx context.Context, address mvm_co
 	}
 	defer tx.Rollback()
 
-	state := s.api.State(tx, blockNrOrHash)
+	state, err := s.api.State(tx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
 	if state == nil {
 		return nil, nil
 	}
@@ -1115,13 +1458,25 @@ func (s *TransactionAPI) GetTransactionReceipt(ctx context.Context, hash mvm_com
 	if len(receipts) <= int(index) {
 		return nil, nil
 	}
-	receipt := receipts[index]
+	header, err := s.api.BlockChain().GetHeaderByHash(blockHash)
+	if err != nil {
+		return nil, err
+	}
+	fields := marshalReceipt(receipts[index], tx, blockHash, blockNumber, index, header)
+
+	//json, _ := json.Marshal(fields)
+	//log.Infof("GetTransactionReceipt, result %s", string(json))
+	return fields, nil
+}
 
+// marshalReceipt converts a receipt into the RPC representation shared by
+// eth_getTransactionReceipt and eth_getBlockReceipts.
+func marshalReceipt(receipt *block.Receipt, tx *transaction.Transaction, blockHash types.Hash, blockNumber uint64, index uint64, header block.IHeader) map[string]interface{} {
 	from := tx.From()
 	fields := map[string]interface{}{
 		"blockHash":         mvm_types.FromAmcHash(blockHash),
 		"blockNumber":       hexutil.Uint64(blockNumber),
-		"transactionHash":   hash,
+		"transactionHash":   mvm_types.FromAmcHash(tx.Hash()),
 		"transactionIndex":  hexutil.Uint64(index),
 		"from":              mvm_types.FromAmcAddress(from),
 		"to":                mvm_types.FromAmcAddress(tx.To()),
@@ -1132,17 +1487,8 @@ func (s *TransactionAPI) GetTransactionReceipt(ctx context.Context, hash mvm_com
 		"type":              hexutil.Uint(tx.Type()),
 	}
 	// Assign the effective gas price paid
-	//todo !IsLondon
-	if false {
-		fields["effectiveGasPrice"] = hexutil.Uint64(tx.GasPrice().Uint64())
-	} else {
-		header, err := s.api.BlockChain().GetHeaderByHash(blockHash)
-		if err != nil {
-			return nil, err
-		}
-		gasPrice := new(big.Int).Add(header.BaseFee64().ToBig(), tx.EffectiveGasTipValue(header.BaseFee64()).ToBig())
-		fields["effectiveGasPrice"] = hexutil.Uint64(gasPrice.Uint64())
-	}
+	gasPrice := new(big.Int).Add(header.BaseFee64().ToBig(), tx.EffectiveGasTipValue(header.BaseFee64()).ToBig())
+	fields["effectiveGasPrice"] = hexutil.Uint64(gasPrice.Uint64())
 	// Assign receipt status or post state.
 	if len(receipt.PostState) > 0 {
 		fields["root"] = hexutil.Bytes(receipt.PostState)
@@ -1158,10 +1504,7 @@ func (s *TransactionAPI) GetTransactionReceipt(ctx context.Context, hash mvm_com
 	if !receipt.ContractAddress.IsNull() {
 		fields["contractAddress"] = mvm_types.FromAmcAddress(&receipt.ContractAddress)
 	}
-
-	//json, _ := json.Marshal(fields)
-	//log.Infof("GetTransactionReceipt, result %s", string(json))
-	return fields, nil
+	return fields
 }
 
 // GetBlockTransactionCountByHash returns the number of transactions in the block with the given hash.
@@ -1227,6 +1570,53 @@ func (s *TransactionAPI) GetTransactionByBlockHashAndIndex(ctx context.Context,
 	return nil
 }
 
+// GetBlockTransactionCountByNumber returns the number of transactions in the block with the given block number.
+func (s *TransactionAPI) GetBlockTransactionCountByNumber(ctx context.Context, blockNr jsonrpc.BlockNumber) *hexutil.Uint {
+	if block, _ := BlockByNumber(ctx, blockNr, s.api); block != nil {
+		n := hexutil.Uint(len(block.Transactions()))
+		return &n
+	}
+	return nil
+}
+
+// GetTransactionByBlockNumberAndIndex returns the transaction for the given block number and index.
+func (s *TransactionAPI) GetTransactionByBlockNumberAndIndex(ctx context.Context, blockNr jsonrpc.BlockNumber, index hexutil.Uint) *RPCTransaction {
+	if block, _ := BlockByNumber(ctx, blockNr, s.api); block != nil {
+		txs := block.Transactions()
+		if uint64(index) >= uint64(len(txs)) {
+			return nil
+		}
+		return newRPCTransaction(txs[index], block.Hash(), block.Number64().Uint64(), uint64(index), block.Header().BaseFee64().ToBig())
+	}
+	return nil
+}
+
+// GetRawTransactionByHash returns the bytes of the transaction for the given hash.
+func (s *TransactionAPI) GetRawTransactionByHash(ctx context.Context, hash mvm_common.Hash) (hexutil.Bytes, error) {
+	var tx *transaction.Transaction
+	if err := s.api.Database().View(ctx, func(t kv.Tx) error {
+		var err error
+		tx, _, _, _, err = rawdb.ReadTransactionByHash(t, mvm_types.ToAmcHash(hash))
+		return err
+	}); nil != err {
+		return nil, err
+	}
+	if tx == nil {
+		if tx = s.api.TxsPool().GetTx(mvm_types.ToAmcHash(hash)); tx == nil {
+			// Transaction not found anywhere, abort
+			return nil, nil
+		}
+	}
+	return marshalRawTransaction(tx)
+}
+
+// marshalRawTransaction returns the canonical ethereum encoding of tx.
+func marshalRawTransaction(tx *transaction.Transaction) (hexutil.Bytes, error) {
+	ethTx := new(mvm_types.Transaction)
+	ethTx.FromAmcTransaction(tx)
+	return ethTx.MarshalBinary()
+}
+
 // SubmitTransaction ?
 func SubmitTransaction(ctx context.Context, api *API, tx *transaction.Transaction) (mvm_common.Hash, error) {
 
@@ -1278,6 +1668,92 @@ func (s *TransactionAPI) SendTransaction(ctx context.Context, args TransactionAr
 	return SubmitTransaction(ctx, s.api, signed)
 }
 
+// SignTransactionResult represents a RLP encoded signed transaction.
+type SignTransactionResult struct {
+	Raw hexutil.Bytes   `json:"raw"`
+	Tx  *RPCTransaction `json:"tx"`
+}
+
+// SignTransaction will sign the given transaction with the from account.
+// The node needs to have the private key of the account corresponding with
+// the given from address and it needs to be unlocked.
+func (s *TransactionAPI) SignTransaction(ctx context.Context, args TransactionArgs) (*SignTransactionResult, error) {
+	if args.Gas == nil {
+		return nil, errors.New("gas not specified")
+	}
+	if args.GasPrice == nil && (args.MaxPriorityFeePerGas == nil || args.MaxFeePerGas == nil) {
+		return nil, errors.New("missing gasPrice or maxFeePerGas/maxPriorityFeePerGas")
+	}
+	if args.Nonce == nil {
+		return nil, errors.New("nonce not specified")
+	}
+	account := accounts.Account{Address: args.from()}
+	wallet, err := s.api.accountManager.Find(account)
+	if err != nil {
+		return nil, err
+	}
+	if err := args.setDefaults(ctx, s.api); err != nil {
+		return nil, err
+	}
+	signed, err := wallet.SignTx(account, args.toTransaction(), s.api.GetChainConfig().ChainID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := marshalRawTransaction(signed)
+	if err != nil {
+		return nil, err
+	}
+	return &SignTransactionResult{data, newRPCPendingTransaction(signed, s.api.BlockChain().CurrentBlock().Header())}, nil
+}
+
+// Sign calculates an ECDSA signature for:
+// keccak256("\x19Ethereum Signed Message:\n" + len(message) + message).
+//
+// Note, the produced signature conforms to the secp256k1 curve R, S and V values,
+// where the V value will be 27 or 28 for legacy reasons.
+//
+// The account associated with addr must be unlocked.
+//
+// https://github.com/ethereum/wiki/wiki/JSON-RPC#eth_sign
+func (s *TransactionAPI) Sign(addr types.Address, data hexutil.Bytes) (hexutil.Bytes, error) {
+	// Look up the wallet containing the requested signer
+	account := accounts.Account{Address: addr}
+
+	wallet, err := s.api.accountManager.Find(account)
+	if err != nil {
+		return nil, err
+	}
+	// Sign the requested hash with the wallet
+	signature, err := wallet.SignText(account, data)
+	if err == nil {
+		signature[crypto.RecoveryIDOffset] += 27 // Transform V from 0/1 to 27/28 according to the yellow paper
+	}
+	return signature, err
+}
+
+// SignTypedData_v4 calculates an ECDSA signature over the EIP-712 digest of
+// the given typed data. The account associated with addr must be unlocked.
+func (s *TransactionAPI) SignTypedData_v4(addr types.Address, typedData apitypes.TypedData) (hexutil.Bytes, error) {
+	account := accounts.Account{Address: addr}
+
+	wallet, err := s.api.accountManager.Find(account)
+	if err != nil {
+		return nil, err
+	}
+	_, rawData, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return nil, err
+	}
+	// The wallet hashes the raw "\x19\x01" ‖ domainSeparator ‖ hashStruct(message)
+	// payload itself, yielding the EIP-712 digest.
+	signature, err := wallet.SignData(account, accounts.MimetypeTypedData, []byte(rawData))
+	if err != nil {
+		return nil, err
+	}
+	signature[crypto.RecoveryIDOffset] += 27 // Transform V from 0/1 to 27/28 according to the yellow paper
+	return signature, nil
+}
+
 // checkTxFee  todo
 func checkTxFee(gasPrice uint256.Int, gas uint64, cap float64) error {
 	return nil
@@ -1313,16 +1789,32 @@ func NewDebugAPI(api *API) *DebugAPI {
 	return &DebugAPI{api: api}
 }
 
-// SetHead rewinds the head of the blockchain to a previous block.
-func (api *DebugAPI) SetHead(number hexutil.Uint64) {
+// SetHead rewinds the head of the blockchain to a previous block, unwinding the
+// state and dropping everything written by the later blocks.
+func (api *DebugAPI) SetHead(number hexutil.Uint64) error {
 	api.api.Downloader().Close()
-	api.api.BlockChain().SetHead(uint64(number))
+	return api.api.BlockChain().SetHead(uint64(number))
 }
 
 func (debug *DebugAPI) GetAccount(ctx context.Context, address types.Address) {
 
 }
 
+// BlockStats returns the per-stage processing times of up to count recently
+// imported or mined blocks, newest first. All retained stats are returned if
+// count is omitted.
+func (api *DebugAPI) BlockStats(count *hexutil.Uint64) ([]*internal.BlockStats, error) {
+	bc, ok := api.api.BlockChain().(*internal.BlockChain)
+	if !ok {
+		return nil, errors.New("block stats are not available")
+	}
+	var n int
+	if count != nil {
+		n = int(*count)
+	}
+	return bc.BlockStats(n), nil
+}
+
 // NetAPI offers network related RPC methods
 type NetAPI struct {
 	api            *API
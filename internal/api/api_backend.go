@@ -68,26 +68,13 @@ func (b *API) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*type
 	if number == rpc.LatestBlockNumber {
 		return b.bc.CurrentBlock().Header().(*types.Header), nil
 	}
-	//if number == rpc.FinalizedBlockNumber {
-	//	if !b.eth.Merger().TDDReached() {
-	//		return nil, errors.New("'finalized' tag not supported on pre-merge network")
-	//	}
-	//	block := b.eth.blockchain.CurrentFinalBlock()
-	//	if block != nil {
-	//		return block, nil
-	//	}
-	//	return nil, errors.New("finalized block not found")
-	//}
-	//if number == rpc.SafeBlockNumber {
-	//	if !b.eth.Merger().TDDReached() {
-	//		return nil, errors.New("'safe' tag not supported on pre-merge network")
-	//	}
-	//	block := b.eth.blockchain.CurrentSafeBlock()
-	//	if block != nil {
-	//		return block, nil
-	//	}
-	//	return nil, errors.New("safe block not found")
-	//}
+	if number == rpc.SafeBlockNumber || number == rpc.FinalizedBlockNumber {
+		header, err := finalityHeader(b.bc, number)
+		if err != nil {
+			return nil, err
+		}
+		return header.(*types.Header), nil
+	}
 	return b.bc.GetHeaderByNumber(uint256.NewInt(uint64(number.Int64()))).(*types.Header), nil
 }
 
@@ -124,20 +111,13 @@ func (b *API) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*types
 		header := b.bc.CurrentBlock()
 		return b.bc.GetBlock(header.Hash(), header.Number64().Uint64()).(*types.Block), nil
 	}
-	//if number == rpc.FinalizedBlockNumber {
-	//	if !b.eth.Merger().TDDReached() {
-	//		return nil, errors.New("'finalized' tag not supported on pre-merge network")
-	//	}
-	//	header := b.eth.blockchain.CurrentFinalBlock()
-	//	return b.eth.blockchain.GetBlock(header.Hash(), header.Number.Uint64()), nil
-	//}
-	//if number == rpc.SafeBlockNumber {
-	//	//if !b.eth.Merger().TDDReached() {
-	//	//	return nil, errors.New("'safe' tag not supported on pre-merge network")
-	//	//}
-	//	header := b.BlockChain().CurrentBlock()
-	//	return b.eth.blockchain.GetBlock(header.Hash(), header.Number.Uint64()), nil
-	//}
+	if number == rpc.SafeBlockNumber || number == rpc.FinalizedBlockNumber {
+		header, err := finalityHeader(b.bc, number)
+		if err != nil {
+			return nil, err
+		}
+		return b.bc.GetBlock(header.Hash(), header.Number64().Uint64()).(*types.Block), nil
+	}
 	iBlock, err := b.bc.GetBlockByNumber(uint256.NewInt(uint64(number)))
 	if nil != err {
 		return nil, err
@@ -517,6 +497,9 @@ func (eth *API) StateAtBlock(ctx context.Context, tx kv.Tx, blk *types.Block) (s
 	// The state is available in live database, create a reference
 	// on top to prevent garbage collection and return a release
 	// function to deref it.
+	if err := rawdb.CheckPruned(tx, rawdb.PruneHistory, origin+1); err != nil {
+		return nil, err
+	}
 	statedb = eth.BlockChain().StateAt(tx, origin)
 	//statedb.Database().TrieDB().Reference(block.Root(), common.Hash{})
 	return statedb, nil
@@ -0,0 +1,158 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"encoding/json"
+	"math/big"
+	"sort"
+	"testing"
+
+	"github.com/amazechain/amc/common/block"
+	"github.com/amazechain/amc/common/crypto"
+	"github.com/amazechain/amc/common/transaction"
+	"github.com/amazechain/amc/common/types"
+	mvm_types "github.com/amazechain/amc/internal/avm/types"
+	"github.com/amazechain/amc/params"
+	"github.com/holiman/uint256"
+)
+
+// jsonKeys returns the sorted top level keys v serializes to.
+func jsonKeys(t *testing.T, v interface{}) []string {
+	blob, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(blob, &fields); err != nil {
+		t.Fatal(err)
+	}
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// missingKeys returns the keys of want that are not in have.
+func missingKeys(have []string, want []string) []string {
+	set := make(map[string]struct{}, len(have))
+	for _, key := range have {
+		set[key] = struct{}{}
+	}
+	var missing []string
+	for _, key := range want {
+		if _, ok := set[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
+
+var testChainID = big.NewInt(97)
+
+// signedTestTxs returns one signed transaction of every supported type.
+func signedTestTxs(t *testing.T) []*transaction.Transaction {
+	key, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	to := types.HexToAddress("0x000000000000000000000000000000000000dead")
+	signer := transaction.LatestSignerForChainID(testChainID)
+	acl := transaction.AccessList{{Address: to, StorageKeys: []types.Hash{types.HexToHash("0x01")}}}
+
+	inners := []transaction.TxData{
+		&transaction.LegacyTx{Nonce: 1, GasPrice: uint256.NewInt(2), Gas: 21000, To: &to, From: &from, Value: uint256.NewInt(3)},
+		&transaction.AccessListTx{ChainID: uint256.NewInt(97), Nonce: 2, GasPrice: uint256.NewInt(2), Gas: 30000, To: &to, From: &from, Value: uint256.NewInt(3), AccessList: acl},
+		&transaction.DynamicFeeTx{ChainID: uint256.NewInt(97), Nonce: 3, GasTipCap: uint256.NewInt(1), GasFeeCap: uint256.NewInt(5), Gas: 30000, From: &from, Value: uint256.NewInt(0), Data: []byte{0x60, 0x00}},
+	}
+	txs := make([]*transaction.Transaction, len(inners))
+	for i, inner := range inners {
+		tx, err := transaction.SignNewTx(key, signer, inner)
+		if err != nil {
+			t.Fatal(err)
+		}
+		txs[i] = tx
+	}
+	return txs
+}
+
+// Tests that the raw encoding returned by eth_getRawTransactionByHash and
+// eth_signTransaction decodes back into the same transaction and sender.
+func TestRawTransactionRoundTrip(t *testing.T) {
+	for _, tx := range signedTestTxs(t) {
+		raw, err := marshalRawTransaction(tx)
+		if err != nil {
+			t.Fatalf("type %d: encode failed: %v", tx.Type(), err)
+		}
+		decoded := new(mvm_types.Transaction)
+		if err := decoded.UnmarshalBinary(raw); err != nil {
+			t.Fatalf("type %d: decode failed: %v", tx.Type(), err)
+		}
+		if have, want := decoded.Hash(), mvm_types.FromAmcHash(tx.Hash()); have != want {
+			t.Fatalf("type %d: hash mismatch: have %x, want %x", tx.Type(), have, want)
+		}
+		sender, err := mvm_types.Sender(mvm_types.LatestSignerForChainID(testChainID), decoded)
+		if err != nil {
+			t.Fatalf("type %d: sender recovery failed: %v", tx.Type(), err)
+		}
+		if have, want := *mvm_types.ToAmcAddress(&sender), *tx.From(); have != want {
+			t.Fatalf("type %d: sender mismatch: have %s, want %s", tx.Type(), have, want)
+		}
+	}
+}
+
+// Tests that receipts, headers and access list results carry every field
+// Geth returns for them.
+func TestRPCResultFields(t *testing.T) {
+	header := &block.Header{
+		Number:     uint256.NewInt(1),
+		Difficulty: uint256.NewInt(2),
+		BaseFee:    uint256.NewInt(params.GWei),
+	}
+	headerKeys := []string{
+		"baseFeePerGas", "difficulty", "extraData", "gasLimit", "gasUsed", "hash", "logsBloom", "miner",
+		"mixHash", "nonce", "number", "parentHash", "receiptsRoot", "sha3Uncles", "size", "stateRoot",
+		"timestamp", "transactionsRoot",
+	}
+	if missing := missingKeys(jsonKeys(t, RPCMarshalHeader(header)), headerKeys); len(missing) > 0 {
+		t.Errorf("header misses fields %v", missing)
+	}
+
+	receiptKeys := []string{
+		"blockHash", "blockNumber", "contractAddress", "cumulativeGasUsed", "effectiveGasPrice", "from",
+		"gasUsed", "logs", "logsBloom", "status", "to", "transactionHash", "transactionIndex", "type",
+	}
+	for _, tx := range signedTestTxs(t) {
+		receipt := &block.Receipt{Status: block.ReceiptStatusSuccessful, GasUsed: tx.Gas(), CumulativeGasUsed: tx.Gas()}
+		fields := marshalReceipt(receipt, tx, header.Hash(), 1, 0, header)
+		if missing := missingKeys(jsonKeys(t, fields), receiptKeys); len(missing) > 0 {
+			t.Errorf("type %d: receipt misses fields %v", tx.Type(), missing)
+		}
+		if have, want := fields["transactionHash"], mvm_types.FromAmcHash(tx.Hash()); have != want {
+			t.Errorf("type %d: receipt hash mismatch: have %v, want %v", tx.Type(), have, want)
+		}
+	}
+
+	acl := mvm_types.FromAmcAccessList(nil)
+	blob, err := json.Marshal(&accessListResult{Accesslist: &acl, GasUsed: 21000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have, want := string(blob), `{"accessList":[],"gasUsed":"0x5208"}`; have != want {
+		t.Errorf("access list result mismatch: have %s, want %s", have, want)
+	}
+}
@@ -136,6 +136,9 @@ func RPCMarshalHeader(head block.IHeader) map[string]interface{} {
 	if header.BaseFee != nil {
 		result["baseFeePerGas"] = (*hexutil.Big)(header.BaseFee.ToBig())
 	}
+	if header.VerifierHash != nil {
+		result["verifiersRoot"] = mvm_types.FromAmcHash(*header.VerifierHash)
+	}
 
 	return result
 }
@@ -184,8 +184,16 @@ func (oracle *Oracle) resolveBlockRange(ctx context.Context, reqEnd jsonrpc.Bloc
 		case jsonrpc.LatestBlockNumber:
 			// Retrieved above.
 			resolved = headBlock
-		//case jsonrpc.SafeBlockNumber:
-		//case jsonrpc.FinalizedBlockNumber:
+		case jsonrpc.SafeBlockNumber:
+			resolved = oracle.backend.CurrentSafeBlock()
+			if resolved == nil {
+				err = errSafeNotFound
+			}
+		case jsonrpc.FinalizedBlockNumber:
+			resolved = oracle.backend.CurrentFinalizedBlock()
+			if resolved == nil {
+				err = errFinalizedNotFound
+			}
 		case jsonrpc.EarliestBlockNumber:
 			resolved = oracle.backend.GetHeaderByNumber(uint256.NewInt(0))
 		}
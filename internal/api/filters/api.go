@@ -208,6 +208,35 @@ func (filterApi *FilterAPI) NewHeads(ctx context.Context) (*jsonrpc.Subscription
 	return rpcSub, nil
 }
 
+// FinalizedHeads send a notification each time a block becomes finalized.
+func (filterApi *FilterAPI) FinalizedHeads(ctx context.Context) (*jsonrpc.Subscription, error) {
+	notifier, supported := jsonrpc.NotifierFromContext(ctx)
+	if !supported {
+		return &jsonrpc.Subscription{}, jsonrpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		headers := make(chan block.IHeader)
+		headersSub := filterApi.events.SubscribeFinalizedHeads(headers)
+		for {
+			select {
+			case h := <-headers:
+				notifier.Notify(rpcSub.ID, mvm_types.FromAmcHeader(h))
+			case <-rpcSub.Err():
+				headersSub.Unsubscribe()
+				return
+			case <-notifier.Closed():
+				headersSub.Unsubscribe()
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
 // Logs creates a subscription that fires for all new log that match the given filter criteria.
 func (filterApi *FilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*jsonrpc.Subscription, error) {
 	notifier, supported := jsonrpc.NotifierFromContext(ctx)
@@ -3,6 +3,8 @@ package filters
 import (
 	"context"
 	"errors"
+	"fmt"
+	"github.com/RoaringBitmap/roaring"
 	"github.com/amazechain/amc/common"
 	"github.com/amazechain/amc/common/block"
 	"github.com/amazechain/amc/common/txs_pool"
@@ -11,6 +13,8 @@ import (
 	"github.com/amazechain/amc/internal/consensus"
 	vm2 "github.com/amazechain/amc/internal/vm"
 	"github.com/amazechain/amc/internal/vm/evmtypes"
+	"github.com/amazechain/amc/modules"
+	"github.com/amazechain/amc/modules/rawdb"
 	"github.com/amazechain/amc/modules/rpc/jsonrpc"
 	"github.com/holiman/uint256"
 	"github.com/ledgerwatch/erigon-lib/kv"
@@ -96,6 +100,28 @@ func newFilter(api Api, addresses []types.Address, topics [][]types.Hash) *Filte
 	}
 }
 
+// resolveNumber maps the latest, pending, safe and finalized tags of a range
+// bound to an absolute block number.
+func (f *Filter) resolveNumber(number int64, head uint64) (int64, error) {
+	switch jsonrpc.BlockNumber(number) {
+	case jsonrpc.LatestBlockNumber, jsonrpc.PendingBlockNumber:
+		return int64(head), nil
+	case jsonrpc.SafeBlockNumber:
+		header := f.api.BlockChain().CurrentSafeBlock()
+		if header == nil {
+			return 0, errors.New("safe header not found")
+		}
+		return int64(header.Number64().Uint64()), nil
+	case jsonrpc.FinalizedBlockNumber:
+		header := f.api.BlockChain().CurrentFinalizedBlock()
+		if header == nil {
+			return 0, errors.New("finalized header not found")
+		}
+		return int64(header.Number64().Uint64()), nil
+	}
+	return number, nil
+}
+
 // Logs searches the blockchain for matching log entries, returning all from the
 // first block that contains matches, updating the start of the filter accordingly.
 func (f *Filter) Logs(ctx context.Context) ([]*block.Log, error) {
@@ -124,30 +150,45 @@ func (f *Filter) Logs(ctx context.Context) ([]*block.Log, error) {
 	}
 	var (
 		head    = header.Number64().Uint64()
-		end     = uint64(f.end)
 		pending = f.end == jsonrpc.PendingBlockNumber.Int64()
+		err     error
 	)
-	if f.begin == jsonrpc.LatestBlockNumber.Int64() {
-		f.begin = int64(head)
+	if f.begin, err = f.resolveNumber(f.begin, head); err != nil {
+		return nil, err
 	}
-	if f.end == jsonrpc.LatestBlockNumber.Int64() || f.end == jsonrpc.PendingBlockNumber.Int64() {
-		end = head
+	resolvedEnd, err := f.resolveNumber(f.end, head)
+	if err != nil {
+		return nil, err
 	}
+	end := uint64(resolvedEnd)
 	// Gather all indexed logs, and finish with non indexed ones
-	var (
-		logs           []*block.Log
-		err            error
-		size, sections = uint64(4096), uint64(10)
-		//todo
-	)
-	if indexed := sections * size; indexed > uint64(f.begin) {
-		if indexed > end {
-			logs, err = f.indexedLogs(ctx, end)
-		} else {
-			logs, err = f.indexedLogs(ctx, indexed-1)
+	var logs []*block.Log
+	if err = f.db.View(ctx, func(tx kv.Tx) error {
+		if err := rawdb.CheckPruned(tx, rawdb.PruneReceipts, uint64(f.begin)); err != nil {
+			return err
 		}
-		if err != nil {
-			return logs, err
+		return rawdb.CheckPruned(tx, rawdb.PruneLogs, uint64(f.begin))
+	}); err != nil {
+		return nil, err
+	}
+	if f.hasCriteria() {
+		var indexed uint64
+		if err = f.db.View(ctx, func(tx kv.Tx) error {
+			var err error
+			indexed, err = rawdb.ReadLogIndexProgress(tx)
+			return err
+		}); err != nil {
+			return nil, err
+		}
+		if indexed >= uint64(f.begin) {
+			if indexed > end {
+				logs, err = f.indexedLogs(ctx, end)
+			} else {
+				logs, err = f.indexedLogs(ctx, indexed)
+			}
+			if err != nil {
+				return logs, err
+			}
 		}
 	}
 	rest, err := f.unindexedLogs(ctx, end)
@@ -162,51 +203,97 @@ func (f *Filter) Logs(ctx context.Context) ([]*block.Log, error) {
 	return logs, err
 }
 
-// indexedLogs returns the logs matching the filter criteria based on the bloom
-// bits indexed available locally or via the network.
+// indexedLogs returns the logs matching the filter criteria based on the
+// LogAddressIndex and LogTopicIndex bitmaps available locally.
 func (f *Filter) indexedLogs(ctx context.Context, end uint64) ([]*block.Log, error) {
-	// Create a matcher session and request servicing from the backend
-	matches := make(chan uint64, 64)
-
-	//session, err := f.matcher.Start(ctx, uint64(f.begin), end, matches)
-	//if err != nil {
-	//	return nil, err
-	//}
-	//defer session.Close()
-
-	//f.api.ServiceFilter(ctx, session)
+	var blockNumbers *roaring.Bitmap
+	if err := f.db.View(ctx, func(tx kv.Tx) error {
+		var err error
+		blockNumbers, err = f.matchingBlocks(tx, uint64(f.begin), end)
+		return err
+	}); err != nil {
+		return nil, err
+	}
 
 	// Iterate over the matches until exhausted or context closed
 	var logs []*block.Log
-
-	for {
+	it := blockNumbers.Iterator()
+	for it.HasNext() {
 		select {
-		case number, ok := <-matches:
-			// Abort if all matches have been fulfilled
-			if !ok {
-				//err := session.Error()
-				//if err == nil {
-				//	f.begin = int64(end) + 1
-				//}
-				return logs, nil
-			}
-			f.begin = int64(number) + 1
+		case <-ctx.Done():
+			return logs, ctx.Err()
+		default:
+		}
+		number := uint64(it.Next())
+		f.begin = int64(number) + 1
 
-			// Retrieve the suggested block and pull any truly matching logs
-			header := f.api.BlockChain().GetHeaderByNumber(uint256.NewInt(number))
-			if header == nil {
-				return logs, nil
+		// Retrieve the suggested block and pull any truly matching logs
+		header := f.api.BlockChain().GetHeaderByNumber(uint256.NewInt(number))
+		if header == nil {
+			return logs, fmt.Errorf("indexed block %d not found", number)
+		}
+		found, err := f.checkMatches(ctx, header)
+		if err != nil {
+			return logs, err
+		}
+		logs = append(logs, found...)
+	}
+	f.begin = int64(end) + 1
+	return logs, nil
+}
+
+// hasCriteria reports whether the filter restricts addresses or topics, which
+// is required for the log index to narrow down the blocks to inspect.
+func (f *Filter) hasCriteria() bool {
+	if len(f.addresses) > 0 {
+		return true
+	}
+	for _, sub := range f.topics {
+		if len(sub) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// matchingBlocks intersects the address and per-position topic bitmaps over
+// [from, to]. The index is not positional, so the result is a superset of the
+// blocks holding matching logs and still has to go through checkMatches.
+func (f *Filter) matchingBlocks(tx kv.Tx, from, to uint64) (*roaring.Bitmap, error) {
+	var result *roaring.Bitmap
+	if len(f.addresses) > 0 {
+		var bitmaps []*roaring.Bitmap
+		for _, addr := range f.addresses {
+			bm, err := rawdb.ReadLogIndex(tx, modules.LogAddressIndex, addr.Bytes(), from, to)
+			if err != nil {
+				return nil, err
 			}
-			found, err := f.checkMatches(ctx, header)
+			bitmaps = append(bitmaps, bm)
+		}
+		result = roaring.FastOr(bitmaps...)
+	}
+	for _, sub := range f.topics {
+		if len(sub) == 0 {
+			continue
+		}
+		var bitmaps []*roaring.Bitmap
+		for _, topic := range sub {
+			bm, err := rawdb.ReadLogIndex(tx, modules.LogTopicIndex, topic.Bytes(), from, to)
 			if err != nil {
-				return logs, err
+				return nil, err
 			}
-			logs = append(logs, found...)
-
-		case <-ctx.Done():
-			return logs, ctx.Err()
+			bitmaps = append(bitmaps, bm)
+		}
+		if result == nil {
+			result = roaring.FastOr(bitmaps...)
+		} else {
+			result.And(roaring.FastOr(bitmaps...))
 		}
 	}
+	if result == nil {
+		result = roaring.New()
+	}
+	return result, nil
 }
 
 // unindexedLogs returns the logs matching the filter criteria based on raw block
@@ -33,6 +33,8 @@ const (
 	PendingTransactionsSubscription
 	// BlocksSubscription queries hashes for blocks that are imported
 	BlocksSubscription
+	// FinalizedHeadsSubscription queries headers of blocks as they become final
+	FinalizedHeadsSubscription
 	// LastSubscription keeps track of the last index
 	LastIndexSubscription
 )
@@ -74,6 +76,7 @@ type EventSystem struct {
 	rmLogsSub      event.Subscription // Subscription for removed log event
 	pendingLogsSub event.Subscription // Subscription for pending log event
 	chainSub       event.Subscription // Subscription for new chain event
+	finalizedSub   event.Subscription // Subscription for finalized head event
 
 	// Channels
 	install       chan *subscription              // install filter for event notification
@@ -83,6 +86,7 @@ type EventSystem struct {
 	pendingLogsCh chan common.NewPendingLogsEvent // Channel to receive new log event
 	rmLogsCh      chan common.RemovedLogsEvent    // Channel to receive removed log event
 	chainCh       chan common.ChainHighestBlock   // Channel to receive new chain event
+	finalizedCh   chan common.FinalizedHeadEvent  // Channel to receive finalized head event
 }
 
 // NewEventSystem creates a new manager that listens for event on the given mux,
@@ -103,6 +107,7 @@ func NewEventSystem(api Api) *EventSystem {
 		rmLogsCh:      make(chan common.RemovedLogsEvent),
 		pendingLogsCh: make(chan common.NewPendingLogsEvent),
 		chainCh:       make(chan common.ChainHighestBlock),
+		finalizedCh:   make(chan common.FinalizedHeadEvent),
 	}
 
 	// Subscribe events
@@ -111,9 +116,10 @@ func NewEventSystem(api Api) *EventSystem {
 	m.rmLogsSub = event.GlobalEvent.Subscribe(m.rmLogsCh)
 	m.chainSub = event.GlobalEvent.Subscribe(m.chainCh)
 	m.pendingLogsSub = event.GlobalEvent.Subscribe(m.pendingLogsCh)
+	m.finalizedSub = event.GlobalEvent.Subscribe(m.finalizedCh)
 
 	// Make sure none of the subscriptions are empty
-	if m.txsSub == nil || m.logsSub == nil || m.rmLogsSub == nil || m.chainSub == nil || m.pendingLogsSub == nil {
+	if m.txsSub == nil || m.logsSub == nil || m.rmLogsSub == nil || m.chainSub == nil || m.pendingLogsSub == nil || m.finalizedSub == nil {
 		log.Error("Subscribe for event system failed")
 	}
 
@@ -272,6 +278,22 @@ func (es *EventSystem) SubscribeNewHeads(headers chan block.IHeader) *Subscripti
 	return es.subscribe(sub)
 }
 
+// SubscribeFinalizedHeads creates a subscription that writes the header of a
+// block once it becomes finalized.
+func (es *EventSystem) SubscribeFinalizedHeads(headers chan block.IHeader) *Subscription {
+	sub := &subscription{
+		id:        jsonrpc.NewID(),
+		typ:       FinalizedHeadsSubscription,
+		created:   time.Now(),
+		logs:      make(chan []*block.Log),
+		hashes:    make(chan []types.Hash),
+		headers:   headers,
+		installed: make(chan struct{}),
+		err:       make(chan error),
+	}
+	return es.subscribe(sub)
+}
+
 // SubscribePendingTxs creates a subscription that writes transaction hashes for
 // transactions that enter the transaction pool.
 func (es *EventSystem) SubscribePendingTxs(hashes chan []types.Hash) *Subscription {
@@ -349,6 +371,12 @@ func (es *EventSystem) handleChainEvent(filters filterIndex, ev common.ChainHigh
 	}
 }
 
+func (es *EventSystem) handleFinalizedEvent(filters filterIndex, ev common.FinalizedHeadEvent) {
+	for _, f := range filters[FinalizedHeadsSubscription] {
+		f.headers <- ev.Header
+	}
+}
+
 func (es *EventSystem) lightFilterNewHead(newHeader block.IHeader, callBack func(block.IHeader, bool)) {
 	oldh := es.lastHead
 	es.lastHead = newHeader
@@ -443,6 +471,7 @@ func (es *EventSystem) eventLoop() {
 		es.rmLogsSub.Unsubscribe()
 		es.pendingLogsSub.Unsubscribe()
 		es.chainSub.Unsubscribe()
+		es.finalizedSub.Unsubscribe()
 	}()
 
 	index := make(filterIndex)
@@ -464,6 +493,8 @@ func (es *EventSystem) eventLoop() {
 			if ev.Inserted {
 				es.handleChainEvent(index, ev)
 			}
+		case ev := <-es.finalizedCh:
+			es.handleFinalizedEvent(index, ev)
 
 		case f := <-es.install:
 			if f.typ == MinedAndPendingLogsSubscription {
@@ -494,6 +525,8 @@ func (es *EventSystem) eventLoop() {
 			return
 		case <-es.chainSub.Err():
 			return
+		case <-es.finalizedSub.Err():
+			return
 		}
 	}
 }
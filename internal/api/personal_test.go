@@ -0,0 +1,133 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/amazechain/amc/accounts"
+	"github.com/amazechain/amc/accounts/keystore"
+	"github.com/amazechain/amc/common/crypto"
+	"github.com/amazechain/amc/common/hexutil"
+	"github.com/amazechain/amc/signer/core/apitypes"
+)
+
+func newPersonalTestAPI(t *testing.T) (*API, accounts.Account) {
+	ks := keystore.NewKeyStore(t.TempDir(), keystore.LightScryptN, keystore.LightScryptP)
+	key, err := crypto.ToECDSA(crypto.Keccak256([]byte("cow")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	account, err := ks.ImportECDSA(key, "pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	am := accounts.NewManager(&accounts.Config{}, ks)
+	t.Cleanup(func() { am.Close() })
+	return &API{accountManager: am}, account
+}
+
+func TestPersonalSignAndRecover(t *testing.T) {
+	api, account := newPersonalTestAPI(t)
+	personal := NewPersonalAccountAPI(api, new(AddrLocker))
+	ctx := context.Background()
+
+	if accs := personal.ListAccounts(); len(accs) != 1 || accs[0] != account.Address {
+		t.Fatalf("unexpected accounts: %v", accs)
+	}
+	if wallets := personal.ListWallets(); len(wallets) != 1 || wallets[0].Status != "Locked" {
+		t.Fatalf("unexpected wallets: %+v", wallets)
+	}
+	data := hexutil.Bytes("hello amc")
+	if _, err := personal.Sign(ctx, data, account.Address, "wrong"); err == nil {
+		t.Fatal("signed with a wrong password")
+	}
+	sig, err := personal.Sign(ctx, data, account.Address, "pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := sig[crypto.RecoveryIDOffset]; v != 27 && v != 28 {
+		t.Fatalf("V not in yellow paper form: %d", v)
+	}
+	recovered, err := personal.EcRecover(ctx, data, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recovered != account.Address {
+		t.Fatalf("recovered %v, want %v", recovered, account.Address)
+	}
+
+	// eth_sign uses the unlocked key rather than a password.
+	eth := NewTransactionAPI(api, new(AddrLocker))
+	if _, err := eth.Sign(account.Address, data); err == nil {
+		t.Fatal("signed with a locked account")
+	}
+	if ok, err := personal.UnlockAccount(ctx, account.Address, "pass", nil); !ok || err != nil {
+		t.Fatalf("unlock failed: %v", err)
+	}
+	ethSig, err := eth.Sign(account.Address, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recovered, err := personal.EcRecover(ctx, data, ethSig); err != nil || recovered != account.Address {
+		t.Fatalf("eth_sign recovered %v (%v), want %v", recovered, err, account.Address)
+	}
+	if !personal.LockAccount(account.Address) {
+		t.Fatal("lock failed")
+	}
+	if _, err := eth.Sign(account.Address, data); err == nil {
+		t.Fatal("signed after locking the account")
+	}
+}
+
+func TestSignTypedData(t *testing.T) {
+	api, account := newPersonalTestAPI(t)
+	personal := NewPersonalAccountAPI(api, new(AddrLocker))
+	if _, err := personal.UnlockAccount(context.Background(), account.Address, "pass", nil); err != nil {
+		t.Fatal(err)
+	}
+	var typedData apitypes.TypedData
+	if err := json.Unmarshal([]byte(`{
+		"types": {
+			"EIP712Domain": [{"name": "name", "type": "string"}, {"name": "chainId", "type": "uint256"}],
+			"Greeting": [{"name": "text", "type": "string"}]
+		},
+		"primaryType": "Greeting",
+		"domain": {"name": "amc", "chainId": "0x61b"},
+		"message": {"text": "hello"}
+	}`), &typedData); err != nil {
+		t.Fatal(err)
+	}
+	sig, err := NewTransactionAPI(api, new(AddrLocker)).SignTypedData_v4(account.Address, typedData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig[crypto.RecoveryIDOffset] -= 27
+	pub, err := crypto.SigToPub(digest, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr := crypto.PubkeyToAddress(*pub); addr != account.Address {
+		t.Fatalf("recovered %v, want %v", addr, account.Address)
+	}
+}
@@ -231,6 +231,7 @@ func (args *TransactionArgs) ToMessage(globalGasCap uint64, baseFee *big.Int) (t
 // toTransaction assemble Transaction
 func (args *TransactionArgs) toTransaction() *transaction.Transaction {
 	var data transaction.TxData
+	from := args.from()
 	switch {
 	case args.MaxFeePerGas != nil:
 		al := transaction.AccessList{}
@@ -239,6 +240,7 @@ func (args *TransactionArgs) toTransaction() *transaction.Transaction {
 		}
 		dy := &transaction.DynamicFeeTx{
 			To:         mvm_types.ToAmcAddress(args.To),
+			From:       &from,
 			Nonce:      uint64(*args.Nonce),
 			Gas:        uint64(*args.Gas),
 			Data:       args.data(),
@@ -265,6 +267,7 @@ func (args *TransactionArgs) toTransaction() *transaction.Transaction {
 	case args.AccessList != nil:
 		alt := &transaction.AccessListTx{
 			To:    mvm_types.ToAmcAddress(args.To),
+			From:  &from,
 			Nonce: uint64(*args.Nonce),
 			Gas:   uint64(*args.Gas),
 			Data:  args.data(),
@@ -287,6 +290,7 @@ func (args *TransactionArgs) toTransaction() *transaction.Transaction {
 	default:
 		lt := &transaction.LegacyTx{
 			To:    mvm_types.ToAmcAddress(args.To),
+			From:  &from,
 			Nonce: uint64(*args.Nonce),
 			Gas:   uint64(*args.Gas),
 			Data:  args.data(),
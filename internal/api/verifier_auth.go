@@ -0,0 +1,340 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/amazechain/amc/common/crypto"
+	"github.com/amazechain/amc/common/crypto/bls"
+	"github.com/amazechain/amc/common/hexutil"
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/modules/rawdb"
+	"github.com/amazechain/amc/params"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/rcrowley/go-metrics"
+)
+
+const (
+	challengeTTL      = time.Minute    // time a verifier has to answer a challenge
+	sessionTTL        = 24 * time.Hour // lifetime of a session token
+	maxChallenges     = 4096           // outstanding challenges kept before new ones are refused
+	maxAddrChallenges = 4              // outstanding challenges of an address
+	pushedBlocks      = 64             // heights of mined blocks verifiers may still sign
+)
+
+var (
+	verifierAuthCounter      = metrics.GetOrRegisterCounter("rpc/verifier/auth", nil)
+	verifierAuthFailCounter  = metrics.GetOrRegisterCounter("rpc/verifier/auth/failure", nil)
+	verifierReplayCounter    = metrics.GetOrRegisterCounter("rpc/verifier/auth/replay", nil)
+	verifierUnauthedCounter  = metrics.GetOrRegisterCounter("rpc/verifier/unauthed", nil)
+	verifierDuplicateCounter = metrics.GetOrRegisterCounter("rpc/verifier/sign/duplicate", nil)
+)
+
+var (
+	errUnknownChallenge = errors.New("unknown or expired challenge")
+	errInvalidSession   = errors.New("invalid or expired session token")
+	errTooManyChallenge = errors.New("too many outstanding challenges")
+	errAddrChallenges   = errors.New("too many outstanding challenges for the address")
+)
+
+// ChallengeHash returns the digest a verifier signs to answer the challenge nonce
+// issued to its deposit address, either with the ECDSA key of the address or with
+// its deposited BLS key.
+func ChallengeHash(addr types.Address, nonce types.Hash) types.Hash {
+	return crypto.Keccak256Hash([]byte("amc verifier challenge"), addr[:], nonce[:])
+}
+
+type challenge struct {
+	addr    types.Address
+	expires time.Time
+}
+
+type verifierSession struct {
+	addr    types.Address
+	expires time.Time
+}
+
+// verifierSessions authenticates the verifiers subscribing to mined blocks and
+// submitting their signatures with a challenge-response handshake.
+type verifierSessions struct {
+	mu         sync.Mutex
+	challenges map[types.Hash]challenge
+	sessions   map[string]verifierSession
+	pushed     map[uint64][]types.Hash                 // state roots of the mined blocks pushed per height
+	signed     map[types.Address]map[uint64]types.Hash // state roots signed per verifier and height
+}
+
+func newVerifierSessions() *verifierSessions {
+	return &verifierSessions{
+		challenges: make(map[types.Hash]challenge),
+		sessions:   make(map[string]verifierSession),
+		pushed:     make(map[uint64][]types.Hash),
+		signed:     make(map[types.Address]map[uint64]types.Hash),
+	}
+}
+
+// expire drops the outdated challenges and sessions. The lock must be held.
+func (v *verifierSessions) expire(now time.Time) {
+	for nonce, c := range v.challenges {
+		if now.After(c.expires) {
+			delete(v.challenges, nonce)
+		}
+	}
+	for token, s := range v.sessions {
+		if now.After(s.expires) {
+			delete(v.sessions, token)
+		}
+	}
+}
+
+// challenge issues a single use nonce for addr, refusing new ones while addr has
+// maxAddrChallenges outstanding.
+func (v *verifierSessions) challenge(addr types.Address) (types.Hash, error) {
+	var nonce types.Hash
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return types.Hash{}, err
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	now := time.Now()
+	v.expire(now)
+	if len(v.challenges) >= maxChallenges {
+		return types.Hash{}, errTooManyChallenge
+	}
+	outstanding := 0
+	for _, c := range v.challenges {
+		if c.addr == addr {
+			outstanding++
+		}
+	}
+	if outstanding >= maxAddrChallenges {
+		return types.Hash{}, errAddrChallenges
+	}
+	v.challenges[nonce] = challenge{addr: addr, expires: now.Add(challengeTTL)}
+	return nonce, nil
+}
+
+// consume removes the challenge nonce issued to addr. A nonce can be answered
+// only once, any later answer is a replay.
+func (v *verifierSessions) consume(addr types.Address, nonce types.Hash) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	c, ok := v.challenges[nonce]
+	if !ok || c.addr != addr || time.Now().After(c.expires) {
+		return errUnknownChallenge
+	}
+	delete(v.challenges, nonce)
+	return nil
+}
+
+// open starts a session for addr and returns its token.
+func (v *verifierSessions) open(addr types.Address) (string, error) {
+	var token [32]byte
+	if _, err := rand.Read(token[:]); err != nil {
+		return "", err
+	}
+	key := hexutil.Encode(token[:])
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.sessions[key] = verifierSession{addr: addr, expires: time.Now().Add(sessionTTL)}
+	return key, nil
+}
+
+// address returns the verifier the session token was issued to.
+func (v *verifierSessions) address(token string) (types.Address, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	s, ok := v.sessions[token]
+	if !ok || time.Now().After(s.expires) {
+		verifierUnauthedCounter.Inc(1)
+		return types.Address{}, errInvalidSession
+	}
+	return s.addr, nil
+}
+
+// revoke ends the session of the token.
+func (v *verifierSessions) revoke(token string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	delete(v.sessions, token)
+}
+
+// push records the state root of a mined block pushed to the verifiers, keeping
+// the last pushedBlocks heights.
+func (v *verifierSessions) push(number uint64, root types.Hash) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	for _, r := range v.pushed[number] {
+		if r == root {
+			return
+		}
+	}
+	v.pushed[number] = append(v.pushed[number], root)
+	for n := range v.pushed {
+		if n+pushedBlocks <= number {
+			delete(v.pushed, n)
+		}
+	}
+}
+
+// wasPushed reports whether a mined block of the height with the state root was
+// pushed to the verifiers.
+func (v *verifierSessions) wasPushed(number uint64, root types.Hash) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	for _, r := range v.pushed[number] {
+		if r == root {
+			return true
+		}
+	}
+	return false
+}
+
+// markSigned records that addr signed the block of the height with the state
+// root. It returns false if addr signed this block already.
+func (v *verifierSessions) markSigned(addr types.Address, number uint64, root types.Hash) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	signed, ok := v.signed[addr]
+	if !ok {
+		signed = make(map[uint64]types.Hash)
+		v.signed[addr] = signed
+	}
+	if r, ok := signed[number]; ok && r == root {
+		verifierDuplicateCounter.Inc(1)
+		return false
+	}
+	signed[number] = root
+	for n := range signed {
+		if n+pushedBlocks <= number {
+			delete(signed, n)
+		}
+	}
+	return true
+}
+
+// verifyChallenge checks that sig answers the challenge nonce issued to the
+// depositor addr, signed either by the ECDSA key of addr or its deposited BLS key.
+func verifyChallenge(tx kv.Tx, schedule *params.DepositSchedule, addr types.Address, nonce types.Hash, sig []byte) error {
+	if is, err := isDeposit(tx, schedule, addr); err != nil {
+		return err
+	} else if !is {
+		return fmt.Errorf("unauthed address: %s", addr)
+	}
+	hash := ChallengeHash(addr, nonce)
+
+	switch len(sig) {
+	case crypto.SignatureLength:
+		sig = append([]byte(nil), sig...)
+		if sig[crypto.RecoveryIDOffset] >= 27 {
+			sig[crypto.RecoveryIDOffset] -= 27 // legacy Ethereum signatures
+		}
+		pub, err := crypto.SigToPub(hash[:], sig)
+		if err != nil {
+			return err
+		}
+		if crypto.PubkeyToAddress(*pub) != addr {
+			return errors.New("challenge signed by another key")
+		}
+	case types.SignatureLength:
+		pubKey, _, err := rawdb.GetDeposit(tx, addr)
+		if err != nil {
+			return err
+		}
+		pub, err := bls.PublicKeyFromBytes(pubKey[:])
+		if err != nil {
+			return err
+		}
+		s, err := bls.SignatureFromBytes(sig)
+		if err != nil {
+			return err
+		}
+		if !s.Verify(pub, hash[:]) {
+			return errors.New("challenge signed by another key")
+		}
+	default:
+		return fmt.Errorf("invalid signature length %d", len(sig))
+	}
+	return nil
+}
+
+// VerifierChallenge issues a single use nonce the verifier of the deposit
+// address has to sign to open a session with VerifierLogin. Only depositors get
+// challenges, a few at a time.
+func (s *BlockChainAPI) VerifierChallenge(address types.Address) (types.Hash, error) {
+	current := s.api.BlockChain().CurrentBlock().Number64().Uint64()
+	if is, err := IsDeposit(s.api.db, s.api.chainConfig.DepositScheduleAt(current), address); nil != err {
+		return types.Hash{}, err
+	} else if !is {
+		verifierUnauthedCounter.Inc(1)
+		return types.Hash{}, fmt.Errorf("unauthed address: %s", address)
+	}
+	return s.api.verifiers.challenge(address)
+}
+
+// VerifierLogin checks the signature over ChallengeHash(address, nonce) made with
+// the ECDSA key of the deposit address or its registered BLS key, and returns the
+// session token MinedBlock and SubmitSign require.
+func (s *BlockChainAPI) VerifierLogin(ctx context.Context, address types.Address, nonce types.Hash, sig hexutil.Bytes) (string, error) {
+	if err := s.api.verifiers.consume(address, nonce); err != nil {
+		verifierReplayCounter.Inc(1)
+		return "", err
+	}
+	schedule := s.api.chainConfig.DepositScheduleAt(s.api.BlockChain().CurrentBlock().Number64().Uint64())
+	if err := s.api.db.View(ctx, func(tx kv.Tx) error {
+		return verifyChallenge(tx, schedule, address, nonce, sig)
+	}); err != nil {
+		verifierAuthFailCounter.Inc(1)
+		return "", err
+	}
+	verifierAuthCounter.Inc(1)
+	return s.api.verifiers.open(address)
+}
+
+// sessionVerifier returns the verifier of the session token while it still holds
+// the minimum deposit at the head. The session of a verifier that withdrew is
+// revoked.
+func (s *BlockChainAPI) sessionVerifier(token string) (types.Address, error) {
+	address, err := s.api.verifiers.address(token)
+	if nil != err {
+		return types.Address{}, err
+	}
+	schedule := s.api.chainConfig.DepositScheduleAt(s.api.BlockChain().CurrentBlock().Number64().Uint64())
+	if is, err := IsDeposit(s.api.db, schedule, address); nil != err {
+		return types.Address{}, err
+	} else if !is {
+		s.api.verifiers.revoke(token)
+		verifierUnauthedCounter.Inc(1)
+		return types.Address{}, fmt.Errorf("unauthed address: %s", address)
+	}
+	return address, nil
+}
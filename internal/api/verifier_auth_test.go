@@ -0,0 +1,192 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/amazechain/amc/common"
+	"github.com/amazechain/amc/common/block"
+	"github.com/amazechain/amc/common/crypto"
+	"github.com/amazechain/amc/common/crypto/bls"
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/modules"
+	"github.com/amazechain/amc/modules/rawdb"
+	"github.com/amazechain/amc/params"
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+)
+
+// testChain is the block chain seen by the verifier API in tests.
+type testChain struct {
+	common.IBlockChain
+	head  block.IBlock
+	final *block.Header
+}
+
+func (c *testChain) CurrentBlock() block.IBlock { return c.head }
+
+func (c *testChain) CurrentFinalizedBlock() block.IHeader {
+	if c.final == nil {
+		return nil
+	}
+	return c.final
+}
+
+func (c *testChain) GetHeaderByNumber(number *uint256.Int) block.IHeader {
+	if c.final == nil || !number.Eq(c.final.Number) {
+		return nil
+	}
+	return c.final
+}
+
+// Tests the challenge-response handshake of the verifiers with both key types,
+// that only depositors are challenged and that challenges can not be replayed.
+// Signatures are only taken for pushed blocks, and for the canonical block once
+// its height is finalized, and sessions end with the deposit.
+func TestVerifierLogin(t *testing.T) {
+	modules.AmcInit()
+	kv.ChaindataTablesCfg = modules.AmcTableCfg
+	db := memdb.New(t.TempDir())
+	defer db.Close()
+
+	ecdsaKey, _ := crypto.GenerateKey()
+	blsKey, err := bls.RandKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := crypto.PubkeyToAddress(ecdsaKey.PublicKey)
+	var pub types.PublicKey
+	pub.SetBytes(blsKey.PublicKey().Marshal())
+	if err := db.Update(context.Background(), func(tx kv.RwTx) error {
+		return rawdb.PutDeposit(tx, addr, pub, *new(uint256.Int).Mul(uint256.NewInt(50), uint256.NewInt(params.AMT)))
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	chain := &testChain{head: block.NewBlock(&block.Header{Number: uint256.NewInt(9)}, nil)}
+	s := &BlockChainAPI{&API{db: db, bc: chain, verifiers: newVerifierSessions()}}
+	signers := map[string]func(hash types.Hash) []byte{
+		"ecdsa": func(hash types.Hash) []byte {
+			sig, _ := crypto.Sign(hash[:], ecdsaKey)
+			return sig
+		},
+		"bls": func(hash types.Hash) []byte {
+			return blsKey.Sign(hash[:]).Marshal()
+		},
+	}
+	var token string
+	for name, sign := range signers {
+		nonce, err := s.VerifierChallenge(addr)
+		if err != nil {
+			t.Fatalf("%s: challenge failed: %v", name, err)
+		}
+		sig := sign(ChallengeHash(addr, nonce))
+		if token, err = s.VerifierLogin(context.Background(), addr, nonce, sig); err != nil {
+			t.Fatalf("%s: login failed: %v", name, err)
+		}
+		if have, err := s.api.verifiers.address(token); err != nil || have != addr {
+			t.Fatalf("%s: session address: have %s (%v), want %s", name, have, err, addr)
+		}
+		if _, err := s.VerifierLogin(context.Background(), addr, nonce, sig); err == nil {
+			t.Fatalf("%s: replayed challenge accepted", name)
+		}
+	}
+
+	// answers signed by another key are refused, non depositors are not challenged
+	other, _ := crypto.GenerateKey()
+	nonce, _ := s.VerifierChallenge(addr)
+	sig, _ := crypto.Sign(ChallengeHash(addr, nonce).Bytes(), other)
+	if _, err := s.VerifierLogin(context.Background(), addr, nonce, sig); err == nil {
+		t.Fatal("challenge signed by another key accepted")
+	}
+	if _, err := s.VerifierChallenge(crypto.PubkeyToAddress(other.PublicKey)); err == nil {
+		t.Fatal("non depositor challenged")
+	}
+	if _, err := s.api.verifiers.address("0x00"); err == nil {
+		t.Fatal("unknown session token accepted")
+	}
+	// a deposit below the minimum does not log in
+	setDeposit := func(amount uint64) {
+		if err := db.Update(context.Background(), func(tx kv.RwTx) error {
+			return rawdb.PutDeposit(tx, addr, pub, *new(uint256.Int).Mul(uint256.NewInt(amount), uint256.NewInt(params.AMT)))
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	nonce, _ = s.VerifierChallenge(addr)
+	setDeposit(1)
+	if _, err := s.VerifierLogin(context.Background(), addr, nonce, signers["bls"](ChallengeHash(addr, nonce))); err == nil {
+		t.Fatal("login below the minimum deposit accepted")
+	}
+	setDeposit(50)
+
+	// an address has a limited number of outstanding challenges
+	for i := 0; i < maxAddrChallenges; i++ {
+		if _, err := s.VerifierChallenge(addr); err != nil {
+			t.Fatalf("challenge %d failed: %v", i, err)
+		}
+	}
+	if _, err := s.VerifierChallenge(addr); err != errAddrChallenges {
+		t.Fatalf("challenge over the limit: have %v, want %v", err, errAddrChallenges)
+	}
+
+	submit := func(number uint64, root types.Hash) error {
+		sign := AggSign{Number: number, StateRoot: root, Address: addr}
+		copy(sign.Sign[:], blsKey.Sign(root[:]).Marshal())
+		return s.SubmitSign(sign, token)
+	}
+	rootA, rootB := types.Hash{0x0a}, types.Hash{0x0b}
+	if err := submit(10, rootA); err == nil {
+		t.Fatal("sign of a block not pushed accepted")
+	}
+	s.api.verifiers.push(10, rootA)
+	s.api.verifiers.push(10, rootB)
+	if err := submit(10, rootA); err != nil {
+		t.Fatalf("sign of block 10: %v", err)
+	}
+	if s.api.verifiers.markSigned(addr, 10, rootA) {
+		t.Fatal("repeated sign of block 10 not detected")
+	}
+	if err := submit(10, rootA); err != nil {
+		t.Fatalf("repeated sign of block 10: %v", err)
+	}
+	// another block of an unfinalized height can be signed, but not of a finalized one
+	chain.final = &block.Header{Number: uint256.NewInt(10), Root: rootA}
+	if err := submit(10, rootB); err == nil {
+		t.Fatal("sign of another block at a finalized height accepted")
+	}
+	chain.final = nil
+	if err := submit(10, rootB); err != nil {
+		t.Fatalf("sign of another block 10: %v", err)
+	}
+
+	// withdrawing the deposit revokes the session
+	if err := db.Update(context.Background(), func(tx kv.RwTx) error {
+		return rawdb.DeleteDeposit(tx, addr)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := submit(10, rootA); err == nil {
+		t.Fatal("sign of a withdrawn verifier accepted")
+	}
+	if _, err := s.api.verifiers.address(token); err == nil {
+		t.Fatal("session of a withdrawn verifier kept")
+	}
+}
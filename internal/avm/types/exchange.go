@@ -58,10 +58,11 @@ func ToAmcAccessList(accessList AccessList) transaction.AccessList {
 }
 
 func FromAmcAccessList(accessList transaction.AccessList) AccessList {
-	var txAccessList AccessList
+	txAccessList := make(AccessList, 0, len(accessList))
 	for _, accessTuple := range accessList {
 		txAccessTuple := new(AccessTuple)
 		txAccessTuple.Address = *FromAmcAddress(&accessTuple.Address)
+		txAccessTuple.StorageKeys = make([]common.Hash, 0, len(accessTuple.StorageKeys))
 		for _, hash := range accessTuple.StorageKeys {
 			txAccessTuple.StorageKeys = append(txAccessTuple.StorageKeys, FromAmcHash(hash))
 		}
@@ -275,6 +276,21 @@ func (tx *Transaction) WithSignature(signer Signer, sig []byte) (*Transaction, e
 	return &Transaction{inner: cpy, time: tx.time}, nil
 }
 
+// MarshalBinary returns the canonical encoding of the transaction.
+// For legacy transactions, it returns the RLP encoding. For EIP-2718 typed
+// transactions, it returns the type and payload.
+func (tx *Transaction) MarshalBinary() ([]byte, error) {
+	if tx.Type() == LegacyTxType {
+		return rlp.EncodeToBytes(tx.inner)
+	}
+	var buf bytes.Buffer
+	buf.WriteByte(tx.Type())
+	if err := rlp.Encode(&buf, tx.inner); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
 // UnmarshalBinary
 func (tx *Transaction) UnmarshalBinary(b []byte) error {
 	if len(b) > 0 && b[0] > 0x7f {
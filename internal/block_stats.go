@@ -0,0 +1,173 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package internal
+
+import (
+	"sync"
+	"time"
+
+	"github.com/amazechain/amc/common"
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/log"
+	"github.com/rcrowley/go-metrics"
+)
+
+const (
+	// blockStatsLimit is the number of recent block stats kept for debug_blockStats.
+	blockStatsLimit = 256
+
+	// DefaultSlowBlockThreshold is the processing time above which a block's
+	// stage breakdown is logged at warn level.
+	DefaultSlowBlockThreshold = 2 * time.Second
+)
+
+var (
+	blockVerifyTimer    = metrics.GetOrRegisterTimer("chain/verify", nil)
+	blockExecutionTimer = metrics.GetOrRegisterTimer("chain/execution", nil)
+	blockStateTimer     = metrics.GetOrRegisterTimer("chain/state/write", nil)
+	blockRewardsTimer   = metrics.GetOrRegisterTimer("chain/rewards", nil)
+	blockCommitTimer    = metrics.GetOrRegisterTimer("chain/commit", nil)
+
+	minedExecutionTimer = metrics.GetOrRegisterTimer("miner/execution", nil)
+	minedStateTimer     = metrics.GetOrRegisterTimer("miner/state/write", nil)
+	minedRewardsTimer   = metrics.GetOrRegisterTimer("miner/rewards", nil)
+	minedCommitTimer    = metrics.GetOrRegisterTimer("miner/commit", nil)
+	minedTotalTimer     = metrics.GetOrRegisterTimer("miner/total", nil)
+)
+
+// BlockStats is the per-stage timing breakdown of importing or sealing a single
+// block. All durations are reported in nanoseconds.
+type BlockStats struct {
+	Number  uint64      `json:"number"`
+	Hash    *types.Hash `json:"hash,omitempty"` // Unset for locally mined blocks, which are not sealed yet
+	Mined   bool        `json:"mined"`
+	Txs     int         `json:"txs"`
+	GasUsed uint64      `json:"gasUsed"`
+
+	SignatureRecovery time.Duration `json:"signatureRecovery"` // Header seal and aggregate signature checks
+	Execution         time.Duration `json:"execution"`         // EVM execution of the transactions
+	StateWrite        time.Duration `json:"stateWrite"`        // Flushing state, trie, change sets and history
+	Rewards           time.Duration `json:"rewards"`           // Engine reward calculation
+	Commit            time.Duration `json:"commit"`            // Database transaction commit and block writes
+	Total             time.Duration `json:"total"`
+}
+
+// update records the stage timings into the import or mining timers.
+func (s *BlockStats) update() {
+	if s.Mined {
+		minedExecutionTimer.Update(s.Execution)
+		minedStateTimer.Update(s.StateWrite)
+		minedRewardsTimer.Update(s.Rewards)
+		minedCommitTimer.Update(s.Commit)
+		minedTotalTimer.Update(s.Total)
+		return
+	}
+	blockVerifyTimer.Update(s.SignatureRecovery)
+	blockExecutionTimer.Update(s.Execution)
+	blockStateTimer.Update(s.StateWrite)
+	blockRewardsTimer.Update(s.Rewards)
+	blockCommitTimer.Update(s.Commit)
+	blockInsertTimer.Update(s.Total)
+}
+
+// logContext returns the stats as key/value pairs for the logger.
+func (s *BlockStats) logContext() []interface{} {
+	ctx := []interface{}{"number", s.Number}
+	if s.Hash != nil {
+		ctx = append(ctx, "hash", *s.Hash)
+	}
+	return append(ctx,
+		"mined", s.Mined,
+		"txs", s.Txs,
+		"gas", s.GasUsed,
+		"sigrecover", common.PrettyDuration(s.SignatureRecovery),
+		"execution", common.PrettyDuration(s.Execution),
+		"statewrite", common.PrettyDuration(s.StateWrite),
+		"rewards", common.PrettyDuration(s.Rewards),
+		"commit", common.PrettyDuration(s.Commit),
+		"total", common.PrettyDuration(s.Total),
+	)
+}
+
+// blockStatsHistory is a fixed size ring of the most recent block stats.
+type blockStatsHistory struct {
+	lock  sync.RWMutex
+	stats []*BlockStats
+	next  int
+	full  bool
+}
+
+func newBlockStatsHistory(limit int) *blockStatsHistory {
+	return &blockStatsHistory{stats: make([]*BlockStats, limit)}
+}
+
+// add stores s, evicting the oldest entry once the ring is full.
+func (h *blockStatsHistory) add(s *BlockStats) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.stats[h.next] = s
+	h.next = (h.next + 1) % len(h.stats)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// recent returns up to count stats, newest first.
+func (h *blockStatsHistory) recent(count int) []*BlockStats {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	size := h.next
+	if h.full {
+		size = len(h.stats)
+	}
+	if count <= 0 || count > size {
+		count = size
+	}
+	res := make([]*BlockStats, 0, count)
+	for i := 1; i <= count; i++ {
+		cpy := *h.stats[(h.next-i+len(h.stats))%len(h.stats)]
+		res = append(res, &cpy)
+	}
+	return res
+}
+
+// SetSlowBlockThreshold sets the processing time above which the stage
+// breakdown of a block is logged at warn level. Zero disables the warning.
+func (bc *BlockChain) SetSlowBlockThreshold(threshold time.Duration) {
+	bc.slowBlockThreshold = threshold
+}
+
+// RecordBlockStats updates the stage timers with the given block stats, keeps
+// them for debug_blockStats and logs them if the block was slow to process.
+func (bc *BlockChain) RecordBlockStats(stats *BlockStats) {
+	stats.update()
+	bc.blockStats.add(stats)
+
+	if bc.slowBlockThreshold > 0 && stats.Total > bc.slowBlockThreshold {
+		log.Warn("Slow block processing", stats.logContext()...)
+	} else {
+		log.Debug("Block processing stats", stats.logContext()...)
+	}
+}
+
+// BlockStats returns the stage timings of up to count recently imported or
+// mined blocks, newest first. A non-positive count returns all retained stats.
+func (bc *BlockChain) BlockStats(count int) []*BlockStats {
+	return bc.blockStats.recent(count)
+}
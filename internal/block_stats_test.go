@@ -0,0 +1,47 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package internal
+
+import "testing"
+
+func TestBlockStatsHistory(t *testing.T) {
+	h := newBlockStatsHistory(4)
+	if got := h.recent(0); len(got) != 0 {
+		t.Fatalf("empty history returned %d stats", len(got))
+	}
+	for i := uint64(1); i <= 6; i++ {
+		h.add(&BlockStats{Number: i})
+	}
+	// The ring holds the last four blocks, newest first
+	got := h.recent(0)
+	if len(got) != 4 {
+		t.Fatalf("stats count mismatch: have %d, want 4", len(got))
+	}
+	for i, want := range []uint64{6, 5, 4, 3} {
+		if got[i].Number != want {
+			t.Errorf("stats %d: number mismatch: have %d, want %d", i, got[i].Number, want)
+		}
+	}
+	if got := h.recent(2); len(got) != 2 || got[0].Number != 6 || got[1].Number != 5 {
+		t.Errorf("limited query mismatch: %+v", got)
+	}
+	// Returned stats must be copies
+	got[0].Number = 100
+	if h.recent(1)[0].Number != 6 {
+		t.Errorf("history was modified through returned stats")
+	}
+}
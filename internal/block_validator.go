@@ -51,25 +51,8 @@ func NewBlockValidator(config *params.ChainConfig, blockchain *BlockChain, engin
 // validated at this point.
 func (v *BlockValidator) ValidateBody(b block.IBlock) error {
 	// Check Signature valid
-	vfs := b.Body().Verifier()
-	ss := make([]bls.PublicKey, len(vfs))
-	for i, p := range vfs {
-		blsP, err := bls.PublicKeyFromBytes(p.PublicKey[:])
-		if nil != err {
-			return err
-		}
-		ss[i] = blsP
-	}
-
-	if v.config.IsBeijing(b.Number64().Uint64()) {
-		header := b.Header().(*block.Header)
-		sig, err := bls.SignatureFromBytes(header.Signature[:])
-		if nil != err {
-			return err
-		}
-		if !sig.FastAggregateVerify(ss, header.Root) {
-			return fmt.Errorf("AggSignature verify falied")
-		}
+	if err := verifyAggSign(v.config, v.engine, b); err != nil {
+		return err
 	}
 
 	// Check whether the block's known, and if not, that it's linkable
@@ -80,6 +63,9 @@ func (v *BlockValidator) ValidateBody(b block.IBlock) error {
 	if hash := DeriveSha(transaction.Transactions(b.Transactions())); hash != b.TxHash() {
 		return fmt.Errorf("transaction root hash mismatch: have %x, want %x", hash, b.TxHash())
 	}
+	if err := verifyVerifierHash(v.config, b); err != nil {
+		return err
+	}
 
 	if !v.bc.HasBlockAndState(b.ParentHash(), b.Number64().Uint64()-1) {
 		if !v.bc.HasBlock(b.ParentHash(), b.Number64().Uint64()-1) {
@@ -87,6 +73,13 @@ func (v *BlockValidator) ValidateBody(b block.IBlock) error {
 		}
 		return ErrPrunedAncestor
 	}
+	if v.config.IsVerifierCommitment(b.Number64().Uint64()) {
+		if verifier, ok := v.engine.(consensus.RewardVerifier); ok {
+			if err := verifier.VerifyRewards(b); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
@@ -117,3 +110,50 @@ func (v *BlockValidator) ValidateState(iBlock block.IBlock, statedb *state.Intra
 	}
 	return nil
 }
+
+// verifyVerifierHash checks the header commitment to the verifiers and rewards
+// of the body.
+func verifyVerifierHash(config *params.ChainConfig, b block.IBlock) error {
+	if !config.IsVerifierCommitment(b.Number64().Uint64()) {
+		return nil
+	}
+	header := b.Header().(*block.Header)
+	if header.VerifierHash == nil {
+		return fmt.Errorf("missing verifier hash")
+	}
+	if hash := block.CalcVerifierHash(b.Body().Verifier(), b.Body().Reward()); hash != *header.VerifierHash {
+		return fmt.Errorf("verifier hash mismatch: have %x, want %x", hash, *header.VerifierHash)
+	}
+	return nil
+}
+
+// verifyAggSign checks the aggregate signature of the verifiers over the state
+// root. From the verifier quorum fork on the verifiers must also be a quorum of the
+// depositors, before it any listed keys may sign.
+func verifyAggSign(config *params.ChainConfig, engine consensus.Engine, b block.IBlock) error {
+	if !config.IsBeijing(b.Number64().Uint64()) {
+		return nil
+	}
+	if verifier, ok := engine.(consensus.AggSignVerifier); ok && config.IsVerifierQuorum(b.Number64().Uint64()) {
+		return verifier.VerifyAggSign(b)
+	}
+	vfs := b.Body().Verifier()
+	ss := make([]bls.PublicKey, len(vfs))
+	for i, p := range vfs {
+		blsP, err := bls.PublicKeyFromBytes(p.PublicKey[:])
+		if nil != err {
+			return err
+		}
+		ss[i] = blsP
+	}
+
+	header := b.Header().(*block.Header)
+	sig, err := bls.SignatureFromBytes(header.Signature[:])
+	if nil != err {
+		return err
+	}
+	if !sig.FastAggregateVerify(ss, header.Root) {
+		return fmt.Errorf("AggSignature verify falied")
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package internal
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/amazechain/amc/common/block"
+	"github.com/amazechain/amc/common/crypto/bls"
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/internal/consensus"
+	"github.com/amazechain/amc/params"
+	"github.com/holiman/uint256"
+)
+
+// Tests that bodies with substituted verifier or reward lists are rejected.
+func TestVerifyVerifierHash(t *testing.T) {
+	config := &params.ChainConfig{VerifierCommitmentBlock: big.NewInt(2)}
+	verifiers := []*block.Verify{{Address: types.Address{1}, PublicKey: types.PublicKey{1}}, {Address: types.Address{2}, PublicKey: types.PublicKey{2}}}
+	rewards := []*block.Reward{{Address: types.Address{1}, Amount: uint256.NewInt(100)}}
+	hash := block.CalcVerifierHash(verifiers, rewards)
+
+	newBlock := func(number uint64, verifierHash *types.Hash, verifiers []*block.Verify, rewards []*block.Reward) block.IBlock {
+		b := block.NewBlock(&block.Header{Number: uint256.NewInt(number), VerifierHash: verifierHash}, nil)
+		body := b.Body().(*block.Body)
+		body.Verifiers, body.Rewards = verifiers, rewards
+		return b
+	}
+	tests := []struct {
+		name  string
+		block block.IBlock
+		ok    bool
+	}{
+		{"before fork", newBlock(1, nil, verifiers, rewards), true},
+		{"committed", newBlock(2, &hash, verifiers, rewards), true},
+		{"missing", newBlock(2, nil, verifiers, rewards), false},
+		{"substituted verifier", newBlock(2, &hash, []*block.Verify{verifiers[0], {Address: types.Address{3}, PublicKey: types.PublicKey{3}}}, rewards), false},
+		{"dropped verifier", newBlock(2, &hash, verifiers[:1], rewards), false},
+		{"substituted reward", newBlock(2, &hash, verifiers, []*block.Reward{{Address: types.Address{3}, Amount: uint256.NewInt(100)}}), false},
+	}
+	for _, tt := range tests {
+		if err := verifyVerifierHash(config, tt.block); (err == nil) != tt.ok {
+			t.Errorf("%s: have error %v, want ok %v", tt.name, err, tt.ok)
+		}
+	}
+}
+
+// quorumEngine rejects every block signature it is asked to check.
+type quorumEngine struct {
+	consensus.Engine
+}
+
+func (quorumEngine) VerifyAggSign(block.IBlock) error { return errors.New("not a quorum") }
+
+// Tests that blocks before the verifier quorum fork only need a valid aggregate
+// signature of the listed keys, while later ones are checked by the engine.
+func TestVerifyAggSignFork(t *testing.T) {
+	config := &params.ChainConfig{BeijingBlock: big.NewInt(1), VerifierQuorumBlock: big.NewInt(3)}
+	key, err := bls.RandKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := types.Hash{0x01}
+	newBlock := func(number uint64) block.IBlock {
+		header := &block.Header{Number: uint256.NewInt(number), Root: root}
+		copy(header.Signature[:], key.Sign(root[:]).Marshal())
+		b := block.NewBlock(header, nil)
+		v := &block.Verify{Address: types.Address{1}}
+		copy(v.PublicKey[:], key.PublicKey().Marshal())
+		b.Body().(*block.Body).Verifiers = []*block.Verify{v}
+		return b
+	}
+	for number, ok := range map[uint64]bool{2: true, 3: false, 4: false} {
+		if err := verifyAggSign(config, quorumEngine{}, newBlock(number)); (err == nil) != ok {
+			t.Errorf("block %d: have error %v, want ok %v", number, err, ok)
+		}
+	}
+}
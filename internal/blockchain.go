@@ -43,6 +43,12 @@ import (
 	"github.com/amazechain/amc/modules/rawdb"
 	lru "github.com/hashicorp/golang-lru/v2"
 	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/rcrowley/go-metrics"
+)
+
+var (
+	headBlockGauge   = metrics.GetOrRegisterGauge("chain/head/block", nil)
+	blockInsertTimer = metrics.GetOrRegisterTimer("chain/inserts", nil)
 )
 
 var (
@@ -118,6 +124,16 @@ type BlockChain struct {
 
 	forker    *ForkChoice
 	validator Validator
+
+	blockStats         *blockStatsHistory
+	slowBlockThreshold time.Duration
+
+	pruneConfig PruneConfig
+	pruneCh     chan struct{}
+
+	safeBlock      atomic.Pointer[block2.Header]
+	finalizedBlock atomic.Pointer[block2.Header]
+	finalityCh     chan struct{}
 }
 
 type insertStats struct {
@@ -175,9 +191,18 @@ func NewBlockChain(ctx context.Context, genesisBlock block2.IBlock, engine conse
 
 		numberCache: numberCache,
 		headerCache: headerCache,
+
+		blockStats:         newBlockStatsHistory(blockStatsLimit),
+		slowBlockThreshold: DefaultSlowBlockThreshold,
+		pruneCh:            make(chan struct{}, 1),
+		finalityCh:         make(chan struct{}, 1),
 	}
 
 	bc.currentBlock.Store(current)
+	headBlockGauge.Update(int64(current.Number64().Uint64()))
+	if err := bc.loadFinality(); nil != err {
+		return nil, err
+	}
 	bc.forker = NewForkChoice(bc, nil)
 	//bc.process = avm.NewVMProcessor(ctx, bc, engine)
 	bc.process = NewStateProcessor(config, bc, engine)
@@ -228,10 +253,16 @@ func (bc *BlockChain) Start() error {
 		return ErrInvalidPubSub
 	}
 
-	bc.wg.Add(3)
+	bc.wg.Add(5)
 	go bc.runLoop()
 	go bc.newBlockLoop()
 	go bc.updateFutureBlocksLoop()
+	go bc.logIndexLoop()
+	go bc.finalityLoop()
+	if bc.pruneConfig.enabled() {
+		bc.wg.Add(1)
+		go bc.pruneLoop()
+	}
 
 	return nil
 }
@@ -272,6 +303,11 @@ func (bc *BlockChain) GetReceipts(blockHash types.Hash) (block2.Receipts, error)
 	if nil != err {
 		return nil, err
 	}
+	if number := rawdb.ReadHeaderNumber(rtx, blockHash); number != nil {
+		if err := rawdb.CheckPruned(rtx, rawdb.PruneReceipts, *number); err != nil {
+			return nil, err
+		}
+	}
 	return rawdb.ReadReceiptsByHash(rtx, blockHash)
 }
 
@@ -1034,6 +1070,7 @@ func (bc *BlockChain) insertChain(chain []block2.IBlock) (int, error) {
 	//	batch.Rollback()
 	//}()
 
+	var bstats *BlockStats
 	evmRecord := func(ctx context.Context, db kv.RwDB, blockNr uint64, f func(tx kv.RwTx, ibs *state.IntraBlockState, reader state.StateReader, writer state.WriterWithChangeSets) error) error {
 		tx, err := db.BeginRw(ctx)
 		if nil != err {
@@ -1059,7 +1096,12 @@ func (bc *BlockChain) insertChain(chain []block2.IBlock) (int, error) {
 		//	return err
 		//}
 
-		return tx.Commit()
+		commitStart := time.Now()
+		if err = tx.Commit(); nil != err {
+			return err
+		}
+		bstats.Commit += time.Since(commitStart)
+		return nil
 	}
 
 	for ; block != nil && err == nil || errors.Is(err, ErrKnownBlock); block, err = it.next() {
@@ -1073,6 +1115,12 @@ func (bc *BlockChain) insertChain(chain []block2.IBlock) (int, error) {
 			bc.CurrentBlock().Number64(), bc.CurrentBlock().Hash(), bc.CurrentBlock().Difficulty(), block.Number64(), block.Hash(), block.Difficulty())
 		// Retrieve the parent block and it's state to execute on top
 		start := time.Now()
+		bstats = &BlockStats{
+			Number:            block.Number64().Uint64(),
+			Txs:               len(block.Transactions()),
+			GasUsed:           block.GasUsed(),
+			SignatureRecovery: it.elapsed,
+		}
 		// TODO
 		//stateDB := statedb.NewStateDB(block.ParentHash(), bc.chainDB, bc.changeDB)
 		//stateReader, stateWriter, err := NewStateReaderWriter(batch, wtx, block.Number64().Uint64(), true)
@@ -1090,8 +1138,13 @@ func (bc *BlockChain) insertChain(chain []block2.IBlock) (int, error) {
 			}
 			blockHashFunc := GetHashFn(block.Header().(*block2.Header), getHeader)
 
-			var err error
-			receipts, logs, usedGas, err = bc.process.Process(tx, block.(*block2.Block), ibs, reader, writer, blockHashFunc)
+			commitment, err := OpenStateCommitment(tx, bc.chainConfig, block.Header().(*block2.Header))
+			if err != nil {
+				return err
+			}
+			ibs.SetStateCommitment(commitment)
+
+			receipts, logs, usedGas, err = bc.process.Process(tx, block.(*block2.Block), ibs, reader, writer, blockHashFunc, bstats)
 			if err != nil {
 				bc.reportBlock(block, receipts, err)
 				//atomic.StoreUint32(&followupInterrupt, 1)
@@ -1133,11 +1186,17 @@ func (bc *BlockChain) insertChain(chain []block2.IBlock) (int, error) {
 		//}
 
 		var status WriteStatus
+		writeStart := time.Now()
 		status, err = bc.writeBlockWithState(block, receipts)
 		//atomic.StoreUint32(&followupInterrupt, 1)
 		if err != nil {
 			return it.index, err
 		}
+		bstats.Commit += time.Since(writeStart)
+		bstats.Total = bstats.SignatureRecovery + time.Since(start)
+		hash := block.Hash()
+		bstats.Hash = &hash
+		bc.RecordBlockStats(bstats)
 
 		// Report the import stats before returning the various results
 		stats.processed++
@@ -1389,6 +1448,11 @@ func (bc *BlockChain) writeBlockWithState(block block2.IBlock, receipts []*block
 		if err := rawdb.WriteBlock(tx, block.(*block2.Block)); err != nil {
 			return err
 		}
+		if tracker, ok := bc.engine.(consensus.DepositTracker); ok {
+			if err := tracker.WriteDeposits(tx, block, receipts); nil != err {
+				return err
+			}
+		}
 		return nil
 	}); nil != err {
 		return NonStatTy, err
@@ -1447,16 +1511,36 @@ func (bc *BlockChain) writeHeadBlock(tx kv.RwTx, block block2.IBlock) error {
 	if err = rawdb.WriteCanonicalHash(tx, block.Hash(), block.Number64().Uint64()); nil != err {
 		return err
 	}
+	if err = bc.indexBlockLogs(tx, block.Number64().Uint64()); nil != err {
+		return err
+	}
+	if err = bc.indexBlockCallTraces(tx, block.Number64().Uint64(), block.Hash()); nil != err {
+		return err
+	}
+	if ledger, ok := bc.engine.(consensus.Ledger); ok {
+		if err = ledger.ApplyBlock(tx, block); nil != err {
+			return err
+		}
+	}
 
 	bc.currentBlock.Store(block.(*block2.Block))
+	headBlockGauge.Update(int64(block.Number64().Uint64()))
 	if notExternalTx {
 		if err = tx.Commit(); nil != err {
 			return err
 		}
+		bc.headCommitted()
 	}
 	return nil
 }
 
+// headCommitted wakes the pruner and the finality tracker up once a new head is
+// committed. Callers of writeHeadBlock with their own tx call it after committing.
+func (bc *BlockChain) headCommitted() {
+	bc.notifyPruner()
+	bc.notifyFinality()
+}
+
 // reportBlock logs a bad block error.
 func (bc *BlockChain) reportBlock(block block2.IBlock, receipts []*block2.Receipt, err error) {
 
@@ -1489,17 +1573,6 @@ func (bc *BlockChain) ReorgNeeded(current block2.IBlock, header block2.IBlock) b
 	return true
 }
 
-// SetHead set new head
-func (bc *BlockChain) SetHead(head uint64) error {
-	newHeadBlock, err := bc.GetBlockByNumber(uint256.NewInt(head))
-	if err != nil {
-		return nil
-	}
-	return bc.ChainDB.Update(bc.ctx, func(tx kv.RwTx) error {
-		return rawdb.WriteHeadHeaderHash(tx, newHeadBlock.Hash())
-	})
-}
-
 // addFutureBlock checks if the block is within the max allowed window to get
 // accepted for future processing, and returns an error if the block is too far
 // ahead and was not added.
@@ -1548,6 +1621,7 @@ func (bc *BlockChain) writeKnownBlock(tx kv.RwTx, block block2.IBlock) error {
 		if err = tx.Commit(); nil != err {
 			return err
 		}
+		bc.headCommitted()
 	}
 	return nil
 }
@@ -1589,7 +1663,7 @@ func (bc *BlockChain) reorg(tx kv.RwTx, oldBlock, newBlock block2.IBlock) error
 		return fmt.Errorf("invalid new chain")
 	}
 
-	var useExternalTx bool
+	useExternalTx := tx != nil
 	var err error
 	if tx == nil {
 		tx, err = bc.ChainDB.BeginRw(bc.ctx)
@@ -1597,7 +1671,6 @@ func (bc *BlockChain) reorg(tx kv.RwTx, oldBlock, newBlock block2.IBlock) error
 			return err
 		}
 		defer tx.Rollback()
-		useExternalTx = false
 	}
 
 	// Both sides of the reorg are at the same number, reduce both until the common
@@ -1629,6 +1702,10 @@ func (bc *BlockChain) reorg(tx kv.RwTx, oldBlock, newBlock block2.IBlock) error
 		}
 	}
 
+	// Finality is only reported over RPC, it does not take part in the fork choice
+	if final := bc.CurrentFinalizedBlock(); final != nil && len(oldChain) > 0 && final.Number64().Uint64() > commonBlock.Number64().Uint64() {
+		log.Warn("Chain reorg drops finalized blocks", "number", commonBlock.Number64(), "finalized", final.Number64())
+	}
 	// Ensure the user sees large reorgs
 	if len(oldChain) > 0 && len(newChain) > 0 {
 		logFn := log.Info
@@ -1648,11 +1725,26 @@ func (bc *BlockChain) reorg(tx kv.RwTx, oldBlock, newBlock block2.IBlock) error
 		// rewind the canonical chain to a lower point.
 		log.Error("Impossible reorg, please file an issue", "oldnum", oldBlock.Number64(), "oldhash", oldBlock.Hash(), "oldblocks", len(oldChain), "newnum", newBlock.Number64(), "newhash", newBlock.Hash(), "newblocks", len(newChain))
 	}
+	// Drop the log and call indices of the abandoned blocks before the new ones are indexed.
+	if err = unwindLogIndex(tx, commonBlock.Number64().Uint64()+1); nil != err {
+		return err
+	}
+	if err = unwindCallIndex(tx, commonBlock.Number64().Uint64()+1); nil != err {
+		return err
+	}
+	// Revert the accounts the engine keeps for the abandoned blocks.
+	if ledger, ok := bc.engine.(consensus.Ledger); ok {
+		if err = ledger.UnwindBlocks(tx, commonBlock.Number64().Uint64()); nil != err {
+			return err
+		}
+	}
 	// Insert the new chain(except the head block(reverse order)),
 	// taking care of the proper incremental order.
 	for i := len(newChain) - 1; i >= 1; i-- {
 		// Insert the block in the canonical way, re-writing history
-		bc.writeHeadBlock(tx, newChain[i])
+		if err = bc.writeHeadBlock(tx, newChain[i]); nil != err {
+			return err
+		}
 
 		// Collect the new added transactions.
 		for _, tx := range newChain[i].Transactions() {
@@ -1687,6 +1779,7 @@ func (bc *BlockChain) reorg(tx kv.RwTx, oldBlock, newBlock block2.IBlock) error
 		if err = tx.Commit(); nil != err {
 			return err
 		}
+		bc.headCommitted()
 	}
 
 	return nil
@@ -76,8 +76,9 @@ type insertIterator struct {
 	results <-chan error // Verification result sink from the consensus engine
 	errors  []error      // Header verification errors for the blocks
 
-	index     int       // Current offset of the iterator
-	validator Validator // Validator to run if verification succeeds
+	index     int           // Current offset of the iterator
+	validator Validator     // Validator to run if verification succeeds
+	elapsed   time.Duration // Time spent waiting for and validating the last returned block
 }
 
 // newInsertIterator creates a new iterator based on the given blocks, which are
@@ -100,6 +101,9 @@ func (it *insertIterator) next() (block2.IBlock, error) {
 		it.index = len(it.chain)
 		return nil, nil
 	}
+	start := time.Now()
+	defer func() { it.elapsed = time.Since(start) }()
+
 	// Advance the iterator and wait for verification result if not yet done
 	it.index++
 	if len(it.errors) <= it.index {
@@ -31,6 +31,7 @@ import (
 	"github.com/amazechain/amc/modules/state"
 	"github.com/amazechain/amc/params"
 	"github.com/ledgerwatch/erigon-lib/kv"
+	"time"
 )
 
 //var (
@@ -386,8 +387,12 @@ func SysCallContract(contract types.Address, data []byte, chainConfig params.Cha
 
 func FinalizeBlockExecution(tx kv.RwTx, engine consensus.Engine, header *block.Header,
 	txs transaction.Transactions, stateWriter state.WriterWithChangeSets, cc *params.ChainConfig, ibs *state.IntraBlockState,
-	receipts block.Receipts, headerReader consensus.ChainHeaderReader, isMining, isBeijing bool,
+	receipts block.Receipts, headerReader consensus.ChainHeaderReader, isMining, isBeijing bool, stats *BlockStats,
 ) (newBlock block.IBlock, newTxs transaction.Transactions, newReceipt block.Receipts, err error) {
+	var (
+		start          = time.Now()
+		rewardsElapsed time.Duration
+	)
 	//syscall := func(contract types.Address, data []byte) ([]byte, error) {
 	//	return SysCallContract(contract, data, *cc, ibs, header, engine)
 	//}
@@ -398,6 +403,7 @@ func FinalizeBlockExecution(tx kv.RwTx, engine consensus.Engine, header *block.H
 		if err != nil {
 			return nil, nil, nil, err
 		}
+		rewardsElapsed = time.Since(start)
 		ibs.SoftFinalise()
 	}
 
@@ -414,6 +420,12 @@ func FinalizeBlockExecution(tx kv.RwTx, engine consensus.Engine, header *block.H
 		return nil, nil, nil, fmt.Errorf("committing block %d failed: %w", header.Number.Uint64(), err)
 	}
 
+	if commitment := ibs.StateCommitment(); commitment != nil {
+		if _, err := commitment.Commit(tx); err != nil {
+			return nil, nil, nil, fmt.Errorf("committing state trie of block %d failed: %w", header.Number.Uint64(), err)
+		}
+	}
+
 	if err := stateWriter.WriteChangeSets(); err != nil {
 		return nil, nil, nil, fmt.Errorf("writing changesets for block %d failed: %w", header.Number.Uint64(), err)
 	}
@@ -422,6 +434,10 @@ func FinalizeBlockExecution(tx kv.RwTx, engine consensus.Engine, header *block.H
 		return nil, nil, nil, fmt.Errorf("writing history for block %d failed: %w", header.Number.Uint64(), err)
 	}
 
+	if stats != nil {
+		stats.Rewards += rewardsElapsed
+		stats.StateWrite += time.Since(start) - rewardsElapsed
+	}
 	return newBlock, newTxs, newReceipt, nil
 }
 
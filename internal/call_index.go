@@ -0,0 +1,40 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package internal
+
+import (
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/modules/rawdb"
+	"github.com/ledgerwatch/erigon-lib/kv"
+)
+
+// indexBlockCallTraces adds the call trace set recorded by the StateProcessor for a
+// block that just became canonical to CallFromIndex and CallToIndex.
+//
+// Blocks imported before the index existed are not traced again; the trace_
+// namespace replays them on demand instead (see rawdb.ReadCallIndexFrom).
+func (bc *BlockChain) indexBlockCallTraces(tx kv.RwTx, number uint64, hash types.Hash) error {
+	if number == 0 {
+		return nil
+	}
+	return rawdb.WriteCallIndex(tx, number, hash)
+}
+
+// unwindCallIndex removes the blocks from the given number onwards from the call index.
+func unwindCallIndex(tx kv.RwTx, from uint64) error {
+	return rawdb.UnwindCallIndex(tx, from)
+}
@@ -0,0 +1,31 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package apoa
+
+import (
+	"github.com/amazechain/amc/common/block"
+	"github.com/amazechain/amc/internal/consensus"
+)
+
+// Finality implements consensus.FinalityReader with the signer rules.
+func (c *Apoa) Finality(chain consensus.ChainReader, head block.IHeader) (uint64, uint64, error) {
+	snap, err := c.snapshot(chain, head.Number64().Uint64(), head.Hash(), nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	return consensus.SignerFinality(chain, head, len(snap.Signers), c.Author, nil)
+}
@@ -36,6 +36,7 @@ import (
 	mvm_types "github.com/amazechain/amc/internal/avm/types"
 	"github.com/amazechain/amc/internal/consensus"
 	"github.com/amazechain/amc/modules/rpc/jsonrpc"
+	"github.com/amazechain/amc/params"
 )
 
 const maxSearchBlock = 1000
@@ -291,7 +292,7 @@ func (api *API) GetDepositInfo(address common.Address) (*deposit.Info, error) {
 	var err error
 
 	api.apos.db.View(context.Background(), func(tx kv.Tx) error {
-		info = deposit.GetDepositInfo(tx, addr)
+		info = deposit.GetDepositInfo(tx, addr, api.apos.chainConfig.DepositScheduleAt(api.chain.CurrentBlock().Number64().Uint64()))
 		return nil
 	})
 
@@ -326,6 +327,55 @@ func (api *API) GetBlockRewards(blockNr jsonrpc.BlockNumberOrHash) (map[types.Ad
 	return resp, err
 }
 
+// GetEpochRewards returns the rewards paid out by the reward block of the epoch.
+func (api *API) GetEpochRewards(epoch hexutil.Uint64) (*EpochRewards, error) {
+	var resp *EpochRewards
+	err := api.apos.db.View(context.Background(), func(tx kv.Tx) (err error) {
+		resp, err = newReward(api.apos.config, api.apos.chainConfig).EpochRewards(tx, uint64(epoch))
+		return err
+	})
+	return resp, err
+}
+
+// GetUnpaidReward returns the reward the address carries over below the payout
+// limit and what it earned so far towards the next payout.
+func (api *API) GetUnpaidReward(address common.Address) (*UnpaidReward, error) {
+	var resp *UnpaidReward
+	err := api.apos.db.View(context.Background(), func(tx kv.Tx) (err error) {
+		resp, err = newReward(api.apos.config, api.apos.chainConfig).UnpaidReward(tx, *mvm_types.ToAmcAddress(&address))
+		return err
+	})
+	return resp, err
+}
+
+// GetRewardHistory returns the rewards paid out to the address by the reward
+// blocks between from and to.
+func (api *API) GetRewardHistory(address common.Address, from jsonrpc.BlockNumberOrHash, to jsonrpc.BlockNumberOrHash) ([]*RewardHistoryEntry, error) {
+	var resp []*RewardHistoryEntry
+	err := api.apos.db.View(context.Background(), func(tx kv.Tx) error {
+		resolvedFromBlock, _, err := rpchelper.GetCanonicalBlockNumber(from, tx)
+		if err != nil {
+			return err
+		}
+		resolvedToBlock, _, err := rpchelper.GetCanonicalBlockNumber(to, tx)
+		if err != nil {
+			return err
+		}
+		resp, err = newReward(api.apos.config, api.apos.chainConfig).RewardHistory(tx, *mvm_types.ToAmcAddress(&address), resolvedFromBlock.Uint64(), resolvedToBlock.Uint64())
+		return err
+	})
+	return resp, err
+}
+
+// GetDepositSchedule returns the deposit tiers and rewards in force at the block.
+func (api *API) GetDepositSchedule(blockNr jsonrpc.BlockNumberOrHash) (*params.DepositSchedule, error) {
+	header := api.getHeader(blockNr)
+	if header == nil {
+		return nil, fmt.Errorf("missing block %v", blockNr.String())
+	}
+	return api.apos.chainConfig.DepositScheduleAt(header.Number64().Uint64()), nil
+}
+
 // getHeader search header by BlockNumberOrHash
 func (api *API) getHeader(from jsonrpc.BlockNumberOrHash) (currentHeader block.IHeader) {
 	//
@@ -359,7 +409,7 @@ func (api *API) GetMinedBlock(address common.Address, from jsonrpc.BlockNumberOr
 	)
 
 	api.apos.db.View(context.Background(), func(tx kv.Tx) error {
-		depositInfo = deposit.GetDepositInfo(tx, addr)
+		depositInfo = deposit.GetDepositInfo(tx, addr, api.apos.chainConfig.DepositScheduleAt(api.chain.CurrentBlock().Number64().Uint64()))
 		return nil
 	})
 	if depositInfo == nil {
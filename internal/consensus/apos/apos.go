@@ -38,6 +38,7 @@ import (
 	"github.com/amazechain/amc/common/transaction"
 	"github.com/amazechain/amc/common/types"
 	"github.com/amazechain/amc/conf"
+	"github.com/amazechain/amc/contracts/deposit"
 	"github.com/amazechain/amc/internal/avm/common"
 	"github.com/amazechain/amc/internal/avm/rlp"
 	mvm_types "github.com/amazechain/amc/internal/avm/types"
@@ -70,6 +71,9 @@ const (
 var (
 	epochLength = uint64(30000) // Default number of blocks after which to checkpoint and reset the pending votes
 
+	quorumNumerator   = uint64(2) // Default share of the active depositors that must sign a block
+	quorumDenominator = uint64(3)
+
 	extraVanity = 32                     // Fixed number of extra-data prefix bytes reserved for signer vanity
 	extraSeal   = crypto.SignatureLength // Fixed number of extra-data suffix bytes reserved for signer seal
 
@@ -150,6 +154,25 @@ var (
 	// errRecentlySigned is returned if a header is signed by an authorized entity
 	// that already signed a header recently, thus is temporarily not allowed to.
 	errRecentlySigned = errors.New("recently signed")
+
+	// errUnknownVerifier is returned if a block is signed by an address that has
+	// no deposit, or with another key than the deposited one.
+	errUnknownVerifier = errors.New("unknown verifier")
+
+	// errDuplicateVerifier is returned if a verifier is listed twice in a block.
+	errDuplicateVerifier = errors.New("duplicate verifier")
+
+	// errInvalidVerifierHash is returned if a block has a verifier commitment before
+	// the fork, or none after it.
+	errInvalidVerifierHash = errors.New("invalid verifier hash")
+
+	// errInvalidAggSign is returned if the aggregate signature of a block does not
+	// match its verifiers.
+	errInvalidAggSign = errors.New("invalid aggregate signature")
+
+	// errInvalidRewards is returned if a block lists other rewards than the ones
+	// it pays out.
+	errInvalidRewards = errors.New("invalid rewards")
 )
 
 // SignerFn hashes and signs the data to be signed by a backing account.
@@ -171,7 +194,7 @@ func ecrecover(iHeader block.IHeader, sigcache *lru.ARCCache) (types.Address, er
 	signature := header.Extra[len(header.Extra)-extraSeal:]
 
 	//Recover the public key and the Ethereum(AMC) address
-	pubkey, err := crypto.Ecrecover(SealHash(header).Bytes(), signature)
+	pubkey, err := crypto.Ecrecover(crypto.Keccak256(APosProto(header)), signature)
 	if err != nil {
 		return types.Address{}, err
 	}
@@ -212,6 +235,9 @@ func New(config *conf.ConsensusConfig, db kv.RwDB, chainConfig *params.ChainConf
 	if conf.APos.Epoch == 0 {
 		conf.APos.Epoch = epochLength
 	}
+	if conf.APos.QuorumDenominator == 0 {
+		conf.APos.QuorumNumerator, conf.APos.QuorumDenominator = quorumNumerator, quorumDenominator
+	}
 	// Allocate the snapshot caches and create the engine
 	recents, _ := lru.NewARC(inmemorySnapshots)
 	signatures, _ := lru.NewARC(inmemorySignatures)
@@ -322,6 +348,21 @@ func (c *APos) verifyHeader(chain consensus.ChainHeaderReader, iHeader block.IHe
 	if header.GasLimit > params.MaxGasLimit {
 		return fmt.Errorf("invalid gasLimit: have %v, max %v", header.GasLimit, params.MaxGasLimit)
 	}
+	// Verify the presence of the verifier commitment
+	if c.chainConfig.IsVerifierCommitment(number) != (header.VerifierHash != nil) {
+		return errInvalidVerifierHash
+	}
+	// Blocks must be signed by the verifiers once there are depositors
+	if c.chainConfig.IsVerifierQuorum(number) && header.Signature == (types.Signature{}) {
+		// The depositors of a parent not written yet are checked with the body
+		_, quorum, err := c.quorumOf(header)
+		if err != nil && !errors.Is(err, deposit.ErrUnknownDeposits) {
+			return err
+		}
+		if quorum > 0 {
+			return consensus.ErrNotEnoughSign
+		}
+	}
 	// If all checks passed, validate any special fields for hard forks
 	//todo
 	//if err := misc.VerifyForkHashes(chain.Config(), header, false); err != nil {
@@ -613,40 +654,112 @@ func (c *APos) Prepare(chain consensus.ChainHeaderReader, header block.IHeader)
 }
 
 func (c *APos) Rewards(tx kv.RwTx, header block.IHeader, state *state.IntraBlockState, setRewards bool) ([]*block.Reward, error) {
-	//calc rewards
-	var rewards []*block.Reward
-
-	beijing, _ := uint256.FromBig(c.chainConfig.BeijingBlock)
-	if new(uint256.Int).Mod(new(uint256.Int).Sub(header.Number64(), beijing), uint256.NewInt(c.config.APos.RewardEpoch)).
-		Cmp(uint256.NewInt(0)) == 0 {
-		log.Debug("begin setreward", "headnumber", header.Number64().ToBig().String())
+	rewards, err := c.blockRewards(tx, header)
+	if err != nil {
+		log.Error("setreward error", "err", err)
+		return nil, err
+	}
+	for _, reward := range rewards {
+		if !state.Exist(reward.Address) {
+			state.CreateAccount(reward.Address, false)
+		}
+		state.AddBalance(reward.Address, reward.Amount)
+		log.Info("set rewards balance:", "addr", reward.Address, "value", reward.Amount)
+	}
+	return rewards, nil
+}
 
-		rewardService := newReward(c.config, c.chainConfig)
-		accRewards, err := rewardService.SetRewards(tx, header.Number64(), setRewards)
-		if err != nil {
-			log.Error("setreward error", "err", err)
-			return nil, err
+// blockRewards returns the rewards paid out by the block, as listed in its body.
+func (c *APos) blockRewards(tx kv.Tx, header block.IHeader) ([]*block.Reward, error) {
+	accRewards, err := newReward(c.config, c.chainConfig).BlockRewards(tx, header)
+	if err != nil {
+		return nil, err
+	}
+	var rewards []*block.Reward
+	for _, detail := range accRewards {
+		if detail.Value.Cmp(uint256.NewInt(0)) > 0 {
+			rewards = append(rewards, &block.Reward{
+				Address: detail.Account,
+				Amount:  detail.Value,
+			})
 		}
+	}
+	return rewards, nil
+}
 
-		for _, detail := range accRewards {
-			if detail.Value.Cmp(uint256.NewInt(0)) > 0 {
-				addr := detail.Account
-				if !state.Exist(addr) {
-					state.CreateAccount(addr, false)
-				}
+// VerifyRewards implements consensus.RewardVerifier, checking that a block lists
+// exactly the rewards it pays out.
+func (c *APos) VerifyRewards(b block.IBlock) error {
+	var rewards []*block.Reward
+	if err := c.db.View(context.Background(), func(tx kv.Tx) error {
+		var err error
+		rewards, err = c.blockRewards(tx, b.Header())
+		return err
+	}); err != nil {
+		return err
+	}
+	listed := b.Body().Reward()
+	if len(listed) != len(rewards) {
+		return fmt.Errorf("%w: have %d, want %d", errInvalidRewards, len(listed), len(rewards))
+	}
+	for i, reward := range rewards {
+		if listed[i].Address != reward.Address || listed[i].Amount == nil || !listed[i].Amount.Eq(reward.Amount) {
+			return fmt.Errorf("%w: have %v %v, want %v %v", errInvalidRewards, listed[i].Address, listed[i].Amount, reward.Address, reward.Amount)
+		}
+	}
+	return nil
+}
 
-				state.AddBalance(addr, detail.Value)
-				rewards = append(rewards, &block.Reward{
-					Address: addr,
-					Amount:  detail.Value,
-				})
+// ApplyBlock implements consensus.Ledger, accounting the rewards of a block
+// becoming the head of the canonical chain.
+func (c *APos) ApplyBlock(tx kv.RwTx, b block.IBlock) error {
+	if err := newReward(c.config, c.chainConfig).ApplyCanonical(tx, b); err != nil {
+		return err
+	}
+	// The Deposit table follows the head
+	deposits, ok, err := rawdb.ReadDepositSnapshot(tx, b.Hash(), b.Number64().Uint64())
+	if err != nil || !ok {
+		return err
+	}
+	return rawdb.WriteDeposits(tx, deposits)
+}
 
-				log.Info("set rewards balance:", "addr", addr, "value", detail.Value)
-			}
-		}
+// UnwindBlocks implements consensus.Ledger, reverting the rewards accounted for
+// the canonical blocks after target and the Deposit table to its state at target.
+func (c *APos) UnwindBlocks(tx kv.RwTx, target uint64) error {
+	if err := newReward(c.config, c.chainConfig).UnwindCanonical(tx, target); err != nil {
+		return fmt.Errorf("unwinding rewards: %w", err)
+	}
+	if err := c.unwindDeposits(tx, target); err != nil {
+		return fmt.Errorf("unwinding deposits: %w", err)
 	}
+	return nil
+}
 
-	return rewards, nil
+// Unwind implements consensus.Unwinder, reverting the rewards and deposits
+// recorded by the blocks after target.
+func (c *APos) Unwind(tx kv.RwTx, head, target uint64) error {
+	return c.UnwindBlocks(tx, target)
+}
+
+// unwindDeposits restores the Deposit table from the snapshot of the canonical
+// block at target, replaying the deposit events when there is none.
+func (c *APos) unwindDeposits(tx kv.RwTx, target uint64) error {
+	hash, err := rawdb.ReadCanonicalHash(tx, target)
+	if err != nil {
+		return err
+	}
+	deposits, ok, err := rawdb.ReadDepositSnapshot(tx, hash, target)
+	if err != nil {
+		return err
+	}
+	if ok {
+		return rawdb.WriteDeposits(tx, deposits)
+	}
+	if c.config.APos.DepositContract == "" {
+		return nil
+	}
+	return deposit.UnwindDeposits(tx, c.chainConfig, types.HexToAddress(c.config.APos.DepositContract), target)
 }
 
 // Finalize implements consensus.Engine, ensuring no uncles are set, nor block
@@ -737,26 +850,34 @@ func (c *APos) Seal(chain consensus.ChainHeaderReader, b block.IBlock, results c
 	if c.chainConfig.IsBeijing(header.Number.Uint64()) {
 		ctx, cancle := context.WithTimeout(context.Background(), delay)
 		defer cancle()
-		member := c.CountDepositor()
-		aggSign, verifiers, err := api.SignMerge(ctx, header, member)
-		if nil != err {
-			return err
-		}
-		ss := make([]bls.PublicKey, len(verifiers))
-		for i, p := range verifiers {
-			blsP, err := bls.PublicKeyFromBytes(p.PublicKey[:])
-			if nil != err {
+		// Before the verifier quorum fork a single signature of anyone suffices
+		deposits, quorum := rawdb.Deposits(nil), uint64(1)
+		if c.chainConfig.IsVerifierQuorum(header.Number.Uint64()) {
+			if deposits, quorum, err = c.quorumOf(header); nil != err {
 				return err
 			}
-			ss[i] = blsP
 		}
-
-		sig, err := bls.SignatureFromBytes(aggSign[:])
+		aggSign, verifiers, err := api.SignMerge(ctx, deposits, header, quorum)
 		if nil != err {
 			return err
 		}
-		if !sig.FastAggregateVerify(ss, header.Root) {
-			return fmt.Errorf("AggSignature verify falied")
+		if len(verifiers) > 0 {
+			ss := make([]bls.PublicKey, len(verifiers))
+			for i, p := range verifiers {
+				blsP, err := bls.PublicKeyFromBytes(p.PublicKey[:])
+				if nil != err {
+					return err
+				}
+				ss[i] = blsP
+			}
+
+			sig, err := bls.SignatureFromBytes(aggSign[:])
+			if nil != err {
+				return err
+			}
+			if !sig.FastAggregateVerify(ss, header.Root) {
+				return fmt.Errorf("AggSignature verify falied")
+			}
 		}
 
 		header.Signature = aggSign
@@ -768,6 +889,10 @@ func (c *APos) Seal(chain consensus.ChainHeaderReader, b block.IBlock, results c
 		//}
 
 	}
+	if c.chainConfig.IsVerifierCommitment(number) {
+		verifierHash := block.CalcVerifierHash(b.Body().Verifier(), b.Body().Reward())
+		header.VerifierHash = &verifierHash
+	}
 
 	// Sign all the things!
 	sighash, err := signFn(accounts.Account{Address: signer}, accounts.MimetypeClique, APosProto(header))
@@ -853,9 +978,14 @@ func SealHash(header block.IHeader) (hash types.Hash) {
 	return hash
 }
 
+// APosProto returns the data the signer signs, which unlike the seal hash also
+// covers the verifiers collected while sealing.
 func APosProto(header block.IHeader) []byte {
 	b := new(bytes.Buffer)
 	encodeSigHeader(b, header)
+	if verifierHash := header.(*block.Header).VerifierHash; verifierHash != nil {
+		b.Write(verifierHash[:])
+	}
 	return b.Bytes()
 }
 
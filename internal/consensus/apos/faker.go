@@ -28,61 +28,69 @@ import (
 	"github.com/ledgerwatch/erigon-lib/kv"
 )
 
+// Faker is a consensus engine that accepts every header and seals blocks
+// immediately. It is meant for tests and in-memory chains that have no
+// signers, such as the simulated contract backend.
 type Faker struct{}
 
+// Author returns the coinbase of the header, there is no seal to recover.
 func (f Faker) Author(header block.IHeader) (types.Address, error) {
-	//TODO implement me
-	panic("implement me")
+	return header.(*block.Header).Coinbase, nil
 }
 
 func (f Faker) VerifyHeader(chain consensus.ChainHeaderReader, header block.IHeader, seal bool) error {
-	//TODO implement me
-	panic("implement me")
+	return nil
 }
 
 func (f Faker) VerifyHeaders(chain consensus.ChainHeaderReader, headers []block.IHeader, seals []bool) (chan<- struct{}, <-chan error) {
-	//TODO implement me
-	panic("implement me")
+	abort, results := make(chan struct{}), make(chan error, len(headers))
+	for range headers {
+		results <- nil
+	}
+	return abort, results
 }
 
 func (f Faker) VerifyUncles(chain consensus.ChainReader, block block.IBlock) error {
-	//TODO implement me
-	panic("implement me")
+	return nil
 }
 
+// Prepare sets the constant faker difficulty on the header.
 func (f Faker) Prepare(chain consensus.ChainHeaderReader, header block.IHeader) error {
-	//TODO implement me
-	panic("implement me")
+	header.(*block.Header).Difficulty = f.CalcDifficulty(chain, 0, nil)
+	return nil
 }
 
+// Finalize sets the state root, no rewards are paid.
 func (f Faker) Finalize(chain consensus.ChainHeaderReader, header block.IHeader, state *state.IntraBlockState, txs []*transaction.Transaction, uncles []block.IHeader) {
-	//TODO implement me
-	panic("implement me")
+	rawHeader := header.(*block.Header)
+	rawHeader.Root = state.IntermediateRoot()
+	rawHeader.MixDigest = state.BeforeStateRoot()
 }
 
 func (f Faker) FinalizeAndAssemble(chain consensus.ChainHeaderReader, header block.IHeader, state *state.IntraBlockState, txs []*transaction.Transaction, uncles []block.IHeader, receipts []*block.Receipt, reward []*block.Reward) (block.IBlock, error) {
-	//TODO implement me
-	panic("implement me")
+	f.Finalize(chain, header, state, txs, uncles)
+	return block.NewBlockFromReceipt(header, txs, uncles, receipts, reward), nil
 }
 
 func (f Faker) Rewards(tx kv.RwTx, header block.IHeader, state *state.IntraBlockState, setRewards bool) ([]*block.Reward, error) {
-	//TODO implement me
-	panic("implement me")
+	return nil, nil
 }
 
+// Seal hands the block back unchanged.
 func (f Faker) Seal(chain consensus.ChainHeaderReader, block block.IBlock, results chan<- block.IBlock, stop <-chan struct{}) error {
-	//TODO implement me
-	panic("implement me")
+	select {
+	case results <- block:
+	case <-stop:
+	}
+	return nil
 }
 
 func (f Faker) SealHash(header block.IHeader) types.Hash {
-	//TODO implement me
-	panic("implement me")
+	return header.Hash()
 }
 
 func (f Faker) CalcDifficulty(chain consensus.ChainHeaderReader, time uint64, parent block.IHeader) *uint256.Int {
-	//TODO implement me
-	panic("implement me")
+	return uint256.NewInt(1)
 }
 
 func (f Faker) Type() params.ConsensusType {
@@ -90,13 +98,11 @@ func (f Faker) Type() params.ConsensusType {
 }
 
 func (f Faker) APIs(chain consensus.ChainReader) []jsonrpc.API {
-	//TODO implement me
-	panic("implement me")
+	return nil
 }
 
 func (f Faker) Close() error {
-	//TODO implement me
-	panic("implement me")
+	return nil
 }
 
 func NewFaker() consensus.Engine {
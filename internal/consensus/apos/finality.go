@@ -0,0 +1,40 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package apos
+
+import (
+	"github.com/amazechain/amc/common/block"
+	"github.com/amazechain/amc/internal/consensus"
+)
+
+// Finality implements consensus.FinalityReader. On top of the signer rules a safe
+// block is finalized once its state root is attested by a quorum of verifiers.
+func (c *APos) Finality(chain consensus.ChainReader, head block.IHeader) (uint64, uint64, error) {
+	snap, err := c.snapshot(chain, head.Number64().Uint64(), head.Hash(), nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	attested := func(header block.IHeader) bool {
+		_, quorum, err := c.quorumOf(header)
+		if err != nil || quorum == 0 {
+			return false
+		}
+		b := chain.GetBlock(header.Hash(), header.Number64().Uint64())
+		return b != nil && uint64(len(b.Body().Verifier())) >= quorum
+	}
+	return consensus.SignerFinality(chain, head, len(snap.Signers), c.Author, attested)
+}
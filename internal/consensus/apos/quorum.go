@@ -0,0 +1,127 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package apos
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/amazechain/amc/common/block"
+	"github.com/amazechain/amc/common/crypto/bls"
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/contracts/deposit"
+	"github.com/amazechain/amc/internal/consensus"
+	"github.com/amazechain/amc/modules/rawdb"
+	"github.com/ledgerwatch/erigon-lib/kv"
+)
+
+// Quorum returns the number of verifiers that must sign a block, the configured
+// share of its signers rounded up.
+func (c *APos) Quorum(deposits rawdb.Deposits) uint64 {
+	numerator, denominator := c.config.APos.QuorumNumerator, c.config.APos.QuorumDenominator
+	return (uint64(len(deposits))*numerator + denominator - 1) / denominator
+}
+
+// parentDeposits returns the depositors after the parent of the header.
+func (c *APos) parentDeposits(tx kv.Tx, header block.IHeader) (rawdb.Deposits, error) {
+	return deposit.ReadDeposits(tx, header.(*block.Header).ParentHash, header.Number64().Uint64()-1)
+}
+
+// signers returns the depositors after the parent of the header whose deposit
+// reaches the minimum deposit in force at the block, who sign it.
+func (c *APos) signers(tx kv.Tx, header block.IHeader) (rawdb.Deposits, error) {
+	deposits, err := c.parentDeposits(tx, header)
+	if err != nil {
+		return nil, err
+	}
+	return deposit.Verifiers(deposits, c.chainConfig.DepositScheduleAt(header.Number64().Uint64())), nil
+}
+
+// quorumOf returns the signers and the signing quorum of the block with the
+// given header.
+func (c *APos) quorumOf(header block.IHeader) (rawdb.Deposits, uint64, error) {
+	var deposits rawdb.Deposits
+	if err := c.db.View(context.Background(), func(tx kv.Tx) error {
+		var err error
+		deposits, err = c.signers(tx, header)
+		return err
+	}); err != nil {
+		return nil, 0, err
+	}
+	return deposits, c.Quorum(deposits), nil
+}
+
+// WriteDeposits implements consensus.DepositTracker, recording the depositors
+// after the block from the events of the deposit contract.
+func (c *APos) WriteDeposits(tx kv.RwTx, b block.IBlock, receipts block.Receipts) error {
+	parent, err := c.parentDeposits(tx, b.Header())
+	if err != nil {
+		return err
+	}
+	schedule := c.chainConfig.DepositScheduleAt(b.Number64().Uint64())
+	deposits, err := deposit.ApplyBlock(parent, types.HexToAddress(c.config.APos.DepositContract), schedule, b, receipts)
+	if err != nil {
+		return err
+	}
+	return rawdb.WriteDepositSnapshot(tx, b.Hash(), b.Number64().Uint64(), deposits)
+}
+
+// VerifyAggSign implements consensus.AggSignVerifier, checking that a block is
+// signed by at least a quorum of distinct signers with their deposited keys.
+func (c *APos) VerifyAggSign(b block.IBlock) error {
+	header := b.Header().(*block.Header)
+	verifiers := b.Body().Verifier()
+
+	deposits, quorum, err := c.quorumOf(header)
+	if err != nil {
+		return err
+	}
+	if uint64(len(verifiers)) < quorum {
+		return fmt.Errorf("%w: have %d, want %d", consensus.ErrNotEnoughSign, len(verifiers), quorum)
+	}
+	if len(verifiers) == 0 {
+		if header.Signature != (types.Signature{}) {
+			return errInvalidAggSign
+		}
+		return nil
+	}
+
+	seen := make(map[types.Address]struct{}, len(verifiers))
+	keys := make([]bls.PublicKey, len(verifiers))
+	for i, v := range verifiers {
+		if _, ok := seen[v.Address]; ok {
+			return fmt.Errorf("%w: %s", errDuplicateVerifier, v.Address)
+		}
+		seen[v.Address] = struct{}{}
+
+		d, ok := deposits[v.Address]
+		if !ok || d.PublicKey != v.PublicKey {
+			return fmt.Errorf("%w: %s", errUnknownVerifier, v.Address)
+		}
+		if keys[i], err = bls.PublicKeyFromBytes(d.PublicKey[:]); err != nil {
+			return err
+		}
+	}
+	sig, err := bls.SignatureFromBytes(header.Signature[:])
+	if err != nil {
+		return err
+	}
+	if !sig.FastAggregateVerify(keys, header.Root) {
+		return errInvalidAggSign
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package apos
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/amazechain/amc/common/block"
+	"github.com/amazechain/amc/common/crypto/bls"
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/conf"
+	"github.com/amazechain/amc/contracts/deposit"
+	"github.com/amazechain/amc/internal/consensus"
+	"github.com/amazechain/amc/modules"
+	"github.com/amazechain/amc/modules/rawdb"
+	"github.com/amazechain/amc/params"
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+)
+
+// Tests that blocks need the signatures of a quorum of distinct depositors after
+// their parent made with their deposited keys, only counting the depositors who
+// reach the minimum deposit.
+func TestVerifyAggSign(t *testing.T) {
+	modules.AmcInit()
+	kv.ChaindataTablesCfg = modules.AmcTableCfg
+	db := memdb.New(t.TempDir())
+	defer db.Close()
+
+	engine := New(&conf.ConsensusConfig{APos: &conf.APosConfig{}}, db, &params.ChainConfig{BeijingBlock: big.NewInt(0), VerifierQuorumBlock: big.NewInt(0)}).(*APos)
+	root := types.HexToHash("0x5c7b6b15c7e7a4a5e3b2c5d0a1b4f6e8d9c0b1a2f3e4d5c6b7a8f9e0d1c2b3a4")
+
+	var (
+		addrs = make([]types.Address, 4)
+		keys  = make([]bls.SecretKey, 4)
+	)
+	for i := range keys {
+		var err error
+		if keys[i], err = bls.RandKey(); err != nil {
+			t.Fatal(err)
+		}
+		addrs[i][0] = byte(i + 1)
+	}
+	verifier := func(i int) *block.Verify {
+		v := &block.Verify{Address: addrs[i]}
+		copy(v.PublicKey[:], keys[i].PublicKey().Marshal())
+		return v
+	}
+	parent, other := types.Hash{0x01}, types.Hash{0x02}
+	signedOn := func(parentHash types.Hash, verifiers []*block.Verify, signers ...int) block.IBlock {
+		header := &block.Header{Number: uint256.NewInt(1), ParentHash: parentHash, Root: root}
+		if len(signers) > 0 {
+			sigs := make([]bls.Signature, len(signers))
+			for i, s := range signers {
+				sigs[i] = keys[s].Sign(root[:])
+			}
+			copy(header.Signature[:], bls.AggregateSignatures(sigs).Marshal())
+		}
+		b := block.NewBlock(header, nil)
+		b.Body().(*block.Body).Verifiers = verifiers
+		return b
+	}
+	signed := func(verifiers []*block.Verify, signers ...int) block.IBlock {
+		return signedOn(parent, verifiers, signers...)
+	}
+	amount := new(uint256.Int).Mul(uint256.NewInt(50), uint256.NewInt(params.AMT))
+	deposits := func(n int) rawdb.Deposits {
+		d := make(rawdb.Deposits)
+		for i := 0; i < n; i++ {
+			d[addrs[i]] = &rawdb.Depositor{PublicKey: verifier(i).PublicKey, Amount: amount}
+		}
+		return d
+	}
+	writeDeposits := func(hash types.Hash, d rawdb.Deposits) {
+		if err := db.Update(context.Background(), func(tx kv.RwTx) error {
+			return rawdb.WriteDepositSnapshot(tx, hash, 0, d)
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Without depositors, blocks are not signed
+	writeDeposits(parent, deposits(0))
+	if err := engine.VerifyAggSign(signed(nil)); err != nil {
+		t.Fatalf("unsigned block rejected without depositors: %v", err)
+	}
+
+	// The first three keys are registered after the parent, all four after the other block
+	writeDeposits(parent, deposits(3))
+	writeDeposits(other, deposits(4))
+	// Deposits made after the parent do not count
+	if err := db.Update(context.Background(), func(tx kv.RwTx) error {
+		return rawdb.PutDeposit(tx, addrs[3], verifier(3).PublicKey, *amount)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// Neither do deposits below the minimum deposit
+	low := types.Hash{0x04}
+	lowDeposits := deposits(3)
+	lowDeposits[addrs[2]] = &rawdb.Depositor{PublicKey: verifier(2).PublicKey, Amount: uint256.NewInt(params.AMT)}
+	writeDeposits(low, lowDeposits)
+
+	wrongKey := verifier(3)
+	wrongKey.Address = addrs[2]
+	tests := []struct {
+		name  string
+		block block.IBlock
+		err   error
+	}{
+		{"quorum", signed([]*block.Verify{verifier(0), verifier(1)}, 0, 1), nil},
+		{"all", signed([]*block.Verify{verifier(0), verifier(1), verifier(2)}, 0, 1, 2), nil},
+		{"unsigned", signed(nil), consensus.ErrNotEnoughSign},
+		{"below quorum", signed([]*block.Verify{verifier(0)}, 0), consensus.ErrNotEnoughSign},
+		{"duplicate", signed([]*block.Verify{verifier(0), verifier(0)}, 0, 0), errDuplicateVerifier},
+		{"no deposit", signed([]*block.Verify{verifier(0), verifier(3)}, 0, 3), errUnknownVerifier},
+		{"wrong key", signed([]*block.Verify{verifier(0), wrongKey}, 0, 3), errUnknownVerifier},
+		{"bad signature", signed([]*block.Verify{verifier(0), verifier(1)}, 0, 2), errInvalidAggSign},
+		{"other parent", signedOn(other, []*block.Verify{verifier(0), verifier(3), verifier(2)}, 0, 3, 2), nil},
+		{"other parent quorum", signedOn(other, []*block.Verify{verifier(0), verifier(1)}, 0, 1), consensus.ErrNotEnoughSign},
+		{"below minimum", signedOn(low, []*block.Verify{verifier(0), verifier(2)}, 0, 2), errUnknownVerifier},
+		{"below minimum quorum", signedOn(low, []*block.Verify{verifier(0), verifier(1)}, 0, 1), nil},
+		{"unknown parent", signedOn(types.Hash{0x03}, []*block.Verify{verifier(0), verifier(1)}, 0, 1), deposit.ErrUnknownDeposits},
+	}
+	for _, tt := range tests {
+		if err := engine.VerifyAggSign(tt.block); !errors.Is(err, tt.err) {
+			t.Errorf("%s: have error %v, want %v", tt.name, err, tt.err)
+		}
+	}
+}
+
+// Tests that unwinding blocks restores the Deposit table from the snapshot of the
+// canonical block unwound to.
+func TestUnwindBlocksDeposits(t *testing.T) {
+	modules.AmcInit()
+	kv.ChaindataTablesCfg = modules.AmcTableCfg
+	db := memdb.New(t.TempDir())
+	defer db.Close()
+
+	engine := New(&conf.ConsensusConfig{APos: &conf.APosConfig{RewardEpoch: 4, RewardLimit: big.NewInt(1)}}, db, &params.ChainConfig{BeijingBlock: big.NewInt(0)}).(*APos)
+	amount := new(uint256.Int).Mul(uint256.NewInt(50), uint256.NewInt(params.AMT))
+	first, second := types.Address{0x01}, types.Address{0x02}
+	before := rawdb.Deposits{first: {Amount: amount}}
+	after := rawdb.Deposits{first: {Amount: amount}, second: {Amount: amount}}
+
+	if err := db.Update(context.Background(), func(tx kv.RwTx) error {
+		for n, hash := range []types.Hash{{0x01}, {0x02}} {
+			if err := rawdb.WriteCanonicalHash(tx, hash, uint64(n+1)); err != nil {
+				return err
+			}
+		}
+		if err := rawdb.WriteDepositSnapshot(tx, types.Hash{0x01}, 1, before); err != nil {
+			return err
+		}
+		if err := rawdb.WriteDepositSnapshot(tx, types.Hash{0x02}, 2, after); err != nil {
+			return err
+		}
+		if err := rawdb.WriteDeposits(tx, after); err != nil {
+			return err
+		}
+		return engine.UnwindBlocks(tx, 1)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.View(context.Background(), func(tx kv.Tx) error {
+		if !rawdb.IsDeposit(tx, first) {
+			t.Errorf("depositor %v of block 1 dropped", first)
+		}
+		if rawdb.IsDeposit(tx, second) {
+			t.Errorf("depositor %v of block 2 kept", second)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -61,6 +61,34 @@ func (r RewardResponseValues) Swap(i, j int) {
 	r[i], r[j] = r[j], r[i]
 }
 
+// EpochRewards is what the reward block of an epoch paid out.
+type EpochRewards struct {
+	Epoch       hexutil.Uint64                 `json:"epoch"`
+	BlockNumber hexutil.Uint64                 `json:"blockNumber"`
+	Timestamp   hexutil.Uint64                 `json:"timestamp"`
+	Rewards     map[types.Address]*uint256.Int `json:"rewards"`
+	Total       *uint256.Int                   `json:"total"`
+}
+
+// UnpaidReward is what a verifier is owed but was not paid out yet: the reward
+// carried over below the payout limit, and what it earned so far towards the
+// payout of the current epoch at its present deposit.
+type UnpaidReward struct {
+	Address types.Address  `json:"address"`
+	Epoch   hexutil.Uint64 `json:"epoch"`
+	Blocks  hexutil.Uint64 `json:"blocks"`
+	Accrued *uint256.Int   `json:"accrued"`
+	Unpaid  *uint256.Int   `json:"unpaid"`
+}
+
+// RewardHistoryEntry is a reward paid out to a verifier.
+type RewardHistoryEntry struct {
+	Epoch       hexutil.Uint64 `json:"epoch"`
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	Timestamp   hexutil.Uint64 `json:"timestamp"`
+	Amount      *uint256.Int   `json:"amount"`
+}
+
 type Reward struct {
 	config      *conf.ConsensusConfig
 	chainConfig *params.ChainConfig
@@ -98,7 +126,7 @@ func newReward(config *conf.ConsensusConfig, chainConfig *params.ChainConfig) *R
 	}
 }
 
-func (r *Reward) GetRewards(tx kv.Getter, addr types.Address, from *uint256.Int, to *uint256.Int) (*RewardResponse, error) {
+func (r *Reward) GetRewards(tx kv.Tx, addr types.Address, from *uint256.Int, to *uint256.Int) (*RewardResponse, error) {
 	resp := new(RewardResponse)
 	resp.Address = addr
 	resp.Data = make([]*RewardResponseValue, 0)
@@ -111,44 +139,35 @@ func (r *Reward) GetRewards(tx kv.Getter, addr types.Address, from *uint256.Int,
 
 	resp.Total = uint256.NewInt(0)
 
-	for i := endEpoch; i.Cmp(startEpoch) > 0; i = i.SubUint64(i, 1) {
-
-		reward, err := rawdb.GetEpochReward(tx, i)
-		if err != nil {
-			return nil, err
+	err := rawdb.ReadAccountRewards(tx, addr, startEpoch.Uint64()+1, endEpoch.Uint64(), func(epoch uint64, entry *uint256.Int) error {
+		blockNumber := r.epoch2number(uint256.NewInt(epoch))
+		header, err := r.canonicalHeader(tx, blockNumber.Uint64())
+		if nil != err {
+			return err
 		}
-
-		if entry, ok := reward[addr]; ok && entry != nil && entry.Cmp(uint256.NewInt(0)) == 1 {
-			blockNumber := r.epoch2number(i)
-			hash, err := rawdb.ReadCanonicalHash(tx, blockNumber.Uint64())
-			if nil != err {
-				log.Error("cannot open chain db", "err", err)
-				return nil, err
-			}
-			if hash == (types.Hash{}) {
-				log.Debug("readcanonicalhash got empty", "number", blockNumber)
-				continue
-			}
-			header := rawdb.ReadHeader(tx, hash, blockNumber.Uint64())
-			if header == nil {
-				return nil, errors.New("buildreward header type assert error")
-			}
-			resp.Data = append(resp.Data, &RewardResponseValue{
-				Value:       *entry,
-				Timestamp:   hexutil.Uint64(header.Time),
-				BlockNumber: blockNumber.String(),
-			})
-			resp.Total = resp.Total.Add(resp.Total, entry)
+		if header == nil {
+			log.Debug("readcanonicalhash got empty", "number", blockNumber)
+			return nil
 		}
+		resp.Data = append(resp.Data, &RewardResponseValue{
+			Value:       *entry,
+			Timestamp:   hexutil.Uint64(header.Time),
+			BlockNumber: blockNumber.String(),
+		})
+		resp.Total = resp.Total.Add(resp.Total, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 	sort.Sort(resp.Data)
 
 	return resp, nil
 }
 
-func (r *Reward) GetBlockRewards(tx kv.Getter, header block.IHeader) (map[types.Address]*uint256.Int, error) {
+func (r *Reward) GetBlockRewards(tx kv.Tx, header block.IHeader) (map[types.Address]*uint256.Int, error) {
 
-	reward, err := rawdb.GetEpochReward(tx, r.number2epoch(header.Number64()))
+	reward, err := rawdb.ReadEpochRewards(tx, r.number2epoch(header.Number64()).Uint64())
 
 	if err != nil {
 		return nil, err
@@ -156,150 +175,490 @@ func (r *Reward) GetBlockRewards(tx kv.Getter, header block.IHeader) (map[types.
 	return reward, nil
 }
 
-func (r *Reward) SetRewards(tx kv.RwTx, number *uint256.Int, setRewards bool) (AccountRewards, error) {
-	currentRewardMap, err := r.buildRewards(tx, number, setRewards)
+// EpochRewards returns what the reward block of the epoch paid out.
+func (r *Reward) EpochRewards(tx kv.Tx, epoch uint64) (*EpochRewards, error) {
+	rewards, err := rawdb.ReadEpochRewards(tx, epoch)
 	if err != nil {
 		return nil, err
 	}
-	resp := make(AccountRewards, 0, len(currentRewardMap))
-	for k, v := range currentRewardMap {
-		resp = append(resp, &AccountReward{
-			Account: k,
-			Value:   v,
-		})
+	number := r.epoch2number(uint256.NewInt(epoch)).Uint64()
+	resp := &EpochRewards{
+		Epoch:       hexutil.Uint64(epoch),
+		BlockNumber: hexutil.Uint64(number),
+		Rewards:     rewards,
+		Total:       uint256.NewInt(0),
+	}
+	header, err := r.canonicalHeader(tx, number)
+	if err != nil {
+		return nil, err
+	}
+	if header != nil {
+		resp.Timestamp = hexutil.Uint64(header.Time)
+	}
+	for _, amount := range rewards {
+		resp.Total.Add(resp.Total, amount)
 	}
-	sort.Sort(resp)
 	return resp, nil
 }
 
-func (r *Reward) buildRewards(tx kv.RwTx, number *uint256.Int, setRewards bool) (map[types.Address]*uint256.Int, error) {
-
-	epoch := r.number2epoch(number)
-	endNumber := new(uint256.Int).Sub(number, r.rewardEpoch)
-
-	//calculate last batch but this one
-	currentNr := number.Clone()
-	currentNr.SubUint64(currentNr, 1)
-	rewardMap := make(map[types.Address]*uint256.Int, 0)
-	depositeMap := map[types.Address]*deposit.Info{}
+// UnpaidReward returns what the address is owed but was not paid out yet, as of
+// the last block accounted in the ledger.
+func (r *Reward) UnpaidReward(tx kv.Tx, addr types.Address) (*UnpaidReward, error) {
+	unpaid, err := rawdb.ReadUnpaidReward(tx, addr)
+	if err != nil {
+		return nil, err
+	}
+	resp := &UnpaidReward{Address: addr, Accrued: uint256.NewInt(0), Unpaid: unpaid}
+	head, _, err := rawdb.ReadRewardLedgerProgress(tx)
+	if err != nil {
+		return nil, err
+	}
+	epoch, ok := r.accrualEpoch(head)
+	if !ok {
+		return resp, nil
+	}
+	blocks, err := rawdb.ReadRewardAccrual(tx, epoch, addr)
+	if err != nil {
+		return nil, err
+	}
+	resp.Epoch, resp.Blocks = hexutil.Uint64(epoch), hexutil.Uint64(blocks)
+	schedule := r.chainConfig.DepositScheduleAt(r.epoch2number(uint256.NewInt(epoch)).Uint64())
+	if depositInfo := deposit.GetDepositInfo(tx, addr, schedule); depositInfo != nil {
+		resp.Accrued = math.Min256(new(uint256.Int).Mul(depositInfo.RewardPerBlock, uint256.NewInt(blocks)), depositInfo.MaxRewardPerEpoch.Clone())
+	}
+	return resp, nil
+}
 
-	for currentNr.Cmp(endNumber) >= 0 {
-		hash, err := rawdb.ReadCanonicalHash(tx, currentNr.Uint64())
-		if nil != err {
-			log.Error("cannot open chain db", "err", err)
-			return nil, err
-		}
-		if hash == (types.Hash{}) {
-			return nil, err
+// RewardHistory returns the rewards paid out to the address by the reward blocks
+// in [from, to], oldest first.
+func (r *Reward) RewardHistory(tx kv.Tx, addr types.Address, from, to uint64) ([]*RewardHistoryEntry, error) {
+	if from > to {
+		return nil, errors.New("from > to number")
+	}
+	entries := make([]*RewardHistoryEntry, 0)
+	err := rawdb.ReadAccountRewards(tx, addr, r.number2epoch(uint256.NewInt(from)).Uint64(), r.number2epoch(uint256.NewInt(to)).Uint64(), func(epoch uint64, amount *uint256.Int) error {
+		number := r.epoch2number(uint256.NewInt(epoch)).Uint64()
+		if number < from || number > to {
+			return nil
 		}
-		header := rawdb.ReadHeader(tx, hash, currentNr.Uint64())
-		if header == nil {
-			return nil, errors.New("buildreward header type assert error")
+		header, err := r.canonicalHeader(tx, number)
+		if err != nil {
+			return err
 		}
-
-		block := rawdb.ReadBlock(tx, header.Hash(), header.Number.Uint64())
-		if block == nil {
-			return nil, errors.New("buildreward block type assert error")
+		entry := &RewardHistoryEntry{Epoch: hexutil.Uint64(epoch), BlockNumber: hexutil.Uint64(number), Amount: amount}
+		if header != nil {
+			entry.Timestamp = hexutil.Uint64(header.Time)
 		}
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
 
-		verifiers := block.Body().Verifier()
-		for _, verifier := range verifiers {
-			addrReward, ok := rewardMap[verifier.Address]
-			if !ok {
-				addrReward = uint256.NewInt(0)
-			}
-
-			depositInfo, ok := depositeMap[verifier.Address]
-			if !ok {
-				depositInfo = deposit.GetDepositInfo(tx, verifier.Address)
-				if depositInfo == nil {
-					continue
-				}
-				depositeMap[verifier.Address] = depositInfo
-				log.Debug("account deposite infos", "addr", verifier.Address, "perblock", depositInfo.RewardPerBlock, "perepoch", depositInfo.MaxRewardPerEpoch)
-			}
-
-			rewardMap[verifier.Address] = math.Min256(addrReward.Add(addrReward, depositInfo.RewardPerBlock), depositInfo.MaxRewardPerEpoch.Clone())
-		}
+// canonicalHeader retrieves the header of the canonical block with the given number,
+// nil if there is none.
+func (r *Reward) canonicalHeader(tx kv.Getter, number uint64) (*block.Header, error) {
+	hash, err := rawdb.ReadCanonicalHash(tx, number)
+	if nil != err {
+		log.Error("cannot open chain db", "err", err)
+		return nil, err
+	}
+	if hash == (types.Hash{}) {
+		return nil, nil
+	}
+	header := rawdb.ReadHeader(tx, hash, number)
+	if header == nil {
+		return nil, errors.New("buildreward header type assert error")
+	}
+	return header, nil
+}
 
-		currentNr.SubUint64(currentNr, 1)
+// BlockRewards returns the rewards paid out by the block with the given header,
+// sorted as they are listed in the block body. They are worked out from the
+// ledger and the depositors as of the parent of the block.
+func (r *Reward) BlockRewards(tx kv.Tx, header block.IHeader) (AccountRewards, error) {
+	number := header.Number64().Uint64()
+	epoch, ok := r.rewardBlockEpoch(number)
+	if !ok {
+		return nil, nil
+	}
+	parentHash := header.(*block.Header).ParentHash
+	ledger, err := r.ledgerAt(tx, parentHash, number-1)
+	if err != nil {
+		return nil, err
 	}
+	deposits, err := deposit.ReadDeposits(tx, parentHash, number-1)
+	if err != nil {
+		return nil, err
+	}
+	paid, _, err := r.settle(ledger, deposits, epoch)
+	if err != nil {
+		return nil, err
+	}
+	resp := make(AccountRewards, 0, len(paid))
+	for k, v := range paid {
+		resp = append(resp, &AccountReward{
+			Account: k,
+			Value:   v,
+		})
+	}
+	sort.Sort(resp)
+	return resp, nil
+}
 
-	for addr, amount := range rewardMap {
-		var payAmount, unpayAmount *uint256.Int
+// settle works out what the reward block of the epoch pays out and carries over,
+// given the ledger and the depositors as of its parent. A verifier earns the
+// reward per block of its deposit tier, as scheduled at the reward block, for
+// every block of the epoch it verified, up to the maximum reward per epoch. What
+// it earned is added to what it carried over and paid out once it reaches the
+// reward limit.
+func (r *Reward) settle(ledger *ledgerView, deposits rawdb.Deposits, epoch uint64) (paid, unpaid map[types.Address]*uint256.Int, err error) {
+	accruals, err := ledger.epochAccruals(epoch)
+	if err != nil {
+		return nil, nil, err
+	}
+	paid = make(map[types.Address]*uint256.Int, len(accruals))
+	unpaid = make(map[types.Address]*uint256.Int, len(accruals))
+	schedule := r.chainConfig.DepositScheduleAt(r.epoch2number(uint256.NewInt(epoch)).Uint64())
+	for addr, blocks := range accruals {
+		d, ok := deposits[addr]
+		if !ok {
+			continue
+		}
+		depositInfo := deposit.NewInfo(d, schedule)
+		if depositInfo == nil {
+			continue
+		}
+		log.Debug("account deposite infos", "addr", addr, "perblock", depositInfo.RewardPerBlock, "perepoch", depositInfo.MaxRewardPerEpoch)
 
-		lastSedi, err := r.getAccountRewardUnpaid(tx, addr)
+		amount := math.Min256(new(uint256.Int).Mul(depositInfo.RewardPerBlock, uint256.NewInt(blocks)), depositInfo.MaxRewardPerEpoch.Clone())
+		lastSedi, err := ledger.unpaidReward(addr)
 		if err != nil {
 			log.Debug("build reward Big map get account reward error,err=", err)
-			return nil, err
-		}
-		if lastSedi != nil {
-			amount.Add(amount, lastSedi)
+			return nil, nil, err
 		}
+		amount.Add(amount, lastSedi)
 
 		if amount.Cmp(r.rewardLimit) >= 0 {
-			payAmount = amount.Clone()
-			unpayAmount = uint256.NewInt(0)
+			paid[addr] = amount
+			unpaid[addr] = uint256.NewInt(0)
 		} else {
-			payAmount = uint256.NewInt(0)
-			unpayAmount = amount.Clone()
+			paid[addr] = uint256.NewInt(0)
+			unpaid[addr] = amount
 		}
+	}
+	return paid, unpaid, nil
+}
+
+// ledgerView is the reward ledger as of a block: the stored ledger as of the last
+// ancestor of the block it accounted, with the blocks after that ancestor, which
+// are on a side chain or not accounted yet, accounted in memory.
+type ledgerView struct {
+	tx       kv.Tx
+	number   uint64
+	accruals map[uint64]map[types.Address]uint64
+	unpaid   map[types.Address]*uint256.Int
+}
+
+func (l *ledgerView) epochAccruals(epoch uint64) (map[types.Address]uint64, error) {
+	if accruals, ok := l.accruals[epoch]; ok {
+		return accruals, nil
+	}
+	accruals, err := rawdb.ReadEpochAccrualsAt(l.tx, epoch, l.number)
+	if err != nil {
+		return nil, err
+	}
+	l.accruals[epoch] = accruals
+	return accruals, nil
+}
 
-		rewardMap[addr] = payAmount
+func (l *ledgerView) unpaidReward(addr types.Address) (*uint256.Int, error) {
+	if amount, ok := l.unpaid[addr]; ok {
+		return amount, nil
+	}
+	return rawdb.ReadUnpaidRewardAt(l.tx, addr, l.number)
+}
 
-		if setRewards {
-			log.Info("🔨 set account reward unpaid", "addr", addr, "non-pay amount", unpayAmount.Uint64(), "pay amount", payAmount.Uint64(), "number", currentNr.String())
-			if err := r.setAccountRewardUnpaid(tx, addr, unpayAmount); err != nil {
+// ledgerAt returns the reward ledger as of the block with the given hash and number.
+func (r *Reward) ledgerAt(tx kv.Tx, hash types.Hash, number uint64) (*ledgerView, error) {
+	progress, started, err := rawdb.ReadRewardLedgerProgress(tx)
+	if err != nil {
+		return nil, err
+	}
+	// Collect the blocks down to the last ancestor the ledger accounted
+	var headers []*block.Header
+	for started {
+		if number <= progress {
+			canonical, err := rawdb.ReadCanonicalHash(tx, number)
+			if err != nil {
 				return nil, err
 			}
+			if canonical == hash {
+				break
+			}
+		}
+		header := rawdb.ReadHeader(tx, hash, number)
+		if header == nil || number == 0 {
+			return nil, fmt.Errorf("unknown block %d %x in the reward ledger", number, hash)
 		}
+		headers = append(headers, header)
+		hash, number = header.ParentHash, number-1
 	}
-
-	log.Debug("buildrewards maps", "rewardMap", rewardMap, "rewardmap len", len(rewardMap), "issetreward", setRewards)
-
-	if setRewards {
-		if err := r.setRewardByEpochPaid(tx, epoch, rewardMap); err != nil {
+	if started && number < progress {
+		if err := rawdb.CheckPruned(tx, rawdb.PruneHistory, number+1); err != nil {
 			return nil, err
 		}
 	}
-
-	return rewardMap, nil
+	ledger := &ledgerView{
+		tx:       tx,
+		number:   number,
+		accruals: make(map[uint64]map[types.Address]uint64),
+		unpaid:   make(map[types.Address]*uint256.Int),
+	}
+	for i := len(headers) - 1; i >= 0; i-- {
+		if err := r.replay(ledger, headers[i]); err != nil {
+			return nil, err
+		}
+	}
+	return ledger, nil
 }
 
-func (r *Reward) setRewardByEpochPaid(tx kv.RwTx, epoch *uint256.Int, rewardMap map[types.Address]*uint256.Int) error {
-	if tx == nil {
-		return errors.New("setrewardepoch tx nil")
+// replay accounts a block the stored ledger did not account in the view.
+func (r *Reward) replay(ledger *ledgerView, header *block.Header) error {
+	number := header.Number64().Uint64()
+	if epoch, ok := r.rewardBlockEpoch(number); ok {
+		deposits, err := deposit.ReadDeposits(ledger.tx, header.ParentHash, number-1)
+		if err != nil {
+			return err
+		}
+		_, unpaid, err := r.settle(ledger, deposits, epoch)
+		if err != nil {
+			return err
+		}
+		for addr, amount := range unpaid {
+			ledger.unpaid[addr] = amount
+		}
 	}
-	if len(rewardMap) == 0 {
+	epoch, ok := r.accrualEpoch(number)
+	if !ok {
 		return nil
 	}
-	key := fmt.Sprintf("epoch:%s", epoch.String())
-	err := rawdb.PutEpochReward(tx, key, rewardMap)
+	verifiers, err := rawdb.ReadVerifies(ledger.tx, header.Hash(), number)
 	if err != nil {
 		return err
 	}
+	accruals, err := ledger.epochAccruals(epoch)
+	if err != nil {
+		return err
+	}
+	for _, verifier := range verifiers {
+		accruals[verifier.Address]++
+	}
+	return nil
+}
+
+// applyBlock accounts a canonical block in the ledger: a reward block settles the
+// epoch it closes, and the verifiers of every block accrue towards the next payout.
+// The ledger accounts the parent of the block at this point.
+func (r *Reward) applyBlock(tx kv.RwTx, b block.IBlock) error {
+	number := b.Number64().Uint64()
+	if epoch, ok := r.rewardBlockEpoch(number); ok {
+		deposits, err := deposit.ReadDeposits(tx, b.ParentHash(), number-1)
+		if errors.Is(err, deposit.ErrUnknownDeposits) {
+			// Blocks written before deposit snapshots are settled against the
+			// Deposit table, which still follows the previous head.
+			deposits, err = rawdb.ReadDeposits(tx)
+		}
+		if err != nil {
+			return err
+		}
+		ledger := &ledgerView{
+			tx:       tx,
+			number:   number - 1,
+			accruals: make(map[uint64]map[types.Address]uint64),
+			unpaid:   make(map[types.Address]*uint256.Int),
+		}
+		paid, unpaid, err := r.settle(ledger, deposits, epoch)
+		if err != nil {
+			return err
+		}
+		for addr, amount := range unpaid {
+			log.Info("🔨 set account reward unpaid", "addr", addr, "non-pay amount", amount.Uint64(), "pay amount", paid[addr].Uint64(), "number", number)
+			if err := rawdb.WriteUnpaidReward(tx, number, addr, amount); err != nil {
+				return err
+			}
+			if err := rawdb.WriteEpochReward(tx, number, epoch, addr, paid[addr]); err != nil {
+				return err
+			}
+		}
+	}
+	return r.accrue(tx, number, b.Body().Verifier(), true)
+}
 
+// accrue counts the block towards the payout of its verifiers. Without
+// changesets the entries are written as they are, which is only used to build
+// the ledger of blocks accounted before it existed.
+func (r *Reward) accrue(tx kv.RwTx, number uint64, verifiers []*block.Verify, changes bool) error {
+	epoch, ok := r.accrualEpoch(number)
+	if !ok {
+		return nil
+	}
+	for _, verifier := range verifiers {
+		blocks, err := rawdb.ReadRewardAccrual(tx, epoch, verifier.Address)
+		if err != nil {
+			return err
+		}
+		if changes {
+			err = rawdb.WriteRewardAccrual(tx, number, epoch, verifier.Address, blocks+1)
+		} else {
+			err = rawdb.PutRewardAccrual(tx, epoch, verifier.Address, blocks+1)
+		}
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (r *Reward) getAccountRewardUnpaid(tx kv.Getter, account types.Address) (*uint256.Int, error) {
-	key := fmt.Sprintf("account:%s", account.String())
-	value, err := rawdb.GetAccountReward(tx, key)
+// ApplyCanonical accounts the block becoming the head of the canonical chain,
+// along with the canonical blocks the ledger missed before it. Blocks the ledger
+// already accounted are ignored.
+func (r *Reward) ApplyCanonical(tx kv.RwTx, b block.IBlock) error {
+	number := b.Number64().Uint64()
+	progress, started, err := rawdb.ReadRewardLedgerProgress(tx)
 	if err != nil {
-		return uint256.NewInt(0), err
+		return err
+	}
+	if !started {
+		if number == 0 {
+			return rawdb.WriteRewardLedgerProgress(tx, 0)
+		}
+		if err := r.migrate(tx, number-1); err != nil {
+			return fmt.Errorf("migrating rewards: %w", err)
+		}
+		progress = number - 1
+	}
+	if number <= progress {
+		return nil
+	}
+	for n := progress + 1; n < number; n++ {
+		missed, err := rawdb.ReadBlockByNumber(tx, n)
+		if err != nil {
+			return err
+		}
+		if missed == nil {
+			continue
+		}
+		if err := r.applyBlock(tx, missed); err != nil {
+			return err
+		}
 	}
-	return value, nil
+	if err := r.applyBlock(tx, b); err != nil {
+		return err
+	}
+	return rawdb.WriteRewardLedgerProgress(tx, number)
 }
 
-func (r *Reward) setAccountRewardUnpaid(tx kv.Putter, account types.Address, val *uint256.Int) error {
-	key := fmt.Sprintf("account:%s", account.String())
-	err := rawdb.PutAccountReward(tx, key, val)
-	if err != nil {
+// UnwindCanonical reverts the accounting of the canonical blocks after target.
+// Blocks accounted before the ledger existed have no changesets and keep their
+// entries.
+func (r *Reward) UnwindCanonical(tx kv.RwTx, target uint64) error {
+	progress, started, err := rawdb.ReadRewardLedgerProgress(tx)
+	if err != nil || !started {
 		return err
 	}
+	if err := rawdb.UnwindRewardLedger(tx, target+1); err != nil {
+		return err
+	}
+	if progress > target {
+		return rawdb.WriteRewardLedgerProgress(tx, target)
+	}
 	return nil
 }
 
+// migrate starts the ledger at the given head from the rewards stored under
+// string keys, and counts the blocks verified since the last reward block.
+func (r *Reward) migrate(tx kv.RwTx, head uint64) error {
+	unpaid, paid, err := rawdb.ReadLegacyRewards(tx)
+	if err != nil {
+		return err
+	}
+	for addr, amount := range unpaid {
+		if err := rawdb.PutUnpaidReward(tx, addr, amount); err != nil {
+			return err
+		}
+	}
+	for epoch, rewards := range paid {
+		for addr, amount := range rewards {
+			if err := rawdb.PutEpochReward(tx, epoch, addr, amount); err != nil {
+				return err
+			}
+		}
+	}
+	if err := rawdb.DeleteLegacyRewards(tx); err != nil {
+		return err
+	}
+	if epoch, ok := r.accrualEpoch(head); ok {
+		for n := r.accrualStart(epoch); n <= head; n++ {
+			b, err := rawdb.ReadBlockByNumber(tx, n)
+			if err != nil {
+				return err
+			}
+			if b == nil {
+				continue
+			}
+			if err := r.accrue(tx, n, b.Body().Verifier(), false); err != nil {
+				return err
+			}
+		}
+	}
+	log.Info("Migrated rewards into the reward ledger", "head", head, "unpaid", len(unpaid), "epochs", len(paid))
+	return rawdb.WriteRewardLedgerProgress(tx, head)
+}
+
+// rewardBlockEpoch returns the epoch a block closes, false if it is not a reward
+// block. The genesis block pays nothing out.
+func (r *Reward) rewardBlockEpoch(number uint64) (uint64, bool) {
+	if r.chainConfig.BeijingBlock == nil || r.rewardEpoch.IsZero() || number == 0 {
+		return 0, false
+	}
+	beijing, epochLen := r.chainConfig.BeijingBlock.Uint64(), r.rewardEpoch.Uint64()
+	if number < beijing || (number-beijing)%epochLen != 0 {
+		return 0, false
+	}
+	return (number - beijing) / epochLen, true
+}
+
+// accrualEpoch returns the epoch whose reward block pays for verifying the block,
+// which is the reward block of the epoch following the block. False is returned
+// for the blocks before the first epoch paid for.
+func (r *Reward) accrualEpoch(number uint64) (uint64, bool) {
+	if r.chainConfig.BeijingBlock == nil || r.rewardEpoch.IsZero() {
+		return 0, false
+	}
+	beijing, epochLen := r.chainConfig.BeijingBlock.Uint64(), r.rewardEpoch.Uint64()
+	if number >= beijing {
+		return (number-beijing)/epochLen + 1, true
+	}
+	// The first reward block pays for the epoch before it, if the chain has one.
+	if beijing >= epochLen && number >= beijing-epochLen {
+		return 0, true
+	}
+	return 0, false
+}
+
+// accrualStart returns the first block accruing towards the payout of the epoch.
+func (r *Reward) accrualStart(epoch uint64) uint64 {
+	beijing, epochLen := r.chainConfig.BeijingBlock.Uint64(), r.rewardEpoch.Uint64()
+	if epoch == 0 {
+		return beijing - epochLen
+	}
+	return beijing + (epoch-1)*epochLen
+}
+
 func (r *Reward) number2epoch(number *uint256.Int) *uint256.Int {
 	beijingBlock, _ := uint256.FromBig(r.chainConfig.BeijingBlock)
 	if beijingBlock.Cmp(number) == 1 {
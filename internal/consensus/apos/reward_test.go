@@ -0,0 +1,361 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+package apos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/amazechain/amc/common/block"
+	"github.com/amazechain/amc/common/crypto/bls"
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/conf"
+	"github.com/amazechain/amc/contracts/deposit"
+	"github.com/amazechain/amc/modules"
+	"github.com/amazechain/amc/modules/rawdb"
+	"github.com/amazechain/amc/params"
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+)
+
+// Tests that the reward ledger accrues verified blocks, settles them on reward
+// blocks and unwinds them when the canonical chain is reorganised.
+func TestRewardLedger(t *testing.T) {
+	modules.AmcInit()
+	kv.ChaindataTablesCfg = modules.AmcTableCfg
+	db := memdb.New(t.TempDir())
+	defer db.Close()
+	tx, err := db.BeginRw(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	var addrA, addrB types.Address
+	addrA[0], addrB[0] = 0xa, 0xb
+	for _, addr := range []types.Address{addrA, addrB} {
+		key, err := bls.RandKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var pub types.PublicKey
+		pub.SetBytes(key.PublicKey().Marshal())
+		if err := rawdb.PutDeposit(tx, addr, pub, *new(uint256.Int).Mul(uint256.NewInt(50), uint256.NewInt(params.AMT))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	deposits, err := rawdb.ReadDeposits(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	perBlock := deposit.GetDepositInfo(tx, addrA, params.DefaultDepositSchedule).RewardPerBlock
+	per := func(blocks uint64) *uint256.Int { return new(uint256.Int).Mul(perBlock, uint256.NewInt(blocks)) }
+
+	engine := New(&conf.ConsensusConfig{APos: &conf.APosConfig{RewardEpoch: 4, RewardLimit: per(3).ToBig()}}, db, &params.ChainConfig{BeijingBlock: big.NewInt(0)}).(*APos)
+	reward := newReward(engine.config, engine.chainConfig)
+	canonical := func(number uint64) types.Hash {
+		t.Helper()
+		hash, err := rawdb.ReadCanonicalHash(tx, number)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return hash
+	}
+	apply := func(number uint64, verifiers ...types.Address) {
+		t.Helper()
+		var parent types.Hash
+		if number > 0 {
+			parent = canonical(number - 1)
+		}
+		header := &block.Header{Number: uint256.NewInt(number), ParentHash: parent, Difficulty: uint256.NewInt(1), BaseFee: uint256.NewInt(0)}
+		rawdb.WriteHeader(tx, header)
+		b := block.NewBlock(header, nil)
+		for _, addr := range verifiers {
+			b.Body().(*block.Body).Verifiers = append(b.Body().(*block.Body).Verifiers, &block.Verify{Address: addr})
+		}
+		if err := rawdb.WriteCanonicalHash(tx, b.Hash(), number); err != nil {
+			t.Fatal(err)
+		}
+		if err := rawdb.WriteDepositSnapshot(tx, b.Hash(), number, deposits); err != nil {
+			t.Fatal(err)
+		}
+		if err := engine.ApplyBlock(tx, b); err != nil {
+			t.Fatalf("block %d: %v", number, err)
+		}
+	}
+	checkPayouts := func(header *block.Header, want map[types.Address]*uint256.Int) {
+		t.Helper()
+		payouts, err := reward.BlockRewards(tx, header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		have := make(map[types.Address]*uint256.Int)
+		for _, p := range payouts {
+			have[p.Account] = p.Value
+		}
+		if len(have) != len(want) {
+			t.Fatalf("block %d: have payouts %v, want %v", header.Number64().Uint64(), have, want)
+		}
+		for addr, amount := range want {
+			if have[addr] == nil || !have[addr].Eq(amount) {
+				t.Fatalf("block %d: have payouts %v, want %v", header.Number64().Uint64(), have, want)
+			}
+		}
+	}
+	checkPaid := func(epoch uint64, want map[types.Address]*uint256.Int) {
+		t.Helper()
+		have, err := reward.EpochRewards(tx, epoch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(have.Rewards) != len(want) {
+			t.Fatalf("epoch %d: have %d rewards, want %d", epoch, len(have.Rewards), len(want))
+		}
+		for addr, amount := range want {
+			if have.Rewards[addr] == nil || !have.Rewards[addr].Eq(amount) {
+				t.Fatalf("epoch %d reward of %v: have %v, want %v", epoch, addr, have.Rewards[addr], amount)
+			}
+		}
+	}
+	checkUnpaid := func(addr types.Address, want *uint256.Int) {
+		t.Helper()
+		have, err := reward.UnpaidReward(tx, addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !have.Unpaid.Eq(want) {
+			t.Fatalf("unpaid reward of %v: have %v, want %v", addr, have.Unpaid, want)
+		}
+	}
+
+	apply(0)
+	for n := uint64(1); n <= 3; n++ {
+		if n <= 2 {
+			apply(n, addrA, addrB)
+		} else {
+			apply(n, addrA)
+		}
+	}
+	// The reward block pays out what reached the limit and carries over the rest.
+	block4 := &block.Header{Number: uint256.NewInt(4), ParentHash: canonical(3)}
+	checkPayouts(block4, map[types.Address]*uint256.Int{addrA: per(3), addrB: uint256.NewInt(0)})
+	for n := uint64(4); n <= 8; n++ {
+		apply(n, addrA)
+	}
+	// Payouts are worked out from the ledger as of the parent, also once the
+	// ledger accounted past it.
+	checkPayouts(block4, map[types.Address]*uint256.Int{addrA: per(3), addrB: uint256.NewInt(0)})
+
+	// A side chain after block 3 verified by B pays B out at its reward block.
+	parent := canonical(3)
+	for n := uint64(4); n <= 7; n++ {
+		header := &block.Header{Number: uint256.NewInt(n), ParentHash: parent, Difficulty: uint256.NewInt(1), BaseFee: uint256.NewInt(0), Time: 1}
+		rawdb.WriteHeader(tx, header)
+		if err := rawdb.WriteVerifies(tx, header.Hash(), n, []*block.Verify{{Address: addrB}}); err != nil {
+			t.Fatal(err)
+		}
+		if err := rawdb.WriteDepositSnapshot(tx, header.Hash(), n, deposits); err != nil {
+			t.Fatal(err)
+		}
+		parent = header.Hash()
+	}
+	checkPayouts(&block.Header{Number: uint256.NewInt(8), ParentHash: parent}, map[types.Address]*uint256.Int{addrB: per(6)})
+	checkPaid(1, map[types.Address]*uint256.Int{addrA: per(3)})
+	checkPaid(2, map[types.Address]*uint256.Int{addrA: per(4)})
+	checkUnpaid(addrB, per(2))
+	history, err := reward.RewardHistory(tx, addrA, 0, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 || history[0].BlockNumber != 4 || history[1].BlockNumber != 8 || !history[1].Amount.Eq(per(4)) {
+		t.Fatalf("unexpected reward history: %v", history)
+	}
+
+	// Reorganise the chain after block 5, B verifying the new blocks.
+	if err := engine.UnwindBlocks(tx, 5); err != nil {
+		t.Fatal(err)
+	}
+	checkPaid(2, nil)
+	if blocks, err := rawdb.ReadRewardAccrual(tx, 2, addrA); err != nil || blocks != 2 {
+		t.Fatalf("accrual after unwind: have %d (%v), want 2", blocks, err)
+	}
+	for n := uint64(6); n <= 8; n++ {
+		apply(n, addrB)
+	}
+	checkPaid(2, map[types.Address]*uint256.Int{addrB: per(4)})
+	checkUnpaid(addrA, per(2))
+	checkUnpaid(addrB, uint256.NewInt(0))
+
+	// Rewinding before the first reward block restores the balances carried then.
+	if err := engine.UnwindBlocks(tx, 3); err != nil {
+		t.Fatal(err)
+	}
+	checkPaid(1, nil)
+	checkUnpaid(addrA, uint256.NewInt(0))
+	checkUnpaid(addrB, uint256.NewInt(0))
+	accruals, err := rawdb.ReadEpochAccruals(tx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if accruals[addrA] != 3 || accruals[addrB] != 2 {
+		t.Fatalf("unexpected accruals after rewind: %v", accruals)
+	}
+	if progress, _, err := rawdb.ReadRewardLedgerProgress(tx); err != nil || progress != 3 {
+		t.Fatalf("ledger progress: have %d (%v), want 3", progress, err)
+	}
+}
+
+// Tests that rewards stored under string keys are moved into the ledger, along
+// with the blocks verified since the last reward block.
+func TestRewardLedgerMigration(t *testing.T) {
+	modules.AmcInit()
+	kv.ChaindataTablesCfg = modules.AmcTableCfg
+	db := memdb.New(t.TempDir())
+	defer db.Close()
+	tx, err := db.BeginRw(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	var addr types.Address
+	addr[0] = 0xa
+	for n := uint64(0); n <= 5; n++ {
+		b := block.NewBlock(&block.Header{Number: uint256.NewInt(n), Difficulty: uint256.NewInt(2), BaseFee: uint256.NewInt(0)}, nil)
+		b.Body().(*block.Body).Verifiers = []*block.Verify{{Address: addr}}
+		if err := rawdb.WriteBlock(tx, b.(*block.Block)); err != nil {
+			t.Fatal(err)
+		}
+		if err := rawdb.WriteCanonicalHash(tx, b.Hash(), n); err != nil {
+			t.Fatal(err)
+		}
+	}
+	paid, err := json.Marshal(map[types.Address]*uint256.Int{addr: uint256.NewInt(7)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Put(modules.Reward, []byte("epoch:"+uint256.NewInt(1).String()), paid); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Put(modules.Reward, []byte("account:"+addr.String()), uint256.NewInt(3).Bytes()); err != nil {
+		t.Fatal(err)
+	}
+
+	engine := New(&conf.ConsensusConfig{APos: &conf.APosConfig{RewardEpoch: 4, RewardLimit: big.NewInt(1)}}, db, &params.ChainConfig{BeijingBlock: big.NewInt(0)}).(*APos)
+	b := block.NewBlock(&block.Header{Number: uint256.NewInt(6)}, nil)
+	if err := engine.ApplyBlock(tx, b); err != nil {
+		t.Fatal(err)
+	}
+	rewards, err := rawdb.ReadEpochRewards(tx, 1)
+	if err != nil || rewards[addr] == nil || rewards[addr].Uint64() != 7 {
+		t.Fatalf("migrated epoch rewards: have %v (%v), want 7", rewards, err)
+	}
+	unpaid, err := rawdb.ReadUnpaidReward(tx, addr)
+	if err != nil || unpaid.Uint64() != 3 {
+		t.Fatalf("migrated unpaid reward: have %v (%v), want 3", unpaid, err)
+	}
+	// Blocks 4 and 5 verified since the reward block 4.
+	if blocks, err := rawdb.ReadRewardAccrual(tx, 2, addr); err != nil || blocks != 2 {
+		t.Fatalf("backfilled accrual: have %d (%v), want 2", blocks, err)
+	}
+	legacy := 0
+	if err := tx.ForEach(modules.Reward, nil, func(k, v []byte) error {
+		legacy++
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if legacy != 0 {
+		t.Fatalf("%d legacy reward entries left", legacy)
+	}
+}
+
+// Tests that a block is only accepted if it lists the rewards it pays out.
+func TestVerifyRewards(t *testing.T) {
+	modules.AmcInit()
+	kv.ChaindataTablesCfg = modules.AmcTableCfg
+	db := memdb.New(t.TempDir())
+	defer db.Close()
+	tx, err := db.BeginRw(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	var addrA, addrB types.Address
+	addrA[0], addrB[0] = 0xa, 0xb
+	for _, addr := range []types.Address{addrA, addrB} {
+		key, err := bls.RandKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var pub types.PublicKey
+		pub.SetBytes(key.PublicKey().Marshal())
+		if err := rawdb.PutDeposit(tx, addr, pub, *new(uint256.Int).Mul(uint256.NewInt(50), uint256.NewInt(params.AMT))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	perBlock := deposit.GetDepositInfo(tx, addrA, params.DefaultDepositSchedule).RewardPerBlock
+	limit := new(uint256.Int).Mul(perBlock, uint256.NewInt(3))
+
+	engine := New(&conf.ConsensusConfig{APos: &conf.APosConfig{RewardEpoch: 4, RewardLimit: limit.ToBig()}}, db, &params.ChainConfig{BeijingBlock: big.NewInt(0)}).(*APos)
+	var parent types.Hash
+	for n := uint64(0); n <= 3; n++ {
+		b := block.NewBlock(&block.Header{Number: uint256.NewInt(n), ParentHash: parent}, nil)
+		if n > 0 {
+			b.Body().(*block.Body).Verifiers = []*block.Verify{{Address: addrA}}
+		}
+		if n == 1 {
+			b.Body().(*block.Body).Verifiers = append(b.Body().(*block.Body).Verifiers, &block.Verify{Address: addrB})
+		}
+		if err := rawdb.WriteCanonicalHash(tx, b.Hash(), n); err != nil {
+			t.Fatal(err)
+		}
+		rawdb.WriteHeadBlockHash(tx, b.Hash())
+		if err := engine.ApplyBlock(tx, b); err != nil {
+			t.Fatalf("block %d: %v", n, err)
+		}
+		parent = b.Hash()
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Block 4 pays A for the three blocks it verified, B stays below the limit.
+	tests := []struct {
+		name    string
+		rewards []*block.Reward
+		err     error
+	}{
+		{"valid", []*block.Reward{{Address: addrA, Amount: limit}}, nil},
+		{"missing", nil, errInvalidRewards},
+		{"other amount", []*block.Reward{{Address: addrA, Amount: new(uint256.Int).Add(limit, uint256.NewInt(1))}}, errInvalidRewards},
+		{"other address", []*block.Reward{{Address: addrB, Amount: limit}}, errInvalidRewards},
+		{"extra", []*block.Reward{{Address: addrA, Amount: limit}, {Address: addrB, Amount: perBlock}}, errInvalidRewards},
+		{"no amount", []*block.Reward{{Address: addrA}}, errInvalidRewards},
+	}
+	for _, tt := range tests {
+		b := block.NewBlock(&block.Header{Number: uint256.NewInt(4), ParentHash: parent}, nil)
+		b.Body().(*block.Body).Rewards = tt.rewards
+		if err := engine.VerifyRewards(b); !errors.Is(err, tt.err) {
+			t.Errorf("%s: have %v, want %v", tt.name, err, tt.err)
+		}
+	}
+}
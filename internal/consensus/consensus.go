@@ -129,6 +129,54 @@ type Engine interface {
 	Close() error
 }
 
+// AggSignVerifier is implemented by engines whose blocks carry an aggregate
+// signature of the active depositors over the state root.
+type AggSignVerifier interface {
+	// VerifyAggSign checks the verifiers and the aggregate signature of a block
+	// against the registered deposits and the signing quorum.
+	VerifyAggSign(block block.IBlock) error
+}
+
+// RewardVerifier is implemented by engines whose blocks list the rewards they
+// pay out.
+type RewardVerifier interface {
+	// VerifyRewards checks the rewards listed by a block against the ones it
+	// pays out on top of its parent.
+	VerifyRewards(block block.IBlock) error
+}
+
+// Unwinder is implemented by engines keeping consensus data outside of the account
+// state, such as deposits and rewards, which has to follow a rewind of the chain.
+type Unwinder interface {
+	// Unwind reverts the consensus data recorded by the canonical blocks
+	// (target, head]. It runs before those blocks are removed.
+	Unwind(tx kv.RwTx, head, target uint64) error
+}
+
+// Ledger is implemented by engines keeping accounts of the canonical chain outside
+// of the account state, such as the rewards owed to verifiers.
+type Ledger interface {
+	// ApplyBlock accounts the block becoming the head of the canonical chain.
+	ApplyBlock(tx kv.RwTx, block block.IBlock) error
+	// UnwindBlocks reverts the accounting of the canonical blocks after target.
+	UnwindBlocks(tx kv.RwTx, target uint64) error
+}
+
+// DepositTracker is implemented by engines whose blocks are signed by depositors,
+// so that every block is checked against the depositors after its parent.
+type DepositTracker interface {
+	// WriteDeposits records the depositors after a block written with its receipts.
+	WriteDeposits(tx kv.RwTx, block block.IBlock, receipts block.Receipts) error
+}
+
+// FinalityReader is implemented by engines that can tell which blocks of the
+// canonical chain are safe and finalized, as reported by the RPC block tags.
+type FinalityReader interface {
+	// Finality returns the numbers of the latest safe and finalized ancestors of
+	// head, 0 if none is known beyond genesis.
+	Finality(chain ChainReader, head block.IHeader) (safe, finalized uint64, err error)
+}
+
 // EngineReader are read-only methods of the consensus engine
 // All of these methods should have thread-safe implementations
 type EngineReader interface {
@@ -0,0 +1,58 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package consensus
+
+import (
+	"github.com/amazechain/amc/common/block"
+	"github.com/amazechain/amc/common/types"
+	"github.com/holiman/uint256"
+)
+
+// MaxFinalityDepth bounds the number of blocks walked back from the head to find
+// the safe and finalized blocks.
+const MaxFinalityDepth = 1024
+
+// SignerFinality implements the finality rules of the signer based engines. A
+// block is safe once a majority of the signers sealed blocks on top of it, as no
+// fork without them can outgrow it. It is finalized once every signer built on
+// it, or once it is safe and attested returns true for it.
+func SignerFinality(chain ChainReader, head block.IHeader, signers int, author func(block.IHeader) (types.Address, error), attested func(block.IHeader) bool) (safe, finalized uint64, err error) {
+	seen := make(map[types.Address]struct{}, signers)
+	header := head
+	for depth := 0; depth < MaxFinalityDepth && header.Number64().Uint64() > 0; depth++ {
+		signer, err := author(header)
+		if err != nil {
+			return 0, 0, err
+		}
+		seen[signer] = struct{}{}
+
+		number := header.Number64().Uint64() - 1
+		parent := chain.GetHeader(header.(*block.Header).ParentHash, uint256.NewInt(number))
+		if parent == nil {
+			break
+		}
+		// len(seen) distinct signers built on top of parent
+		if safe == 0 && len(seen) > signers/2 {
+			safe = number
+		}
+		if safe != 0 && (len(seen) >= signers || (attested != nil && attested(parent))) {
+			return safe, number, nil
+		}
+		header = parent
+	}
+	return safe, 0, nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package consensus
+
+import (
+	"testing"
+
+	"github.com/amazechain/amc/common/block"
+	"github.com/amazechain/amc/common/types"
+	"github.com/holiman/uint256"
+)
+
+type finalityChain struct {
+	ChainReader
+	headers map[types.Hash]block.IHeader
+}
+
+func (c *finalityChain) GetHeader(hash types.Hash, number *uint256.Int) block.IHeader {
+	if h, ok := c.headers[hash]; ok && h.Number64().Eq(number) {
+		return h
+	}
+	return nil
+}
+
+// Tests that blocks become safe once a majority of the signers built on them
+// and finalized once all signers did, or once a safe block is attested.
+func TestSignerFinality(t *testing.T) {
+	signers := []types.Address{{0x1}, {0x2}, {0x3}}
+	chain := &finalityChain{headers: make(map[types.Hash]block.IHeader)}
+
+	var head block.IHeader = &block.Header{Number: uint256.NewInt(0), Difficulty: uint256.NewInt(0)}
+	chain.headers[head.Hash()] = head
+	for i := 1; i <= 6; i++ {
+		head = &block.Header{
+			ParentHash: head.Hash(),
+			Coinbase:   signers[(i-1)%len(signers)],
+			Number:     uint256.NewInt(uint64(i)),
+			Difficulty: uint256.NewInt(0),
+		}
+		chain.headers[head.Hash()] = head
+	}
+	author := func(h block.IHeader) (types.Address, error) { return h.(*block.Header).Coinbase, nil }
+
+	tests := []struct {
+		attested  func(block.IHeader) bool
+		safe      uint64
+		finalized uint64
+	}{
+		{nil, 4, 3},
+		{func(block.IHeader) bool { return true }, 4, 4},
+		{func(h block.IHeader) bool { return h.Number64().Uint64() == 2 }, 4, 3},
+	}
+	for i, tt := range tests {
+		safe, finalized, err := SignerFinality(chain, head, len(signers), author, tt.attested)
+		if err != nil {
+			t.Fatalf("test %d: %v", i, err)
+		}
+		if safe != tt.safe || finalized != tt.finalized {
+			t.Errorf("test %d: have safe %d finalized %d, want %d %d", i, safe, finalized, tt.safe, tt.finalized)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/RoaringBitmap/roaring/roaring64"
+	block2 "github.com/amazechain/amc/common/block"
+	"github.com/amazechain/amc/common/transaction"
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/modules"
+	"github.com/amazechain/amc/modules/changeset"
+	"github.com/amazechain/amc/modules/ethdb/bitmapdb"
+	"github.com/amazechain/amc/modules/rawdb"
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+)
+
+// DBIssue is an inconsistency between the chain tables of a database.
+type DBIssue struct {
+	Number uint64 // canonical block the issue was found at
+	Table  string // table holding the missing or wrong entry
+	Reason string
+
+	repair func(tx kv.RwTx) error
+}
+
+func (i *DBIssue) String() string {
+	return fmt.Sprintf("block %d: %s: %s", i.Number, i.Table, i.Reason)
+}
+
+// Repairable reports whether the entry can be rebuilt from the other tables.
+func (i *DBIssue) Repairable() bool {
+	return i.repair != nil
+}
+
+// Repair rewrites the entry from the other tables. The transaction must be one
+// of a database nothing else writes to.
+func (i *DBIssue) Repair(tx kv.RwTx) error {
+	if i.repair == nil {
+		return fmt.Errorf("%v cannot be repaired", i)
+	}
+	return i.repair(tx)
+}
+
+// VerifyDatabase walks the canonical blocks [from, to] and checks the chain
+// tables against each other: the canonical hashes, headers, hash to number
+// mappings and parent hashes, the bodies with their transaction ids, senders,
+// receipts and lookup entries, and that the history indices hold every account
+// changeset. Receipts and changesets are only checked above their prune horizon.
+// Every inconsistency is passed to report; the returned error is one of the
+// database.
+func VerifyDatabase(ctx context.Context, tx kv.Tx, from, to uint64, report func(*DBIssue)) error {
+	receiptsFrom, err := rawdb.ReceiptsAvailableFrom(tx)
+	if nil != err {
+		return err
+	}
+	historyFrom, err := rawdb.ReadPrunedTo(tx, rawdb.PruneHistory)
+	if nil != err {
+		return err
+	}
+
+	var (
+		lastTxId uint64 // last transaction id of the previous canonical body
+		haveBody bool
+	)
+	for n := from; n <= to; n++ {
+		if err := ctx.Err(); nil != err {
+			return err
+		}
+		number := n
+		issue := func(table, format string, args ...interface{}) *DBIssue {
+			i := &DBIssue{Number: number, Table: table, Reason: fmt.Sprintf(format, args...)}
+			report(i)
+			return i
+		}
+
+		hash, err := rawdb.ReadCanonicalHash(tx, number)
+		if nil != err {
+			return err
+		}
+		if hash == (types.Hash{}) {
+			issue(modules.HeaderCanonical, "missing canonical hash")
+			continue
+		}
+		header := rawdb.ReadHeader(tx, hash, number)
+		if header == nil {
+			issue(modules.Headers, "missing or undecodable header %v", hash)
+			continue
+		}
+		if h := header.Hash(); h != hash {
+			issue(modules.Headers, "header of %v hashes to %v", hash, h)
+		}
+		if stored := rawdb.ReadHeaderNumber(tx, hash); stored == nil || *stored != number {
+			i := issue(modules.HeaderNumber, "%v is not mapped to its number", hash)
+			i.repair = func(tx kv.RwTx) error {
+				return rawdb.WriteHeaderNumber(tx, hash, number)
+			}
+		}
+		if number > 0 {
+			parent, err := rawdb.ReadCanonicalHash(tx, number-1)
+			if nil != err {
+				return err
+			}
+			if header.ParentHash != parent {
+				issue(modules.Headers, "parent hash %v, canonical parent is %v", header.ParentHash, parent)
+			}
+		}
+
+		body, err := rawdb.ReadStorageBody(tx, hash, number)
+		if nil != err {
+			issue(modules.BlockBody, "missing or malformed body of %v", hash)
+			continue
+		}
+		if body.TxAmount < 2 {
+			issue(modules.BlockBody, "body holds %d transaction ids, want at least 2", body.TxAmount)
+			continue
+		}
+		last := body.BaseTxId + uint64(body.TxAmount) - 1
+		if haveBody && body.BaseTxId <= lastTxId {
+			issue(modules.BlockBody, "transaction ids from %d overlap the previous block ending at %d", body.BaseTxId, lastTxId)
+		}
+		lastTxId, haveBody = last, true
+		if v, err := tx.GetOne(modules.MaxTxNum, modules.EncodeBlockNumber(number)); nil != err {
+			return err
+		} else if len(v) > 0 && (len(v) != 8 || binary.BigEndian.Uint64(v) != last) {
+			i := issue(modules.MaxTxNum, "max transaction id is %x, want %d", v, last)
+			i.repair = func(tx kv.RwTx) error {
+				return tx.Put(modules.MaxTxNum, modules.EncodeBlockNumber(number), modules.EncodeBlockNumber(last))
+			}
+		}
+
+		// The first and the last id of a body are reserved for system transactions.
+		txs := make([]*transaction.Transaction, 0, body.TxAmount-2)
+		for id := body.BaseTxId + 1; id < last; id++ {
+			v, err := tx.GetOne(modules.BlockTx, modules.EncodeBlockNumber(id))
+			if nil != err {
+				return err
+			}
+			if len(v) == 0 {
+				issue(modules.BlockTx, "missing transaction %d", id)
+				continue
+			}
+			t := new(transaction.Transaction)
+			if err := t.Unmarshal(v); nil != err {
+				issue(modules.BlockTx, "undecodable transaction %d: %v", id, err)
+				continue
+			}
+			txs = append(txs, t)
+		}
+
+		if senders, err := rawdb.ReadSenders(tx, hash, number); nil != err {
+			return err
+		} else if len(senders) > 0 && len(senders) != len(txs) {
+			// Readers recover missing senders from the signatures.
+			i := issue(modules.Senders, "%d senders for %d transactions", len(senders), len(txs))
+			i.repair = func(tx kv.RwTx) error {
+				return tx.Delete(modules.Senders, modules.BlockBodyKey(number, hash))
+			}
+		}
+
+		for _, t := range txs {
+			txHash := t.Hash()
+			n, err := rawdb.ReadTxLookupEntry(tx, txHash)
+			if nil != err {
+				return err
+			}
+			if n == nil || *n != number {
+				i := issue(modules.TxLookup, "transaction %v is not mapped to its block", txHash)
+				i.repair = func(tx kv.RwTx) error {
+					return tx.Put(modules.TxLookup, txHash.Bytes(), uint256.NewInt(number).Bytes())
+				}
+			}
+		}
+
+		if number >= receiptsFrom && len(txs) > 0 {
+			v, err := tx.GetOne(modules.Receipts, modules.EncodeBlockNumber(number))
+			if nil != err {
+				return err
+			}
+			var receipts block2.Receipts
+			if len(v) == 0 {
+				issue(modules.Receipts, "missing receipts")
+			} else if err := receipts.Unmarshal(v); nil != err {
+				issue(modules.Receipts, "undecodable receipts: %v", err)
+			} else if len(receipts) != len(txs) {
+				issue(modules.Receipts, "%d receipts for %d transactions", len(receipts), len(txs))
+			}
+		}
+
+		if number >= historyFrom {
+			if err := verifyHistoryIndex(tx, number, issue); nil != err {
+				return err
+			}
+		}
+	}
+
+	if seq, err := tx.ReadSequence(modules.BlockTx); nil != err {
+		return err
+	} else if haveBody && lastTxId >= seq {
+		report(&DBIssue{Number: to, Table: modules.BlockTx, Reason: fmt.Sprintf("transaction id sequence %d is behind the last id %d", seq, lastTxId)})
+	}
+	return nil
+}
+
+// verifyHistoryIndex checks that the history index of every account changed by
+// the block holds the block.
+func verifyHistoryIndex(tx kv.Tx, number uint64, issue func(table, format string, args ...interface{}) *DBIssue) error {
+	var addrs [][]byte
+	if err := changeset.ForPrefix(tx, modules.AccountChangeSet, modules.EncodeBlockNumber(number), func(_ uint64, k, _ []byte) error {
+		if len(addrs) == 0 || !bytes.Equal(addrs[len(addrs)-1], k) {
+			addrs = append(addrs, types.CopyBytes(k))
+		}
+		return nil
+	}); nil != err {
+		return err
+	}
+	for _, addr := range addrs {
+		index, err := bitmapdb.Get64(tx, modules.AccountsHistory, addr, number, number)
+		if nil != err {
+			return err
+		}
+		if !index.Contains(number) {
+			addr := addr
+			i := issue(modules.AccountsHistory, "history of %x lacks the account changeset", addr)
+			i.repair = func(tx kv.RwTx) error {
+				return addToHistoryIndex(tx, modules.AccountsHistory, addr, number)
+			}
+		}
+	}
+	return nil
+}
+
+// addToHistoryIndex adds the block to the sharded history index of the key.
+func addToHistoryIndex(tx kv.RwTx, bucket string, key []byte, number uint64) error {
+	index, err := bitmapdb.Get64(tx, bucket, key, 0, math.MaxUint32)
+	if nil != err {
+		return err
+	}
+	index.Add(number)
+	var buf bytes.Buffer
+	return bitmapdb.WalkChunkWithKeys64(key, index, bitmapdb.ChunkLimit, func(chunkKey []byte, chunk *roaring64.Bitmap) error {
+		buf.Reset()
+		if _, err := chunk.WriteTo(&buf); nil != err {
+			return err
+		}
+		return tx.Put(bucket, chunkKey, types.CopyBytes(buf.Bytes()))
+	})
+}
@@ -0,0 +1,138 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+package internal
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	block2 "github.com/amazechain/amc/common/block"
+	"github.com/amazechain/amc/common/transaction"
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/modules"
+	"github.com/amazechain/amc/modules/rawdb"
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+)
+
+func verifyIssues(t *testing.T, db kv.RwDB, to uint64) []*DBIssue {
+	t.Helper()
+	var issues []*DBIssue
+	if err := db.View(context.Background(), func(tx kv.Tx) error {
+		return VerifyDatabase(context.Background(), tx, 0, to, func(issue *DBIssue) {
+			issues = append(issues, issue)
+		})
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return issues
+}
+
+func TestVerifyDatabase(t *testing.T) {
+	modules.AmcInit()
+	kv.ChaindataTablesCfg = modules.AmcTableCfg
+	db := memdb.New(t.TempDir())
+	defer db.Close()
+
+	// A genesis block and two blocks with one transaction each
+	from, to := types.Address{1}, types.Address{2}
+	var hashes []types.Hash
+	if err := db.Update(context.Background(), func(tx kv.RwTx) error {
+		var parent types.Hash
+		for i := uint64(0); i < 3; i++ {
+			header := &block2.Header{Number: uint256.NewInt(i), ParentHash: parent, Difficulty: uint256.NewInt(2), BaseFee: uint256.NewInt(0)}
+			var txs []*transaction.Transaction
+			var receipts block2.Receipts
+			if i > 0 {
+				txs = append(txs, transaction.NewTransaction(i, from, &to, uint256.NewInt(i), 21000, uint256.NewInt(1), nil))
+				receipts = append(receipts, &block2.Receipt{Status: block2.ReceiptStatusSuccessful, CumulativeGasUsed: 21000, TxHash: txs[0].Hash(), BlockNumber: uint256.NewInt(i)})
+			}
+			b := block2.NewBlockFromStorage(header.Hash(), header, &block2.Body{Txs: txs})
+			if err := rawdb.WriteBlock(tx, b); err != nil {
+				return err
+			}
+			if err := rawdb.WriteCanonicalHash(tx, b.Hash(), i); err != nil {
+				return err
+			}
+			rawdb.WriteTxLookupEntries(tx, b)
+			if err := rawdb.WriteReceipts(tx, i, receipts); err != nil {
+				return err
+			}
+			parent = b.Hash()
+			hashes = append(hashes, parent)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if issues := verifyIssues(t, db, 2); len(issues) != 0 {
+		t.Fatalf("consistent database reported %v", issues)
+	}
+
+	// Drop a hash to number mapping and a lookup entry, and break the linkage
+	if err := db.Update(context.Background(), func(tx kv.RwTx) error {
+		rawdb.DeleteHeaderNumber(tx, hashes[1])
+		b := rawdb.ReadBlock(tx, hashes[2], 2)
+		if err := rawdb.DeleteTxLookupEntry(tx, b.Transactions()[0].Hash()); err != nil {
+			return err
+		}
+		return rawdb.WriteCanonicalHash(tx, types.Hash{9}, 0)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	issues := verifyIssues(t, db, 2)
+	var reasons []string
+	repairable := 0
+	for _, issue := range issues {
+		reasons = append(reasons, issue.String())
+		if issue.Repairable() {
+			repairable++
+		}
+	}
+	want := []string{
+		"block 0: " + modules.Headers + ": missing",
+		"block 1: " + modules.HeaderNumber,
+		"block 1: " + modules.Headers + ": parent hash",
+		"block 2: " + modules.TxLookup,
+	}
+	if len(reasons) != len(want) || repairable != 2 {
+		t.Fatalf("have issues %q, %d repairable, want %d issues, 2 repairable", reasons, repairable, len(want))
+	}
+	for i, prefix := range want {
+		if !strings.HasPrefix(reasons[i], prefix) {
+			t.Errorf("issue %d: have %q, want prefix %q", i, reasons[i], prefix)
+		}
+	}
+
+	// Repair what can be derived and restore the canonical genesis hash
+	if err := db.Update(context.Background(), func(tx kv.RwTx) error {
+		for _, issue := range issues {
+			if issue.Repairable() {
+				if err := issue.Repair(tx); err != nil {
+					return err
+				}
+			}
+		}
+		return rawdb.WriteCanonicalHash(tx, hashes[0], 0)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if issues := verifyIssues(t, db, 2); len(issues) != 0 {
+		t.Fatalf("repaired database reported %v", issues)
+	}
+}
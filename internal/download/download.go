@@ -30,9 +30,11 @@ import (
 	"github.com/amazechain/amc/api/protocol/sync_proto"
 	"github.com/amazechain/amc/api/protocol/types_pb"
 	"github.com/amazechain/amc/common"
+	"github.com/amazechain/amc/common/block"
 	"github.com/amazechain/amc/log"
 	event "github.com/amazechain/amc/modules/event/v2"
 	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/rcrowley/go-metrics"
 	"go.uber.org/zap"
 )
 
@@ -44,6 +46,12 @@ var (
 	ErrBadPeer       = fmt.Errorf("bad peer error")
 	ErrNoPeers       = fmt.Errorf("no peers to download")
 	ErrInvalidPubSub = fmt.Errorf("PubSub is nil")
+	ErrBadState      = fmt.Errorf("bad state data")
+)
+
+var (
+	headerQueueGauge = metrics.GetOrRegisterGauge("downloader/headers/queue", nil)
+	bodyQueueGauge   = metrics.GetOrRegisterGauge("downloader/bodies/queue", nil)
 )
 
 const (
@@ -58,6 +66,13 @@ const (
 	syncPeerIntervalRequest = time.Duration(3 * time.Second)
 	syncPeerInfoTimeTick    = time.Duration(10 * time.Second)
 	maxDifferenceNumber     = 2
+	maxStateFetch           = 1024            // Number of accounts or storage slots per state response
+	maxCodeFetch            = 64              // Number of contract codes per state response
+	snapPivotOffset         = 64              // Distance of the snap sync pivot below the highest block
+	stateAccountTasks       = 16              // Number of account ranges downloaded in parallel
+	stateTasksPerPeer       = 4               // Number of state requests in flight per peer
+	statePeerPenalty        = 5 * time.Minute // Time a peer serving bad state is not asked again
+	maxStateAttempts        = 3               // Number of state downloads before a root mismatch fails the sync
 )
 
 type headerResponse struct {
@@ -96,6 +111,7 @@ type Downloader struct {
 	isDownloading int32
 
 	highestNumber uint256.Int
+	startNumber   uint64 // block the current sync cycle began at, use atomic access
 
 	ctx        context.Context
 	cancel     context.CancelFunc
@@ -123,9 +139,19 @@ type Downloader struct {
 	bodyTaskPool        []*blockTask
 	bodyProcessingTasks map[uint64]*blockTask
 	bodyResultStore     map[uint256.Int]*types_pb.Block
+
+	// snap sync
+	pivot                uint64 // pivot block while its state is downloaded, use atomic access
+	snapHead             uint64 // last block stored without state below the pivot
+	pivotCh              chan block.IBlock
+	stateProcCh          chan *stateResponse
+	stateTaskLock        sync.Mutex
+	stateTasks           []*stateTask
+	stateProcessingTasks map[uint64]*stateTask
+	stateBadPeers        map[peer.ID]time.Time // peers that served bad state, until when they are skipped
 }
 
-func NewDownloader(ctx context.Context, bc common.IBlockChain, network common.INetwork, pubsub common.IPubSub, peers common.PeerMap) common.IDownloader {
+func NewDownloader(ctx context.Context, bc common.IBlockChain, network common.INetwork, pubsub common.IPubSub, peers common.PeerMap, mode SyncMode) common.IDownloader {
 	c, cancel := context.WithCancel(ctx)
 
 	highestNumber := bc.CurrentBlock().Number64().Clone()
@@ -136,7 +162,7 @@ func NewDownloader(ctx context.Context, bc common.IBlockChain, network common.IN
 	}
 
 	return &Downloader{
-		mode:                  uint32(FullSync),
+		mode:                  uint32(mode),
 		bc:                    bc,
 		network:               network,
 		ctx:                   c,
@@ -154,7 +180,20 @@ func NewDownloader(ctx context.Context, bc common.IBlockChain, network common.IN
 		bodyResultStore:       make(map[uint256.Int]*types_pb.Block),
 		highestNumber:         *highestNumber,
 		peersInfo:             newPeersInfo(c, peers),
+		pivotCh:               make(chan block.IBlock, 1),
+		stateProcCh:           make(chan *stateResponse, 10),
+		stateProcessingTasks:  make(map[uint64]*stateTask),
+		stateBadPeers:         make(map[peer.ID]time.Time),
+	}
+}
+
+// PeerInfo returns the last block number and difficulty announced by a peer.
+func (d *Downloader) PeerInfo(id peer.ID) (number *uint256.Int, difficulty *uint256.Int, ok bool) {
+	info, ok := d.peersInfo.peerInfo(id)
+	if !ok {
+		return nil, nil, false
 	}
+	return info.Number, info.Difficulty, true
 }
 
 func (d *Downloader) getMode() SyncMode {
@@ -217,6 +256,7 @@ func (d *Downloader) doSync(mode SyncMode) error {
 	if err != nil {
 		return err
 	}
+	atomic.StoreUint64(&d.startNumber, origin.Uint64())
 	// downloader current height
 	latest, err := d.findHead()
 	if err != nil {
@@ -225,6 +265,16 @@ func (d *Downloader) doSync(mode SyncMode) error {
 
 	var fetchers []func() error
 
+	if mode == SnapSync {
+		if pivot, ok := d.snapPivot(origin, latest); ok {
+			atomic.StoreUint64(&d.pivot, pivot)
+			d.snapHead = origin.Uint64()
+			fetchers = append(fetchers, func() error { return d.fetchState(pivot) })
+		} else {
+			log.Info("Snap sync not possible, falling back to full sync", "current", origin.Uint64(), "highest", latest.Uint64())
+		}
+	}
+
 	switch mode {
 	case HeaderSync:
 	default:
@@ -282,6 +332,16 @@ func (d *Downloader) IsDownloading() bool {
 	return true
 }
 
+// Progress retrieves the synchronisation boundaries of the current or the
+// last sync cycle.
+func (d *Downloader) Progress() common.SyncProgress {
+	return common.SyncProgress{
+		StartingBlock: atomic.LoadUint64(&d.startNumber),
+		CurrentBlock:  d.bc.CurrentBlock().Number64().Uint64(),
+		HighestBlock:  d.highestNumber.Uint64(),
+	}
+}
+
 func (d *Downloader) findAncestor() (uint256.Int, error) {
 	return *d.bc.CurrentBlock().Number64(), nil
 }
@@ -420,6 +480,22 @@ func (d *Downloader) ConnHandler(data []byte, ID peer.ID) error {
 		params = append(params, "bodyNumberFrom", utils.ConvertH256ToUint256Int(blockRequest.Number[0]).Uint64(), "bodyNumberTo", utils.ConvertH256ToUint256Int(blockRequest.Number[len(blockRequest.Number)-1]).Uint64())
 		go d.responseBlocks(taskID, p, blockRequest)
 
+	case sync_proto.SyncType_StateRes:
+		rangeResponse := syncTask.Payload.(*sync_proto.SyncTask_SyncStateResponse).SyncStateResponse
+		params = append(params, "table", rangeResponse.Table, "stateCount", len(rangeResponse.Keys), "more", rangeResponse.More)
+		// Late and unsolicited responses must not stall the handler
+		select {
+		case d.stateProcCh <- &stateResponse{taskID: taskID, peer: ID, ok: syncTask.Ok, keys: rangeResponse.Keys, values: rangeResponse.Values, more: rangeResponse.More}:
+		case <-d.ctx.Done():
+		default:
+			log.Debug("Dropping state response", params...)
+		}
+
+	case sync_proto.SyncType_StateReq:
+		stateRequest := syncTask.Payload.(*sync_proto.SyncTask_SyncStateRequest).SyncStateRequest
+		params = append(params, "table", stateRequest.Table, "number", utils.ConvertH256ToUint256Int(stateRequest.Number).Uint64())
+		go d.responseState(taskID, p, stateRequest)
+
 	case sync_proto.SyncType_PeerInfoBroadcast:
 		peerInfoBroadcast := syncTask.Payload.(*sync_proto.SyncTask_SyncPeerInfoBroadcast).SyncPeerInfoBroadcast
 		//
@@ -57,6 +57,7 @@ func (d *Downloader) fetchHeaders(from uint256.Int, latest uint256.Int) error {
 
 	for {
 		log.Tracef("header tasks count is %v, header processing tasks count is: %v", len(d.headerTasks), len(d.headerProcessingTasks))
+		headerQueueGauge.Update(int64(len(d.headerTasks)))
 
 		if len(d.headerTasks) == 0 && len(d.headerProcessingTasks) == 0 { // break if there are no tasks
 			break
@@ -141,6 +142,7 @@ func (d *Downloader) fetchBodies(latest uint256.Int) error {
 		peerSet := d.peersInfo.findPeers(&latest, syncPeerCount)
 
 		log.Tracef("downloader body task count is %d, processing task count is %d", len(d.bodyTaskPool), len(d.bodyProcessingTasks))
+		bodyQueueGauge.Update(int64(len(d.bodyTaskPool)))
 		if startProcess && len(d.bodyTaskPool) == 0 && len(d.bodyProcessingTasks) == 0 {
 			return nil
 		}
@@ -78,18 +78,21 @@ func (p *peersInfo) update(id peer.ID, Number *uint256.Int, Difficulty *uint256.
 	p.lock.Lock()
 	defer p.lock.Unlock()
 
-	if peer, ok := p.info[id]; ok {
-		peer.Number = Number
-		peer.Difficulty = Difficulty
-	} else {
-		p.info[id] = peerInfo{
-			ID:         id,
-			Difficulty: Difficulty,
-			Number:     Number,
-		}
+	p.info[id] = peerInfo{
+		ID:         id,
+		Difficulty: Difficulty,
+		Number:     Number,
 	}
 }
 
+// peerInfo returns the last height and difficulty announced by the given peer.
+func (p *peersInfo) peerInfo(id peer.ID) (peerInfo, bool) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	info, ok := p.info[id]
+	return info, ok
+}
+
 func (p *peersInfo) drop(id peer.ID) {
 	p.lock.Lock()
 	defer p.lock.Unlock()
@@ -20,6 +20,7 @@ import (
 	"github.com/amazechain/amc/utils"
 	"github.com/holiman/uint256"
 	"math/rand"
+	"sync/atomic"
 	"time"
 
 	block2 "github.com/amazechain/amc/common/block"
@@ -158,10 +159,18 @@ func (d *Downloader) processChain() error {
 
 			d.bodyTaskPoolLock.Lock()
 			wantBlockNumber := new(uint256.Int).AddUint64(d.bc.CurrentBlock().Number64(), 1)
+			// Below the snap sync pivot blocks are stored without state
+			pivot := atomic.LoadUint64(&d.pivot)
+			if pivot > 0 {
+				wantBlockNumber.SetUint64(d.snapHead + 1)
+			}
 			log.Tracef("want block %d have blocks count is %d", wantBlockNumber.Uint64(), len(d.bodyResultStore))
 
 			blocks := make([]block2.IBlock, 0)
 			for i := 0; i < maxResultsProcess; i++ {
+				if pivot > 0 && wantBlockNumber.Uint64() > pivot {
+					break
+				}
 				if blockMsg, ok := d.bodyResultStore[*wantBlockNumber]; ok {
 					var block block2.Block
 					err := block.FromProtoMessage(blockMsg)
@@ -171,6 +180,8 @@ func (d *Downloader) processChain() error {
 					}
 					delete(d.bodyResultStore, *wantBlockNumber)
 					blocks = append(blocks, &block)
+				} else if pivot > 0 {
+					break
 				}
 				wantBlockNumber.AddUint64(wantBlockNumber, 1)
 			}
@@ -180,6 +191,21 @@ func (d *Downloader) processChain() error {
 				continue
 			}
 
+			if pivot > 0 {
+				if _, err := d.bc.InsertBlocksWithoutState(blocks); err != nil {
+					log.Errorf("downloader failed to store blocks below the pivot, err:%v", err)
+					d.bodyTaskPoolLock.Unlock()
+					return err
+				}
+				last := blocks[len(blocks)-1]
+				d.snapHead = last.Number64().Uint64()
+				if d.snapHead == pivot {
+					d.pivotCh <- last
+				}
+				d.bodyTaskPoolLock.Unlock()
+				continue
+			}
+
 			first, last := blocks[0].Header(), blocks[len(blocks)-1].Header()
 			log.Info("Inserting downloaded chain", "items", len(blocks),
 				"firstnum", first.Number64().Uint64(), "firsthash", first.Hash(),
@@ -17,13 +17,19 @@
 package download
 
 import (
+	"fmt"
+
 	"github.com/amazechain/amc/api/protocol/sync_proto"
 	"github.com/amazechain/amc/api/protocol/types_pb"
 	"github.com/amazechain/amc/common"
 	"github.com/amazechain/amc/common/message"
+	"github.com/amazechain/amc/common/types"
 	"github.com/amazechain/amc/log"
+	"github.com/amazechain/amc/modules"
+	"github.com/amazechain/amc/modules/state"
 	"github.com/amazechain/amc/utils"
 	"github.com/golang/protobuf/proto"
+	"github.com/ledgerwatch/erigon-lib/kv"
 
 	"github.com/holiman/uint256"
 )
@@ -118,3 +124,76 @@ func (d *Downloader) responseBlocks(taskID uint64, p common.Peer, task *sync_pro
 	p.WriteMsg(message.MsgDownloader, payload)
 	log.Debugf("response sync task(blockRequest) ok: %v , taskID: %v, block count: %v", ok, taskID, len(task.Number))
 }
+
+// responseState serves a range of the Account or Storage table as of the requested
+// pivot block, or the contract codes with the requested hashes.
+func (d *Downloader) responseState(taskID uint64, p common.Peer, task *sync_proto.SyncStateRequest) {
+
+	var (
+		keys, values [][]byte
+		more         bool
+		ok           = true
+	)
+
+	number := utils.ConvertH256ToUint256Int(task.Number).Uint64()
+	if number > d.bc.CurrentBlock().Number64().Uint64() {
+		log.Warnf("cannot serve state of future block %d", number)
+		ok = false
+	} else if err := d.bc.DB().View(d.ctx, func(tx kv.Tx) error {
+		var err error
+		switch task.Table {
+		case modules.Account:
+			keys, values, more, err = state.AccountRange(tx, number, task.Origin, task.Limit, maxStateFetch)
+		case modules.Storage:
+			if len(task.Origin) != types.AddressLength+types.IncarnationLength+types.HashLength {
+				return fmt.Errorf("invalid storage origin %x", task.Origin)
+			}
+			addr, incarnation, origin := modules.PlainParseCompositeStorageKey(task.Origin)
+			keys, values, more, err = state.StorageRange(tx, number, addr, incarnation, origin, maxStateFetch)
+		case modules.Code:
+			for i, hash := range task.Hashes {
+				if i >= maxCodeFetch {
+					break
+				}
+				code, err := tx.GetOne(modules.Code, hash)
+				if err != nil {
+					return err
+				}
+				if len(code) > 0 {
+					keys = append(keys, hash)
+					values = append(values, types.CopyBytes(code))
+				}
+			}
+		default:
+			err = fmt.Errorf("unknown state table %q", task.Table)
+		}
+		return err
+	}); err != nil {
+		log.Warnf("cannot fetch state from db the number is:%d, table is:%s, err: %v", number, task.Table, err)
+		keys, values, more = nil, nil, false
+		ok = false
+	}
+
+	msg := &sync_proto.SyncTask{
+		Id:       taskID,
+		Ok:       ok,
+		SyncType: sync_proto.SyncType_StateRes,
+		Payload: &sync_proto.SyncTask_SyncStateResponse{
+			SyncStateResponse: &sync_proto.SyncStateResponse{
+				Table:  task.Table,
+				Keys:   keys,
+				Values: values,
+				More:   more,
+			},
+		},
+	}
+	payload, err := proto.Marshal(msg)
+
+	if err != nil {
+		log.Errorf("proto Marshal err: %v", err)
+		return
+	}
+
+	p.WriteMsg(message.MsgDownloader, payload)
+	log.Debugf("response sync task(stateRequest) ok: %v , taskID: %v, table: %v, count: %v", ok, taskID, task.Table, len(keys))
+}
@@ -0,0 +1,404 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package download
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	"github.com/amazechain/amc/api/protocol/sync_proto"
+	"github.com/amazechain/amc/common"
+	"github.com/amazechain/amc/common/account"
+	"github.com/amazechain/amc/common/block"
+	"github.com/amazechain/amc/common/crypto"
+	"github.com/amazechain/amc/common/message"
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/log"
+	"github.com/amazechain/amc/modules"
+	"github.com/amazechain/amc/modules/state"
+	"github.com/amazechain/amc/utils"
+	"github.com/golang/protobuf/proto"
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+type stateTask struct {
+	taskID    uint64
+	table     string
+	origin    []byte
+	limit     []byte
+	hashes    [][]byte
+	peer      peer.ID // peer the task was last requested from
+	TimeBegin time.Time
+}
+
+type stateResponse struct {
+	taskID uint64
+	peer   peer.ID
+	ok     bool
+	keys   [][]byte
+	values [][]byte
+	more   bool
+}
+
+// snapPivot returns the block whose state is downloaded by snap sync. Snap sync is only
+// used by a node without blocks, and the state at the pivot must be verifiable against
+// the state commitment.
+func (d *Downloader) snapPivot(origin, latest uint256.Int) (uint64, bool) {
+	if !origin.IsZero() || latest.Uint64() <= snapPivotOffset {
+		return 0, false
+	}
+	pivot := latest.Uint64() - snapPivotOffset
+	if !d.bc.Config().IsStateCommitment(pivot) {
+		return 0, false
+	}
+	return pivot, true
+}
+
+// fetchState downloads the Account, Storage and Code tables at the pivot block in
+// parallel from the peers, checks them against the state root of the pivot block and
+// then makes the pivot block the head of the chain. A download that does not match
+// the state root is started over a few times before the sync fails.
+func (d *Downloader) fetchState(pivot uint64) error {
+
+	log.Infof("Starting state downloads pivot: %v", pivot)
+	defer log.Infof("State download Finished")
+
+	var pivotBlock block.IBlock
+	for attempt := 1; ; attempt++ {
+		if err := d.downloadState(pivot); err != nil {
+			return err
+		}
+
+		// Wait for the pivot block to verify the downloaded state against
+		if pivotBlock == nil {
+			select {
+			case <-d.ctx.Done():
+				return ErrCanceled
+			case pivotBlock = <-d.pivotCh:
+			}
+		}
+
+		err := d.bc.DB().Update(d.ctx, func(tx kv.RwTx) error {
+			commitment, err := state.GenerateStateCommitment(tx, d.bc.Config().Rules(pivot))
+			if err != nil {
+				return err
+			}
+			if root := commitment.Hash(); root != pivotBlock.StateRoot() {
+				return fmt.Errorf("%w: state root mismatch at pivot %d: have %x, want %x", ErrBadState, pivot, root, pivotBlock.StateRoot())
+			}
+			_, err = commitment.Commit(tx)
+			return err
+		})
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, ErrBadState) || attempt >= maxStateAttempts {
+			return err
+		}
+		log.Warn("Restarting state download", "pivot", pivot, "attempt", attempt, "err", err)
+	}
+	if err := d.bc.SnapSyncCommitHead(pivotBlock.Hash()); err != nil {
+		return err
+	}
+	atomic.StoreUint64(&d.pivot, 0)
+	return nil
+}
+
+// downloadState clears the state tables and downloads them at the pivot block.
+func (d *Downloader) downloadState(pivot uint64) error {
+	// The state of the genesis block is replaced by the state of the pivot block
+	if err := d.bc.DB().Update(d.ctx, func(tx kv.RwTx) error {
+		for _, table := range []string{modules.Account, modules.Storage, modules.PlainContractCode} {
+			if err := tx.ClearBucket(table); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	d.stateTaskLock.Lock()
+	d.stateTasks = d.stateTasks[:0]
+	d.stateProcessingTasks = make(map[uint64]*stateTask)
+	step := 256 / stateAccountTasks
+	for i := 0; i < stateAccountTasks; i++ {
+		task := &stateTask{
+			taskID: rand.Uint64(),
+			table:  modules.Account,
+			origin: make([]byte, types.AddressLength),
+		}
+		task.origin[0] = byte(i * step)
+		if i < stateAccountTasks-1 {
+			task.limit = make([]byte, types.AddressLength)
+			task.limit[0] = byte((i + 1) * step)
+		}
+		d.stateTasks = append(d.stateTasks, task)
+	}
+	d.stateTaskLock.Unlock()
+
+	tick := time.NewTicker(syncPeerIntervalRequest)
+	defer tick.Stop()
+
+	for {
+		d.stateTaskLock.Lock()
+		log.Tracef("state tasks count is %v, state processing tasks count is: %v", len(d.stateTasks), len(d.stateProcessingTasks))
+		if len(d.stateTasks) == 0 && len(d.stateProcessingTasks) == 0 {
+			d.stateTaskLock.Unlock()
+			return nil
+		}
+
+		peerSet := d.statePeers(pivot)
+		for i := 0; len(peerSet) > 0 && len(d.stateTasks) > 0 && len(d.stateProcessingTasks) < len(peerSet)*stateTasksPerPeer; i++ {
+			p := peerSet[i%len(peerSet)]
+			task := d.stateTasks[0]
+
+			msg := &sync_proto.SyncTask{
+				Id:       task.taskID,
+				SyncType: sync_proto.SyncType_StateReq,
+				Payload: &sync_proto.SyncTask_SyncStateRequest{
+					SyncStateRequest: &sync_proto.SyncStateRequest{
+						Number: utils.ConvertUint256IntToH256(uint256.NewInt(pivot)),
+						Table:  task.table,
+						Origin: task.origin,
+						Limit:  task.limit,
+						Hashes: task.hashes,
+					},
+				},
+			}
+			payload, _ := proto.Marshal(msg)
+			if err := p.WriteMsg(message.MsgDownloader, payload); err != nil {
+				log.Errorf("send sync request message to peer %v err is %v", p.ID(), err)
+				break
+			}
+			task.TimeBegin = time.Now()
+			task.peer = p.ID()
+			d.stateTasks = d.stateTasks[1:]
+			d.stateProcessingTasks[task.taskID] = task
+		}
+
+		// If it times out, put the task back
+		for taskID, task := range d.stateProcessingTasks {
+			if time.Since(task.TimeBegin) > syncTimeOutPerRequest {
+				delete(d.stateProcessingTasks, taskID)
+				d.stateTasks = append(d.stateTasks, task)
+			}
+		}
+		d.stateTaskLock.Unlock()
+
+		select {
+		case <-d.ctx.Done():
+			return ErrCanceled
+		case response := <-d.stateProcCh:
+			if err := d.processState(response); err != nil {
+				return err
+			}
+		case <-tick.C:
+			tick.Reset(syncPeerIntervalRequest)
+		}
+	}
+}
+
+// statePeers returns the peers state is requested from, leaving out the peers
+// penalized for serving bad state. It is called with the state task lock held.
+func (d *Downloader) statePeers(pivot uint64) common.PeerSet {
+	var set common.PeerSet
+	for _, p := range d.peersInfo.findPeers(uint256.NewInt(pivot), syncPeerCount) {
+		if until, ok := d.stateBadPeers[p.ID()]; ok {
+			if time.Now().Before(until) {
+				continue
+			}
+			delete(d.stateBadPeers, p.ID())
+		}
+		set = append(set, p)
+	}
+	return set
+}
+
+// processState writes a state response to the database and schedules the tasks it
+// leads to: the rest of the range, the storage of contracts and their codes. A bad
+// response penalizes the peer that sent it and puts the task back.
+func (d *Downloader) processState(response *stateResponse) error {
+	d.stateTaskLock.Lock()
+	defer d.stateTaskLock.Unlock()
+
+	task, ok := d.stateProcessingTasks[response.taskID]
+	if !ok || task.peer != response.peer {
+		return nil
+	}
+	delete(d.stateProcessingTasks, response.taskID)
+
+	// A response claiming more data without any keys would lose the rest of the range
+	if !response.ok || len(response.keys) != len(response.values) || (response.more && len(response.keys) == 0) {
+		d.stateTasks = append(d.stateTasks, task)
+		return nil
+	}
+	log.Tracef("received state from remote peers, the table is %v, the count is %v", task.table, len(response.keys))
+
+	var tasks []*stateTask
+	err := d.bc.DB().Update(d.ctx, func(tx kv.RwTx) error {
+		var err error
+		switch task.table {
+		case modules.Account:
+			tasks, err = processAccounts(tx, task, response)
+		case modules.Storage:
+			tasks, err = processStorage(tx, task, response)
+		case modules.Code:
+			tasks, err = processCodes(tx, task, response)
+		}
+		return err
+	})
+	if errors.Is(err, ErrBadState) {
+		log.Warn("Dropping bad state response", "peer", response.peer, "table", task.table, "err", err)
+		d.stateBadPeers[response.peer] = time.Now().Add(statePeerPenalty)
+		d.stateTasks = append(d.stateTasks, task)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	d.stateTasks = append(d.stateTasks, tasks...)
+	return nil
+}
+
+func processAccounts(tx kv.RwTx, task *stateTask, response *stateResponse) ([]*stateTask, error) {
+	var (
+		tasks []*stateTask
+		codes [][]byte
+	)
+	for i, k := range response.keys {
+		if len(k) != types.AddressLength || bytes.Compare(k, task.origin) < 0 || (task.limit != nil && bytes.Compare(k, task.limit) >= 0) {
+			return nil, fmt.Errorf("%w: account %x out of range", ErrBadState, k)
+		}
+		var acc account.StateAccount
+		if err := acc.DecodeForStorage(response.values[i]); err != nil {
+			return nil, fmt.Errorf("%w: account %x: %v", ErrBadState, k, err)
+		}
+		if err := tx.Put(modules.Account, k, response.values[i]); err != nil {
+			return nil, err
+		}
+		if acc.Incarnation == 0 {
+			continue
+		}
+		if err := tx.Put(modules.PlainContractCode, modules.PlainGenerateStoragePrefix(k, acc.Incarnation), acc.CodeHash[:]); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, &stateTask{
+			taskID: rand.Uint64(),
+			table:  modules.Storage,
+			origin: modules.PlainGenerateCompositeStorageKey(k, acc.Incarnation, nil),
+		})
+		if !acc.IsEmptyCodeHash() {
+			if has, err := tx.Has(modules.Code, acc.CodeHash[:]); err != nil {
+				return nil, err
+			} else if !has {
+				codes = append(codes, types.CopyBytes(acc.CodeHash[:]))
+			}
+		}
+	}
+	for len(codes) > 0 {
+		n := len(codes)
+		if n > maxCodeFetch {
+			n = maxCodeFetch
+		}
+		tasks = append(tasks, &stateTask{
+			taskID: rand.Uint64(),
+			table:  modules.Code,
+			hashes: codes[:n],
+		})
+		codes = codes[n:]
+	}
+	if response.more {
+		if next, ok := incrementKey(response.keys[len(response.keys)-1]); ok {
+			tasks = append(tasks, &stateTask{
+				taskID: rand.Uint64(),
+				table:  modules.Account,
+				origin: next,
+				limit:  task.limit,
+			})
+		}
+	}
+	return tasks, nil
+}
+
+func processStorage(tx kv.RwTx, task *stateTask, response *stateResponse) ([]*stateTask, error) {
+	prefix := task.origin[:types.AddressLength+types.IncarnationLength]
+	for i, k := range response.keys {
+		if len(k) != len(task.origin) || !bytes.HasPrefix(k, prefix) || bytes.Compare(k, task.origin) < 0 {
+			return nil, fmt.Errorf("%w: storage %x out of range", ErrBadState, k)
+		}
+		if err := tx.Put(modules.Storage, k, response.values[i]); err != nil {
+			return nil, err
+		}
+	}
+	if response.more {
+		last := response.keys[len(response.keys)-1]
+		if slot, ok := incrementKey(last[len(prefix):]); ok {
+			return []*stateTask{{
+				taskID: rand.Uint64(),
+				table:  modules.Storage,
+				origin: append(types.CopyBytes(prefix), slot...),
+			}}, nil
+		}
+	}
+	return nil, nil
+}
+
+func processCodes(tx kv.RwTx, task *stateTask, response *stateResponse) ([]*stateTask, error) {
+	delivered := make(map[string]struct{}, len(response.keys))
+	for i, hash := range response.keys {
+		if !bytes.Equal(crypto.Keccak256(response.values[i]), hash) {
+			return nil, fmt.Errorf("%w: code %x does not match its hash", ErrBadState, hash)
+		}
+		if err := tx.Put(modules.Code, hash, response.values[i]); err != nil {
+			return nil, err
+		}
+		delivered[string(hash)] = struct{}{}
+	}
+	var missing [][]byte
+	for _, hash := range task.hashes {
+		if _, ok := delivered[string(hash)]; !ok {
+			missing = append(missing, hash)
+		}
+	}
+	if len(missing) > 0 {
+		return []*stateTask{{
+			taskID: rand.Uint64(),
+			table:  modules.Code,
+			hashes: missing,
+		}}, nil
+	}
+	return nil, nil
+}
+
+// incrementKey returns the big-endian successor of k, or false if k is all 0xff.
+func incrementKey(k []byte) ([]byte, bool) {
+	next := types.CopyBytes(k)
+	for i := len(next) - 1; i >= 0; i-- {
+		next[i]++
+		if next[i] != 0 {
+			return next, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,304 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package download
+
+import (
+	"context"
+	"encoding/binary"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/amazechain/amc/common"
+	"github.com/amazechain/amc/common/block"
+	"github.com/amazechain/amc/common/message"
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/conf"
+	"github.com/amazechain/amc/internal/network"
+	"github.com/amazechain/amc/modules"
+	"github.com/amazechain/amc/modules/state"
+	"github.com/amazechain/amc/params"
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// testChain is the part of the block chain used by state sync.
+type testChain struct {
+	common.IBlockChain
+	db      kv.RwDB
+	config  *params.ChainConfig
+	current block.IBlock
+	head    types.Hash
+}
+
+func (c *testChain) CurrentBlock() block.IBlock  { return c.current }
+func (c *testChain) DB() kv.RwDB                 { return c.db }
+func (c *testChain) Config() *params.ChainConfig { return c.config }
+func (c *testChain) SnapSyncCommitHead(hash types.Hash) error {
+	c.head = hash
+	return nil
+}
+
+func newTestNode(t *testing.T, ctx context.Context, chain *testChain) (*Downloader, *network.Service, common.PeerMap) {
+	peers := make(common.PeerMap)
+	handshake := func(p common.IPeer, genesisHash types.Hash, currentHeight *uint256.Int) (common.Peer, bool) {
+		return common.Peer{IPeer: p, CurrentHeight: currentHeight, AddTimer: time.Now()}, true
+	}
+	info := func() (types.Hash, *uint256.Int, error) {
+		return types.Hash{}, chain.current.Number64(), nil
+	}
+	s, err := network.NewService(ctx, &conf.NetWorkConfig{ListenersAddress: []string{"/ip4/127.0.0.1/tcp/0"}}, peers, handshake, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := NewDownloader(ctx, chain, s, nil, peers, SnapSync).(*Downloader)
+	_ = s.SetHandler(message.MsgDownloader, d.ConnHandler)
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	return d, s.(*network.Service), peers
+}
+
+// Tests that a node downloads the state at the pivot block from a peer over libp2p,
+// although the peer has moved on since, and verifies it against the state root.
+func TestSnapSyncState(t *testing.T) {
+	modules.AmcInit()
+	kv.ChaindataTablesCfg = modules.AmcTableCfg
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		config   = &params.ChainConfig{ChainID: big.NewInt(1), StateCommitmentBlock: big.NewInt(0)}
+		rules    = config.Rules(1)
+		contract = types.HexToAddress("0x3000000000000000000000000000000000000003")
+		code     = []byte{0x60, 0x00, 0x60, 0x00}
+	)
+	account := func(i int) types.Address {
+		var addr types.Address
+		// More accounts than fit into one response share the first range
+		if i >= 1200 {
+			addr[0] = byte(i)
+		}
+		binary.BigEndian.PutUint32(addr[16:], uint32(i+1))
+		return addr
+	}
+	slot := func(i int) *types.Hash {
+		var h types.Hash
+		binary.BigEndian.PutUint32(h[28:], uint32(i+1))
+		return &h
+	}
+
+	serverDB := memdb.New(t.TempDir())
+	defer serverDB.Close()
+	runBlock := func(tx kv.RwTx, number uint64, fn func(ibs *state.IntraBlockState)) {
+		ibs := state.New(state.NewPlainStateReader(tx))
+		fn(ibs)
+		if err := ibs.FinalizeTx(rules, state.NewNoopWriter()); err != nil {
+			t.Fatal(err)
+		}
+		w := state.NewPlainStateWriter(tx, tx, number)
+		if err := ibs.CommitBlock(rules, w); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.WriteChangeSets(); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.WriteHistory(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var root types.Hash
+	if err := serverDB.Update(ctx, func(tx kv.RwTx) error {
+		runBlock(tx, 1, func(ibs *state.IntraBlockState) {
+			for i := 0; i < 1500; i++ {
+				ibs.AddBalance(account(i), uint256.NewInt(uint64(i+1)))
+			}
+			ibs.CreateAccount(contract, true)
+			ibs.SetCode(contract, code)
+			ibs.SetNonce(contract, 1)
+			for i := 0; i < 1500; i++ {
+				ibs.SetState(contract, slot(i), *uint256.NewInt(uint64(i + 1)))
+			}
+		})
+		c, err := state.GenerateStateCommitment(tx, rules)
+		if err != nil {
+			return err
+		}
+		root = c.Hash()
+		// The peer moves on after the pivot block
+		runBlock(tx, 2, func(ibs *state.IntraBlockState) {
+			for i := 0; i < 100; i++ {
+				ibs.AddBalance(account(i), uint256.NewInt(1))
+				ibs.SetState(contract, slot(i), *uint256.NewInt(0))
+			}
+			for i := 1500; i < 1550; i++ {
+				ibs.AddBalance(account(i), uint256.NewInt(1))
+			}
+		})
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	pivot := block.NewBlock(&block.Header{Number: uint256.NewInt(1), Root: root, Difficulty: uint256.NewInt(0)}, nil)
+	server := &testChain{db: serverDB, config: config, current: block.NewBlock(&block.Header{Number: uint256.NewInt(2), Difficulty: uint256.NewInt(0)}, nil)}
+	clientDB := memdb.New(t.TempDir())
+	defer clientDB.Close()
+	client := &testChain{db: clientDB, config: config, current: block.NewBlock(&block.Header{Number: uint256.NewInt(0), Difficulty: uint256.NewInt(0)}, nil)}
+
+	_, serverService, serverPeers := newTestNode(t, ctx, server)
+	d, clientService, clientPeers := newTestNode(t, ctx, client)
+
+	host := serverService.Host()
+	addr := peer.AddrInfo{ID: host.ID(), Addrs: host.Addrs()}
+	if err := clientService.Host().Connect(ctx, addr); err != nil {
+		t.Fatal(err)
+	}
+	clientService.HandlePeerFound(addr)
+	for deadline := time.Now().Add(10 * time.Second); len(clientPeers) == 0 || len(serverPeers) == 0; time.Sleep(100 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("peers not connected")
+		}
+	}
+	d.peersInfo.update(host.ID(), server.current.Number64(), uint256.NewInt(0))
+
+	d.pivotCh <- pivot
+	errc := make(chan error, 1)
+	go func() { errc <- d.fetchState(1) }()
+	select {
+	case err := <-errc:
+		if err != nil {
+			t.Fatalf("state sync failed: %v", err)
+		}
+	case <-time.After(time.Minute):
+		t.Fatalf("state sync timed out")
+	}
+	if client.head != pivot.Hash() {
+		t.Fatalf("head not committed: have %x, want %x", client.head, pivot.Hash())
+	}
+
+	if err := clientDB.View(ctx, func(tx kv.Tx) error {
+		c, err := state.GenerateStateCommitment(tx, rules)
+		if err != nil {
+			return err
+		}
+		if c.Hash() != root {
+			t.Errorf("state root mismatch: have %x, want %x", c.Hash(), root)
+		}
+		if _, err := state.NewStateCommitment(tx, root, rules); err != nil {
+			t.Errorf("state commitment not committed: %v", err)
+		}
+		r := state.NewPlainStateReader(tx)
+		acc, err := r.ReadAccountData(contract)
+		if err != nil || acc == nil {
+			t.Fatalf("contract missing: %v", err)
+		}
+		if have, err := r.ReadAccountCode(contract, acc.Incarnation, acc.CodeHash); err != nil || string(have) != string(code) {
+			t.Errorf("contract code mismatch: have %x (%v), want %x", have, err, code)
+		}
+		if acc, _ := r.ReadAccountData(account(1520)); acc != nil {
+			t.Errorf("account created after the pivot was synced")
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// testPeer is a peer that is only identified.
+type testPeer struct {
+	common.IPeer
+	id peer.ID
+}
+
+func (p testPeer) ID() peer.ID { return p.id }
+
+// Tests that a bad state response penalizes the peer that sent it and puts the task
+// back instead of failing the sync, and that responses of other peers are ignored.
+func TestProcessBadState(t *testing.T) {
+	modules.AmcInit()
+	kv.ChaindataTablesCfg = modules.AmcTableCfg
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	db := memdb.New(t.TempDir())
+	defer db.Close()
+	config := &params.ChainConfig{ChainID: big.NewInt(1), StateCommitmentBlock: big.NewInt(0)}
+	chain := &testChain{db: db, config: config, current: block.NewBlock(&block.Header{Number: uint256.NewInt(0), Difficulty: uint256.NewInt(0)}, nil)}
+
+	good, bad := peer.ID("good"), peer.ID("bad")
+	peers := common.PeerMap{
+		good: {IPeer: testPeer{id: good}, CurrentHeight: uint256.NewInt(10)},
+		bad:  {IPeer: testPeer{id: bad}, CurrentHeight: uint256.NewInt(10)},
+	}
+	d := NewDownloader(ctx, chain, nil, nil, peers, SnapSync).(*Downloader)
+	for id := range peers {
+		d.peersInfo.update(id, uint256.NewInt(10), uint256.NewInt(0))
+	}
+
+	limit := make([]byte, types.AddressLength)
+	limit[0] = 0x10
+	task := &stateTask{taskID: 1, table: modules.Account, origin: make([]byte, types.AddressLength), limit: limit, peer: bad}
+	d.stateProcessingTasks[task.taskID] = task
+
+	outside := make([]byte, types.AddressLength)
+	outside[0] = 0x20
+	value := make([]byte, 1)
+
+	// A response of another peer than the one asked is ignored
+	if err := d.processState(&stateResponse{taskID: 1, peer: good, ok: true, keys: [][]byte{outside}, values: [][]byte{value}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := d.stateProcessingTasks[1]; !ok || len(d.stateTasks) != 0 {
+		t.Fatalf("response of another peer was processed")
+	}
+
+	// An account outside of the range is bad state
+	if err := d.processState(&stateResponse{taskID: 1, peer: bad, ok: true, keys: [][]byte{outside}, values: [][]byte{value}}); err != nil {
+		t.Fatalf("bad response failed the sync: %v", err)
+	}
+	if len(d.stateTasks) != 1 || d.stateTasks[0] != task || len(d.stateProcessingTasks) != 0 {
+		t.Fatalf("task not put back: %d tasks, %d processing", len(d.stateTasks), len(d.stateProcessingTasks))
+	}
+	if _, ok := d.stateBadPeers[bad]; !ok {
+		t.Fatalf("bad peer not penalized")
+	}
+	if set := d.statePeers(10); len(set) != 1 || set[0].ID() != good {
+		t.Fatalf("penalized peer still asked: %v", set)
+	}
+	if err := db.View(ctx, func(tx kv.Tx) error {
+		if has, err := tx.Has(modules.Account, outside); err != nil || has {
+			t.Errorf("bad state written: %v", err)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A response promising more without keys would lose the range
+	d.stateTasks = d.stateTasks[:0]
+	task.peer = good
+	d.stateProcessingTasks[task.taskID] = task
+	if err := d.processState(&stateResponse{taskID: 1, peer: good, ok: true, more: true}); err != nil {
+		t.Fatal(err)
+	}
+	if len(d.stateTasks) != 1 || d.stateTasks[0] != task {
+		t.Fatalf("empty response dropped the range")
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package internal
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/amazechain/amc/common"
+	block2 "github.com/amazechain/amc/common/block"
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/internal/consensus"
+	"github.com/amazechain/amc/log"
+	event "github.com/amazechain/amc/modules/event/v2"
+	"github.com/amazechain/amc/modules/rawdb"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/rcrowley/go-metrics"
+)
+
+var (
+	safeBlockGauge      = metrics.GetOrRegisterGauge("chain/head/safe", nil)
+	finalizedBlockGauge = metrics.GetOrRegisterGauge("chain/head/finalized", nil)
+)
+
+// CurrentSafeBlock returns the latest block a majority of the signers built on,
+// or nil if the engine tracks no finality.
+func (bc *BlockChain) CurrentSafeBlock() block2.IHeader {
+	if h := bc.safeBlock.Load(); h != nil {
+		return h
+	}
+	return nil
+}
+
+// CurrentFinalizedBlock returns the latest block the engine considers final, or
+// nil if the engine tracks no finality. It is only reported over RPC, reorgs may
+// still drop it.
+func (bc *BlockChain) CurrentFinalizedBlock() block2.IHeader {
+	if h := bc.finalizedBlock.Load(); h != nil {
+		return h
+	}
+	return nil
+}
+
+// loadFinality restores the safe and finalized blocks stored in the database.
+func (bc *BlockChain) loadFinality() error {
+	return bc.ChainDB.View(bc.ctx, func(tx kv.Tx) error {
+		for _, m := range []struct {
+			read  func(kv.Getter) (types.Hash, error)
+			block *atomic.Pointer[block2.Header]
+			gauge metrics.Gauge
+		}{
+			{rawdb.ReadSafeBlockHash, &bc.safeBlock, safeBlockGauge},
+			{rawdb.ReadFinalizedBlockHash, &bc.finalizedBlock, finalizedBlockGauge},
+		} {
+			hash, err := m.read(tx)
+			if nil != err {
+				return err
+			}
+			if hash == (types.Hash{}) {
+				continue
+			}
+			header, err := rawdb.ReadHeaderByHash(tx, hash)
+			if nil != err {
+				return err
+			}
+			if header != nil {
+				m.block.Store(header)
+				m.gauge.Update(int64(header.Number.Uint64()))
+			}
+		}
+		return nil
+	})
+}
+
+// notifyFinality wakes the finality tracker up after a new head was written.
+func (bc *BlockChain) notifyFinality() {
+	select {
+	case bc.finalityCh <- struct{}{}:
+	default:
+	}
+}
+
+// finalityLoop moves the safe and finalized blocks along with the head.
+func (bc *BlockChain) finalityLoop() {
+	defer bc.wg.Done()
+
+	for {
+		select {
+		case <-bc.ctx.Done():
+			return
+		case <-bc.finalityCh:
+		}
+		if err := bc.updateFinality(); nil != err && bc.ctx.Err() == nil {
+			log.Warn("Failed to update finality", "err", err)
+		}
+	}
+}
+
+// updateFinality asks the engine for the safe and finalized ancestors of the
+// head and stores them. Neither moves backwards while it stays canonical.
+func (bc *BlockChain) updateFinality() error {
+	engine, ok := bc.engine.(consensus.FinalityReader)
+	if !ok {
+		return nil
+	}
+	head := bc.CurrentBlock().Header()
+	safe, finalized, err := engine.Finality(bc, head)
+	if nil != err {
+		return err
+	}
+
+	var safeHeader, finalizedHeader *block2.Header
+	if err := bc.ChainDB.Update(bc.ctx, func(tx kv.RwTx) error {
+		if finalizedHeader, err = bc.advanceFinality(tx, bc.CurrentFinalizedBlock(), finalized); nil != err {
+			return err
+		}
+		if finalized = finalizedHeader.Number.Uint64(); safe < finalized {
+			safe = finalized
+		}
+		if safeHeader, err = bc.advanceFinality(tx, bc.CurrentSafeBlock(), safe); nil != err {
+			return err
+		}
+		if err := rawdb.WriteSafeBlockHash(tx, safeHeader.Hash()); nil != err {
+			return err
+		}
+		return rawdb.WriteFinalizedBlockHash(tx, finalizedHeader.Hash())
+	}); nil != err {
+		return err
+	}
+
+	bc.safeBlock.Store(safeHeader)
+	safeBlockGauge.Update(int64(safeHeader.Number.Uint64()))
+	if old := bc.finalizedBlock.Swap(finalizedHeader); old == nil || old.Hash() != finalizedHeader.Hash() {
+		finalizedBlockGauge.Update(int64(finalized))
+		log.Debug("Finalized block", "number", finalized, "hash", finalizedHeader.Hash())
+		event.GlobalEvent.Send(&common.FinalizedHeadEvent{Header: finalizedHeader})
+	}
+	return nil
+}
+
+// advanceFinality returns the canonical header at number, or current if it is
+// later and still canonical.
+func (bc *BlockChain) advanceFinality(tx kv.Tx, current block2.IHeader, number uint64) (*block2.Header, error) {
+	if current != nil && current.Number64().Uint64() > number {
+		if hash, err := rawdb.ReadCanonicalHash(tx, current.Number64().Uint64()); nil == err && hash == current.Hash() {
+			return current.(*block2.Header), nil
+		}
+	}
+	header := rawdb.ReadHeaderByNumber(tx, number)
+	if header == nil {
+		return nil, fmt.Errorf("canonical header %d not found", number)
+	}
+	return header, nil
+}
+
+// rewindFinality moves the stored safe and finalized blocks back to the new
+// head if they are above it. loadFinality picks them up once tx is committed.
+func rewindFinality(tx kv.RwTx, head *block2.Block) error {
+	number := head.Number64().Uint64()
+	for _, m := range []struct {
+		read  func(kv.Getter) (types.Hash, error)
+		write func(kv.Putter, types.Hash) error
+	}{
+		{rawdb.ReadSafeBlockHash, rawdb.WriteSafeBlockHash},
+		{rawdb.ReadFinalizedBlockHash, rawdb.WriteFinalizedBlockHash},
+	} {
+		hash, err := m.read(tx)
+		if nil != err {
+			return err
+		}
+		if n := rawdb.ReadHeaderNumber(tx, hash); n == nil || *n <= number {
+			continue
+		}
+		if err := m.write(tx, head.Hash()); nil != err {
+			return err
+		}
+	}
+	return nil
+}
@@ -131,7 +131,15 @@ func (g *GenesisBlock) ToBlock() (*block2.Block, *state.IntraBlockState, error)
 		if err := statedb.FinalizeTx(g.GenesisBlockConfig.Config.Rules(0), w); err != nil {
 			panic(err)
 		}
-		root = statedb.GenerateRootHash()
+		if g.GenesisBlockConfig.Config.IsStateCommitment(0) {
+			commitment, err := state.GenerateStateCommitment(tx, g.GenesisBlockConfig.Config.Rules(0))
+			if err != nil {
+				panic(err)
+			}
+			root = commitment.Hash()
+		} else {
+			root = statedb.GenerateRootHash()
+		}
 	}()
 	wg.Wait()
 
@@ -183,6 +191,10 @@ func (g *GenesisBlock) ToBlock() (*block2.Block, *state.IntraBlockState, error)
 		BaseFee:     g.GenesisBlockConfig.BaseFee,
 	}
 	head.Extra = ExtraData
+	if g.GenesisBlockConfig.Config.IsVerifierCommitment(0) {
+		verifierHash := block2.CalcVerifierHash(nil, nil)
+		head.VerifierHash = &verifierHash
+	}
 
 	if g.GenesisBlockConfig.GasLimit == 0 {
 		head.GasLimit = params.GenesisGasLimit
@@ -238,6 +250,15 @@ func (g *GenesisBlock) WriteGenesisState(tx kv.RwTx) (*block2.Block, *state.Intr
 	if err := blockWriter.WriteHistory(); err != nil {
 		return nil, statedb, fmt.Errorf("cannot write history: %w", err)
 	}
+	if g.GenesisBlockConfig.Config.IsStateCommitment(0) {
+		commitment, err := state.GenerateStateCommitment(tx, g.GenesisBlockConfig.Config.Rules(0))
+		if err != nil {
+			return nil, statedb, err
+		}
+		if _, err := commitment.Commit(tx); err != nil {
+			return nil, statedb, fmt.Errorf("cannot write state trie: %w", err)
+		}
+	}
 
 	return block, statedb, nil
 }
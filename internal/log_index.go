@@ -0,0 +1,129 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package internal
+
+import (
+	"time"
+
+	"github.com/amazechain/amc/log"
+	"github.com/amazechain/amc/modules/rawdb"
+	"github.com/ledgerwatch/erigon-lib/kv"
+)
+
+const (
+	// logIndexBatch is the number of blocks indexed per write transaction while backfilling.
+	logIndexBatch = 1000
+
+	// logIndexRetry is the delay before backfilling is retried after a failed batch.
+	logIndexRetry = 10 * time.Second
+)
+
+// indexBlockLogs adds the logs of a block that just became canonical to the log
+// index. Blocks ahead of the index progress are left to logIndexLoop.
+func (bc *BlockChain) indexBlockLogs(tx kv.RwTx, number uint64) error {
+	progress, err := rawdb.ReadLogIndexProgress(tx)
+	if nil != err {
+		return err
+	}
+	if number > progress+1 {
+		return nil
+	}
+	logs, err := rawdb.ReadBlockLogs(tx, number)
+	if nil != err {
+		return err
+	}
+	if err := rawdb.WriteLogIndex(tx, number, logs); nil != err {
+		return err
+	}
+	return rawdb.WriteLogIndexProgress(tx, number)
+}
+
+// unwindLogIndex removes the blocks from the given number onwards from the log index.
+func unwindLogIndex(tx kv.RwTx, from uint64) error {
+	progress, err := rawdb.ReadLogIndexProgress(tx)
+	if nil != err {
+		return err
+	}
+	if err := rawdb.UnwindLogIndex(tx, from); nil != err {
+		return err
+	}
+	if from > 0 && progress >= from {
+		return rawdb.WriteLogIndexProgress(tx, from-1)
+	}
+	return nil
+}
+
+// logIndexLoop backfills the log index for blocks written before it existed. A
+// failed batch is retried until the index has caught up with the head.
+func (bc *BlockChain) logIndexLoop() {
+	defer bc.wg.Done()
+
+	logEvery := time.NewTicker(30 * time.Second)
+	defer logEvery.Stop()
+
+	for {
+		select {
+		case <-bc.ctx.Done():
+			return
+		default:
+		}
+
+		done, progress, err := bc.indexLogsBatch()
+		if nil != err {
+			log.Error("Failed to index logs", "number", progress, "err", err)
+			select {
+			case <-bc.ctx.Done():
+				return
+			case <-time.After(logIndexRetry):
+			}
+			continue
+		}
+		if done {
+			log.Debug("Log index is up to date", "number", progress)
+			return
+		}
+
+		select {
+		case <-logEvery.C:
+			log.Info("Indexing logs", "number", progress, "head", bc.CurrentBlock().Number64().Uint64())
+		default:
+		}
+	}
+}
+
+// indexLogsBatch indexes up to logIndexBatch canonical blocks after the current
+// index progress. It holds the chain lock so that it never races block insertion.
+func (bc *BlockChain) indexLogsBatch() (done bool, progress uint64, err error) {
+	bc.lock.Lock()
+	defer bc.lock.Unlock()
+
+	err = bc.ChainDB.Update(bc.ctx, func(tx kv.RwTx) error {
+		if progress, err = rawdb.ReadLogIndexProgress(tx); nil != err {
+			return err
+		}
+		head := bc.CurrentBlock().Number64().Uint64()
+		for i := 0; i < logIndexBatch && progress < head; i++ {
+			if err := bc.indexBlockLogs(tx, progress+1); nil != err {
+				return err
+			}
+			progress++
+		}
+		done = progress >= head
+		return nil
+	})
+	return done, progress, err
+}
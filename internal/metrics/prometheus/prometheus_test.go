@@ -0,0 +1,56 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package prometheus
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+func TestHandler(t *testing.T) {
+	reg := metrics.NewRegistry()
+
+	metrics.GetOrRegisterCounter("test/counter", reg).Inc(12345)
+	metrics.GetOrRegisterGauge("test/gauge", reg).Update(23456)
+	metrics.GetOrRegisterGaugeFloat64("test/gauge_float64", reg).Update(34567.89)
+	metrics.GetOrRegisterTimer("test/timer", reg).Update(20 * time.Millisecond)
+
+	rec := httptest.NewRecorder()
+	Handler(reg).ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+
+	for _, want := range []string{
+		"# TYPE test_counter gauge\ntest_counter 12345\n",
+		"# TYPE test_gauge gauge\ntest_gauge 23456\n",
+		"# TYPE test_gauge_float64 gauge\ntest_gauge_float64 34567.89\n",
+		"# TYPE test_timer_count counter\ntest_timer_count 1\n",
+		"# TYPE test_timer summary\n",
+		"test_timer {quantile=\"0.5\"} 2e+07\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("missing %q in output:\n%s", want, body)
+		}
+	}
+	// Metrics must be listed in sorted order
+	if strings.Index(body, "test_counter") > strings.Index(body, "test_timer") {
+		t.Errorf("metrics not sorted:\n%s", body)
+	}
+}
@@ -116,6 +116,11 @@ const (
 	commitInterruptResubmit
 )
 
+// blockStatsRecorder is implemented by chains that keep per-stage block timings.
+type blockStatsRecorder interface {
+	RecordBlockStats(stats *internal.BlockStats)
+}
+
 type worker struct {
 	minerConf conf.MinerConfig
 	engine    consensus.Engine
@@ -403,10 +408,22 @@ func (w *worker) commitWork(interrupt *int32, noempty bool, timestamp int64) err
 	stateReader := state.NewPlainStateReader(tx)
 	stateWriter := state.NewPlainStateWriter(tx, tx, current.header.Number.Uint64())
 	ibs := state.New(stateReader)
+	commitment, err := internal.OpenWitnessStateCommitment(tx, w.chainConfig, current.header)
+	if err != nil {
+		log.Error("cannot open state commitment", "err", err)
+		return err
+	}
+	ibs.SetStateCommitment(commitment)
 	// generate state for mobile verify
 	ibs.BeginWriteSnapshot()
 	ibs.BeginWriteCodes()
 	headers := make([]*block.Header, 0)
+	if commitment != nil {
+		// verifiers check the root of the witness against the parent
+		if parent := rawdb.ReadHeader(tx, current.header.ParentHash, current.header.Number.Uint64()-1); parent != nil {
+			headers = append(headers, parent)
+		}
+	}
 	//stateWriter := state.NewPlainStateWriter(tx, tx, current.header.Number.Uint64())
 	getHeader := func(hash types.Hash, number uint64) *block.Header {
 		h := rawdb.ReadHeader(tx, hash, number)
@@ -416,20 +433,25 @@ func (w *worker) commitWork(interrupt *int32, noempty bool, timestamp int64) err
 		return h
 	}
 
+	stats := &internal.BlockStats{Number: current.header.Number.Uint64(), Mined: true}
+	execStart := time.Now()
 	if err := w.fillTransactions(interrupt, current, ibs, getHeader); err != nil {
 		log.Errorf("w.fillTransactions failed, error %v\n", err)
 		return err
 	}
+	stats.Execution = time.Since(execStart)
 
 	var rewards []*block.Reward
 	if w.chainConfig.IsBeijing(current.header.Number.Uint64()) {
+		rewardsStart := time.Now()
 		rewards, err = w.engine.Rewards(tx, block.CopyHeader(current.header), ibs, false)
 		if err != nil {
 			return err
 		}
+		stats.Rewards = time.Since(rewardsStart)
 	}
 
-	if err = w.commit(tx, current, stateWriter, ibs, start, rewards, headers); nil != err {
+	if err = w.commit(tx, current, stateWriter, ibs, start, rewards, headers, stats); nil != err {
 		log.Errorf("w.commit failed, error %v\n", err)
 		return err
 	}
@@ -653,17 +675,25 @@ func (w *worker) makeEnv(parent *block.Header, header *block.Header, coinbase ty
 	return env
 }
 
-func (w *worker) commit(tx kv.RwTx, env *environment, writer state.WriterWithChangeSets, ibs *state.IntraBlockState, start time.Time, rewards []*block.Reward, needHeaders []*block.Header) error {
+func (w *worker) commit(tx kv.RwTx, env *environment, writer state.WriterWithChangeSets, ibs *state.IntraBlockState, start time.Time, rewards []*block.Reward, needHeaders []*block.Header, stats *internal.BlockStats) error {
 	if w.isRunning() {
 
-		iblock, _, _, err := internal.FinalizeBlockExecution(tx, w.engine, env.header, env.txs, writer, w.chainConfig, ibs, env.receipts, nil, true, w.chainConfig.IsBeijing(env.header.Number.Uint64()))
+		iblock, _, _, err := internal.FinalizeBlockExecution(tx, w.engine, env.header, env.txs, writer, w.chainConfig, ibs, env.receipts, nil, true, w.chainConfig.IsBeijing(env.header.Number.Uint64()), stats)
 		if nil != err {
 			return err
 		}
 
+		commitStart := time.Now()
 		if err := tx.Commit(); nil != err {
 			return err
 		}
+		stats.Commit = time.Since(commitStart)
+		stats.Txs = env.tcount
+		stats.GasUsed = iblock.GasUsed()
+		stats.Total = time.Since(start)
+		if recorder, ok := w.chain.(blockStatsRecorder); ok {
+			recorder.RecordBlockStats(stats)
+		}
 		//noop := state.NewNoopWriter()
 		//if err := ibs.CommitBlock(w.chainConfig.Rules(env.header.Number.Uint64()), noop); err != nil {
 		//	return fmt.Errorf("committing block %d failed: %w", env.header.Number.Uint64(), err)
@@ -696,7 +726,12 @@ func (w *worker) commit(tx kv.RwTx, env *environment, writer state.WriterWithCha
 			}
 			sort.Sort(hs)
 
-			event.GlobalFeed.Send(common.MinedEntireEvent{Entire: state.EntireCode{Codes: hs, Headers: needHeaders, Entire: entri, Rewards: rewards, CoinBase: env.coinbase}})
+			entire := state.EntireCode{Codes: hs, Headers: needHeaders, Entire: entri, Rewards: rewards, CoinBase: env.coinbase}
+			if commitment := ibs.StateCommitment(); commitment != nil {
+				entire.StateRoot = commitment.Root()
+				entire.TrieNodes = commitment.Witness()
+			}
+			event.GlobalFeed.Send(common.MinedEntireEvent{Entire: entire})
 		}
 
 		//
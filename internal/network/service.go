@@ -19,6 +19,7 @@ package network
 import (
 	"context"
 	"crypto/rand"
+	"fmt"
 	"github.com/amazechain/amc/api/protocol/msg_proto"
 	"github.com/amazechain/amc/common"
 	"github.com/amazechain/amc/common/message"
@@ -50,6 +51,7 @@ const (
 var (
 	egressTrafficMeter  = metrics.GetOrRegisterMeter("p2p/egress", nil)
 	ingressTrafficMeter = metrics.GetOrRegisterMeter("p2p/ingress", nil)
+	peerCountGauge      = metrics.GetOrRegisterGauge("p2p/peers", nil)
 )
 
 type metricsLog struct{}
@@ -188,6 +190,13 @@ func (s *Service) PeerCount() int {
 	return len(s.nodes)
 }
 
+// Peers returns a snapshot of the handshaked peers.
+func (s *Service) Peers() common.PeerSet {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.nodes.ToSlice()
+}
+
 // nodeManager node insert
 func (s *Service) nodeManager(peerCh chan peer.AddrInfo) {
 
@@ -298,6 +307,7 @@ func (s *Service) addNode(node common.Peer) {
 	defer s.lock.Unlock()
 	if _, ok := s.nodes[node.ID()]; !ok {
 		s.nodes[node.ID()] = node
+		peerCountGauge.Update(int64(len(s.nodes)))
 	}
 }
 
@@ -306,6 +316,7 @@ func (s *Service) deleteNode(id peer.ID) {
 	defer s.lock.Unlock()
 	if _, ok := s.nodes[id]; ok {
 		delete(s.nodes, id)
+		peerCountGauge.Update(int64(len(s.nodes)))
 	}
 }
 
@@ -412,6 +423,32 @@ func (s *Service) SetHandler(mt message.MessageType, handler common.ConnHandler)
 	return nil
 }
 
-func (s *Service) ClosePeer(id peer.ID) error {
+// AddPeer dials the given peer and queues it for the protocol handshake.
+func (s *Service) AddPeer(info peer.AddrInfo) error {
+	if info.ID == s.host.ID() {
+		return fmt.Errorf("cannot add self as peer")
+	}
+	if s.checkNode(info.ID) {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
+	defer cancel()
+	if err := s.host.Connect(ctx, info); err != nil {
+		return err
+	}
+	s.HandlePeerFound(info)
 	return nil
 }
+
+// ClosePeer disconnects the given peer and drops it from the peer set.
+func (s *Service) ClosePeer(id peer.ID) error {
+	s.lock.RLock()
+	p, ok := s.nodes[id]
+	s.lock.RUnlock()
+	if !ok {
+		return notFoundPeer
+	}
+	_ = p.Close()
+	s.removeCh <- id
+	return s.host.Network().ClosePeer(id)
+}
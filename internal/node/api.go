@@ -0,0 +1,234 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/amazechain/amc/common/hexutil"
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/internal/download"
+	"github.com/amazechain/amc/internal/network"
+	"github.com/amazechain/amc/modules/rpc/jsonrpc"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// apis returns the collection of built-in RPC APIs of the node itself.
+func (n *Node) apis() []jsonrpc.API {
+	return []jsonrpc.API{
+		{
+			Namespace: "admin",
+			Service:   &adminAPI{n},
+		},
+	}
+}
+
+// adminAPI is the collection of administrative API methods exposed over
+// IPC and the authenticated HTTP endpoint.
+type adminAPI struct {
+	node *Node
+}
+
+// NodeInfo is the identity and chain position of the local node.
+type NodeInfo struct {
+	ID          string         `json:"id"`
+	ListenAddrs []string       `json:"listenAddrs"`
+	Protocols   []string       `json:"protocols"`
+	Genesis     types.Hash     `json:"genesis"`
+	Head        types.Hash     `json:"head"`
+	HeadNumber  hexutil.Uint64 `json:"headNumber"`
+}
+
+// PeerInfo is the connection and sync state of a connected peer.
+type PeerInfo struct {
+	ID         string         `json:"id"`
+	Addrs      []string       `json:"addrs"`
+	Inbound    bool           `json:"inbound"`
+	Protocols  []string       `json:"protocols"`
+	Latency    string         `json:"latency"`
+	Height     *hexutil.Big   `json:"height"`
+	Difficulty *hexutil.Big   `json:"difficulty"`
+	Connected  hexutil.Uint64 `json:"connected"` // Unix time the handshake completed
+}
+
+// NodeInfo retrieves the libp2p identity, listen addresses and chain head of
+// the node.
+func (api *adminAPI) NodeInfo() (*NodeInfo, error) {
+	h := api.node.service.Host()
+	if h == nil {
+		return nil, errors.New("p2p service not started")
+	}
+	info := &NodeInfo{
+		ID:        h.ID().String(),
+		Protocols: []string{network.AppProtocol},
+		Genesis:   api.node.blocks.GenesisBlock().Hash(),
+	}
+	for _, addr := range h.Addrs() {
+		info.ListenAddrs = append(info.ListenAddrs, fmt.Sprintf("%s/p2p/%s", addr, h.ID()))
+	}
+	head := api.node.blocks.CurrentBlock()
+	info.Head = head.Hash()
+	info.HeadNumber = hexutil.Uint64(head.Number64().Uint64())
+	return info, nil
+}
+
+// Peers retrieves all the information we know about each connected peer.
+func (api *adminAPI) Peers() ([]*PeerInfo, error) {
+	h := api.node.service.Host()
+	if h == nil {
+		return nil, errors.New("p2p service not started")
+	}
+	downloader, _ := api.node.downloader.(*download.Downloader)
+
+	peers := api.node.service.Peers()
+	infos := make([]*PeerInfo, 0, len(peers))
+	for _, p := range peers {
+		id := p.ID()
+		info := &PeerInfo{
+			ID:        id.String(),
+			Latency:   h.Peerstore().LatencyEWMA(id).String(),
+			Connected: hexutil.Uint64(p.AddTimer.Unix()),
+		}
+		for _, conn := range h.Network().ConnsToPeer(id) {
+			info.Addrs = append(info.Addrs, conn.RemoteMultiaddr().String())
+			info.Inbound = info.Inbound || conn.Stat().Direction.String() == "Inbound"
+		}
+		if protocols, err := h.Peerstore().GetProtocols(id); err == nil {
+			for _, proto := range protocols {
+				info.Protocols = append(info.Protocols, string(proto))
+			}
+		}
+		if p.CurrentHeight != nil {
+			info.Height = (*hexutil.Big)(p.CurrentHeight.ToBig())
+		}
+		if downloader != nil {
+			if number, difficulty, ok := downloader.PeerInfo(id); ok {
+				if number != nil {
+					info.Height = (*hexutil.Big)(number.ToBig())
+				}
+				if difficulty != nil {
+					info.Difficulty = (*hexutil.Big)(difficulty.ToBig())
+				}
+			}
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// AddPeer connects to the peer at the given multiaddr, which must include the
+// /p2p/<id> component, and runs the protocol handshake with it.
+func (api *adminAPI) AddPeer(url string) (bool, error) {
+	info, err := peer.AddrInfoFromString(url)
+	if err != nil {
+		return false, fmt.Errorf("invalid peer address: %v", err)
+	}
+	if err := api.node.service.AddPeer(*info); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RemovePeer disconnects the peer with the given libp2p ID or multiaddr.
+func (api *adminAPI) RemovePeer(url string) (bool, error) {
+	var id peer.ID
+	if strings.HasPrefix(url, "/") {
+		info, err := peer.AddrInfoFromString(url)
+		if err != nil {
+			return false, fmt.Errorf("invalid peer address: %v", err)
+		}
+		id = info.ID
+	} else {
+		decoded, err := peer.Decode(url)
+		if err != nil {
+			return false, fmt.Errorf("invalid peer id: %v", err)
+		}
+		id = decoded
+	}
+	if err := api.node.service.ClosePeer(id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// StartHTTP starts the public HTTP RPC API server.
+func (api *adminAPI) StartHTTP(host *string, port *int, cors *string, apis *string, vhosts *string) (bool, error) {
+	api.node.lock.Lock()
+	defer api.node.lock.Unlock()
+
+	// Determine host and port.
+	if host == nil {
+		h := "127.0.0.1"
+		if api.node.config.NodeCfg.HTTPHost != "" {
+			h = api.node.config.NodeCfg.HTTPHost
+		}
+		host = &h
+	}
+	if port == nil {
+		p, _ := strconv.Atoi(api.node.config.NodeCfg.HTTPPort)
+		port = &p
+	}
+
+	// Determine config.
+	config := httpConfig{
+		CorsAllowedOrigins: []string{},
+		Vhosts:             []string{"*"},
+		Modules:            api.node.httpModules(),
+	}
+	if cors != nil {
+		config.CorsAllowedOrigins = nil
+		for _, origin := range strings.Split(*cors, ",") {
+			config.CorsAllowedOrigins = append(config.CorsAllowedOrigins, strings.TrimSpace(origin))
+		}
+	}
+	if vhosts != nil {
+		config.Vhosts = nil
+		for _, vhost := range strings.Split(*vhosts, ",") {
+			config.Vhosts = append(config.Vhosts, strings.TrimSpace(vhost))
+		}
+	}
+	if apis != nil {
+		config.Modules = nil
+		for _, m := range strings.Split(*apis, ",") {
+			m = strings.TrimSpace(m)
+			if m == "personal" && !api.node.config.NodeCfg.InsecureUnlockAllowed {
+				return false, errors.New("personal namespace over HTTP requires --account.allow.insecure.unlock")
+			}
+			config.Modules = append(config.Modules, m)
+		}
+	}
+
+	if err := api.node.http.setListenAddr(*host, *port); err != nil {
+		return false, err
+	}
+	if err := api.node.http.enableRPC(api.node.rpcAPIs, config); err != nil {
+		return false, err
+	}
+	if err := api.node.http.start(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// StopHTTP shuts down the public HTTP RPC API server.
+func (api *adminAPI) StopHTTP() bool {
+	api.node.http.stop()
+	return true
+}
@@ -0,0 +1,94 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/amazechain/amc/common/block"
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/conf"
+	"github.com/amazechain/amc/modules/rawdb"
+	"github.com/ledgerwatch/erigon-lib/kv"
+)
+
+// InitGenesis writes the genesis block of a custom network, such as a fork of
+// the state of another chain, into an empty database. The specification is kept
+// so later starts of the node use it instead of the built-in genesis.
+func InitGenesis(ctx context.Context, cfg *conf.Config, genesis *conf.GenesisBlockConfig) (*block.Block, error) {
+	db, err := OpenDatabase(cfg, nil, kv.ChainDB.String())
+	if nil != err {
+		return nil, err
+	}
+	defer db.Close()
+
+	var genesisBlock *block.Block
+	if err := db.Update(ctx, func(tx kv.RwTx) error {
+		stored, err := rawdb.ReadCanonicalHash(tx, 0)
+		if nil != err {
+			return err
+		}
+		if stored != (types.Hash{}) {
+			return fmt.Errorf("database already contains the genesis block %v", stored)
+		}
+		if genesisBlock, err = WriteGenesisBlock(tx, genesis); nil != err {
+			return err
+		}
+		spec := *genesis
+		spec.Alloc = nil
+		data, err := json.Marshal(&spec)
+		if nil != err {
+			return err
+		}
+		return rawdb.WriteGenesisSpec(tx, data)
+	}); nil != err {
+		return nil, err
+	}
+	return genesisBlock, nil
+}
+
+// loadGenesis returns the genesis block of a database initialised with a custom
+// genesis and makes its specification the one of the node. The etherbase stays
+// a setting of the node.
+func loadGenesis(tx kv.Tx, cfg *conf.Config) (*block.Block, error) {
+	data, err := rawdb.ReadGenesisSpec(tx)
+	if nil != err || len(data) == 0 {
+		return nil, err
+	}
+	spec := new(conf.GenesisBlockConfig)
+	if err := json.Unmarshal(data, spec); nil != err {
+		return nil, fmt.Errorf("invalid genesis specification: %w", err)
+	}
+	if spec.Engine != nil && cfg.GenesisBlockCfg != nil && cfg.GenesisBlockCfg.Engine != nil {
+		spec.Engine.Etherbase = cfg.GenesisBlockCfg.Engine.Etherbase
+	}
+	hash, err := rawdb.ReadCanonicalHash(tx, 0)
+	if nil != err {
+		return nil, err
+	}
+	genesisBlock, err := rawdb.ReadBlockByHash(tx, hash)
+	if nil != err {
+		return nil, err
+	}
+	if genesisBlock == nil {
+		return nil, fmt.Errorf("genesis block %v not found", hash)
+	}
+	cfg.GenesisBlockCfg = spec
+	return genesisBlock, nil
+}
@@ -17,14 +17,47 @@
 package node
 
 import (
+	"crypto/rand"
+	"fmt"
+	"github.com/amazechain/amc/common/hexutil"
+	"github.com/amazechain/amc/log"
 	"github.com/golang-jwt/jwt/v4"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 )
 
 const jwtExpiryTimeout = 60 * time.Second
 
+// obtainJWTSecret loads the hex encoded 32 byte JWT secret from the given file,
+// generating and persisting a new random secret if the file does not exist.
+func obtainJWTSecret(fileName string) ([]byte, error) {
+	if data, err := os.ReadFile(fileName); err == nil {
+		jwtSecret, err := hexutil.Decode(strings.TrimSpace(string(data)))
+		if err != nil || len(jwtSecret) != 32 {
+			return nil, fmt.Errorf("invalid JWT secret in %s", fileName)
+		}
+		log.Info("Loaded JWT secret file", "path", fileName)
+		return jwtSecret, nil
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	jwtSecret := make([]byte, 32)
+	if _, err := rand.Read(jwtSecret); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(fileName), 0700); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(fileName, []byte(hexutil.Encode(jwtSecret)), 0600); err != nil {
+		return nil, err
+	}
+	log.Info("Generated JWT secret", "path", fileName)
+	return jwtSecret, nil
+}
+
 type jwtHandler struct {
 	keyFunc func(token *jwt.Token) (interface{}, error)
 	next    http.Handler
@@ -20,7 +20,6 @@ import (
 	"context"
 	"crypto/rand"
 	"fmt"
-	"github.com/amazechain/amc/contracts/deposit"
 	"github.com/amazechain/amc/internal/debug"
 	"github.com/amazechain/amc/internal/tracers"
 	"github.com/golang/protobuf/proto"
@@ -42,9 +41,11 @@ import (
 	"golang.org/x/sync/semaphore"
 
 	"github.com/amazechain/amc/internal/metrics/influxdb"
+	"github.com/amazechain/amc/internal/metrics/prometheus"
 	"github.com/amazechain/amc/log"
 	"github.com/rcrowley/go-metrics"
 
+	"net"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -53,6 +54,7 @@ import (
 	"time"
 
 	"github.com/amazechain/amc/accounts"
+	"github.com/amazechain/amc/accounts/external"
 	"github.com/amazechain/amc/accounts/keystore"
 	"github.com/amazechain/amc/api/protocol/types_pb"
 	"github.com/amazechain/amc/common"
@@ -87,18 +89,17 @@ type Node struct {
 	config *conf.Config
 
 	//engine       consensus.IEngine
-	miner           *miner.Miner
-	pubsubServer    common.IPubSub
-	genesisBlock    block.IBlock
-	service         common.INetwork
-	peers           map[peer.ID]common.Peer
-	blocks          common.IBlockChain
-	engine          consensus.Engine
-	db              kv.RwDB
-	txspool         txs_pool.ITxsPool
-	txsFetcher      *txspool.TxsFetcher
-	nodeKey         crypto.PrivKey
-	depositContract *deposit.Deposit
+	miner        *miner.Miner
+	pubsubServer common.IPubSub
+	genesisBlock block.IBlock
+	service      common.INetwork
+	peers        map[peer.ID]common.Peer
+	blocks       common.IBlockChain
+	engine       consensus.Engine
+	db           kv.RwDB
+	txspool      txs_pool.ITxsPool
+	txsFetcher   *txspool.TxsFetcher
+	nodeKey      crypto.PrivKey
 	//nodeKey      *ecdsa.PrivateKey
 
 	//downloader
@@ -113,6 +114,7 @@ type Node struct {
 	rpcAPIs []jsonrpc.API
 
 	http          *httpServer
+	httpAuth      *httpServer
 	ipc           *ipcServer
 	ws            *httpServer
 	inprocHandler *jsonrpc.Server
@@ -157,6 +159,11 @@ func NewNode(ctx context.Context, cfg *conf.Config) (*Node, error) {
 	}
 	log.Info("new node", "address", types.PrivateToAddress(privateKey))
 
+	prune, err := pruneConfig(cfg)
+	if nil != err {
+		return nil, err
+	}
+
 	//
 	chainKv, err := OpenDatabase(cfg, nil, name)
 	if nil != err {
@@ -164,10 +171,18 @@ func NewNode(ctx context.Context, cfg *conf.Config) (*Node, error) {
 	}
 
 	if err := chainKv.Update(ctx, func(tx kv.RwTx) error {
-		var genesisErr error
-		genesisBlock, genesisErr = WriteGenesisBlock(tx, cfg.GenesisBlockCfg)
-		if nil != genesisErr {
-			return genesisErr
+		custom, err := loadGenesis(tx, cfg)
+		if nil != err {
+			return err
+		}
+		if custom != nil {
+			genesisBlock = custom
+		} else {
+			var genesisErr error
+			genesisBlock, genesisErr = WriteGenesisBlock(tx, cfg.GenesisBlockCfg)
+			if nil != genesisErr {
+				return genesisErr
+			}
 		}
 		if cfg.GenesisBlockCfg.Miners != nil {
 			miners := consensus_pb.PBSigners{}
@@ -207,17 +222,16 @@ func NewNode(ctx context.Context, cfg *conf.Config) (*Node, error) {
 		return nil, err
 	}
 
-	switch cfg.GenesisBlockCfg.Engine.EngineName {
-	case "APoaEngine":
-		engine = apoa.New(cfg.GenesisBlockCfg.Engine, chainKv)
-	case "APosEngine":
-		engine = apos.New(cfg.GenesisBlockCfg.Engine, chainKv, cfg.GenesisBlockCfg.Config)
-	default:
-		return nil, fmt.Errorf("invalid engine name %s", cfg.GenesisBlockCfg.Engine.EngineName)
+	if engine, err = newEngine(cfg, chainKv); nil != err {
+		return nil, err
 	}
 
 	bc, _ := internal.NewBlockChain(ctx, genesisBlock, engine, downloader, chainKv, pubsubServer, cfg.GenesisBlockCfg.Config)
-	pool, _ := txspool.NewTxsPool(ctx, bc)
+	if chain, ok := bc.(*internal.BlockChain); ok {
+		chain.SetSlowBlockThreshold(cfg.NodeCfg.SlowBlockThreshold)
+		chain.SetPruneConfig(prune)
+	}
+	pool, _ := txspool.NewTxsPool(ctx, txPoolConfig(cfg), bc)
 
 	//todo
 	var txs []*transaction.Transaction
@@ -240,7 +254,13 @@ func NewNode(ctx context.Context, cfg *conf.Config) (*Node, error) {
 
 	c, cancel := context.WithCancel(ctx)
 
-	downloader = download.NewDownloader(ctx, bc, s, pubsubServer, peers)
+	var syncMode download.SyncMode
+	if cfg.NodeCfg.SyncMode != "" {
+		if err := syncMode.UnmarshalText([]byte(cfg.NodeCfg.SyncMode)); err != nil {
+			return nil, err
+		}
+	}
+	downloader = download.NewDownloader(ctx, bc, s, pubsubServer, peers, syncMode)
 
 	_ = s.SetHandler(message.MsgDownloader, downloader.ConnHandler)
 	_ = s.SetHandler(message.MsgTransaction, txsFetcher.ConnHandler)
@@ -256,26 +276,26 @@ func NewNode(ctx context.Context, cfg *conf.Config) (*Node, error) {
 	accman := accounts.NewManager(&accounts.Config{InsecureUnlockAllowed: cfg.NodeCfg.InsecureUnlockAllowed})
 
 	node = Node{
-		ctx:             c,
-		cancel:          cancel,
-		config:          cfg,
-		miner:           miner,
-		genesisBlock:    genesisBlock,
-		service:         s,
-		nodeKey:         privateKey,
-		blocks:          bc,
-		db:              chainKv,
-		shutDown:        make(chan struct{}),
-		pubsubServer:    pubsubServer,
-		peers:           peers,
-		downloader:      downloader,
-		txspool:         pool,
-		txsFetcher:      txsFetcher,
-		engine:          engine,
-		depositContract: deposit.NewDeposit(ctx, cfg.GenesisBlockCfg.Engine, bc, chainKv),
+		ctx:          c,
+		cancel:       cancel,
+		config:       cfg,
+		miner:        miner,
+		genesisBlock: genesisBlock,
+		service:      s,
+		nodeKey:      privateKey,
+		blocks:       bc,
+		db:           chainKv,
+		shutDown:     make(chan struct{}),
+		pubsubServer: pubsubServer,
+		peers:        peers,
+		downloader:   downloader,
+		txspool:      pool,
+		txsFetcher:   txsFetcher,
+		engine:       engine,
 
 		inprocHandler: jsonrpc.NewServer(),
 		http:          newHTTPServer(),
+		httpAuth:      newHTTPServer(),
 		ws:            newHTTPServer(),
 		ipc:           newIPCServer(&cfg.NodeCfg),
 		etherbase:     types.HexToAddress(cfg.GenesisBlockCfg.Engine.Etherbase),
@@ -290,6 +310,7 @@ func NewNode(ctx context.Context, cfg *conf.Config) (*Node, error) {
 	// Node doesn't by default populate account manager backends
 	if err = setAccountManagerBackends(&node, &cfg.NodeCfg); err != nil {
 		log.Errorf("Failed to set account manager backends: %v", err)
+		return nil, err
 	}
 
 	gpoParams := cfg.GPO
@@ -350,6 +371,15 @@ func (n *Node) Start() error {
 				return fmt.Errorf("signer missing: %v", err)
 			}
 			pos.Authorize(eb, wallet.SignData)
+
+			// Sign mined blocks as a verifier if the etherbase has a deposit
+			if ks := n.accman.Backends(keystore.KeyStoreType); len(ks) > 0 {
+				if key, err := ks[0].(*keystore.KeyStore).BLSSecretKey(accounts.Account{Address: eb}); err != nil {
+					log.Warn("Verifier key unavailable, mined blocks are not signed locally", "err", err)
+				} else {
+					api.SetVerifier(eb, key)
+				}
+			}
 		}
 
 		n.miner.SetCoinbase(eb)
@@ -365,16 +395,14 @@ func (n *Node) Start() error {
 		return err
 	}
 
-	if n.config.NodeCfg.HTTP {
-
-		n.rpcAPIs = append(n.rpcAPIs, n.engine.APIs(n.blocks)...)
-		n.rpcAPIs = append(n.rpcAPIs, n.api.Apis()...)
-		n.rpcAPIs = append(n.rpcAPIs, tracers.APIs(n.api)...)
-		n.rpcAPIs = append(n.rpcAPIs, debug.APIs()...)
-		if err := n.startRPC(); err != nil {
-			log.Error("failed start jsonrpc service", zap.Error(err))
-			return err
-		}
+	n.rpcAPIs = append(n.rpcAPIs, n.engine.APIs(n.blocks)...)
+	n.rpcAPIs = append(n.rpcAPIs, n.api.Apis()...)
+	n.rpcAPIs = append(n.rpcAPIs, tracers.APIs(n.api)...)
+	n.rpcAPIs = append(n.rpcAPIs, debug.APIs()...)
+	n.rpcAPIs = append(n.rpcAPIs, n.apis()...)
+	if err := n.startRPC(); err != nil {
+		log.Error("failed start jsonrpc service", zap.Error(err))
+		return err
 	}
 
 	n.SetupMetrics(n.config.MetricsCfg)
@@ -387,8 +415,6 @@ func (n *Node) Start() error {
 	go n.txsBroadcastLoop()
 	go n.txsMessageFetcherLoop()
 
-	n.depositContract.Start()
-
 	//rwTx, _ := n.db.BeginRw(n.ctx)
 	//defer rwTx.Rollback()
 	//rawdb.PutDeposit(rwTx, types.HexToAddress("0x7Ac869Ff8b6232f7cfC4370A2df4a81641Cba3d9").Bytes(), []byte("1111"))
@@ -508,6 +534,22 @@ func (n *Node) stopInProc() {
 	n.inprocHandler.Stop()
 }
 
+// defaultHTTPModules are the namespaces exposed over the public HTTP and
+// WebSocket endpoints. The admin namespace is only served over IPC and the
+// authenticated endpoint.
+var defaultHTTPModules = []string{"eth", "web3", "debug", "net", "apoa", "txpool", "apos", "trace"}
+
+// httpModules returns the namespaces served over HTTP and WebSocket. The
+// personal namespace handles passwords and is therefore only exposed beyond
+// IPC when insecure unlocking has been explicitly allowed.
+func (n *Node) httpModules() []string {
+	modules := append([]string{}, defaultHTTPModules...)
+	if n.config.NodeCfg.InsecureUnlockAllowed {
+		modules = append(modules, "personal")
+	}
+	return modules
+}
+
 func (n *Node) startRPC() error {
 	if err := n.startInProc(); err != nil {
 		return err
@@ -517,13 +559,13 @@ func (n *Node) startRPC() error {
 			return err
 		}
 	}
-	if n.config.NodeCfg.HTTPHost != "" {
+	if n.config.NodeCfg.HTTP && n.config.NodeCfg.HTTPHost != "" {
 
 		//todo
 		config := httpConfig{
 			CorsAllowedOrigins: []string{},
 			Vhosts:             []string{"*"},
-			Modules:            []string{"eth", "web3", "debug", "net", "apoa", "txpool", "apos"},
+			Modules:            n.httpModules(),
 			prefix:             "",
 		}
 		port, _ := strconv.Atoi(n.config.NodeCfg.HTTPPort)
@@ -538,6 +580,37 @@ func (n *Node) startRPC() error {
 		}
 	}
 
+	// Configure the authenticated endpoint, which also serves the admin namespace.
+	if n.config.NodeCfg.AuthRPC {
+		secretPath := n.config.NodeCfg.JWTSecret
+		if secretPath == "" {
+			secretPath = "jwtsecret"
+		}
+		if !filepath.IsAbs(secretPath) {
+			secretPath = filepath.Join(n.config.NodeCfg.DataDir, secretPath)
+		}
+		secret, err := obtainJWTSecret(secretPath)
+		if err != nil {
+			return err
+		}
+		config := httpConfig{
+			CorsAllowedOrigins: []string{},
+			Vhosts:             []string{"localhost"},
+			Modules:            append([]string{"admin"}, n.httpModules()...),
+			jwtSecret:          secret,
+		}
+		port, _ := strconv.Atoi(n.config.NodeCfg.AuthPort)
+		if err := n.httpAuth.setListenAddr(n.config.NodeCfg.AuthHost, port); err != nil {
+			return err
+		}
+		if err := n.httpAuth.enableRPC(n.rpcAPIs, config); err != nil {
+			return err
+		}
+		if err := n.httpAuth.start(); err != nil {
+			return err
+		}
+	}
+
 	// Configure WebSocket.
 	if n.config.NodeCfg.WS {
 		port, _ := strconv.Atoi(n.config.NodeCfg.WSPort)
@@ -546,7 +619,7 @@ func (n *Node) startRPC() error {
 		}
 		//todo
 		config := wsConfig{
-			Modules:   []string{"eth", "web3", "debug", "net", "apoa", "txpool", "apos"},
+			Modules:   n.httpModules(),
 			Origins:   []string{"*"},
 			prefix:    "",
 			jwtSecret: []byte{},
@@ -563,6 +636,7 @@ func (n *Node) startRPC() error {
 
 func (n *Node) stopRPC() {
 	n.http.stop()
+	n.httpAuth.stop()
 	n.ws.stop()
 	n.ipc.stop()
 	n.stopInProc()
@@ -619,6 +693,7 @@ func (n *Node) Close() {
 	default:
 		n.cancel()
 		close(n.shutDown)
+		n.txspool.Stop()
 		n.db.Close()
 	}
 }
@@ -675,6 +750,15 @@ func setAccountManagerBackends(stack *Node, conf *conf.NodeConfig) error {
 	// If/when we implement some form of lockfile for USB and keystore wallets,
 	// we can have both, but it's very confusing for the user to see the same
 	// accounts in both externally and locally, plus very racey.
+	if conf.ExternalSigner != "" {
+		log.Info("Using external signer", "url", conf.ExternalSigner)
+		extapi, err := external.NewExternalBackend(conf.ExternalSigner)
+		if err != nil {
+			return fmt.Errorf("error connecting to external signer: %v", err)
+		}
+		am.AddBackend(extapi)
+		return nil
+	}
 	am.AddBackend(keystore.NewKeyStore(keydir, scryptN, scryptP))
 
 	return nil
@@ -698,6 +782,11 @@ func (n *Node) SetupMetrics(config conf.MetricsConfig) {
 
 		go influxdb.InfluxDBV2WithTags(metrics.DefaultRegistry, 10*time.Second, endpoint, token, bucket, organization, "amc.", tagsMap)
 	}
+
+	if config.HTTP != "" {
+		address := net.JoinHostPort(config.HTTP, strconv.Itoa(config.Port))
+		prometheus.Setup(address, metrics.DefaultRegistry)
+	}
 }
 
 func (s *Node) Etherbase() (eb types.Address, err error) {
@@ -723,6 +812,91 @@ func (s *Node) Etherbase() (eb types.Address, err error) {
 	return types.Address{}, fmt.Errorf("etherbase must be explicitly specified")
 }
 
+// txPoolConfig converts the txpool section of the node configuration into the
+// pool's own settings, resolving the journal path against the data directory.
+func txPoolConfig(cfg *conf.Config) txspool.TxsPoolConfig {
+	poolCfg := txspool.TxsPoolConfig{
+		NoLocals:     cfg.TxPoolCfg.NoLocals,
+		Journal:      cfg.TxPoolCfg.Journal,
+		Rejournal:    cfg.TxPoolCfg.Rejournal,
+		PriceLimit:   cfg.TxPoolCfg.PriceLimit,
+		PriceBump:    cfg.TxPoolCfg.PriceBump,
+		AccountSlots: cfg.TxPoolCfg.AccountSlots,
+		GlobalSlots:  cfg.TxPoolCfg.GlobalSlots,
+		AccountQueue: cfg.TxPoolCfg.AccountQueue,
+		GlobalQueue:  cfg.TxPoolCfg.GlobalQueue,
+		Lifetime:     cfg.TxPoolCfg.Lifetime,
+	}
+	for _, account := range cfg.TxPoolCfg.Locals {
+		if trimmed := strings.TrimSpace(account); !types.IsHexAddress(trimmed) {
+			log.Warn("Invalid account in txpool locals", "account", trimmed)
+		} else {
+			poolCfg.Locals = append(poolCfg.Locals, types.HexToAddress(trimmed))
+		}
+	}
+	if poolCfg.Journal != "" && !filepath.IsAbs(poolCfg.Journal) {
+		if cfg.NodeCfg.DataDir == "" {
+			// Nothing to persist to for ephemeral nodes
+			poolCfg.Journal = ""
+		} else {
+			poolCfg.Journal = filepath.Join(cfg.NodeCfg.DataDir, poolCfg.Journal)
+		}
+	}
+	return poolCfg
+}
+
+// pruneConfig returns the history distances kept by the block chain in the
+// configured prune mode.
+func pruneConfig(cfg *conf.Config) (internal.PruneConfig, error) {
+	switch cfg.DatabaseCfg.Prune {
+	case conf.PruneFull:
+		return internal.PruneConfig{
+			History:  cfg.DatabaseCfg.PruneHistory,
+			Receipts: cfg.DatabaseCfg.PruneReceipts,
+			Logs:     cfg.DatabaseCfg.PruneLogs,
+		}, nil
+	case "", conf.PruneArchive:
+		return internal.PruneConfig{}, nil
+	default:
+		return internal.PruneConfig{}, fmt.Errorf("unknown prune mode %q, want %q or %q", cfg.DatabaseCfg.Prune, conf.PruneArchive, conf.PruneFull)
+	}
+}
+
+// newEngine creates the consensus engine of the chain.
+func newEngine(cfg *conf.Config, db kv.RwDB) (consensus.Engine, error) {
+	switch cfg.GenesisBlockCfg.Engine.EngineName {
+	case "APoaEngine":
+		return apoa.New(cfg.GenesisBlockCfg.Engine, db), nil
+	case "APosEngine":
+		return apos.New(cfg.GenesisBlockCfg.Engine, db, cfg.GenesisBlockCfg.Config), nil
+	default:
+		return nil, fmt.Errorf("invalid engine name %s", cfg.GenesisBlockCfg.Engine.EngineName)
+	}
+}
+
+// OpenChainDatabase opens the chain database of a stopped node together with its
+// consensus engine, without starting any of the node's services. The genesis
+// specification stored in the database takes precedence over the one in cfg.
+func OpenChainDatabase(ctx context.Context, cfg *conf.Config) (kv.RwDB, consensus.Engine, error) {
+	db, err := OpenDatabase(cfg, nil, kv.ChainDB.String())
+	if nil != err {
+		return nil, nil, err
+	}
+	if err = db.View(ctx, func(tx kv.Tx) error {
+		_, err := loadGenesis(tx, cfg)
+		return err
+	}); nil != err {
+		db.Close()
+		return nil, nil, err
+	}
+	engine, err := newEngine(cfg, db)
+	if nil != err {
+		db.Close()
+		return nil, nil, err
+	}
+	return db, engine, nil
+}
+
 func OpenDatabase(cfg *conf.Config, logger log2.Logger, name string) (kv.RwDB, error) {
 	var chainKv kv.RwDB
 	if cfg.NodeCfg.DataDir == "" {
@@ -766,10 +940,26 @@ func OpenDatabase(cfg *conf.Config, logger log2.Logger, name string) (kv.RwDB, e
 	return chainKv, nil
 }
 
+// OpenDatabaseReadonly opens an existing database for reading only. It can be
+// opened while a node has the database open for writing.
+func OpenDatabaseReadonly(cfg *conf.Config, logger log2.Logger, name string) (kv.RoDB, error) {
+	dbPath := filepath.Join(cfg.NodeCfg.DataDir, name)
+	if _, err := os.Stat(dbPath); nil != err {
+		return nil, err
+	}
+	log.Info("Opening Database", "label", name, "path", dbPath, "readonly", true)
+	modules.AmcInit()
+	kv.ChaindataTablesCfg = modules.AmcTableCfg
+	return mdbx.NewMDBX(logger).Path(dbPath).Label(kv.ChainDB).Readonly().Open()
+}
+
 func WriteGenesisBlock(db kv.RwTx, genesis *conf.GenesisBlockConfig) (*block.Block, error) {
 	if genesis == nil {
 		return nil, internal.ErrGenesisNoConfig
 	}
+	if err := genesis.Config.CheckDepositSchedules(); err != nil {
+		return nil, fmt.Errorf("invalid genesis: %w", err)
+	}
 	storedHash, storedErr := rawdb.ReadCanonicalHash(db, 0)
 	if storedErr != nil {
 		return nil, storedErr
@@ -0,0 +1,156 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package internal
+
+import (
+	"context"
+	"errors"
+
+	"github.com/amazechain/amc/log"
+	"github.com/amazechain/amc/modules/changeset"
+	"github.com/amazechain/amc/modules/rawdb"
+	"github.com/ledgerwatch/erigon-lib/kv"
+)
+
+const (
+	// pruneBatch is the number of blocks of a data class pruned per write
+	// transaction, so that pruning never holds up block import for long.
+	pruneBatch = 100
+
+	// MinPruneHistory is the least number of blocks of state history kept, so that
+	// the chain can still be rewound past a reorg.
+	MinPruneHistory = 128
+)
+
+// PruneConfig holds the number of recent blocks kept per data class. A zero
+// distance keeps the whole history of the class.
+type PruneConfig struct {
+	History  uint64 // account and storage changesets and history indices, deposit snapshots
+	Receipts uint64
+	Logs     uint64
+}
+
+func (c PruneConfig) enabled() bool {
+	return c.History > 0 || c.Receipts > 0 || c.Logs > 0
+}
+
+// SetPruneConfig configures the history pruned behind the head. It has to be
+// called before Start.
+func (bc *BlockChain) SetPruneConfig(config PruneConfig) {
+	if config.History > 0 && config.History < MinPruneHistory {
+		log.Warn("Sanitizing state history distance", "provided", config.History, "updated", MinPruneHistory)
+		config.History = MinPruneHistory
+	}
+	bc.pruneConfig = config
+}
+
+// pruneClass is a data class pruned up to a distance behind the head.
+type pruneClass struct {
+	name     string
+	distance uint64
+	prune    func(ctx context.Context, tx kv.RwTx, from, to uint64) error
+}
+
+func (bc *BlockChain) pruneClasses() []pruneClass {
+	return []pruneClass{
+		{rawdb.PruneHistory, bc.pruneConfig.History, func(ctx context.Context, tx kv.RwTx, from, to uint64) error {
+			if err := changeset.PruneHistoryTo(ctx, tx, from, to); nil != err {
+				return err
+			}
+			if err := rawdb.PruneDepositSnapshotsTo(ctx, tx, to); nil != err {
+				return err
+			}
+			return rawdb.PruneRewardChangeSetsTo(ctx, tx, to)
+		}},
+		{rawdb.PruneReceipts, bc.pruneConfig.Receipts, func(ctx context.Context, tx kv.RwTx, _, to uint64) error {
+			return rawdb.PruneReceiptsTo(ctx, tx, to)
+		}},
+		{rawdb.PruneLogs, bc.pruneConfig.Logs, func(ctx context.Context, tx kv.RwTx, from, to uint64) error {
+			if err := rawdb.PruneLogsTo(ctx, tx, to); nil != err {
+				return err
+			}
+			return rawdb.PruneLogIndexTo(tx, from, to)
+		}},
+	}
+}
+
+// notifyPruner wakes the pruner up after a new head was written.
+func (bc *BlockChain) notifyPruner() {
+	select {
+	case bc.pruneCh <- struct{}{}:
+	default:
+	}
+}
+
+// pruneLoop prunes the history that fell out of the configured distances whenever
+// the head moves.
+func (bc *BlockChain) pruneLoop() {
+	defer bc.wg.Done()
+
+	for {
+		select {
+		case <-bc.ctx.Done():
+			return
+		case <-bc.pruneCh:
+		}
+		if err := bc.prune(); nil != err {
+			if errors.Is(err, context.Canceled) {
+				return
+			}
+			log.Error("Failed to prune history", "err", err)
+		}
+	}
+}
+
+// prune removes the data of every class behind its distance from the head, a
+// batch of blocks per transaction.
+func (bc *BlockChain) prune() error {
+	head := bc.CurrentBlock().Number64().Uint64()
+	for _, class := range bc.pruneClasses() {
+		if class.distance == 0 || head <= class.distance {
+			continue
+		}
+		target := head - class.distance
+		for done := false; !done; {
+			if err := bc.ctx.Err(); nil != err {
+				return err
+			}
+			if err := bc.ChainDB.Update(bc.ctx, func(tx kv.RwTx) error {
+				from, err := rawdb.ReadPrunedTo(tx, class.name)
+				if nil != err {
+					return err
+				}
+				if from >= target {
+					done = true
+					return nil
+				}
+				to := from + pruneBatch
+				if to > target {
+					to = target
+				}
+				if err := class.prune(bc.ctx, tx, from, to); nil != err {
+					return err
+				}
+				log.Debug("Pruned history", "class", class.name, "from", from, "to", to)
+				return rawdb.WritePrunedTo(tx, class.name, to)
+			}); nil != err {
+				return err
+			}
+		}
+	}
+	return nil
+}
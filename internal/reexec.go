@@ -0,0 +1,329 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sort"
+
+	"github.com/amazechain/amc/common/account"
+	block2 "github.com/amazechain/amc/common/block"
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/modules"
+	"github.com/amazechain/amc/modules/changeset"
+	"github.com/amazechain/amc/modules/rawdb"
+	"github.com/amazechain/amc/modules/state"
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+)
+
+// Divergence describes a block whose re-execution does not reproduce the
+// results stored in the database.
+type Divergence struct {
+	Number  uint64
+	Hash    types.Hash
+	Reasons []string     // results that differ, or the execution error
+	Diffs   []*StateDiff // post state that differs, if the block executed
+}
+
+// StateDiff is a field of an account whose value after re-execution differs
+// from the stored one.
+type StateDiff struct {
+	Address types.Address
+	Field   string // account field, or the storage slot
+	Stored  string
+	Reexec  string
+}
+
+func (d *StateDiff) String() string {
+	return fmt.Sprintf("%v %s: stored %s, re-executed %s", d.Address, d.Field, d.Stored, d.Reexec)
+}
+
+// Reexec executes the canonical blocks [from, to] again on top of the
+// historical state of their parents and compares the gas used, receipts and
+// state root with the stored ones. It stops at the first block that diverges,
+// and returns nil if all of them match. Nothing is written to the database.
+func (bc *BlockChain) Reexec(ctx context.Context, from, to uint64, progress func(number uint64)) (*Divergence, error) {
+	if from == 0 {
+		return nil, fmt.Errorf("the genesis block cannot be re-executed")
+	}
+	for number := from; number <= to; number++ {
+		if err := ctx.Err(); nil != err {
+			return nil, err
+		}
+		divergence, err := bc.reexecBlock(ctx, number)
+		if nil != err || divergence != nil {
+			return divergence, err
+		}
+		if progress != nil {
+			progress(number)
+		}
+	}
+	return nil, nil
+}
+
+// reexecBlock executes a block in a transaction that is rolled back.
+func (bc *BlockChain) reexecBlock(ctx context.Context, number uint64) (*Divergence, error) {
+	tx, err := bc.ChainDB.BeginRw(ctx)
+	if nil != err {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := rawdb.CheckPruned(tx, rawdb.PruneHistory, number); nil != err {
+		return nil, fmt.Errorf("state of block %d: %w", number, err)
+	}
+	b, err := rawdb.ReadBlockByNumber(tx, number)
+	if nil != err {
+		return nil, err
+	}
+	if b == nil {
+		return nil, fmt.Errorf("block %d not found", number)
+	}
+	header := b.Header().(*block2.Header)
+	divergence := &Divergence{Number: number, Hash: b.Hash()}
+
+	reader := state.NewPlainState(tx, number)
+	ibs := state.New(reader)
+	writer := newRecordingWriter()
+	getHeader := func(hash types.Hash, number uint64) *block2.Header {
+		return rawdb.ReadHeader(tx, hash, number)
+	}
+	// The commitment of the activation block is generated from the plain state,
+	// which only matches the parent state at the head.
+	checkRoot := !bc.chainConfig.IsStateCommitment(number) || bc.chainConfig.IsStateCommitment(number-1)
+	if checkRoot {
+		commitment, err := OpenStateCommitment(tx, bc.chainConfig, header)
+		if nil != err {
+			return nil, err
+		}
+		ibs.SetStateCommitment(commitment)
+	}
+
+	receipts, _, usedGas, err := bc.process.Process(tx, b, ibs, reader, writer, GetHashFn(header, getHeader), nil)
+	if nil != err {
+		divergence.Reasons = append(divergence.Reasons, err.Error())
+		return divergence, nil
+	}
+
+	if usedGas != header.GasUsed {
+		divergence.Reasons = append(divergence.Reasons, fmt.Sprintf("gas used %d, header %d", usedGas, header.GasUsed))
+	}
+	if bloom := block2.CreateBloom(receipts); bloom != header.Bloom {
+		divergence.Reasons = append(divergence.Reasons, "logs bloom differs from the header")
+	}
+	if hash := DeriveSha(receipts); hash != header.ReceiptHash {
+		divergence.Reasons = append(divergence.Reasons, fmt.Sprintf("receipt root %v, header %v", hash, header.ReceiptHash))
+	}
+	receiptsFrom, err := rawdb.ReceiptsAvailableFrom(tx)
+	if nil != err {
+		return nil, err
+	}
+	if number >= receiptsFrom {
+		divergence.Reasons = append(divergence.Reasons, compareReceipts(rawdb.ReadRawReceipts(tx, number), receipts)...)
+	}
+	if checkRoot {
+		if root := ibs.IntermediateRoot(); root != header.Root {
+			divergence.Reasons = append(divergence.Reasons, fmt.Sprintf("state root %v, header %v", root, header.Root))
+		}
+	}
+	if len(divergence.Reasons) == 0 {
+		return nil, nil
+	}
+	if divergence.Diffs, err = writer.diff(tx, number); nil != err {
+		return nil, err
+	}
+	return divergence, nil
+}
+
+// compareReceipts lists the differences between the stored receipts of a block
+// and the ones of its re-execution.
+func compareReceipts(stored, reexec block2.Receipts) []string {
+	if len(stored) != len(reexec) {
+		return []string{fmt.Sprintf("%d receipts, %d stored", len(reexec), len(stored))}
+	}
+	var reasons []string
+	for i, r := range reexec {
+		s := stored[i]
+		switch {
+		case r.Status != s.Status:
+			reasons = append(reasons, fmt.Sprintf("receipt %d: status %d, stored %d", i, r.Status, s.Status))
+		case r.GasUsed != s.GasUsed:
+			reasons = append(reasons, fmt.Sprintf("receipt %d: gas used %d, stored %d", i, r.GasUsed, s.GasUsed))
+		case r.CumulativeGasUsed != s.CumulativeGasUsed:
+			reasons = append(reasons, fmt.Sprintf("receipt %d: cumulative gas used %d, stored %d", i, r.CumulativeGasUsed, s.CumulativeGasUsed))
+		case len(r.Logs) != len(s.Logs):
+			reasons = append(reasons, fmt.Sprintf("receipt %d: %d logs, stored %d", i, len(r.Logs), len(s.Logs)))
+		case r.ContractAddress != s.ContractAddress:
+			reasons = append(reasons, fmt.Sprintf("receipt %d: contract %v, stored %v", i, r.ContractAddress, s.ContractAddress))
+		}
+	}
+	return reasons
+}
+
+type storageSlot struct {
+	address     types.Address
+	incarnation uint16
+	key         types.Hash
+}
+
+// recordingWriter keeps the post state of the accounts and storage a block
+// writes, instead of writing it to the database.
+type recordingWriter struct {
+	accounts map[types.Address]*account.StateAccount // nil for deleted accounts
+	storage  map[storageSlot]uint256.Int
+}
+
+func newRecordingWriter() *recordingWriter {
+	return &recordingWriter{
+		accounts: make(map[types.Address]*account.StateAccount),
+		storage:  make(map[storageSlot]uint256.Int),
+	}
+}
+
+func (w *recordingWriter) UpdateAccountData(address types.Address, original, account *account.StateAccount) error {
+	w.accounts[address] = account.SelfCopy()
+	return nil
+}
+
+func (w *recordingWriter) UpdateAccountCode(address types.Address, incarnation uint16, codeHash types.Hash, code []byte) error {
+	return nil
+}
+
+func (w *recordingWriter) DeleteAccount(address types.Address, original *account.StateAccount) error {
+	w.accounts[address] = nil
+	return nil
+}
+
+func (w *recordingWriter) WriteAccountStorage(address types.Address, incarnation uint16, key *types.Hash, original, value *uint256.Int) error {
+	w.storage[storageSlot{address, incarnation, *key}] = *value
+	return nil
+}
+
+func (w *recordingWriter) CreateContract(address types.Address) error {
+	return nil
+}
+
+func (w *recordingWriter) WriteChangeSets() error {
+	return nil
+}
+
+func (w *recordingWriter) WriteHistory() error {
+	return nil
+}
+
+// diff compares the recorded post state of the block with the stored one, for
+// the accounts and slots written by either the re-execution or the changesets
+// of the block.
+func (w *recordingWriter) diff(tx kv.Tx, number uint64) ([]*StateDiff, error) {
+	addresses := make(map[types.Address]struct{}, len(w.accounts))
+	for addr := range w.accounts {
+		addresses[addr] = struct{}{}
+	}
+	slots := make(map[storageSlot]struct{}, len(w.storage))
+	for slot := range w.storage {
+		slots[slot] = struct{}{}
+	}
+	prefix := modules.EncodeBlockNumber(number)
+	if err := changeset.ForPrefix(tx, modules.AccountChangeSet, prefix, func(_ uint64, k, _ []byte) error {
+		addresses[types.BytesToAddress(k)] = struct{}{}
+		return nil
+	}); nil != err {
+		return nil, err
+	}
+	if err := changeset.ForPrefix(tx, modules.StorageChangeSet, prefix, func(_ uint64, k, _ []byte) error {
+		var slot storageSlot
+		slot.address = types.BytesToAddress(k[:types.AddressLength])
+		slot.incarnation = binary.BigEndian.Uint16(k[types.AddressLength:])
+		copy(slot.key[:], k[types.AddressLength+modules.Incarnation:])
+		slots[slot] = struct{}{}
+		return nil
+	}); nil != err {
+		return nil, err
+	}
+
+	parent, stored := state.NewPlainState(tx, number), state.NewPlainState(tx, number+1)
+	var diffs []*StateDiff
+	for addr := range addresses {
+		want, err := stored.ReadAccountData(addr)
+		if nil != err {
+			return nil, err
+		}
+		have, ok := w.accounts[addr]
+		if !ok {
+			if have, err = parent.ReadAccountData(addr); nil != err {
+				return nil, err
+			}
+		}
+		diffs = append(diffs, diffAccount(addr, want, have)...)
+	}
+	for slot := range slots {
+		want, err := stored.ReadAccountStorage(slot.address, slot.incarnation, &slot.key)
+		if nil != err {
+			return nil, err
+		}
+		have, ok := w.storage[slot]
+		if !ok {
+			v, err := parent.ReadAccountStorage(slot.address, slot.incarnation, &slot.key)
+			if nil != err {
+				return nil, err
+			}
+			have.SetBytes(v)
+		}
+		if wantValue := new(uint256.Int).SetBytes(want); !wantValue.Eq(&have) {
+			diffs = append(diffs, &StateDiff{Address: slot.address, Field: fmt.Sprintf("storage %v", slot.key), Stored: wantValue.Hex(), Reexec: have.Hex()})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		if diffs[i].Address != diffs[j].Address {
+			return bytes.Compare(diffs[i].Address[:], diffs[j].Address[:]) < 0
+		}
+		return diffs[i].Field < diffs[j].Field
+	})
+	return diffs, nil
+}
+
+func diffAccount(addr types.Address, stored, reexec *account.StateAccount) []*StateDiff {
+	if stored == nil || reexec == nil {
+		if stored == nil && reexec == nil {
+			return nil
+		}
+		exists := func(a *account.StateAccount) string {
+			if a == nil {
+				return "no"
+			}
+			return "yes"
+		}
+		return []*StateDiff{{Address: addr, Field: "exists", Stored: exists(stored), Reexec: exists(reexec)}}
+	}
+	var diffs []*StateDiff
+	if !stored.Balance.Eq(&reexec.Balance) {
+		diffs = append(diffs, &StateDiff{addr, "balance", stored.Balance.Dec(), reexec.Balance.Dec()})
+	}
+	if stored.Nonce != reexec.Nonce {
+		diffs = append(diffs, &StateDiff{addr, "nonce", fmt.Sprint(stored.Nonce), fmt.Sprint(reexec.Nonce)})
+	}
+	if stored.Incarnation != reexec.Incarnation {
+		diffs = append(diffs, &StateDiff{addr, "incarnation", fmt.Sprint(stored.Incarnation), fmt.Sprint(reexec.Incarnation)})
+	}
+	if stored.CodeHash != reexec.CodeHash {
+		diffs = append(diffs, &StateDiff{addr, "code hash", stored.CodeHash.String(), reexec.CodeHash.String()})
+	}
+	return diffs
+}
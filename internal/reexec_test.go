@@ -0,0 +1,85 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+package internal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/amazechain/amc/common/account"
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/modules"
+	"github.com/amazechain/amc/modules/state"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+)
+
+func TestReexecStateDiff(t *testing.T) {
+	modules.AmcInit()
+	kv.ChaindataTablesCfg = modules.AmcTableCfg
+	db := memdb.New(t.TempDir())
+	defer db.Close()
+
+	a, b, c := types.Address{1}, types.Address{2}, types.Address{3}
+	newAccount := func(balance uint64) *account.StateAccount {
+		acc := account.NewAccount()
+		acc.Initialised = true
+		acc.Balance.SetUint64(balance)
+		return &acc
+	}
+	empty := account.NewAccount()
+
+	tx, err := db.BeginRw(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	// Block 1 creates three accounts
+	w := state.NewPlainStateWriter(tx, tx, 1)
+	for addr, balance := range map[types.Address]uint64{a: 10, b: 5, c: 7} {
+		if err := w.UpdateAccountData(addr, &empty, newAccount(balance)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.WriteChangeSets(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteHistory(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The re-execution credits a differently, misses b and matches c
+	r := newRecordingWriter()
+	r.UpdateAccountData(a, &empty, newAccount(11))
+	r.UpdateAccountData(c, &empty, newAccount(7))
+	diffs, err := r.diff(tx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []StateDiff{
+		{Address: a, Field: "balance", Stored: "10", Reexec: "11"},
+		{Address: b, Field: "exists", Stored: "yes", Reexec: "no"},
+	}
+	if len(diffs) != len(want) {
+		t.Fatalf("have diffs %v, want %v", diffs, want)
+	}
+	for i := range want {
+		if *diffs[i] != want[i] {
+			t.Errorf("diff %d: have %v, want %v", i, diffs[i], &want[i])
+		}
+	}
+}
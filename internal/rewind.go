@@ -0,0 +1,232 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package internal
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	block2 "github.com/amazechain/amc/common/block"
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/internal/consensus"
+	"github.com/amazechain/amc/log"
+	"github.com/amazechain/amc/modules"
+	"github.com/amazechain/amc/modules/changeset"
+	"github.com/amazechain/amc/modules/rawdb"
+	"github.com/amazechain/amc/modules/state"
+	"github.com/amazechain/amc/params"
+	"github.com/ledgerwatch/erigon-lib/kv"
+)
+
+// SetHead rewinds the canonical chain to the given block, see Rewind.
+func (bc *BlockChain) SetHead(head uint64) error {
+	bc.lock.Lock()
+	defer bc.lock.Unlock()
+
+	current := bc.CurrentBlock().Number64().Uint64()
+	var newHead *block2.Block
+	if err := bc.ChainDB.Update(bc.ctx, func(tx kv.RwTx) error {
+		var err error
+		newHead, err = Rewind(bc.ctx, tx, bc.chainConfig, bc.engine, head)
+		return err
+	}); nil != err {
+		return err
+	}
+	if err := bc.loadFinality(); nil != err {
+		return err
+	}
+
+	bc.currentBlock.Store(newHead)
+	bc.futureBlocks.Purge()
+	bc.receiptCache.Purge()
+	bc.blockCache.Purge()
+	bc.headerCache.Purge()
+	bc.numberCache.Purge()
+	bc.tdCache.Purge()
+	headBlockGauge.Update(int64(head))
+	log.Info("Rewound chain", "number", head, "hash", newHead.Hash(), "dropped", current-head)
+	return nil
+}
+
+// Rewind rewinds the canonical chain stored in tx to the given block. The plain
+// state is reverted with the changesets of the dropped blocks, which are removed
+// together with their receipts, logs, indices and consensus data. The database is
+// checked for consistency before the new head is returned.
+func Rewind(ctx context.Context, tx kv.RwTx, config *params.ChainConfig, engine consensus.Engine, target uint64) (*block2.Block, error) {
+	current := rawdb.ReadHeaderNumber(tx, rawdb.ReadHeadBlockHash(tx))
+	if current == nil {
+		return nil, fmt.Errorf("database has no head block")
+	}
+	if target > *current {
+		return nil, fmt.Errorf("cannot rewind to block %d ahead of the current block %d", target, *current)
+	}
+	// The accounts changed by the dropped blocks are checked against the state root
+	changed, err := changeset.GetModifiedAccounts(tx, target+1, *current+1)
+	if nil != err {
+		return nil, err
+	}
+	newHead, err := rewind(ctx, tx, engine, *current, target)
+	if nil != err {
+		return nil, err
+	}
+	if err = rewindFinality(tx, newHead); nil != err {
+		return nil, err
+	}
+	if err = verifyRewind(tx, config, newHead, changed); nil != err {
+		return nil, err
+	}
+	return newHead, nil
+}
+
+// rewind removes the canonical blocks (target, current] and everything derived
+// from them, and makes target the head of the chain.
+func rewind(ctx context.Context, tx kv.RwTx, engine consensus.Engine, current, target uint64) (*block2.Block, error) {
+	newHead, err := rawdb.ReadBlockByNumber(tx, target)
+	if nil != err {
+		return nil, err
+	}
+	if newHead == nil {
+		return nil, fmt.Errorf("canonical block %d not found", target)
+	}
+	if target == current {
+		return newHead, nil
+	}
+	if from, err := changeset.AvailableFrom(tx); nil != err {
+		return nil, err
+	} else if from != math.MaxUint64 && from > target+1 {
+		return nil, fmt.Errorf("state history is only available from block %d, cannot rewind to %d", from, target)
+	}
+
+	// Consensus data is derived from the blocks, revert it while they still exist.
+	if unwinder, ok := engine.(consensus.Unwinder); ok {
+		if err := unwinder.Unwind(tx, current, target); nil != err {
+			return nil, err
+		}
+	}
+	if err := state.UnwindState(tx, current, target, ctx.Done()); nil != err {
+		return nil, fmt.Errorf("unwinding state: %w", err)
+	}
+	for n := target + 1; n <= current; n++ {
+		b, err := rawdb.ReadBlockByNumber(tx, n)
+		if nil != err {
+			return nil, err
+		}
+		if b == nil {
+			continue
+		}
+		for _, t := range b.Transactions() {
+			if err := rawdb.DeleteTxLookupEntry(tx, t.Hash()); nil != err {
+				return nil, err
+			}
+		}
+	}
+	if err := unwindLogIndex(tx, target+1); nil != err {
+		return nil, err
+	}
+	if err := unwindCallIndex(tx, target+1); nil != err {
+		return nil, err
+	}
+	if err := rawdb.TruncateReceipts(tx, target+1); nil != err {
+		return nil, err
+	}
+	for _, table := range []string{modules.Senders, modules.BlockVerify, modules.BlockRewards, modules.CallTraceSet} {
+		if err := truncateTable(tx, table, target+1); nil != err {
+			return nil, err
+		}
+	}
+	if err := rawdb.TruncateTd(tx, target+1); nil != err {
+		return nil, err
+	}
+	if err := rawdb.TruncateCanonicalHash(tx, target+1, true); nil != err {
+		return nil, err
+	}
+	if err := rawdb.TruncateBlocks(ctx, tx, target+1); nil != err {
+		return nil, err
+	}
+
+	rawdb.WriteHeadBlockHash(tx, newHead.Hash())
+	if err := rawdb.WriteHeadHeaderHash(tx, newHead.Hash()); nil != err {
+		return nil, err
+	}
+	return newHead, nil
+}
+
+// verifyRewind checks that nothing of the dropped blocks is left and, once the state
+// commitment is active, that the accounts they changed match the state root of the
+// head.
+func verifyRewind(tx kv.Tx, config *params.ChainConfig, head *block2.Block, changed []types.Address) error {
+	number := head.Number64().Uint64()
+	if hash := rawdb.ReadHeadBlockHash(tx); hash != head.Hash() {
+		return fmt.Errorf("head block is %x, want %x", hash, head.Hash())
+	}
+	if hash := rawdb.ReadHeadHeaderHash(tx); hash != head.Hash() {
+		return fmt.Errorf("head header is %x, want %x", hash, head.Hash())
+	}
+	if td, err := rawdb.ReadTd(tx, head.Hash(), number); nil != err || td == nil {
+		return fmt.Errorf("total difficulty of the head is missing: %v", err)
+	}
+	for _, table := range []string{modules.HeaderCanonical, modules.Headers, modules.BlockBody, modules.HeaderTD,
+		modules.Receipts, modules.Log, modules.AccountChangeSet, modules.StorageChangeSet, modules.CallTraceSet,
+		modules.Senders, modules.BlockVerify, modules.BlockRewards} {
+		c, err := tx.Cursor(table)
+		if nil != err {
+			return err
+		}
+		k, _, err := c.Seek(modules.EncodeBlockNumber(number + 1))
+		c.Close()
+		if nil != err {
+			return err
+		}
+		if k != nil {
+			return fmt.Errorf("%s still holds block %d", table, binary.BigEndian.Uint64(k[:8]))
+		}
+	}
+	if progress, err := rawdb.ReadLogIndexProgress(tx); nil != err {
+		return err
+	} else if progress > number {
+		return fmt.Errorf("log index progress %d is ahead of the head", progress)
+	}
+
+	if config.IsStateCommitment(number) {
+		if err := state.VerifyAccounts(tx, head.StateRoot(), changed); nil != err {
+			return fmt.Errorf("state after rewind does not match the state root %x: %w", head.StateRoot(), err)
+		}
+	}
+	return nil
+}
+
+// truncateTable deletes the entries of a table keyed by block number from the given
+// block onwards.
+func truncateTable(tx kv.RwTx, table string, from uint64) error {
+	c, err := tx.RwCursor(table)
+	if nil != err {
+		return err
+	}
+	defer c.Close()
+
+	for k, _, err := c.Seek(modules.EncodeBlockNumber(from)); k != nil; k, _, err = c.Next() {
+		if nil != err {
+			return err
+		}
+		if err = c.DeleteCurrent(); nil != err {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package internal
+
+import (
+	"fmt"
+
+	block2 "github.com/amazechain/amc/common/block"
+	"github.com/amazechain/amc/common/transaction"
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/internal/consensus"
+	"github.com/amazechain/amc/log"
+	"github.com/amazechain/amc/modules/rawdb"
+	"github.com/holiman/uint256"
+)
+
+// InsertBlocksWithoutState stores blocks downloaded below the snap sync pivot. Their
+// headers are checked by the consensus engine and their bodies against the headers,
+// but they are not executed, and neither the head nor the canonical chain moves until
+// SnapSyncCommitHead links them up.
+func (bc *BlockChain) InsertBlocksWithoutState(chain []block2.IBlock) (int, error) {
+	if len(chain) == 0 {
+		return 0, nil
+	}
+	for i := 1; i < len(chain); i++ {
+		block, prev := chain[i], chain[i-1]
+		if block.Number64().Uint64() != prev.Number64().Uint64()+1 || block.ParentHash() != prev.Hash() {
+			return 0, fmt.Errorf("non contiguous insert: item %d is #%d [%x..], item %d is #%d [%x..] (parent [%x..])", i-1, prev.Number64().Uint64(),
+				prev.Hash().Bytes()[:4], i, block.Number64().Uint64(), block.Hash().Bytes()[:4], block.ParentHash().Bytes()[:4])
+		}
+	}
+	bc.lock.Lock()
+	defer bc.lock.Unlock()
+
+	headers := make([]block2.IHeader, len(chain))
+	seals := make([]bool, len(chain))
+	for i, block := range chain {
+		headers[i] = block.Header()
+		seals[i] = true
+	}
+	abort, results := bc.engine.VerifyHeaders(bc, headers, seals)
+	defer close(abort)
+
+	tx, err := bc.ChainDB.BeginRw(bc.ctx)
+	if nil != err {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	for i, block := range chain {
+		if err := <-results; nil != err {
+			return i, err
+		}
+		if hash := DeriveSha(transaction.Transactions(block.Transactions())); hash != block.TxHash() {
+			return i, fmt.Errorf("transaction root hash mismatch: have %x, want %x", hash, block.TxHash())
+		}
+		if err := verifyVerifierHash(bc.chainConfig, block); err != nil {
+			return i, err
+		}
+		number := block.Number64().Uint64()
+		ptd, err := rawdb.ReadTd(tx, block.ParentHash(), number-1)
+		if nil != err {
+			return i, err
+		}
+		if ptd == nil {
+			return i, consensus.ErrUnknownAncestor
+		}
+		if err := rawdb.WriteTd(tx, block.Hash(), number, new(uint256.Int).Add(ptd, block.Difficulty())); nil != err {
+			return i, err
+		}
+		if err := rawdb.WriteBlock(tx, block.(*block2.Block)); nil != err {
+			return i, err
+		}
+	}
+	if err := tx.Commit(); nil != err {
+		return 0, err
+	}
+	return len(chain), nil
+}
+
+// SnapSyncCommitHead makes the given block, whose state has been downloaded by snap
+// sync, the head of the chain. The blocks stored by InsertBlocksWithoutState between
+// the canonical chain and the pivot become canonical as well.
+func (bc *BlockChain) SnapSyncCommitHead(hash types.Hash) error {
+	bc.lock.Lock()
+	defer bc.lock.Unlock()
+
+	tx, err := bc.ChainDB.BeginRw(bc.ctx)
+	if nil != err {
+		return err
+	}
+	defer tx.Rollback()
+
+	pivot, err := rawdb.ReadBlockByHash(tx, hash)
+	if nil != err {
+		return err
+	}
+	if pivot == nil {
+		return fmt.Errorf("non existent block [%x..]", hash[:4])
+	}
+	for header := pivot.Header().(*block2.Header); header.Number.Uint64() > 0; {
+		number := header.Number.Uint64()
+		canonical, err := rawdb.ReadCanonicalHash(tx, number)
+		if nil != err {
+			return err
+		}
+		if canonical == header.Hash() {
+			break
+		}
+		if err := rawdb.WriteCanonicalHash(tx, header.Hash(), number); nil != err {
+			return err
+		}
+		if header = rawdb.ReadHeader(tx, header.ParentHash, number-1); header == nil {
+			return fmt.Errorf("missing ancestor of block #%d", number)
+		}
+	}
+	if err := rawdb.WriteHeadHeaderHash(tx, hash); nil != err {
+		return err
+	}
+	// Blocks below the pivot have no receipts, so the log index starts after it.
+	if err := rawdb.WriteLogIndexProgress(tx, pivot.Number64().Uint64()); nil != err {
+		return err
+	}
+	if err := bc.writeHeadBlock(tx, pivot); nil != err {
+		return err
+	}
+	if err := tx.Commit(); nil != err {
+		return err
+	}
+	bc.headCommitted()
+	log.Info("Committed snap sync head", "number", pivot.Number64().Uint64(), "hash", hash)
+	return nil
+}
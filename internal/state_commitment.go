@@ -0,0 +1,77 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package internal
+
+import (
+	"fmt"
+
+	"github.com/amazechain/amc/common/block"
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/modules/rawdb"
+	"github.com/amazechain/amc/modules/state"
+	"github.com/amazechain/amc/params"
+	"github.com/ledgerwatch/erigon-lib/kv"
+)
+
+// OpenStateCommitment returns the state commitment to execute header with, or nil if
+// the state commitment fork is not active at header. At the fork block the commitment
+// is generated from the plain state, which must hold the state of the parent block.
+func OpenStateCommitment(tx kv.Tx, config *params.ChainConfig, header *block.Header) (*state.StateCommitment, error) {
+	number := header.Number.Uint64()
+	if !config.IsStateCommitment(number) {
+		return nil, nil
+	}
+	rules := config.Rules(number)
+	if number == 0 || !config.IsStateCommitment(number-1) {
+		return state.GenerateStateCommitment(tx, rules)
+	}
+	parent := rawdb.ReadHeader(tx, header.ParentHash, number-1)
+	if parent == nil {
+		return nil, fmt.Errorf("unknown parent %x of block %d", header.ParentHash, number)
+	}
+	return state.NewStateCommitment(tx, parent.Root, rules)
+}
+
+// OpenWitnessStateCommitment is OpenStateCommitment for a block being mined. The
+// commitment records the trie nodes the block reads, which are sent to the verifiers
+// along with the block (see state.EntireCode). At the fork block the commitment
+// generated from the plain state is stored first, so that the block is applied on
+// stored nodes as well.
+func OpenWitnessStateCommitment(tx kv.RwTx, config *params.ChainConfig, header *block.Header) (*state.StateCommitment, error) {
+	number := header.Number.Uint64()
+	if !config.IsStateCommitment(number) {
+		return nil, nil
+	}
+	rules := config.Rules(number)
+	var root types.Hash
+	if number == 0 || !config.IsStateCommitment(number-1) {
+		generated, err := state.GenerateStateCommitment(tx, rules)
+		if err != nil {
+			return nil, err
+		}
+		if root, err = generated.Commit(tx); err != nil {
+			return nil, err
+		}
+	} else {
+		parent := rawdb.ReadHeader(tx, header.ParentHash, number-1)
+		if parent == nil {
+			return nil, fmt.Errorf("unknown parent %x of block %d", header.ParentHash, number)
+		}
+		root = parent.Root
+	}
+	return state.NewWitnessStateCommitment(tx, root, rules)
+}
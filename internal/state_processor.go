@@ -28,9 +28,11 @@ import (
 	vm2 "github.com/amazechain/amc/internal/vm"
 	"github.com/amazechain/amc/internal/vm/evmtypes"
 	"github.com/amazechain/amc/modules/ethdb"
+	"github.com/amazechain/amc/modules/rawdb"
 	"github.com/amazechain/amc/modules/state"
 	"github.com/amazechain/amc/params"
 	"github.com/ledgerwatch/erigon-lib/kv"
+	"time"
 )
 
 // StateProcessor is a basic Processor, which takes care of transitioning
@@ -59,7 +61,7 @@ func NewStateProcessor(config *params.ChainConfig, bc *BlockChain, engine consen
 // Process returns the receipts and logs accumulated during the process and
 // returns the amount of gas that was used in the process. If any of the
 // transactions failed to execute due to insufficient gas it will return an error.
-func (p *StateProcessor) Process(tx kv.RwTx, b *block.Block, ibs *state.IntraBlockState, stateReader state.StateReader, stateWriter state.WriterWithChangeSets, blockHashFunc func(n uint64) types.Hash) (block.Receipts, []*block.Log, uint64, error) {
+func (p *StateProcessor) Process(tx kv.RwTx, b *block.Block, ibs *state.IntraBlockState, stateReader state.StateReader, stateWriter state.WriterWithChangeSets, blockHashFunc func(n uint64) types.Hash, stats *BlockStats) (block.Receipts, []*block.Log, uint64, error) {
 	header := b.Header()
 	usedGas := new(uint64)
 	gp := new(common.GasPool)
@@ -72,7 +74,12 @@ func (p *StateProcessor) Process(tx kv.RwTx, b *block.Block, ibs *state.IntraBlo
 	)
 
 	chainReader := p.bc
-	cfg := vm2.Config{}
+	// The call tracer feeds the CallFromIndex/CallToIndex used by the trace_ namespace.
+	// It only needs the frames, so the per-opcode hooks stay off during import. The
+	// native callTracer keeps inputs, outputs and the whole call tree, which trace_
+	// rebuilds on demand instead.
+	callTracer := vm2.NewCallTracer()
+	cfg := vm2.Config{Debug: true, Tracer: callTracer, FramesOnly: true}
 
 	//if !cfg.ReadOnly {
 	//	if err := InitializeBlockExecution(p.engine, chainReader, b.Header().(*block.Header), b.Transactions(), b.Uncles(), params.AmazeChainConfig, ibs); err != nil {
@@ -102,6 +109,7 @@ func (p *StateProcessor) Process(tx kv.RwTx, b *block.Block, ibs *state.IntraBlo
 	}
 	noop := state.NewNoopWriter()
 
+	execStart := time.Now()
 	//posa, isPoSA := p.engine.(*apoa.Apoa)
 	for i, tx := range b.Transactions() {
 		//if isPoSA {
@@ -126,15 +134,26 @@ func (p *StateProcessor) Process(tx kv.RwTx, b *block.Block, ibs *state.IntraBlo
 		}
 	}
 
+	if stats != nil {
+		stats.Execution += time.Since(execStart)
+	}
+
 	if !cfg.StatelessExec && *usedGas != header.(*block.Header).GasUsed {
 		return nil, nil, 0, fmt.Errorf("gas used by execution: %d, in header: %d", *usedGas, header.(*block.Header).GasUsed)
 	}
 
 	if !cfg.ReadOnly {
 		txs := b.Transactions()
-		if _, _, _, err := FinalizeBlockExecution(tx, p.engine, b.Header().(*block.Header), txs, stateWriter, chainConfig, ibs, receipts, chainReader, false, p.config.IsBeijing(b.Number64().Uint64())); err != nil {
+		if _, _, _, err := FinalizeBlockExecution(tx, p.engine, b.Header().(*block.Header), txs, stateWriter, chainConfig, ibs, receipts, chainReader, false, p.config.IsBeijing(b.Number64().Uint64()), stats); err != nil {
 			return nil, nil, 0, err
 		}
+		traceStart := time.Now()
+		if err := writeCallTraceSet(tx, b.Number64().Uint64(), b.Hash(), callTracer); err != nil {
+			return nil, nil, 0, err
+		}
+		if stats != nil {
+			stats.StateWrite += time.Since(traceStart)
+		}
 	}
 	allLogs := ibs.Logs()
 
@@ -268,3 +287,15 @@ func NewStateReaderWriter(
 	}
 	return stateReader, stateWriter, nil
 }
+
+// writeCallTraceSet stores the accounts touched by the call frames of a block.
+func writeCallTraceSet(tx kv.RwTx, number uint64, hash types.Hash, tracer *vm2.CallTracer) error {
+	touched := make(map[types.Address]byte, len(tracer.Froms())+len(tracer.Tos()))
+	for addr := range tracer.Froms() {
+		touched[addr] |= rawdb.CallTraceFrom
+	}
+	for addr := range tracer.Tos() {
+		touched[addr] |= rawdb.CallTraceTo
+	}
+	return rawdb.WriteCallTraceSet(tx, number, hash, touched)
+}
@@ -0,0 +1,412 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/RoaringBitmap/roaring"
+	types "github.com/amazechain/amc/common/block"
+	"github.com/amazechain/amc/common/hexutil"
+	common "github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/internal/api"
+	"github.com/amazechain/amc/modules"
+	"github.com/amazechain/amc/modules/rawdb"
+	rpc "github.com/amazechain/amc/modules/rpc/jsonrpc"
+	"github.com/ledgerwatch/erigon-lib/kv"
+)
+
+const (
+	// flatCallTracerName is the native tracer producing Parity style traces.
+	flatCallTracerName = "flatCallTracer"
+
+	// TraceTypeTrace is the only Parity trace type served by the trace_ namespace.
+	TraceTypeTrace = "trace"
+)
+
+var errUnsupportedTraceType = errors.New("only the \"trace\" trace type is supported")
+
+// TraceAPI is the Parity/OpenEthereum style trace_ namespace. Traces are produced
+// by replaying blocks with the flatCallTracer; trace_filter narrows down the blocks
+// to replay through the CallFromIndex and CallToIndex tables.
+type TraceAPI struct {
+	api *API
+}
+
+// NewTraceAPI creates a new API definition for the trace_ methods.
+func NewTraceAPI(backend Backend) *TraceAPI {
+	return &TraceAPI{api: NewAPI(backend)}
+}
+
+// TraceFilterRequest is the argument of trace_filter.
+type TraceFilterRequest struct {
+	FromBlock   *hexutil.Uint64  `json:"fromBlock"`
+	ToBlock     *hexutil.Uint64  `json:"toBlock"`
+	FromAddress []common.Address `json:"fromAddress"`
+	ToAddress   []common.Address `json:"toAddress"`
+	After       *uint64          `json:"after"`
+	Count       *uint64          `json:"count"`
+}
+
+// TraceResult is the result of trace_call and of every transaction of
+// trace_replayBlockTransactions.
+type TraceResult struct {
+	Output          hexutil.Bytes     `json:"output"`
+	StateDiff       interface{}       `json:"stateDiff"`
+	Trace           []json.RawMessage `json:"trace"`
+	VmTrace         interface{}       `json:"vmTrace"`
+	TransactionHash *common.Hash      `json:"transactionHash,omitempty"`
+}
+
+// flatTrace holds the fields of a flatCallTracer frame needed for filtering.
+type flatTrace struct {
+	Action struct {
+		From          *common.Address `json:"from"`
+		To            *common.Address `json:"to"`
+		Address       *common.Address `json:"address"`
+		RefundAddress *common.Address `json:"refundAddress"`
+	} `json:"action"`
+	Result *struct {
+		Address *common.Address `json:"address"`
+		Code    hexutil.Bytes   `json:"code"`
+		Output  hexutil.Bytes   `json:"output"`
+	} `json:"result"`
+}
+
+// from returns the sender of the frame, or the destructed account of a suicide.
+func (t *flatTrace) from() *common.Address {
+	if t.Action.From != nil {
+		return t.Action.From
+	}
+	return t.Action.Address
+}
+
+// to returns the callee of the frame, the created contract or the suicide beneficiary.
+func (t *flatTrace) to() *common.Address {
+	if t.Action.To != nil {
+		return t.Action.To
+	}
+	if t.Result != nil && t.Result.Address != nil {
+		return t.Result.Address
+	}
+	return t.Action.RefundAddress
+}
+
+func flatTraceConfig() *TraceConfig {
+	tracer := flatCallTracerName
+	return &TraceConfig{
+		Tracer:       &tracer,
+		TracerConfig: json.RawMessage(`{"convertParityErrors":true}`),
+	}
+}
+
+// decodeFlatTraces splits the output of a flatCallTracer run into its frames.
+func decodeFlatTraces(result interface{}) ([]json.RawMessage, error) {
+	raw, ok := result.(json.RawMessage)
+	if !ok {
+		return nil, fmt.Errorf("unexpected tracer result %T", result)
+	}
+	var frames []json.RawMessage
+	if err := json.Unmarshal(raw, &frames); err != nil {
+		return nil, err
+	}
+	return frames, nil
+}
+
+// traceOutput returns the return data (or deployed code) of the top frame.
+func traceOutput(frames []json.RawMessage) (hexutil.Bytes, error) {
+	if len(frames) == 0 {
+		return hexutil.Bytes{}, nil
+	}
+	var top flatTrace
+	if err := json.Unmarshal(frames[0], &top); err != nil {
+		return nil, err
+	}
+	if top.Result == nil {
+		return hexutil.Bytes{}, nil
+	}
+	if top.Result.Code != nil {
+		return top.Result.Code, nil
+	}
+	return top.Result.Output, nil
+}
+
+func checkTraceTypes(traceTypes []string) error {
+	for _, typ := range traceTypes {
+		if typ != TraceTypeTrace {
+			return errUnsupportedTraceType
+		}
+	}
+	return nil
+}
+
+// blockTraces replays a block and returns the flat traces of all its transactions.
+func (t *TraceAPI) blockTraces(ctx context.Context, block *types.Block) ([]json.RawMessage, error) {
+	if block.Number64().Uint64() == 0 {
+		return nil, nil
+	}
+	results, err := t.api.traceBlock(ctx, block, flatTraceConfig())
+	if err != nil {
+		return nil, err
+	}
+	var traces []json.RawMessage
+	for _, res := range results {
+		frames, err := decodeFlatTraces(res.Result)
+		if err != nil {
+			return nil, err
+		}
+		traces = append(traces, frames...)
+	}
+	return traces, nil
+}
+
+// Block returns the traces of all transactions of the given block.
+func (t *TraceAPI) Block(ctx context.Context, number rpc.BlockNumber) ([]json.RawMessage, error) {
+	block, err := t.api.blockByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	return t.blockTraces(ctx, block)
+}
+
+// Transaction returns the traces of the given transaction.
+func (t *TraceAPI) Transaction(ctx context.Context, hash common.Hash) ([]json.RawMessage, error) {
+	res, err := t.api.TraceTransaction(ctx, hash, flatTraceConfig())
+	if err != nil {
+		return nil, err
+	}
+	return decodeFlatTraces(res)
+}
+
+// ReplayBlockTransactions replays all transactions of a block and returns their traces.
+func (t *TraceAPI) ReplayBlockTransactions(ctx context.Context, number rpc.BlockNumber, traceTypes []string) ([]*TraceResult, error) {
+	if err := checkTraceTypes(traceTypes); err != nil {
+		return nil, err
+	}
+	block, err := t.api.blockByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	if block.Number64().Uint64() == 0 {
+		return nil, errors.New("genesis is not traceable")
+	}
+	results, err := t.api.traceBlock(ctx, block, flatTraceConfig())
+	if err != nil {
+		return nil, err
+	}
+	replays := make([]*TraceResult, len(results))
+	for i, res := range results {
+		frames, err := decodeFlatTraces(res.Result)
+		if err != nil {
+			return nil, err
+		}
+		output, err := traceOutput(frames)
+		if err != nil {
+			return nil, err
+		}
+		hash := block.Transactions()[i].Hash()
+		replays[i] = &TraceResult{Output: output, Trace: frames, TransactionHash: &hash}
+	}
+	return replays, nil
+}
+
+// Call executes the given call on top of a block and returns its traces.
+func (t *TraceAPI) Call(ctx context.Context, args api.TransactionArgs, traceTypes []string, blockNrOrHash *rpc.BlockNumberOrHash) (*TraceResult, error) {
+	if err := checkTraceTypes(traceTypes); err != nil {
+		return nil, err
+	}
+	bNrOrHash := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	if blockNrOrHash != nil {
+		bNrOrHash = *blockNrOrHash
+	}
+	res, err := t.api.TraceCall(ctx, args, bNrOrHash, &TraceCallConfig{TraceConfig: *flatTraceConfig()})
+	if err != nil {
+		return nil, err
+	}
+	frames, err := decodeFlatTraces(res)
+	if err != nil {
+		return nil, err
+	}
+	output, err := traceOutput(frames)
+	if err != nil {
+		return nil, err
+	}
+	return &TraceResult{Output: output, Trace: frames}, nil
+}
+
+// Filter returns the traces matching the given sender and recipient addresses
+// within a block range.
+func (t *TraceAPI) Filter(ctx context.Context, req TraceFilterRequest) ([]json.RawMessage, error) {
+	head, err := t.api.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if head == nil {
+		return nil, errors.New("unknown head block")
+	}
+	var (
+		from = uint64(0)
+		to   = head.Number64().Uint64()
+	)
+	if req.FromBlock != nil {
+		from = uint64(*req.FromBlock)
+	}
+	if req.ToBlock != nil {
+		to = uint64(*req.ToBlock)
+	}
+	if from > to {
+		return nil, fmt.Errorf("invalid block range: %d > %d", from, to)
+	}
+
+	blocks, err := t.filterBlocks(ctx, req, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		fromAddrs = make(map[common.Address]struct{}, len(req.FromAddress))
+		toAddrs   = make(map[common.Address]struct{}, len(req.ToAddress))
+		skipped   uint64
+		traces    = []json.RawMessage{}
+	)
+	for _, addr := range req.FromAddress {
+		fromAddrs[addr] = struct{}{}
+	}
+	for _, addr := range req.ToAddress {
+		toAddrs[addr] = struct{}{}
+	}
+	matches := func(addrs map[common.Address]struct{}, addr *common.Address) bool {
+		if len(addrs) == 0 {
+			return true
+		}
+		if addr == nil {
+			return false
+		}
+		_, ok := addrs[*addr]
+		return ok
+	}
+
+	it := blocks.Iterator()
+	for it.HasNext() {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+		block, err := t.api.blockByNumber(ctx, rpc.BlockNumber(it.Next()))
+		if err != nil {
+			return nil, err
+		}
+		frames, err := t.blockTraces(ctx, block)
+		if err != nil {
+			return nil, err
+		}
+		for _, frame := range frames {
+			var trace flatTrace
+			if err := json.Unmarshal(frame, &trace); err != nil {
+				return nil, err
+			}
+			if !matches(fromAddrs, trace.from()) || !matches(toAddrs, trace.to()) {
+				continue
+			}
+			if req.After != nil && skipped < *req.After {
+				skipped++
+				continue
+			}
+			traces = append(traces, frame)
+			if req.Count != nil && uint64(len(traces)) >= *req.Count {
+				return traces, nil
+			}
+		}
+	}
+	return traces, nil
+}
+
+// filterBlocks returns the blocks in [from, to] that may hold traces matching the
+// request. Blocks imported before the call trace index existed are always included.
+func (t *TraceAPI) filterBlocks(ctx context.Context, req TraceFilterRequest, from, to uint64) (*roaring.Bitmap, error) {
+	blocks := roaring.New()
+	if from == 0 {
+		from = 1 // genesis is not traceable
+	}
+	if from > to {
+		return blocks, nil
+	}
+	if len(req.FromAddress) == 0 && len(req.ToAddress) == 0 {
+		blocks.AddRange(from, to+1)
+		return blocks, nil
+	}
+
+	err := t.api.backend.ChainDb().View(ctx, func(tx kv.Tx) error {
+		indexFrom, ok, err := rawdb.ReadCallIndexFrom(tx)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			indexFrom = to + 1
+		}
+		if from < indexFrom {
+			blocks.AddRange(from, minUint64(indexFrom, to+1))
+			from = indexFrom
+		}
+		if from > to {
+			return nil
+		}
+
+		union := func(table string, addrs []common.Address) (*roaring.Bitmap, error) {
+			var bitmaps []*roaring.Bitmap
+			for _, addr := range addrs {
+				bm, err := rawdb.ReadCallIndex(tx, table, addr, from, to)
+				if err != nil {
+					return nil, err
+				}
+				bitmaps = append(bitmaps, bm)
+			}
+			return roaring.FastOr(bitmaps...), nil
+		}
+		var indexed *roaring.Bitmap
+		if len(req.FromAddress) > 0 {
+			if indexed, err = union(modules.CallFromIndex, req.FromAddress); err != nil {
+				return err
+			}
+		}
+		if len(req.ToAddress) > 0 {
+			bm, err := union(modules.CallToIndex, req.ToAddress)
+			if err != nil {
+				return err
+			}
+			if indexed == nil {
+				indexed = bm
+			} else {
+				indexed.And(bm)
+			}
+		}
+		blocks.Or(indexed)
+		return nil
+	})
+	return blocks, err
+}
+
+func minUint64(a, b uint64) uint64 {
+	if a < b {
+		return a
+	}
+	return b
+}
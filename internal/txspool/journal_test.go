@@ -0,0 +1,76 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package txspool
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/amazechain/amc/common/transaction"
+	"github.com/amazechain/amc/common/types"
+	"github.com/holiman/uint256"
+)
+
+func TestTxJournalRoundTrip(t *testing.T) {
+	var (
+		path    = filepath.Join(t.TempDir(), "transactions.journal")
+		from    = types.HexToAddress("0x1000000000000000000000000000000000000001")
+		to      = types.HexToAddress("0x2000000000000000000000000000000000000002")
+		journal = newTxJournal(path)
+	)
+	var txs []*transaction.Transaction
+	for i := uint64(0); i < 3; i++ {
+		txs = append(txs, transaction.NewTransaction(i, from, &to, uint256.NewInt(i), 21000, uint256.NewInt(1), []byte{byte(i)}))
+	}
+	// Writing without an open journal must fail
+	if err := journal.insert(txs[0]); err != errNoActiveJournal {
+		t.Fatalf("insert without journal: have %v, want %v", err, errNoActiveJournal)
+	}
+	// Rotate in the first transaction and append the rest
+	if err := journal.rotate(map[types.Address][]*transaction.Transaction{from: txs[:1]}); err != nil {
+		t.Fatalf("rotate failed: %v", err)
+	}
+	for _, tx := range txs[1:] {
+		if err := journal.insert(tx); err != nil {
+			t.Fatalf("insert failed: %v", err)
+		}
+	}
+	if err := journal.close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+	// Replay and check everything came back in order
+	var loaded []*transaction.Transaction
+	err := newTxJournal(path).load(func(batch []*transaction.Transaction) []error {
+		loaded = append(loaded, batch...)
+		return make([]error, len(batch))
+	})
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if len(loaded) != len(txs) {
+		t.Fatalf("loaded transaction count mismatch: have %d, want %d", len(loaded), len(txs))
+	}
+	for i, tx := range loaded {
+		if tx.Hash() != txs[i].Hash() {
+			t.Errorf("tx %d: hash mismatch: have %v, want %v", i, tx.Hash(), txs[i].Hash())
+		}
+	}
+	// A missing journal is not an error
+	if err := newTxJournal(path + ".missing").load(nil); err != nil {
+		t.Fatalf("load of missing journal failed: %v", err)
+	}
+}
@@ -41,6 +41,7 @@ import (
 	"github.com/amazechain/amc/common/types"
 	"github.com/amazechain/amc/log"
 	event "github.com/amazechain/amc/modules/event/v2"
+	"github.com/rcrowley/go-metrics"
 )
 
 const (
@@ -79,13 +80,22 @@ var (
 	ErrTipAboveFeeCap = fmt.Errorf("max priority fee per gas higher than max fee per gas")
 )
 
+var (
+	pendingGauge = metrics.GetOrRegisterGauge("txpool/pending", nil)
+	queuedGauge  = metrics.GetOrRegisterGauge("txpool/queued", nil)
+	localGauge   = metrics.GetOrRegisterGauge("txpool/local", nil)
+	slotsGauge   = metrics.GetOrRegisterGauge("txpool/slots", nil)
+)
+
 type txspoolResetRequest struct {
 	oldBlock, newBlock block.IBlock
 }
 
 type TxsPoolConfig struct {
-	Locals   []types.Address
-	NoLocals bool
+	Locals    []types.Address // Addresses that should be treated by default as local
+	NoLocals  bool            // Whether local transaction handling should be disabled
+	Journal   string          // Journal of local transactions to survive node restarts
+	Rejournal time.Duration   // Time interval to regenerate the local transaction journal
 
 	PriceLimit uint64
 	PriceBump  uint64
@@ -100,6 +110,8 @@ type TxsPoolConfig struct {
 
 // DefaultTxPoolConfig default blockchain
 var DefaultTxPoolConfig = TxsPoolConfig{
+	Journal:   "transactions.journal",
+	Rejournal: time.Hour,
 
 	PriceLimit: 1,
 	PriceBump:  10,
@@ -112,6 +124,45 @@ var DefaultTxPoolConfig = TxsPoolConfig{
 	Lifetime: 3 * time.Hour,
 }
 
+// sanitize checks the provided user configurations and changes anything that's
+// unreasonable or unworkable.
+func (config *TxsPoolConfig) sanitize() TxsPoolConfig {
+	conf := *config
+	if conf.Rejournal < time.Second {
+		log.Warn("Sanitizing invalid txpool journal time", "provided", conf.Rejournal, "updated", time.Second)
+		conf.Rejournal = time.Second
+	}
+	if conf.PriceLimit < 1 {
+		log.Warn("Sanitizing invalid txpool price limit", "provided", conf.PriceLimit, "updated", DefaultTxPoolConfig.PriceLimit)
+		conf.PriceLimit = DefaultTxPoolConfig.PriceLimit
+	}
+	if conf.PriceBump < 1 {
+		log.Warn("Sanitizing invalid txpool price bump", "provided", conf.PriceBump, "updated", DefaultTxPoolConfig.PriceBump)
+		conf.PriceBump = DefaultTxPoolConfig.PriceBump
+	}
+	if conf.AccountSlots < 1 {
+		log.Warn("Sanitizing invalid txpool account slots", "provided", conf.AccountSlots, "updated", DefaultTxPoolConfig.AccountSlots)
+		conf.AccountSlots = DefaultTxPoolConfig.AccountSlots
+	}
+	if conf.GlobalSlots < 1 {
+		log.Warn("Sanitizing invalid txpool global slots", "provided", conf.GlobalSlots, "updated", DefaultTxPoolConfig.GlobalSlots)
+		conf.GlobalSlots = DefaultTxPoolConfig.GlobalSlots
+	}
+	if conf.AccountQueue < 1 {
+		log.Warn("Sanitizing invalid txpool account queue", "provided", conf.AccountQueue, "updated", DefaultTxPoolConfig.AccountQueue)
+		conf.AccountQueue = DefaultTxPoolConfig.AccountQueue
+	}
+	if conf.GlobalQueue < 1 {
+		log.Warn("Sanitizing invalid txpool global queue", "provided", conf.GlobalQueue, "updated", DefaultTxPoolConfig.GlobalQueue)
+		conf.GlobalQueue = DefaultTxPoolConfig.GlobalQueue
+	}
+	if conf.Lifetime < 1 {
+		log.Warn("Sanitizing invalid txpool lifetime", "provided", conf.Lifetime, "updated", DefaultTxPoolConfig.Lifetime)
+		conf.Lifetime = DefaultTxPoolConfig.Lifetime
+	}
+	return conf
+}
+
 type TxsPool struct {
 	config      TxsPoolConfig
 	chainconfig *params.ChainConfig
@@ -133,6 +184,7 @@ type TxsPool struct {
 	shanghai bool // Fork indicator whether we are in the Shanghai stage.
 
 	locals   *accountSet
+	journal  *txJournal // Journal of local transaction to back up to disk
 	pending  map[types.Address]*txsList
 	queue    map[types.Address]*txsList
 	beats    map[types.Address]time.Time
@@ -152,14 +204,16 @@ type TxsPool struct {
 	isRun uint32
 }
 
-func NewTxsPool(ctx context.Context, bc common.IBlockChain) (txs_pool.ITxsPool, error) {
+func NewTxsPool(ctx context.Context, config TxsPoolConfig, bc common.IBlockChain) (txs_pool.ITxsPool, error) {
+	// Sanitize the input to ensure no vulnerable gas prices are set
+	config = (&config).sanitize()
 
 	c, cancel := context.WithCancel(ctx)
 	// for test
 	//log.Init(nil)
 	pool := &TxsPool{
 		chainconfig: bc.Config(),
-		config:      DefaultTxPoolConfig,
+		config:      config,
 		ctx:         c,
 		cancel:      cancel,
 
@@ -180,7 +234,11 @@ func NewTxsPool(ctx context.Context, bc common.IBlockChain) (txs_pool.ITxsPool,
 		queueTxEventCh:  make(chan *transaction.Transaction),
 		reorgDoneCh:     make(chan chan struct{}),
 		reorgShutdownCh: make(chan struct{}),
-		gasPrice:        uint256.NewInt(DefaultTxPoolConfig.PriceLimit),
+		gasPrice:        uint256.NewInt(config.PriceLimit),
+	}
+	for _, addr := range config.Locals {
+		log.Info("Setting new local account", "address", addr)
+		pool.locals.add(addr)
 	}
 
 	//
@@ -189,21 +247,31 @@ func NewTxsPool(ctx context.Context, bc common.IBlockChain) (txs_pool.ITxsPool,
 	pool.priced = newTxPricedList(pool.all)
 	pool.reset(nil, bc.CurrentBlock())
 
+	// If local transactions and journaling is enabled, load from disk before
+	// the scheduler starts.
+	if !config.NoLocals && config.Journal != "" {
+		pool.journal = newTxJournal(config.Journal)
+
+		if err := pool.loadJournal(); err != nil {
+			log.Warn("Failed to load transaction journal", "err", err)
+		}
+		pool.mu.Lock()
+		if err := pool.journal.rotate(pool.local()); err != nil {
+			log.Warn("Failed to rotate transaction journal", "err", err)
+		}
+		pool.mu.Unlock()
+	}
+
 	pool.wg.Add(1)
 	go pool.scheduleLoop()
 
 	pool.wg.Add(1)
 	go pool.blockChangeLoop()
 
-	//todo for test
-	//pool.wg.Add(1)
-	//go pool.ethFetchTxPoolLoop()
-
-	//pool.wg.Add(1)
-	//go pool.ethImportTxPoolLoop()
-
-	pool.wg.Add(1)
-	//go pool.ethTxPoolCheckLoop()
+	if pool.journal != nil {
+		pool.wg.Add(1)
+		go pool.journalLoop()
+	}
 
 	return pool, nil
 }
@@ -315,6 +383,25 @@ func (pool *TxsPool) addTxs(txs []*transaction.Transaction, local, sync bool) []
 	return errs
 }
 
+// loadJournal replays the journaled local transactions into the pool and
+// promotes their senders. It runs before the scheduler starts: nothing is
+// pending yet, so the replay only fills the queue and needs no reorg.
+func (pool *TxsPool) loadJournal() error {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	dirty := newAccountSet()
+	err := pool.journal.load(func(txs []*transaction.Transaction) []error {
+		errs, set := pool.addTxsLocked(txs, true)
+		dirty.merge(set)
+		return errs
+	})
+	pool.promoteExecutables(dirty.flatten())
+	pool.truncatePending()
+	pool.truncateQueue()
+	return err
+}
+
 // addTxsLocked attempts to queue a batch of transactions if they are valid.
 // The transaction pool lock must be held.
 func (pool *TxsPool) addTxsLocked(txs []*transaction.Transaction, local bool) ([]error, *accountSet) {
@@ -460,6 +547,7 @@ func (pool *TxsPool) add(tx *transaction.Transaction, local bool) (replaced bool
 		}
 		pool.all.Add(tx, isLocal)
 		pool.priced.Put(tx, isLocal)
+		pool.journalTx(from, tx)
 		pool.queueTxEvent(tx)
 		log.Debug("Pooled new executable transaction", "hash", hash, "from", from, "to", tx.To)
 
@@ -478,6 +566,7 @@ func (pool *TxsPool) add(tx *transaction.Transaction, local bool) (replaced bool
 		pool.locals.add(from)
 		pool.priced.Removed(pool.all.RemoteToLocals(pool.locals)) // Migrate the remotes if it's marked as local first time.
 	}
+	pool.journalTx(from, tx)
 
 	//log.Debug("Pooled new future transaction", "hash", hash, "from", from, "to", tx.To)
 	return replaced, nil
@@ -611,6 +700,13 @@ func (pool *TxsPool) requestReset(oldBlock block.IBlock, newBlock block.IBlock)
 	}
 }
 
+// ResetHead resets the pool from oldBlock to the current chain head and waits
+// for the reset to finish. It is used by callers that write blocks without
+// going through the sync service, such as the simulated backend.
+func (pool *TxsPool) ResetHead(oldBlock block.IBlock) {
+	<-pool.requestReset(oldBlock, pool.bc.CurrentBlock())
+}
+
 // requestPromoteExecutables requests transaction promotion checks for the given addresses.
 // The returned channel is closed when the promotion checks have occurred.
 func (pool *TxsPool) requestPromoteExecutables(set *accountSet) <-chan struct{} {
@@ -1024,6 +1120,7 @@ func (pool *TxsPool) runReorg(done chan struct{}, reset *txspoolResetRequest, di
 	pool.truncateQueue()
 
 	pool.changesSinceReorg = 0 // Reset change counter
+	pool.updateGauges()
 	pool.mu.Unlock()
 
 	// Notify subsystems for newly added transactions
@@ -1148,10 +1245,84 @@ func (pool *TxsPool) blockChangeLoop() {
 	}
 }
 
+// updateGauges refreshes the pool size metrics.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxsPool) updateGauges() {
+	var pending, queued int
+	for _, list := range pool.pending {
+		pending += list.Len()
+	}
+	for _, list := range pool.queue {
+		queued += list.Len()
+	}
+	pendingGauge.Update(int64(pending))
+	queuedGauge.Update(int64(queued))
+	localGauge.Update(int64(pool.all.LocalCount()))
+	slotsGauge.Update(int64(pool.all.Slots()))
+}
+
+// journalLoop periodically regenerates the local transaction journal and
+// closes it once the pool is shut down.
+func (pool *TxsPool) journalLoop() {
+	defer pool.wg.Done()
+
+	journal := time.NewTicker(pool.config.Rejournal)
+	defer journal.Stop()
+
+	for {
+		select {
+		case <-pool.ctx.Done():
+			return
+		case <-journal.C:
+			pool.mu.Lock()
+			if err := pool.journal.rotate(pool.local()); err != nil {
+				log.Warn("Failed to rotate local tx journal", "err", err)
+			}
+			pool.mu.Unlock()
+		}
+	}
+}
+
+// journalTx adds the specified transaction to the local disk journal if it is
+// deemed to have been sent from a local account.
+func (pool *TxsPool) journalTx(from types.Address, tx *transaction.Transaction) {
+	// Only journal if it's enabled and the transaction is local
+	if pool.journal == nil || !pool.locals.contains(from) {
+		return
+	}
+	if err := pool.journal.insert(tx); err != nil {
+		log.Warn("Failed to journal local transaction", "err", err)
+	}
+}
+
+// local retrieves all currently known local transactions, grouped by origin
+// account and sorted by nonce. The returned transaction set is a copy and can be
+// freely modified by calling code.
+//
+// Note, this method assumes the pool lock is held!
+func (pool *TxsPool) local() map[types.Address][]*transaction.Transaction {
+	txs := make(map[types.Address][]*transaction.Transaction)
+	for addr := range pool.locals.accounts {
+		if pending := pool.pending[addr]; pending != nil {
+			txs[addr] = append(txs[addr], pending.Flatten()...)
+		}
+		if queued := pool.queue[addr]; queued != nil {
+			txs[addr] = append(txs[addr], queued.Flatten()...)
+		}
+	}
+	return txs
+}
+
 // Stop terminates the transaction pool.
 func (pool *TxsPool) Stop() {
 	pool.cancel()
 	pool.wg.Wait()
+	pool.releaseState()
+
+	if pool.journal != nil {
+		pool.journal.close()
+	}
 	log.Info("Transaction pool stopped")
 }
 
@@ -1277,14 +1448,7 @@ func (pool *TxsPool) Stats() (int, int, int, int) {
 }
 
 func (pool *TxsPool) ResetState(blockHash types.Hash) error {
-	if pool.currentState != nil {
-		reader := pool.currentState.GetStateReader()
-		if reader != nil {
-			if hreader, ok := reader.(*state.HistoryStateReader); ok {
-				hreader.Rollback()
-			}
-		}
-	}
+	pool.releaseState()
 
 	tx, err := pool.bc.DB().BeginRo(pool.ctx)
 	if nil != err {
@@ -1292,9 +1456,20 @@ func (pool *TxsPool) ResetState(blockHash types.Hash) error {
 	}
 	blockNr := rawdb.ReadHeaderNumber(tx, blockHash)
 	if nil == blockNr {
+		tx.Rollback()
 		return fmt.Errorf("invaild block hash")
 	}
 	stateReader := state.NewStateHistoryReader(tx, tx, *blockNr)
 	pool.currentState = state.New(stateReader)
 	return nil
 }
+
+// releaseState rolls back the read transaction held by the current state.
+func (pool *TxsPool) releaseState() {
+	if pool.currentState == nil {
+		return
+	}
+	if hreader, ok := pool.currentState.GetStateReader().(*state.HistoryStateReader); ok {
+		hreader.Rollback()
+	}
+}
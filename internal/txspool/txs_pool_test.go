@@ -0,0 +1,117 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package txspool
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/amazechain/amc/common/block"
+	"github.com/amazechain/amc/common/transaction"
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/conf"
+	"github.com/amazechain/amc/internal"
+	"github.com/amazechain/amc/internal/consensus/apos"
+	"github.com/amazechain/amc/modules"
+	"github.com/amazechain/amc/params"
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+)
+
+// Tests that the local transactions of a pool are journaled and replayed into the
+// pool started after it.
+func TestJournalReplay(t *testing.T) {
+	modules.AmcInit()
+	kv.ChaindataTablesCfg = modules.AmcTableCfg
+	db := memdb.New(t.TempDir())
+	defer db.Close()
+
+	var (
+		from   = types.HexToAddress("0x1000000000000000000000000000000000000001")
+		to     = types.HexToAddress("0x2000000000000000000000000000000000000002")
+		config = &params.ChainConfig{ChainID: big.NewInt(1337), Consensus: params.Faker, BerlinBlock: big.NewInt(0), LondonBlock: big.NewInt(0)}
+	)
+	genesis := &internal.GenesisBlock{
+		GenesisBlockConfig: &conf.GenesisBlockConfig{
+			Config:   config,
+			GasLimit: 30_000_000,
+			Engine:   &conf.ConsensusConfig{EngineName: "APosEngine", GasCeil: 30_000_000},
+			Alloc:    []conf.Allocate{{Address: "AMC" + hex.EncodeToString(from[:]), Balance: "1000000000000000000000"}},
+		},
+	}
+	var genesisBlock *block.Block
+	if err := db.Update(context.Background(), func(tx kv.RwTx) error {
+		var err error
+		genesisBlock, _, err = genesis.Write(tx)
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	bc, err := internal.NewBlockChain(ctx, genesisBlock, apos.NewFaker(), nil, db, nil, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	poolConfig := DefaultTxPoolConfig
+	poolConfig.Journal = filepath.Join(t.TempDir(), "transactions.journal")
+	newPool := func() *TxsPool {
+		t.Helper()
+		pool, err := NewTxsPool(ctx, poolConfig, bc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pool.(*TxsPool)
+	}
+
+	// Add a pending and a queued local transaction, and replace the pending one
+	pool := newPool()
+	txs := []*transaction.Transaction{
+		transaction.NewTransaction(0, from, &to, uint256.NewInt(1), 21000, uint256.NewInt(10*params.GWei), nil),
+		transaction.NewTransaction(2, from, &to, uint256.NewInt(1), 21000, uint256.NewInt(10*params.GWei), nil),
+		transaction.NewTransaction(0, from, &to, uint256.NewInt(1), 22000, uint256.NewInt(20*params.GWei), nil),
+	}
+	errs := pool.AddLocals(txs)
+	pool.Stop()
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("tx %d: %v", i, err)
+		}
+	}
+
+	// Restart the pool and check the locals are back where they were
+	pool = newPool()
+	defer pool.Stop()
+	if pending, _, queued, _ := pool.Stats(); pending != 1 || queued != 1 {
+		t.Fatalf("replayed accounts: have %d pending, %d queued, want 1 and 1", pending, queued)
+	}
+	pending, queued := pool.Content()
+	if len(pending[from]) != 1 || pending[from][0].Hash() != txs[2].Hash() {
+		t.Fatalf("pending transactions not replayed: %v", pending[from])
+	}
+	if len(queued[from]) != 1 || queued[from][0].Hash() != txs[1].Hash() {
+		t.Fatalf("queued transactions not replayed: %v", queued[from])
+	}
+	if !pool.locals.contains(from) {
+		t.Fatalf("sender of replayed transactions not local")
+	}
+}
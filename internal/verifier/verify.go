@@ -14,15 +14,19 @@
 // You should have received a copy of the GNU Lesser General Public License
 // along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
 
-package evmsdk
+// Package verifier re-executes the blocks pushed by a mining node to its
+// verifiers and computes the state root they sign.
+package verifier
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"unsafe"
 
 	common2 "github.com/amazechain/amc/common"
 	block2 "github.com/amazechain/amc/common/block"
+	"github.com/amazechain/amc/common/crypto"
 	"github.com/amazechain/amc/common/transaction"
 	"github.com/amazechain/amc/common/types"
 	"github.com/amazechain/amc/internal"
@@ -32,9 +36,24 @@ import (
 	"github.com/amazechain/amc/modules/ethdb/olddb"
 	"github.com/amazechain/amc/modules/state"
 	"github.com/amazechain/amc/params"
+	"golang.org/x/crypto/sha3"
 )
 
-func verify(ctx context.Context, msg *state.EntireCode) types.Hash {
+// Verify executes the block of msg on the state snapshot it carries and returns
+// the resulting state root.
+func Verify(ctx context.Context, chainConfig *params.ChainConfig, msg *state.EntireCode) (types.Hash, error) {
+	if msg.Entire.Header == nil {
+		return types.Hash{}, errors.New("missing block header")
+	}
+	// the header commits to the state the block is executed on
+	var hash types.Hash
+	hasher := sha3.NewLegacyKeccak256()
+	state.EncodeBeforeState(hasher, msg.Entire.Snap.Items, msg.Codes)
+	hasher.(crypto.KeccakState).Read(hash[:])
+	if msg.Entire.Header.MixDigest != hash {
+		return types.Hash{}, fmt.Errorf("state hash mismatch of block %d, header %s, snapshot %s", msg.Entire.Header.Number.Uint64(), msg.Entire.Header.MixDigest, hash)
+	}
+
 	codeMap := make(map[types.Hash][]byte)
 	for _, pair := range msg.Codes {
 		codeMap[pair.Hash] = pair.Code
@@ -62,7 +81,7 @@ func verify(ctx context.Context, msg *state.EntireCode) types.Hash {
 	for _, tByte := range msg.Entire.Transactions {
 		tmp := &transaction.Transaction{}
 		if err := tmp.Unmarshal(tByte); nil != err {
-			panic(err)
+			return types.Hash{}, err
 		}
 		txs = append(txs, tmp)
 	}
@@ -85,16 +104,48 @@ func verify(ctx context.Context, msg *state.EntireCode) types.Hash {
 	ibs.SetHeight(block.Number64().Uint64())
 	ibs.SetGetOneFun(batch.GetOne)
 
-	root, err := checkBlock2(getNumberHash, block, ibs, msg.CoinBase, msg.Rewards)
-	if nil != err {
-		panic(err)
+	commitment, err := openStateCommitment(chainConfig, msg)
+	if err != nil {
+		return types.Hash{}, err
 	}
-	return root
+	ibs.SetStateCommitment(commitment)
+
+	return checkBlock(chainConfig, getNumberHash, block, ibs, msg.CoinBase, msg.Rewards)
 }
 
-func checkBlock2(getHashF func(n uint64) types.Hash, block *block2.Block, ibs *state.IntraBlockState, coinbase types.Address, rewards []*block2.Reward) (types.Hash, error) {
+// openStateCommitment opens the state trie the block of msg is executed on from the
+// witness it carries, or returns nil before the state commitment fork. Past the fork
+// block the root of the witness has to be the state root of the parent; at the fork
+// block it is the root generated from the whole state, which only the miner has.
+func openStateCommitment(chainConfig *params.ChainConfig, msg *state.EntireCode) (*state.StateCommitment, error) {
+	header := msg.Entire.Header
+	number := header.Number.Uint64()
+	if !chainConfig.IsStateCommitment(number) {
+		return nil, nil
+	}
+	if len(msg.TrieNodes) == 0 {
+		return nil, fmt.Errorf("missing state witness of block %d", number)
+	}
+	if number > 0 && chainConfig.IsStateCommitment(number-1) {
+		var parent *block2.Header
+		for _, h := range msg.Headers {
+			if h.Hash() == header.ParentHash {
+				parent = h
+				break
+			}
+		}
+		if parent == nil {
+			return nil, fmt.Errorf("missing parent header of block %d", number)
+		}
+		if parent.Root != msg.StateRoot {
+			return nil, fmt.Errorf("state witness root mismatch of block %d, parent %s, witness %s", number, parent.Root, msg.StateRoot)
+		}
+	}
+	return state.NewStatelessStateCommitment(msg.TrieNodes, msg.StateRoot, chainConfig.Rules(number))
+}
+
+func checkBlock(chainConfig *params.ChainConfig, getHashF func(n uint64) types.Hash, block *block2.Block, ibs *state.IntraBlockState, coinbase types.Address, rewards []*block2.Reward) (types.Hash, error) {
 	header := block.Header().(*block2.Header)
-	chainConfig := params.AmazeChainConfig
 	if chainConfig.DAOForkSupport && chainConfig.DAOForkBlock != nil && chainConfig.DAOForkBlock.Cmp(block.Number64().ToBig()) == 0 {
 		misc.ApplyDAOHardFork(ibs)
 	}
@@ -104,14 +155,12 @@ func checkBlock2(getHashF func(n uint64) types.Hash, block *block2.Block, ibs *s
 	gp := new(common2.GasPool)
 	gp.AddGas(block.GasLimit())
 	cfg := vm.Config{}
-	//cfg := vm.Config{Debug: true, Tracer: logger.NewMarkdownLogger(nil, os.Stdout)}
 
 	engine := apos.NewFaker()
 	for i, tx := range block.Transactions() {
 		ibs.Prepare(tx.Hash(), block.Hash(), i)
 		_, _, err := internal.ApplyTransaction(chainConfig, getHashF, engine, &coinbase, gp, ibs, noop, header, tx, usedGas, cfg)
 		if err != nil {
-
 			return types.Hash{}, err
 		}
 	}
@@ -132,5 +181,9 @@ func checkBlock2(getHashF func(n uint64) types.Hash, block *block2.Block, ibs *s
 		ibs.SoftFinalise()
 	}
 
-	return ibs.IntermediateRoot(), nil
+	root := ibs.IntermediateRoot()
+	if ibs.StateCommitment() != nil && ibs.Error() != nil {
+		return types.Hash{}, fmt.Errorf("updating state trie of block %d: %w", block.Number64().Uint64(), ibs.Error())
+	}
+	return root, nil
 }
@@ -0,0 +1,71 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"github.com/amazechain/amc/common/types"
+	"github.com/holiman/uint256"
+)
+
+// CallTracer is a lightweight EVMLogger collecting the senders and recipients of
+// every call frame executed in a block. It feeds the call trace indices and keeps
+// no per-frame data, so it can stay enabled during block import.
+type CallTracer struct {
+	froms map[types.Address]struct{}
+	tos   map[types.Address]struct{}
+}
+
+// NewCallTracer creates an empty CallTracer.
+func NewCallTracer() *CallTracer {
+	return &CallTracer{
+		froms: make(map[types.Address]struct{}),
+		tos:   make(map[types.Address]struct{}),
+	}
+}
+
+func (ct *CallTracer) CaptureTxStart(gasLimit uint64) {}
+func (ct *CallTracer) CaptureTxEnd(restGas uint64)    {}
+
+// CaptureStart records the top call frame of a transaction.
+func (ct *CallTracer) CaptureStart(env VMInterface, from types.Address, to types.Address, create bool, input []byte, gas uint64, value *uint256.Int) {
+	ct.froms[from] = struct{}{}
+	ct.tos[to] = struct{}{}
+}
+
+func (ct *CallTracer) CaptureEnd(output []byte, usedGas uint64, err error) {}
+
+// CaptureEnter records a nested call, create or selfdestruct frame.
+func (ct *CallTracer) CaptureEnter(typ OpCode, from types.Address, to types.Address, input []byte, gas uint64, value *uint256.Int) {
+	ct.froms[from] = struct{}{}
+	ct.tos[to] = struct{}{}
+}
+
+func (ct *CallTracer) CaptureExit(output []byte, usedGas uint64, err error) {}
+func (ct *CallTracer) CaptureState(pc uint64, op OpCode, gas, cost uint64, scope *ScopeContext, rData []byte, depth int, err error) {
+}
+func (ct *CallTracer) CaptureFault(pc uint64, op OpCode, gas, cost uint64, scope *ScopeContext, depth int, err error) {
+}
+
+// Froms returns the accounts seen as the caller of a frame.
+func (ct *CallTracer) Froms() map[types.Address]struct{} {
+	return ct.froms
+}
+
+// Tos returns the accounts seen as the callee of a frame.
+func (ct *CallTracer) Tos() map[types.Address]struct{} {
+	return ct.tos
+}
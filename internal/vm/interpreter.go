@@ -29,6 +29,7 @@ import (
 type Config struct {
 	Debug         bool      // Enables debugging
 	Tracer        EVMLogger // Opcode logger
+	FramesOnly    bool      // Only reports call frames to the Tracer, not every opcode
 	NoRecursion   bool      // Disables call, callcode, delegate call and create
 	NoBaseFee     bool      // Forces the EIP-1559 baseFee to 0 (needed for 0 price calls)
 	SkipAnalysis  bool      // Whether we can skip jumpdest analysis based on the checked history
@@ -224,7 +225,7 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 	defer stack.ReturnNormalStack(locStack)
 	contract.Input = input
 
-	if in.cfg.Debug {
+	if in.cfg.Debug && !in.cfg.FramesOnly {
 		defer func() {
 			if err != nil {
 				if !logged {
@@ -245,7 +246,7 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 		if steps%1000 == 0 && in.evm.Cancelled() {
 			break
 		}
-		if in.cfg.Debug {
+		if in.cfg.Debug && !in.cfg.FramesOnly {
 			// Capture pre-execution values for tracing.
 			logged, pcCopy, gasCopy = false, _pc, contract.Gas
 		}
@@ -293,7 +294,7 @@ func (in *EVMInterpreter) Run(contract *Contract, input []byte, readOnly bool) (
 				mem.Resize(memorySize)
 			}
 		}
-		if in.cfg.Debug {
+		if in.cfg.Debug && !in.cfg.FramesOnly {
 			in.cfg.Tracer.CaptureState(_pc, op, gasCopy, cost, callContext, in.returnData, in.depth, err) //nolint:errcheck
 			logged = true
 		}
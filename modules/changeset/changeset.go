@@ -23,6 +23,7 @@ import (
 	"github.com/amazechain/amc/common/types"
 	"github.com/amazechain/amc/modules"
 	"github.com/amazechain/amc/modules/ethdb"
+	"github.com/amazechain/amc/modules/rawdb"
 	"math"
 	"reflect"
 
@@ -129,23 +130,23 @@ func FromDBFormat(dbKey, dbValue []byte) (uint64, []byte, []byte, error) {
 	}
 }
 
+// AvailableFrom returns the first block whose account changes are kept: the prune
+// horizon once the state history was pruned, the first stored block otherwise.
 func AvailableFrom(tx kv.Tx) (uint64, error) {
-	c, err := tx.Cursor(modules.AccountChangeSet)
-	if err != nil {
-		return math.MaxUint64, err
-	}
-	defer c.Close()
-	k, _, err := c.First()
+	return availableFrom(tx, modules.AccountChangeSet)
+}
+
+// AvailableStorageFrom is AvailableFrom for storage changes.
+func AvailableStorageFrom(tx kv.Tx) (uint64, error) {
+	return availableFrom(tx, modules.StorageChangeSet)
+}
+
+func availableFrom(tx kv.Tx, bucket string) (uint64, error) {
+	prunedTo, err := rawdb.ReadPrunedTo(tx, rawdb.PruneHistory)
 	if err != nil {
 		return math.MaxUint64, err
 	}
-	if len(k) == 0 {
-		return math.MaxUint64, nil
-	}
-	return binary.BigEndian.Uint64(k), nil
-}
-func AvailableStorageFrom(tx kv.Tx) (uint64, error) {
-	c, err := tx.Cursor(modules.StorageChangeSet)
+	c, err := tx.Cursor(bucket)
 	if err != nil {
 		return math.MaxUint64, err
 	}
@@ -155,9 +156,15 @@ func AvailableStorageFrom(tx kv.Tx) (uint64, error) {
 		return math.MaxUint64, err
 	}
 	if len(k) == 0 {
+		if prunedTo > 0 {
+			return prunedTo, nil
+		}
 		return math.MaxUint64, nil
 	}
-	return binary.BigEndian.Uint64(k), nil
+	if first := binary.BigEndian.Uint64(k); first > prunedTo {
+		return first, nil
+	}
+	return prunedTo, nil
 }
 
 // [from:to)
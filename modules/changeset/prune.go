@@ -0,0 +1,81 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package changeset
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"time"
+
+	"github.com/amazechain/amc/modules"
+	"github.com/amazechain/amc/modules/rawdb"
+	"github.com/ledgerwatch/erigon-lib/kv"
+)
+
+// PruneHistoryTo removes the account and storage changesets of the blocks
+// [from, to) along with the history index chunks that only cover blocks before to.
+// Everything before from must have been pruned already.
+func PruneHistoryTo(ctx context.Context, tx kv.RwTx, from, to uint64) error {
+	logEvery := time.NewTicker(30 * time.Second)
+	defer logEvery.Stop()
+
+	for _, bucket := range []string{modules.AccountChangeSet, modules.StorageChangeSet} {
+		keys := make(map[string]struct{})
+		if err := ForRange(tx, bucket, from, to, func(_ uint64, k, _ []byte) error {
+			keys[string(modules.CompositeKeyWithoutIncarnation(k))] = struct{}{}
+			return nil
+		}); err != nil {
+			return err
+		}
+		index := Mapper[bucket].IndexBucket
+		for k := range keys {
+			if err := pruneIndexChunks(tx, index, []byte(k), to); err != nil {
+				return err
+			}
+		}
+		if err := rawdb.PruneTableDupSort(tx, bucket, "Prune", to, logEvery, ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pruneIndexChunks deletes the history index chunks of key whose highest block is
+// before to. A chunk key is the key followed by the highest block of the chunk.
+func pruneIndexChunks(tx kv.RwTx, bucket string, key []byte, to uint64) error {
+	c, err := tx.RwCursor(bucket)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	for k, _, err := c.Seek(key); k != nil; k, _, err = c.Next() {
+		if err != nil {
+			return err
+		}
+		if len(k) != len(key)+8 || !bytes.HasPrefix(k, key) {
+			break
+		}
+		if binary.BigEndian.Uint64(k[len(key):]) >= to {
+			break
+		}
+		if err := c.DeleteCurrent(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
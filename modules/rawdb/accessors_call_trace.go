@@ -0,0 +1,187 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sort"
+
+	"github.com/RoaringBitmap/roaring"
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/modules"
+	"github.com/amazechain/amc/modules/ethdb/bitmapdb"
+	"github.com/ledgerwatch/erigon-lib/kv"
+)
+
+const (
+	// CallTraceFrom flags an address that appeared as the caller of a call frame.
+	CallTraceFrom byte = 1 << iota
+	// CallTraceTo flags an address that appeared as the callee of a call frame.
+	CallTraceTo
+)
+
+// callIndexFromKey stores the first block for which call traces have been indexed.
+var callIndexFromKey = []byte("CallTraceIndexFrom")
+
+// ReadCallIndexFrom retrieves the first block covered by CallFromIndex and CallToIndex.
+// ok is false if no block has been indexed yet.
+func ReadCallIndexFrom(db kv.Getter) (number uint64, ok bool, err error) {
+	data, err := db.GetOne(modules.DatabaseInfo, callIndexFromKey)
+	if err != nil {
+		return 0, false, err
+	}
+	if len(data) != modules.NumberLength {
+		return 0, false, nil
+	}
+	return binary.BigEndian.Uint64(data), true, nil
+}
+
+// WriteCallTraceSet stores the accounts touched by the call frames of a block,
+// each with the CallTraceFrom/CallTraceTo flags they appeared with. A set stored
+// by an earlier execution of the block is replaced.
+func WriteCallTraceSet(tx kv.RwTx, number uint64, hash types.Hash, touched map[types.Address]byte) error {
+	if err := deleteCallTraceSet(tx, number, hash); err != nil {
+		return err
+	}
+	addrs := make([]types.Address, 0, len(touched))
+	for addr := range touched {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		return bytes.Compare(addrs[i][:], addrs[j][:]) < 0
+	})
+
+	key := modules.HeaderKey(number, hash)
+	for _, addr := range addrs {
+		v := make([]byte, types.AddressLength+1)
+		copy(v, addr[:])
+		v[types.AddressLength] = touched[addr]
+		if err := tx.Put(modules.CallTraceSet, key, v); err != nil {
+			return fmt.Errorf("writing call trace set for block %d: %w", number, err)
+		}
+	}
+	return nil
+}
+
+// ReadCallTraceSet retrieves the accounts touched by the call frames of a block.
+func ReadCallTraceSet(db kv.Tx, number uint64, hash types.Hash) (map[types.Address]byte, error) {
+	touched := make(map[types.Address]byte)
+	if err := db.ForPrefix(modules.CallTraceSet, modules.HeaderKey(number, hash), func(k, v []byte) error {
+		if len(v) != types.AddressLength+1 {
+			return fmt.Errorf("invalid call trace set entry for block %d", number)
+		}
+		touched[types.BytesToAddress(v[:types.AddressLength])] |= v[types.AddressLength]
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return touched, nil
+}
+
+// deleteCallTraceSet removes the call trace set of a block.
+func deleteCallTraceSet(tx kv.RwTx, number uint64, hash types.Hash) error {
+	c, err := tx.RwCursorDupSort(modules.CallTraceSet)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	k, _, err := c.SeekExact(modules.HeaderKey(number, hash))
+	if err != nil || k == nil {
+		return err
+	}
+	return c.DeleteCurrentDuplicates()
+}
+
+// WriteCallIndex adds the block number to the CallFromIndex and CallToIndex bitmaps
+// of every account in the call trace set of the canonical block.
+func WriteCallIndex(tx kv.RwTx, number uint64, hash types.Hash) error {
+	touched, err := ReadCallTraceSet(tx, number, hash)
+	if err != nil {
+		return err
+	}
+	for addr, flags := range touched {
+		if flags&CallTraceFrom != 0 {
+			if err := appendBitmapIndex(tx, modules.CallFromIndex, addr.Bytes(), number); err != nil {
+				return err
+			}
+		}
+		if flags&CallTraceTo != 0 {
+			if err := appendBitmapIndex(tx, modules.CallToIndex, addr.Bytes(), number); err != nil {
+				return err
+			}
+		}
+	}
+	if _, ok, err := ReadCallIndexFrom(tx); err != nil {
+		return err
+	} else if !ok {
+		return tx.Put(modules.DatabaseInfo, callIndexFromKey, modules.EncodeBlockNumber(number))
+	}
+	return nil
+}
+
+// UnwindCallIndex removes every block number >= from out of the call index bitmaps
+// touched by the call trace sets of the canonical blocks from that number on, and
+// deletes those sets. It must run before the canonical hashes are replaced.
+func UnwindCallIndex(tx kv.RwTx, from uint64) error {
+	touched := make(map[types.Address]byte)
+	for number := from; ; number++ {
+		hash, err := ReadCanonicalHash(tx, number)
+		if err != nil {
+			return err
+		}
+		if hash == (types.Hash{}) {
+			break
+		}
+		set, err := ReadCallTraceSet(tx, number, hash)
+		if err != nil {
+			return err
+		}
+		for addr, flags := range set {
+			touched[addr] |= flags
+		}
+		if err := deleteCallTraceSet(tx, number, hash); err != nil {
+			return err
+		}
+	}
+
+	for addr, flags := range touched {
+		if flags&CallTraceFrom != 0 {
+			if err := bitmapdb.TruncateRange(tx, modules.CallFromIndex, addr.Bytes(), uint32(from)); err != nil {
+				return err
+			}
+		}
+		if flags&CallTraceTo != 0 {
+			if err := bitmapdb.TruncateRange(tx, modules.CallToIndex, addr.Bytes(), uint32(from)); err != nil {
+				return err
+			}
+		}
+	}
+	if indexFrom, ok, err := ReadCallIndexFrom(tx); err != nil {
+		return err
+	} else if ok && indexFrom >= from {
+		return tx.Delete(modules.DatabaseInfo, callIndexFromKey)
+	}
+	return nil
+}
+
+// ReadCallIndex returns the blocks in [from, to] with call frames from (CallFromIndex)
+// or to (CallToIndex) the given address.
+func ReadCallIndex(db kv.Tx, table string, addr types.Address, from, to uint64) (*roaring.Bitmap, error) {
+	return readBitmapIndex(db, table, addr.Bytes(), from, to)
+}
@@ -0,0 +1,121 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"testing"
+
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/modules"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+)
+
+// Tests that call trace sets feed the call index bitmaps and that they unwind.
+func TestCallIndex(t *testing.T) {
+	_, tx := memdb.NewTestTx(t)
+
+	var (
+		sender   = types.HexToAddress("0x1000000000000000000000000000000000000001")
+		contract = types.HexToAddress("0x2000000000000000000000000000000000000002")
+		side     = types.HexToAddress("0x3000000000000000000000000000000000000003")
+	)
+	if _, ok, err := ReadCallIndexFrom(tx); err != nil || ok {
+		t.Fatalf("unexpected index start: ok %v, err %v", ok, err)
+	}
+	for number := uint64(5); number <= 50; number++ {
+		touched := map[types.Address]byte{sender: CallTraceFrom}
+		if number%5 == 0 {
+			touched[contract] = CallTraceTo | CallTraceFrom
+		}
+		hash := types.Hash{byte(number)}
+		if err := WriteCallTraceSet(tx, number, hash, touched); err != nil {
+			t.Fatalf("WriteCallTraceSet failed: %v", err)
+		}
+		// A side chain block at the same height must not leak into the index
+		if err := WriteCallTraceSet(tx, number, types.Hash{byte(number), 1}, map[types.Address]byte{side: CallTraceTo}); err != nil {
+			t.Fatalf("WriteCallTraceSet failed: %v", err)
+		}
+		if err := WriteCanonicalHash(tx, hash, number); err != nil {
+			t.Fatalf("WriteCanonicalHash failed: %v", err)
+		}
+		if err := WriteCallIndex(tx, number, hash); err != nil {
+			t.Fatalf("WriteCallIndex failed: %v", err)
+		}
+	}
+	if from, ok, err := ReadCallIndexFrom(tx); err != nil || !ok || from != 5 {
+		t.Fatalf("index start mismatch: have %d (ok %v, err %v), want 5", from, ok, err)
+	}
+
+	bm, err := ReadCallIndex(tx, modules.CallToIndex, contract, 12, 31)
+	if err != nil {
+		t.Fatalf("ReadCallIndex failed: %v", err)
+	}
+	if have, want := bm.ToArray(), []uint32{15, 20, 25, 30}; !equalUint32s(have, want) {
+		t.Fatalf("to index mismatch: have %v, want %v", have, want)
+	}
+	bm, err = ReadCallIndex(tx, modules.CallToIndex, sender, 0, 100)
+	if err != nil {
+		t.Fatalf("ReadCallIndex failed: %v", err)
+	}
+	if !bm.IsEmpty() {
+		t.Fatalf("sender unexpectedly indexed as callee: %v", bm.ToArray())
+	}
+	bm, err = ReadCallIndex(tx, modules.CallToIndex, side, 0, 100)
+	if err != nil {
+		t.Fatalf("ReadCallIndex failed: %v", err)
+	}
+	if !bm.IsEmpty() {
+		t.Fatalf("side chain callee indexed: %v", bm.ToArray())
+	}
+
+	if err := UnwindCallIndex(tx, 21); err != nil {
+		t.Fatalf("UnwindCallIndex failed: %v", err)
+	}
+	bm, err = ReadCallIndex(tx, modules.CallFromIndex, contract, 0, 100)
+	if err != nil {
+		t.Fatalf("ReadCallIndex failed: %v", err)
+	}
+	if have, want := bm.ToArray(), []uint32{5, 10, 15, 20}; !equalUint32s(have, want) {
+		t.Fatalf("unwound from index mismatch: have %v, want %v", have, want)
+	}
+	bm, err = ReadCallIndex(tx, modules.CallFromIndex, sender, 0, 100)
+	if err != nil {
+		t.Fatalf("ReadCallIndex failed: %v", err)
+	}
+	if bm.GetCardinality() != 16 || bm.Maximum() != 20 {
+		t.Fatalf("unwound from index mismatch: have %v", bm.ToArray())
+	}
+
+	// The sets of the unwound canonical blocks are gone, the kept ones are not
+	for number, want := range map[uint64]int{20: 2, 21: 0, 50: 0} {
+		touched, err := ReadCallTraceSet(tx, number, types.Hash{byte(number)})
+		if err != nil {
+			t.Fatalf("ReadCallTraceSet failed: %v", err)
+		}
+		if len(touched) != want {
+			t.Errorf("block %d: have %d accounts in the call trace set, want %d", number, len(touched), want)
+		}
+	}
+	// Storing the set of a block again replaces it
+	hash := types.Hash{byte(20)}
+	if err := WriteCallTraceSet(tx, 20, hash, map[types.Address]byte{side: CallTraceFrom}); err != nil {
+		t.Fatalf("WriteCallTraceSet failed: %v", err)
+	}
+	if touched, err := ReadCallTraceSet(tx, 20, hash); err != nil || len(touched) != 1 || touched[side] != CallTraceFrom {
+		t.Fatalf("rewritten call trace set mismatch: have %v, err %v", touched, err)
+	}
+}
@@ -842,7 +842,13 @@ func TruncateReceipts(db kv.RwTx, number uint64) error {
 	return nil
 }
 
+// ReceiptsAvailableFrom returns the first block whose receipts are kept: the prune
+// horizon once receipts were pruned, the first stored block otherwise.
 func ReceiptsAvailableFrom(tx kv.Tx) (uint64, error) {
+	prunedTo, err := ReadPrunedTo(tx, PruneReceipts)
+	if err != nil {
+		return math.MaxUint64, err
+	}
 	c, err := tx.Cursor(modules.Receipts)
 	if err != nil {
 		return math.MaxUint64, err
@@ -853,9 +859,15 @@ func ReceiptsAvailableFrom(tx kv.Tx) (uint64, error) {
 		return math.MaxUint64, err
 	}
 	if len(k) == 0 {
+		if prunedTo > 0 {
+			return prunedTo, nil
+		}
 		return math.MaxUint64, nil
 	}
-	return binary.BigEndian.Uint64(k), nil
+	if first := binary.BigEndian.Uint64(k); first > prunedTo {
+		return first, nil
+	}
+	return prunedTo, nil
 }
 
 // ReadBlock retrieves an entire block corresponding to the hash, assembling it
@@ -1338,6 +1350,39 @@ func WriteHeadHeaderHash(db kv.Putter, hash types.Hash) error {
 	return nil
 }
 
+var (
+	safeBlockKey      = []byte("SafeBlock")
+	finalizedBlockKey = []byte("FinalizedBlock")
+)
+
+// ReadSafeBlockHash retrieves the hash of the latest safe block.
+func ReadSafeBlockHash(db kv.Getter) (types.Hash, error) {
+	data, err := db.GetOne(modules.DatabaseInfo, safeBlockKey)
+	if err != nil || len(data) == 0 {
+		return types.Hash{}, err
+	}
+	return types.BytesToHash(data), nil
+}
+
+// WriteSafeBlockHash stores the hash of the latest safe block.
+func WriteSafeBlockHash(db kv.Putter, hash types.Hash) error {
+	return db.Put(modules.DatabaseInfo, safeBlockKey, hash.Bytes())
+}
+
+// ReadFinalizedBlockHash retrieves the hash of the latest finalized block.
+func ReadFinalizedBlockHash(db kv.Getter) (types.Hash, error) {
+	data, err := db.GetOne(modules.DatabaseInfo, finalizedBlockKey)
+	if err != nil || len(data) == 0 {
+		return types.Hash{}, err
+	}
+	return types.BytesToHash(data), nil
+}
+
+// WriteFinalizedBlockHash stores the hash of the latest finalized block.
+func WriteFinalizedBlockHash(db kv.Putter, hash types.Hash) error {
+	return db.Put(modules.DatabaseInfo, finalizedBlockKey, hash.Bytes())
+}
+
 func GetPoaSnapshot(db kv.Getter, hash types.Hash) ([]byte, error) {
 
 	return db.GetOne(modules.PoaSnapshot, hash.Bytes())
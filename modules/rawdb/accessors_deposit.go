@@ -17,7 +17,13 @@
 package rawdb
 
 import (
+	"context"
+	"encoding/binary"
 	"fmt"
+	"math"
+	"sort"
+
+	"github.com/amazechain/amc/common/crypto"
 	"github.com/amazechain/amc/common/crypto/bls"
 	"github.com/amazechain/amc/common/types"
 	"github.com/amazechain/amc/modules"
@@ -83,3 +89,114 @@ func DepositNum(tx kv.Tx) (uint64, error) {
 	defer cur.Close()
 	return cur.Count()
 }
+
+// Depositor is the BLS key registered by a depositor and the amount it deposited.
+type Depositor struct {
+	PublicKey types.PublicKey
+	Amount    *uint256.Int
+}
+
+// Deposits holds the depositors by address.
+type Deposits map[types.Address]*Depositor
+
+// depositorLength is the length of an encoded depositor: address + public key + amount.
+const depositorLength = types.AddressLength + types.PublicKeyLength + 32
+
+// ReadDeposits retrieves the depositors of the Deposit table, which follows the
+// head of the canonical chain.
+func ReadDeposits(tx kv.Tx) (Deposits, error) {
+	deposits := make(Deposits)
+	if err := tx.ForEach(modules.Deposit, nil, func(k, _ []byte) error {
+		addr := types.BytesToAddress(k)
+		pub, amount, err := GetDeposit(tx, addr)
+		if err != nil {
+			return err
+		}
+		deposits[addr] = &Depositor{PublicKey: pub, Amount: amount}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return deposits, nil
+}
+
+// WriteDeposits replaces the depositors of the Deposit table.
+func WriteDeposits(tx kv.RwTx, deposits Deposits) error {
+	if err := tx.ClearBucket(modules.Deposit); err != nil {
+		return err
+	}
+	for addr, d := range deposits {
+		if err := PutDeposit(tx, addr, d.PublicKey, *d.Amount); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// depositSnapshotKey = block_num_u64 + hash
+func depositSnapshotKey(number uint64, hash types.Hash) []byte {
+	k := make([]byte, modules.NumberLength+types.HashLength)
+	binary.BigEndian.PutUint64(k, number)
+	copy(k[modules.NumberLength:], hash[:])
+	return k
+}
+
+// ReadDepositSnapshot retrieves the depositors after the block, and whether they
+// were recorded for the block at all.
+func ReadDepositSnapshot(db kv.Getter, hash types.Hash, number uint64) (Deposits, bool, error) {
+	setHash, err := db.GetOne(modules.DepositSnapshot, depositSnapshotKey(number, hash))
+	if err != nil {
+		return nil, false, err
+	}
+	if len(setHash) != types.HashLength {
+		return nil, false, nil
+	}
+	deposits := make(Deposits)
+	// The empty set is not stored
+	if types.BytesToHash(setHash) == (types.Hash{}) {
+		return deposits, true, nil
+	}
+	data, err := db.GetOne(modules.DepositSet, setHash)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(data)%depositorLength != 0 || len(data) == 0 {
+		return nil, false, fmt.Errorf("corrupt deposit set %x of block %d", setHash, number)
+	}
+	for ; len(data) > 0; data = data[depositorLength:] {
+		d := &Depositor{Amount: new(uint256.Int).SetBytes(data[types.AddressLength+types.PublicKeyLength : depositorLength])}
+		copy(d.PublicKey[:], data[types.AddressLength:])
+		deposits[types.BytesToAddress(data[:types.AddressLength])] = d
+	}
+	return deposits, true, nil
+}
+
+// WriteDepositSnapshot stores the depositors after the block. Blocks with the
+// same depositors share their encoding.
+func WriteDepositSnapshot(db kv.Putter, hash types.Hash, number uint64, deposits Deposits) error {
+	var setHash types.Hash
+	if len(deposits) > 0 {
+		addrs := make(types.Addresses, 0, len(deposits))
+		for addr := range deposits {
+			addrs = append(addrs, addr)
+		}
+		sort.Sort(addrs)
+		data := make([]byte, 0, len(addrs)*depositorLength)
+		for _, addr := range addrs {
+			d := deposits[addr]
+			amount := d.Amount.Bytes32()
+			data = append(append(append(data, addr[:]...), d.PublicKey[:]...), amount[:]...)
+		}
+		setHash = crypto.Keccak256Hash(data)
+		if err := db.Put(modules.DepositSet, setHash[:], data); err != nil {
+			return err
+		}
+	}
+	return db.Put(modules.DepositSnapshot, depositSnapshotKey(number, hash), setHash[:])
+}
+
+// PruneDepositSnapshotsTo removes the deposit snapshots of the blocks before the
+// given block. The sets they refer to are kept, as later blocks may share them.
+func PruneDepositSnapshotsTo(ctx context.Context, tx kv.RwTx, to uint64) error {
+	return PruneTable(tx, modules.DepositSnapshot, to, ctx, math.MaxInt)
+}
@@ -0,0 +1,265 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/RoaringBitmap/roaring"
+	"github.com/amazechain/amc/common/block"
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/modules"
+	"github.com/amazechain/amc/modules/ethdb/bitmapdb"
+	"github.com/ledgerwatch/erigon-lib/kv"
+)
+
+// logIndexProgressKey tracks the highest block covered by LogAddressIndex and LogTopicIndex.
+var logIndexProgressKey = []byte("LogIndexProgress")
+
+// ReadLogIndexProgress retrieves the number of the last block whose logs are indexed.
+func ReadLogIndexProgress(db kv.Getter) (uint64, error) {
+	data, err := db.GetOne(modules.DatabaseInfo, logIndexProgressKey)
+	if err != nil {
+		return 0, err
+	}
+	if len(data) != modules.NumberLength {
+		return 0, nil
+	}
+	return binary.BigEndian.Uint64(data), nil
+}
+
+// WriteLogIndexProgress stores the number of the last block whose logs are indexed.
+func WriteLogIndexProgress(db kv.Putter, number uint64) error {
+	return db.Put(modules.DatabaseInfo, logIndexProgressKey, modules.EncodeBlockNumber(number))
+}
+
+// ReadBlockLogs retrieves the logs of all transactions of a canonical block from the Log table.
+func ReadBlockLogs(db kv.Tx, number uint64) ([]*block.Log, error) {
+	var logs []*block.Log
+	if err := db.ForPrefix(modules.Log, modules.EncodeBlockNumber(number), func(k, v []byte) error {
+		var txLogs block.Logs
+		if err := txLogs.Unmarshal(v); err != nil {
+			return fmt.Errorf("logs unmarshal failed for block %d: %w", number, err)
+		}
+		logs = append(logs, txLogs...)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// WriteLogIndex adds the block number to the address and topic bitmaps of every log in the block
+// and records the keys it touched in LogIndexSet.
+func WriteLogIndex(tx kv.RwTx, number uint64, logs []*block.Log) error {
+	addresses := make(map[types.Address]struct{})
+	topics := make(map[types.Hash]struct{})
+	for _, l := range logs {
+		addresses[l.Address] = struct{}{}
+		for _, topic := range l.Topics {
+			topics[topic] = struct{}{}
+		}
+	}
+	key := modules.EncodeBlockNumber(number)
+	for addr := range addresses {
+		if err := appendBitmapIndex(tx, modules.LogAddressIndex, addr.Bytes(), number); err != nil {
+			return err
+		}
+		if err := tx.Put(modules.LogIndexSet, key, addr.Bytes()); err != nil {
+			return fmt.Errorf("writing log index set for block %d: %w", number, err)
+		}
+	}
+	for topic := range topics {
+		if err := appendBitmapIndex(tx, modules.LogTopicIndex, topic.Bytes(), number); err != nil {
+			return err
+		}
+		if err := tx.Put(modules.LogIndexSet, key, topic.Bytes()); err != nil {
+			return fmt.Errorf("writing log index set for block %d: %w", number, err)
+		}
+	}
+	return nil
+}
+
+// readLogIndexSet collects the addresses and topics recorded in LogIndexSet for the
+// blocks in [from, to).
+func readLogIndexSet(tx kv.Tx, from, to uint64) (map[types.Address]struct{}, map[types.Hash]struct{}, error) {
+	addresses := make(map[types.Address]struct{})
+	topics := make(map[types.Hash]struct{})
+	c, err := tx.Cursor(modules.LogIndexSet)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer c.Close()
+	for k, v, err := c.Seek(modules.EncodeBlockNumber(from)); k != nil; k, v, err = c.Next() {
+		if err != nil {
+			return nil, nil, err
+		}
+		if binary.BigEndian.Uint64(k) >= to {
+			break
+		}
+		switch len(v) {
+		case types.AddressLength:
+			addresses[types.BytesToAddress(v)] = struct{}{}
+		case types.HashLength:
+			topics[types.BytesToHash(v)] = struct{}{}
+		default:
+			return nil, nil, fmt.Errorf("invalid log index set entry %x", k)
+		}
+	}
+	return addresses, topics, nil
+}
+
+// deleteLogIndexSet removes the LogIndexSet entries of the blocks in [from, to).
+func deleteLogIndexSet(tx kv.RwTx, from, to uint64) error {
+	c, err := tx.RwCursorDupSort(modules.LogIndexSet)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	for k, _, err := c.Seek(modules.EncodeBlockNumber(from)); k != nil; k, _, err = c.NextNoDup() {
+		if err != nil {
+			return err
+		}
+		if binary.BigEndian.Uint64(k) >= to {
+			break
+		}
+		if err := c.DeleteCurrentDuplicates(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendBitmapIndex merges number into the hot (last) shard of key and re-shards it if it grew too large.
+func appendBitmapIndex(tx kv.RwTx, table string, key []byte, number uint64) error {
+	last, err := bitmapdb.Get(tx, table, key, math.MaxUint32, math.MaxUint32)
+	if err != nil {
+		return fmt.Errorf("find chunk failed: %w", err)
+	}
+	if last.Contains(uint32(number)) {
+		return nil
+	}
+	last.Add(uint32(number))
+
+	buf := bytes.NewBuffer(nil)
+	return bitmapdb.WalkChunkWithKeys(key, last, bitmapdb.ChunkLimit, func(chunkKey []byte, chunk *roaring.Bitmap) error {
+		buf.Reset()
+		if _, err := chunk.WriteTo(buf); err != nil {
+			return err
+		}
+		return tx.Put(table, chunkKey, types.CopyBytes(buf.Bytes()))
+	})
+}
+
+// UnwindLogIndex removes every block number >= from out of the bitmaps recorded in
+// LogIndexSet for those blocks. The set is used rather than the Log table, which a
+// reorg may already have overwritten with the logs of the new chain.
+func UnwindLogIndex(tx kv.RwTx, from uint64) error {
+	addresses, topics, err := readLogIndexSet(tx, from, math.MaxUint64)
+	if err != nil {
+		return err
+	}
+	for addr := range addresses {
+		if err := bitmapdb.TruncateRange(tx, modules.LogAddressIndex, addr.Bytes(), uint32(from)); err != nil {
+			return err
+		}
+	}
+	for topic := range topics {
+		if err := bitmapdb.TruncateRange(tx, modules.LogTopicIndex, topic.Bytes(), uint32(from)); err != nil {
+			return err
+		}
+	}
+	return deleteLogIndexSet(tx, from, math.MaxUint64)
+}
+
+// PruneLogIndexTo removes the blocks before the given block from the log index
+// bitmaps, together with their LogIndexSet entries.
+func PruneLogIndexTo(tx kv.RwTx, from, to uint64) error {
+	addresses, topics, err := readLogIndexSet(tx, from, to)
+	if err != nil {
+		return err
+	}
+	for addr := range addresses {
+		if err := pruneBitmapIndex(tx, modules.LogAddressIndex, addr.Bytes(), to); err != nil {
+			return err
+		}
+	}
+	for topic := range topics {
+		if err := pruneBitmapIndex(tx, modules.LogTopicIndex, topic.Bytes(), to); err != nil {
+			return err
+		}
+	}
+	return deleteLogIndexSet(tx, from, to)
+}
+
+// pruneBitmapIndex removes every block number below to from the shards of key. Shards
+// are keyed by their highest number, so the ones entirely below to are dropped and the
+// first one overlapping it is rewritten.
+func pruneBitmapIndex(tx kv.RwTx, table string, key []byte, to uint64) error {
+	c, err := tx.RwCursor(table)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	for k, v, err := c.Seek(key); k != nil; k, v, err = c.Next() {
+		if err != nil {
+			return err
+		}
+		if len(k) != len(key)+4 || !bytes.HasPrefix(k, key) {
+			break
+		}
+		if uint64(binary.BigEndian.Uint32(k[len(key):])) < to {
+			if err := c.DeleteCurrent(); err != nil {
+				return err
+			}
+			continue
+		}
+		bm := roaring.New()
+		if _, err := bm.ReadFrom(bytes.NewReader(v)); err != nil {
+			return err
+		}
+		if bm.Minimum() < uint32(to) {
+			bm.RemoveRange(0, to)
+			buf := bytes.NewBuffer(nil)
+			if _, err := bm.WriteTo(buf); err != nil {
+				return err
+			}
+			return tx.Put(table, types.CopyBytes(k), buf.Bytes())
+		}
+		break
+	}
+	return nil
+}
+
+// ReadLogIndex returns the blocks in [from, to] that emitted logs for key (an address or a topic).
+func ReadLogIndex(db kv.Tx, table string, key []byte, from, to uint64) (*roaring.Bitmap, error) {
+	return readBitmapIndex(db, table, key, from, to)
+}
+
+// readBitmapIndex returns the block numbers in [from, to] stored in the sharded bitmaps of key.
+func readBitmapIndex(db kv.Tx, table string, key []byte, from, to uint64) (*roaring.Bitmap, error) {
+	bm, err := bitmapdb.Get(db, table, key, uint32(from), uint32(to))
+	if err != nil {
+		return nil, err
+	}
+	bm.RemoveRange(0, from)
+	bm.RemoveRange(to+1, uint64(math.MaxUint32)+1)
+	return bm, nil
+}
@@ -0,0 +1,171 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"testing"
+
+	"github.com/amazechain/amc/common/block"
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/modules"
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+)
+
+func newLogIndexTestTx(t *testing.T) kv.RwTx {
+	modules.AmcInit()
+	kv.ChaindataTablesCfg = modules.AmcTableCfg
+	_, tx := memdb.NewTestTx(t)
+	return tx
+}
+
+// Tests that log index bitmaps are written, queried by range and unwound.
+func TestLogIndex(t *testing.T) {
+	tx := newLogIndexTestTx(t)
+
+	var (
+		addr1  = types.HexToAddress("0x1000000000000000000000000000000000000001")
+		addr2  = types.HexToAddress("0x2000000000000000000000000000000000000002")
+		topic1 = types.HexToHash("0x01")
+	)
+	for number := uint64(1); number <= 100; number++ {
+		logs := block.Logs{{Address: addr1, Topics: []types.Hash{topic1}, BlockNumber: uint256.NewInt(number)}}
+		if number%10 == 0 {
+			logs = append(logs, &block.Log{Address: addr2, BlockNumber: uint256.NewInt(number)})
+		}
+		v, err := logs.Marshal()
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+		if err := tx.Put(modules.Log, modules.LogKey(number, 0), v); err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+		stored, err := ReadBlockLogs(tx, number)
+		if err != nil {
+			t.Fatalf("ReadBlockLogs failed: %v", err)
+		}
+		if err := WriteLogIndex(tx, number, stored); err != nil {
+			t.Fatalf("WriteLogIndex failed: %v", err)
+		}
+	}
+
+	bm, err := ReadLogIndex(tx, modules.LogAddressIndex, addr2.Bytes(), 15, 55)
+	if err != nil {
+		t.Fatalf("ReadLogIndex failed: %v", err)
+	}
+	if have, want := bm.ToArray(), []uint32{20, 30, 40, 50}; !equalUint32s(have, want) {
+		t.Fatalf("address index mismatch: have %v, want %v", have, want)
+	}
+	bm, err = ReadLogIndex(tx, modules.LogTopicIndex, topic1.Bytes(), 0, 100)
+	if err != nil {
+		t.Fatalf("ReadLogIndex failed: %v", err)
+	}
+	if bm.GetCardinality() != 100 {
+		t.Fatalf("topic index cardinality mismatch: have %d, want 100", bm.GetCardinality())
+	}
+
+	// a reorg replaces the stored logs before the index is unwound
+	for number := uint64(35); number <= 100; number++ {
+		replacement := block.Logs{{Address: types.HexToAddress("0x03"), BlockNumber: uint256.NewInt(number)}}
+		v, err := replacement.Marshal()
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+		if err := tx.Put(modules.Log, modules.LogKey(number, 0), v); err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+	}
+	if err := UnwindLogIndex(tx, 35); err != nil {
+		t.Fatalf("UnwindLogIndex failed: %v", err)
+	}
+	bm, err = ReadLogIndex(tx, modules.LogAddressIndex, addr2.Bytes(), 0, 100)
+	if err != nil {
+		t.Fatalf("ReadLogIndex failed: %v", err)
+	}
+	if have, want := bm.ToArray(), []uint32{10, 20, 30}; !equalUint32s(have, want) {
+		t.Fatalf("unwound address index mismatch: have %v, want %v", have, want)
+	}
+	bm, err = ReadLogIndex(tx, modules.LogAddressIndex, addr1.Bytes(), 0, 100)
+	if err != nil {
+		t.Fatalf("ReadLogIndex failed: %v", err)
+	}
+	if bm.GetCardinality() != 34 || bm.Maximum() != 34 {
+		t.Fatalf("unwound address index mismatch: have %v", bm.ToArray())
+	}
+	if n := countLogIndexSet(t, tx); n != 34*2+3 {
+		t.Fatalf("log index set size: have %d, want 71", n)
+	}
+}
+
+// Tests that pruning drops the pruned blocks from the log index bitmaps.
+func TestPruneLogIndex(t *testing.T) {
+	tx := newLogIndexTestTx(t)
+
+	var (
+		addr  = types.HexToAddress("0x1000000000000000000000000000000000000001")
+		topic = types.HexToHash("0x01")
+	)
+	for number := uint64(1); number <= 20; number++ {
+		logs := []*block.Log{{Address: addr, Topics: []types.Hash{topic}}}
+		if err := WriteLogIndex(tx, number, logs); err != nil {
+			t.Fatalf("WriteLogIndex failed: %v", err)
+		}
+	}
+	if err := PruneLogIndexTo(tx, 0, 8); err != nil {
+		t.Fatalf("PruneLogIndexTo failed: %v", err)
+	}
+	for _, table := range []string{modules.LogAddressIndex, modules.LogTopicIndex} {
+		key := addr.Bytes()
+		if table == modules.LogTopicIndex {
+			key = topic.Bytes()
+		}
+		bm, err := ReadLogIndex(tx, table, key, 0, 100)
+		if err != nil {
+			t.Fatalf("ReadLogIndex failed: %v", err)
+		}
+		if bm.GetCardinality() != 13 || bm.Minimum() != 8 {
+			t.Fatalf("%s: pruned index mismatch: have %v", table, bm.ToArray())
+		}
+	}
+	if n := countLogIndexSet(t, tx); n != 13*2 {
+		t.Fatalf("log index set size: have %d, want 26", n)
+	}
+}
+
+func countLogIndexSet(t *testing.T, tx kv.Tx) int {
+	var n int
+	if err := tx.ForEach(modules.LogIndexSet, nil, func(k, v []byte) error {
+		n++
+		return nil
+	}); err != nil {
+		t.Fatalf("ForEach failed: %v", err)
+	}
+	return n
+}
+
+func equalUint32s(a, b []uint32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
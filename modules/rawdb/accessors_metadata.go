@@ -59,3 +59,17 @@ func WriteChainConfig(db kv.RwTx, hash types.Hash, cfg *params.ChainConfig) erro
 	}
 	return nil
 }
+
+var genesisSpecKey = []byte("GenesisSpec")
+
+// ReadGenesisSpec retrieves the JSON genesis specification, without the
+// allocations, a database was initialised with. It is nil for databases that
+// use the built-in genesis.
+func ReadGenesisSpec(db kv.Getter) ([]byte, error) {
+	return db.GetOne(modules.DatabaseInfo, genesisSpecKey)
+}
+
+// WriteGenesisSpec stores the genesis specification of a custom network.
+func WriteGenesisSpec(db kv.Putter, spec []byte) error {
+	return db.Put(modules.DatabaseInfo, genesisSpecKey, spec)
+}
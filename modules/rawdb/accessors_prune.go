@@ -0,0 +1,84 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/amazechain/amc/modules"
+	"github.com/ledgerwatch/erigon-lib/kv"
+)
+
+// Data classes pruned independently of each other.
+const (
+	PruneHistory  = "History"  // account and storage changesets and history indices
+	PruneReceipts = "Receipts" // receipts
+	PruneLogs     = "Logs"     // transaction logs
+)
+
+// ErrHistoryPruned is returned when the data requested for a block has been pruned.
+var ErrHistoryPruned = errors.New("history pruned")
+
+func prunedToKey(class string) []byte {
+	return []byte("PrunedTo" + class)
+}
+
+// ReadPrunedTo retrieves the first block whose data of the class is kept, or 0
+// if the class was never pruned.
+func ReadPrunedTo(db kv.Getter, class string) (uint64, error) {
+	data, err := db.GetOne(modules.DatabaseInfo, prunedToKey(class))
+	if err != nil {
+		return 0, err
+	}
+	if len(data) != modules.NumberLength {
+		return 0, nil
+	}
+	return binary.BigEndian.Uint64(data), nil
+}
+
+// WritePrunedTo stores the first block whose data of the class is kept.
+func WritePrunedTo(db kv.Putter, class string, number uint64) error {
+	return db.Put(modules.DatabaseInfo, prunedToKey(class), modules.EncodeBlockNumber(number))
+}
+
+// PruneReceiptsTo removes the receipts of the blocks before the given block.
+func PruneReceiptsTo(ctx context.Context, tx kv.RwTx, to uint64) error {
+	return PruneTable(tx, modules.Receipts, to, ctx, math.MaxInt)
+}
+
+// PruneLogsTo removes the transaction logs of the blocks before the given block.
+func PruneLogsTo(ctx context.Context, tx kv.RwTx, to uint64) error {
+	return PruneTable(tx, modules.Log, to, ctx, math.MaxInt)
+}
+
+// CheckPruned returns ErrHistoryPruned if the data of the class has been pruned
+// for the given block.
+func CheckPruned(db kv.Getter, class string, number uint64) error {
+	prunedTo, err := ReadPrunedTo(db, class)
+	if err != nil {
+		return err
+	}
+	if number < prunedTo {
+		return fmt.Errorf("%w: %s of block %d, first available block is %d", ErrHistoryPruned, strings.ToLower(class), number, prunedTo)
+	}
+	return nil
+}
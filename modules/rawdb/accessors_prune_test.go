@@ -0,0 +1,74 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/amazechain/amc/modules"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+)
+
+// Tests that pruned receipts move the availability horizon and are reported
+// as pruned.
+func TestPruneReceipts(t *testing.T) {
+	_, tx := memdb.NewTestTx(t)
+
+	for number := uint64(1); number <= 10; number++ {
+		if err := tx.Put(modules.Receipts, modules.EncodeBlockNumber(number), []byte{1}); err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+	}
+	if from, err := ReceiptsAvailableFrom(tx); err != nil || from != 1 {
+		t.Fatalf("available from: have %d (%v), want 1", from, err)
+	}
+
+	if err := PruneReceiptsTo(context.Background(), tx, 6); err != nil {
+		t.Fatalf("PruneReceiptsTo failed: %v", err)
+	}
+	if err := WritePrunedTo(tx, PruneReceipts, 6); err != nil {
+		t.Fatalf("WritePrunedTo failed: %v", err)
+	}
+	if from, err := ReceiptsAvailableFrom(tx); err != nil || from != 6 {
+		t.Fatalf("available from: have %d (%v), want 6", from, err)
+	}
+	if v, _ := tx.GetOne(modules.Receipts, modules.EncodeBlockNumber(5)); v != nil {
+		t.Fatalf("receipts of block 5 not pruned")
+	}
+	if err := CheckPruned(tx, PruneReceipts, 5); !errors.Is(err, ErrHistoryPruned) {
+		t.Fatalf("block 5: have %v, want %v", err, ErrHistoryPruned)
+	}
+	if err := CheckPruned(tx, PruneReceipts, 6); err != nil {
+		t.Fatalf("block 6: %v", err)
+	}
+	if err := CheckPruned(tx, PruneLogs, 1); err != nil {
+		t.Fatalf("logs of block 1: %v", err)
+	}
+
+	// once everything is pruned the horizon is kept by the pruned marker
+	if err := PruneReceiptsTo(context.Background(), tx, 11); err != nil {
+		t.Fatalf("PruneReceiptsTo failed: %v", err)
+	}
+	if err := WritePrunedTo(tx, PruneReceipts, 11); err != nil {
+		t.Fatalf("WritePrunedTo failed: %v", err)
+	}
+	if from, err := ReceiptsAvailableFrom(tx); err != nil || from != 11 {
+		t.Fatalf("available from: have %d (%v), want 11", from, err)
+	}
+}
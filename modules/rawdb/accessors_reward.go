@@ -13,54 +13,370 @@
 //
 // You should have received a copy of the GNU Lesser General Public License
 // along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
-
 package rawdb
 
 import (
+	"bytes"
+	"context"
+	"encoding/binary"
 	"encoding/json"
-	"fmt"
-	"github.com/amazechain/amc/common/types"
-	"github.com/holiman/uint256"
+	"math"
+	"strings"
 
+	"github.com/amazechain/amc/common/types"
 	"github.com/amazechain/amc/modules"
+	"github.com/holiman/uint256"
 	"github.com/ledgerwatch/erigon-lib/kv"
 )
 
-// PutEpochReward
-func PutEpochReward(db kv.Putter, key string, val map[types.Address]*uint256.Int) error {
-	valBytes, err := json.Marshal(val)
+// Identifiers of the reward ledger tables in RewardChangeSet keys.
+const (
+	rewardAccrualID byte = iota
+	rewardPaidID
+	rewardUnpaidID
+)
+
+var rewardTables = map[byte]string{
+	rewardAccrualID: modules.RewardAccrual,
+	rewardPaidID:    modules.RewardPaid,
+	rewardUnpaidID:  modules.RewardUnpaid,
+}
+
+// rewardLedgerProgressKey tracks the highest canonical block accounted in the reward ledger.
+var rewardLedgerProgressKey = []byte("RewardLedgerProgress")
+
+// rewardEpochKey = epoch_u64 + address
+func rewardEpochKey(epoch uint64, addr types.Address) []byte {
+	k := make([]byte, modules.NumberLength+types.AddressLength)
+	binary.BigEndian.PutUint64(k, epoch)
+	copy(k[modules.NumberLength:], addr[:])
+	return k
+}
+
+// rewardHistoryKey = address + epoch_u64
+func rewardHistoryKey(addr types.Address, epoch uint64) []byte {
+	k := make([]byte, types.AddressLength+modules.NumberLength)
+	copy(k, addr[:])
+	binary.BigEndian.PutUint64(k[types.AddressLength:], epoch)
+	return k
+}
+
+// rewardChangeKey = block_num_u64 + table_id + key
+func rewardChangeKey(number uint64, id byte, key []byte) []byte {
+	k := make([]byte, modules.NumberLength+1+len(key))
+	binary.BigEndian.PutUint64(k, number)
+	k[modules.NumberLength] = id
+	copy(k[modules.NumberLength+1:], key)
+	return k
+}
+
+// ReadRewardLedgerProgress retrieves the number of the last block accounted in
+// the reward ledger, and whether the ledger was started at all.
+func ReadRewardLedgerProgress(db kv.Getter) (uint64, bool, error) {
+	data, err := db.GetOne(modules.DatabaseInfo, rewardLedgerProgressKey)
+	if err != nil {
+		return 0, false, err
+	}
+	if len(data) != modules.NumberLength {
+		return 0, false, nil
+	}
+	return binary.BigEndian.Uint64(data), true, nil
+}
+
+// WriteRewardLedgerProgress stores the number of the last block accounted in the reward ledger.
+func WriteRewardLedgerProgress(db kv.Putter, number uint64) error {
+	return db.Put(modules.DatabaseInfo, rewardLedgerProgressKey, modules.EncodeBlockNumber(number))
+}
+
+// ReadRewardAccrual retrieves the number of blocks the address verified towards
+// the payout of the epoch.
+func ReadRewardAccrual(db kv.Getter, epoch uint64, addr types.Address) (uint64, error) {
+	data, err := db.GetOne(modules.RewardAccrual, rewardEpochKey(epoch, addr))
+	if err != nil {
+		return 0, err
+	}
+	if len(data) != 8 {
+		return 0, nil
+	}
+	return binary.BigEndian.Uint64(data), nil
+}
+
+// ReadEpochAccruals retrieves the number of blocks verified by every address
+// towards the payout of the epoch.
+func ReadEpochAccruals(db kv.Tx, epoch uint64) (map[types.Address]uint64, error) {
+	accruals := make(map[types.Address]uint64)
+	if err := db.ForPrefix(modules.RewardAccrual, modules.EncodeBlockNumber(epoch), func(k, v []byte) error {
+		if len(k) != modules.NumberLength+types.AddressLength || len(v) != 8 {
+			return nil
+		}
+		accruals[types.BytesToAddress(k[modules.NumberLength:])] = binary.BigEndian.Uint64(v)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return accruals, nil
+}
+
+// ReadEpochAccrualsAt retrieves the number of blocks verified by every address
+// towards the payout of the epoch as of the given block, undoing the changes of
+// the blocks accounted after it.
+func ReadEpochAccrualsAt(db kv.Tx, epoch, number uint64) (map[types.Address]uint64, error) {
+	accruals, err := ReadEpochAccruals(db, epoch)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := rewardChangesSince(db, number+1, rewardAccrualID, modules.EncodeBlockNumber(epoch))
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range changes {
+		addr := types.BytesToAddress([]byte(key)[modules.NumberLength:])
+		if len(value) != 8 {
+			delete(accruals, addr)
+			continue
+		}
+		accruals[addr] = binary.BigEndian.Uint64(value)
+	}
+	return accruals, nil
+}
+
+// WriteRewardAccrual stores the number of blocks the address verified towards
+// the payout of the epoch, as changed by the given block.
+func WriteRewardAccrual(tx kv.RwTx, number, epoch uint64, addr types.Address, count uint64) error {
+	var value []byte
+	if count > 0 {
+		value = modules.EncodeBlockNumber(count)
+	}
+	return writeRewardEntry(tx, number, rewardAccrualID, rewardEpochKey(epoch, addr), value)
+}
+
+// PutRewardAccrual stores the number of blocks the address verified towards the
+// payout of the epoch without recording a change.
+func PutRewardAccrual(tx kv.RwTx, epoch uint64, addr types.Address, count uint64) error {
+	return putRewardEntry(tx, rewardAccrualID, rewardEpochKey(epoch, addr), modules.EncodeBlockNumber(count))
+}
+
+// ReadEpochRewards retrieves the rewards paid out by the reward block of the epoch.
+func ReadEpochRewards(db kv.Tx, epoch uint64) (map[types.Address]*uint256.Int, error) {
+	rewards := make(map[types.Address]*uint256.Int)
+	if err := db.ForPrefix(modules.RewardPaid, modules.EncodeBlockNumber(epoch), func(k, v []byte) error {
+		if len(k) != modules.NumberLength+types.AddressLength {
+			return nil
+		}
+		rewards[types.BytesToAddress(k[modules.NumberLength:])] = new(uint256.Int).SetBytes(v)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return rewards, nil
+}
+
+// ReadAccountRewards calls fn with the rewards paid out to the address by the
+// epochs in [from, to], oldest first.
+func ReadAccountRewards(db kv.Tx, addr types.Address, from, to uint64, fn func(epoch uint64, amount *uint256.Int) error) error {
+	c, err := db.Cursor(modules.RewardHistory)
 	if err != nil {
 		return err
 	}
-	return db.Put(modules.Reward, []byte(key), valBytes)
+	defer c.Close()
+	for k, v, err := c.Seek(rewardHistoryKey(addr, from)); k != nil; k, v, err = c.Next() {
+		if err != nil {
+			return err
+		}
+		if len(k) != types.AddressLength+modules.NumberLength || !bytes.HasPrefix(k, addr[:]) {
+			break
+		}
+		epoch := binary.BigEndian.Uint64(k[types.AddressLength:])
+		if epoch > to {
+			break
+		}
+		if err := fn(epoch, new(uint256.Int).SetBytes(v)); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-// PutAccountReward
-func PutAccountReward(db kv.Putter, key string, val *uint256.Int) error {
-	return db.Put(modules.Reward, []byte(key), val.Bytes())
+// WriteEpochReward stores the reward paid out to the address by the reward block
+// of the epoch, which is the given block.
+func WriteEpochReward(tx kv.RwTx, number, epoch uint64, addr types.Address, amount *uint256.Int) error {
+	return writeRewardEntry(tx, number, rewardPaidID, rewardEpochKey(epoch, addr), amount.Bytes())
 }
 
-// GetAccountReward
-func GetAccountReward(db kv.Getter, key string) (*uint256.Int, error) {
-	val, err := db.GetOne(modules.Reward, []byte(key))
+// PutEpochReward stores the reward paid out to the address by the reward block of
+// the epoch without recording a change.
+func PutEpochReward(tx kv.RwTx, epoch uint64, addr types.Address, amount *uint256.Int) error {
+	return putRewardEntry(tx, rewardPaidID, rewardEpochKey(epoch, addr), amount.Bytes())
+}
+
+// ReadUnpaidReward retrieves the reward carried over by the address while below
+// the payout limit.
+func ReadUnpaidReward(db kv.Getter, addr types.Address) (*uint256.Int, error) {
+	data, err := db.GetOne(modules.RewardUnpaid, addr[:])
 	if err != nil {
-		return uint256.NewInt(0), err
+		return nil, err
 	}
-	return uint256.NewInt(0).SetBytes(val), nil
+	return new(uint256.Int).SetBytes(data), nil
 }
 
-func GetEpochReward(db kv.Getter, epoch *uint256.Int) (map[types.Address]*uint256.Int, error) {
-	key := fmt.Sprintf("epoch:%s", epoch.String())
-	valBytes, err := db.GetOne(modules.Reward, []byte(key))
+// ReadUnpaidRewardAt retrieves the reward carried over by the address as of the
+// given block, undoing the changes of the blocks accounted after it.
+func ReadUnpaidRewardAt(db kv.Tx, addr types.Address, number uint64) (*uint256.Int, error) {
+	changes, err := rewardChangesSince(db, number+1, rewardUnpaidID, addr[:])
 	if err != nil {
 		return nil, err
 	}
-	if len(valBytes) == 0 {
-		return nil, nil
+	if value, ok := changes[string(addr[:])]; ok {
+		return new(uint256.Int).SetBytes(value), nil
+	}
+	return ReadUnpaidReward(db, addr)
+}
+
+// WriteUnpaidReward stores the reward carried over by the address, as changed by
+// the given block.
+func WriteUnpaidReward(tx kv.RwTx, number uint64, addr types.Address, amount *uint256.Int) error {
+	return writeRewardEntry(tx, number, rewardUnpaidID, types.CopyBytes(addr[:]), amount.Bytes())
+}
+
+// PutUnpaidReward stores the reward carried over by the address without recording
+// a change.
+func PutUnpaidReward(tx kv.RwTx, addr types.Address, amount *uint256.Int) error {
+	return putRewardEntry(tx, rewardUnpaidID, types.CopyBytes(addr[:]), amount.Bytes())
+}
+
+// writeRewardEntry stores a reward ledger entry, recording the value it held
+// before the block the first time the block changes it. An empty value deletes
+// the entry, an unchanged one is not recorded.
+func writeRewardEntry(tx kv.RwTx, number uint64, id byte, key, value []byte) error {
+	prev, err := tx.GetOne(rewardTables[id], key)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(prev, value) {
+		return nil
+	}
+	changeKey := rewardChangeKey(number, id, key)
+	recorded, err := tx.Has(modules.RewardChangeSet, changeKey)
+	if err != nil {
+		return err
+	}
+	if !recorded {
+		if err := tx.Put(modules.RewardChangeSet, changeKey, types.CopyBytes(prev)); err != nil {
+			return err
+		}
 	}
-	re := make(map[types.Address]*uint256.Int, 0)
-	if err := json.Unmarshal(valBytes, &re); err != nil {
+	return putRewardEntry(tx, id, key, value)
+}
+
+// putRewardEntry stores or deletes a reward ledger entry, keeping RewardHistory
+// in line with RewardPaid.
+func putRewardEntry(tx kv.RwTx, id byte, key, value []byte) error {
+	table := rewardTables[id]
+	if len(value) == 0 {
+		if err := tx.Delete(table, key); err != nil {
+			return err
+		}
+	} else if err := tx.Put(table, key, value); err != nil {
+		return err
+	}
+	if id != rewardPaidID {
+		return nil
+	}
+	historyKey := rewardHistoryKey(types.BytesToAddress(key[modules.NumberLength:]), binary.BigEndian.Uint64(key))
+	if len(value) == 0 {
+		return tx.Delete(modules.RewardHistory, historyKey)
+	}
+	return tx.Put(modules.RewardHistory, historyKey, value)
+}
+
+// rewardChangesSince retrieves the entries of a reward ledger table under the key
+// prefix changed by the blocks from the given number onwards, with the value
+// each held before them.
+func rewardChangesSince(db kv.Tx, from uint64, id byte, prefix []byte) (map[string][]byte, error) {
+	changes := make(map[string][]byte)
+	if err := db.ForEach(modules.RewardChangeSet, modules.EncodeBlockNumber(from), func(k, v []byte) error {
+		if len(k) <= modules.NumberLength || k[modules.NumberLength] != id || !bytes.HasPrefix(k[modules.NumberLength+1:], prefix) {
+			return nil
+		}
+		// The oldest change of an entry holds its value before the blocks.
+		key := string(k[modules.NumberLength+1:])
+		if _, ok := changes[key]; !ok {
+			changes[key] = types.CopyBytes(v)
+		}
+		return nil
+	}); err != nil {
 		return nil, err
 	}
-	return re, nil
+	return changes, nil
+}
+
+// UnwindRewardLedger restores the reward ledger entries changed by the blocks
+// from the given number onwards and drops their changesets.
+func UnwindRewardLedger(tx kv.RwTx, from uint64) error {
+	c, err := tx.RwCursor(modules.RewardChangeSet)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	restored := make(map[string]struct{})
+	for k, v, err := c.Seek(modules.EncodeBlockNumber(from)); k != nil; k, v, err = c.Next() {
+		if err != nil {
+			return err
+		}
+		if len(k) <= modules.NumberLength {
+			continue
+		}
+		// The oldest change of an entry holds its value before the unwound blocks.
+		entry := types.CopyBytes(k[modules.NumberLength:])
+		if _, ok := restored[string(entry)]; !ok {
+			restored[string(entry)] = struct{}{}
+			if err := putRewardEntry(tx, entry[0], entry[1:], types.CopyBytes(v)); err != nil {
+				return err
+			}
+		}
+		if err := c.DeleteCurrent(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadLegacyRewards retrieves the unpaid rewards and the epoch payouts stored
+// under "account:" and "epoch:" keys before the reward ledger.
+func ReadLegacyRewards(db kv.Tx) (map[types.Address]*uint256.Int, map[uint64]map[types.Address]*uint256.Int, error) {
+	unpaid := make(map[types.Address]*uint256.Int)
+	paid := make(map[uint64]map[types.Address]*uint256.Int)
+	if err := db.ForEach(modules.Reward, nil, func(k, v []byte) error {
+		key := string(k)
+		switch {
+		case strings.HasPrefix(key, "account:"):
+			unpaid[types.HexToAddress(strings.TrimPrefix(key, "account:"))] = new(uint256.Int).SetBytes(v)
+		case strings.HasPrefix(key, "epoch:"):
+			epoch, err := uint256.FromHex(strings.TrimPrefix(key, "epoch:"))
+			if err != nil {
+				return nil
+			}
+			rewards := make(map[types.Address]*uint256.Int)
+			if err := json.Unmarshal(v, &rewards); err != nil {
+				return err
+			}
+			paid[epoch.Uint64()] = rewards
+		}
+		return nil
+	}); err != nil {
+		return nil, nil, err
+	}
+	return unpaid, paid, nil
+}
+
+// DeleteLegacyRewards removes the rewards stored before the reward ledger.
+func DeleteLegacyRewards(tx kv.RwTx) error {
+	return tx.ClearBucket(modules.Reward)
+}
+
+// PruneRewardChangeSetsTo removes the reward ledger changesets of the blocks
+// before the given block.
+func PruneRewardChangeSetsTo(ctx context.Context, tx kv.RwTx, to uint64) error {
+	return PruneTable(tx, modules.RewardChangeSet, to, ctx, math.MaxInt)
 }
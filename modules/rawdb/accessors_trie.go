@@ -0,0 +1,48 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/modules"
+	"github.com/amazechain/amc/modules/trie"
+	"github.com/ledgerwatch/erigon-lib/kv"
+)
+
+// ReadTrieNode retrieves the encoded state trie node with the given hash.
+func ReadTrieNode(db kv.Getter, hash types.Hash) ([]byte, error) {
+	return db.GetOne(modules.TrieNode, hash[:])
+}
+
+// WriteTrieNode stores an encoded state trie node under its hash.
+func WriteTrieNode(db kv.Putter, hash types.Hash, enc []byte) error {
+	return db.Put(modules.TrieNode, hash[:], enc)
+}
+
+// trieNodeReader serves state trie nodes from the TrieNode table.
+type trieNodeReader struct {
+	db kv.Getter
+}
+
+// NewTrieNodeReader returns a trie.NodeReader backed by the TrieNode table.
+func NewTrieNodeReader(db kv.Getter) trie.NodeReader {
+	return &trieNodeReader{db: db}
+}
+
+func (r *trieNodeReader) Node(hash types.Hash) ([]byte, error) {
+	return ReadTrieNode(r.db, hash)
+}
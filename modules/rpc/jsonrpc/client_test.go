@@ -41,6 +41,55 @@ func TestHttpClient(t *testing.T) {
 	}
 }
 
+type countService struct{}
+
+// Count notifies the numbers below n.
+func (countService) Count(ctx context.Context, n int) (*Subscription, error) {
+	notifier, supported := NotifierFromContext(ctx)
+	if !supported {
+		return nil, ErrNotificationsUnsupported
+	}
+	sub := notifier.CreateSubscription()
+	go func() {
+		for i := 0; i < n; i++ {
+			if err := notifier.Notify(sub.ID, i); err != nil {
+				return
+			}
+		}
+	}()
+	return sub, nil
+}
+
+// Tests that notifications reach the subscriptions of the client.
+func TestClientSubscribe(t *testing.T) {
+	server := NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("test", countService{}); err != nil {
+		t.Fatal(err)
+	}
+	client := DialInProc(server)
+	defer client.Close()
+
+	ch := make(chan int)
+	sub, err := client.Subscribe(context.Background(), "test", ch, "count", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+	for want := 0; want < 3; want++ {
+		select {
+		case have := <-ch:
+			if have != want {
+				t.Fatalf("have notification %d, want %d", have, want)
+			}
+		case err := <-sub.Err():
+			t.Fatalf("subscription failed: %v", err)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("notification %d not delivered", want)
+		}
+	}
+}
+
 //func TestIPC(t *testing.T) {
 //	client, _ := Dial("")
 //	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
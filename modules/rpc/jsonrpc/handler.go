@@ -157,6 +157,10 @@ func (h *handler) cancelAllRequests(err error, inflightReq *requestOp) {
 			didClose[op] = true
 		}
 	}
+	for id, sub := range h.clientSubs {
+		delete(h.clientSubs, id)
+		sub.close(err)
+	}
 }
 
 func (h *handler) addSubscriptions(nn []*Notifier) {
@@ -216,6 +220,9 @@ func (h *handler) handleSubscriptionResult(msg *jsonrpcMessage) {
 		log.Debug("Dropping invalid subscription message")
 		return
 	}
+	if h.clientSubs[result.ID] != nil {
+		h.clientSubs[result.ID].deliver(result.Result)
+	}
 }
 
 func (h *handler) handleResponse(msg *jsonrpcMessage) {
@@ -225,7 +232,23 @@ func (h *handler) handleResponse(msg *jsonrpcMessage) {
 		return
 	}
 	delete(h.respWait, string(msg.ID))
-	op.resp <- msg
+	// For normal responses, just forward the reply to Call/BatchCall.
+	if op.sub == nil {
+		op.resp <- msg
+		return
+	}
+	// For subscription responses, start the subscription if the server
+	// indicates success. Subscribe gets unblocked in either case through
+	// the op.resp channel.
+	defer close(op.resp)
+	if msg.Error != nil {
+		op.err = msg.Error
+		return
+	}
+	if op.err = json.Unmarshal(msg.Result, &op.sub.subid); op.err == nil {
+		go op.sub.run()
+		h.clientSubs[op.sub.subid] = op.sub
+	}
 }
 
 func (h *handler) handleCallMsg(ctx *callProc, msg *jsonrpcMessage) *jsonrpcMessage {
@@ -235,6 +258,7 @@ func (h *handler) handleCallMsg(ctx *callProc, msg *jsonrpcMessage) *jsonrpcMess
 	case msg.isCall():
 		log.Trace("begin "+msg.Method, "p", string(msg.Params))
 		resp := h.handleCall(ctx, msg)
+		h.recordCall(msg.Method, resp, time.Since(start))
 		var ctx []interface{}
 		ctx = append(ctx, "reqid", idForLog{msg.ID}, "t", time.Since(start), "p", string(msg.Params), "r", string(resp.Result))
 		if resp.Error != nil {
@@ -254,6 +278,22 @@ func (h *handler) handleCallMsg(ctx *callProc, msg *jsonrpcMessage) *jsonrpcMess
 	}
 }
 
+// recordCall updates the request counters and the per-method latency timer.
+// Calls to unknown methods only count as failures, so that arbitrary method
+// names cannot flood the metrics registry.
+func (h *handler) recordCall(method string, resp *jsonrpcMessage, elapsed time.Duration) {
+	rpcRequestCounter.Inc(1)
+	if resp.Error != nil {
+		failedRequestCounter.Inc(1)
+	} else {
+		successfulRequestCounter.Inc(1)
+	}
+	if resp.Error != nil && resp.Error.Code == (&methodNotFoundError{}).ErrorCode() {
+		return
+	}
+	newRPCServingTimer(method, resp.Error == nil).Update(elapsed)
+}
+
 func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage {
 	if msg.isSubscribe() {
 		return h.handleSubscribe(cp, msg)
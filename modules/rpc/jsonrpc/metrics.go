@@ -0,0 +1,40 @@
+// Copyright 2022 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package jsonrpc
+
+import (
+	"fmt"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+var (
+	rpcRequestCounter        = metrics.GetOrRegisterCounter("rpc/requests", nil)
+	successfulRequestCounter = metrics.GetOrRegisterCounter("rpc/success", nil)
+	failedRequestCounter     = metrics.GetOrRegisterCounter("rpc/failure", nil)
+)
+
+// newRPCServingTimer returns the timer recording the serving latency of the
+// given method, split by whether the call succeeded.
+func newRPCServingTimer(method string, valid bool) metrics.Timer {
+	flag := "success"
+	if !valid {
+		flag = "failure"
+	}
+	m := fmt.Sprintf("rpc/duration/%s/%s", method, flag)
+	return metrics.GetOrRegisterTimer(m, nil)
+}
@@ -0,0 +1,87 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"bytes"
+
+	"github.com/amazechain/amc/common/account"
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/modules"
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+)
+
+// DumpAccount is an account with its code and storage as it was after a block.
+type DumpAccount struct {
+	Address types.Address
+	Balance uint256.Int
+	Nonce   uint64
+	Code    []byte
+	Storage map[types.Hash]types.Hash
+}
+
+// DumpState calls fn for every account as it was after block blockNr, reading
+// the history tables for blocks below the head.
+func DumpState(tx kv.Tx, blockNr uint64, fn func(*DumpAccount) error) error {
+	return WalkAsOfAccounts(tx, types.Address{}, blockNr+1, func(k, v []byte) (bool, error) {
+		if len(v) == 0 {
+			return true, nil
+		}
+		var acc account.StateAccount
+		if err := acc.DecodeForStorage(v); err != nil {
+			return false, err
+		}
+		dump := &DumpAccount{
+			Address: types.BytesToAddress(k),
+			Balance: acc.Balance,
+			Nonce:   acc.Nonce,
+		}
+		if acc.Incarnation > 0 {
+			// Account change sets omit the code hash of contracts
+			codeHash := acc.CodeHash
+			if acc.IsEmptyCodeHash() {
+				hash, err := tx.GetOne(modules.PlainContractCode, modules.PlainGenerateStoragePrefix(k, acc.Incarnation))
+				if err != nil {
+					return false, err
+				}
+				codeHash = types.BytesToHash(hash)
+			}
+			if !account.IsEmptyCodeHash(codeHash) && codeHash != (types.Hash{}) {
+				code, err := tx.GetOne(modules.Code, codeHash[:])
+				if err != nil {
+					return false, err
+				}
+				dump.Code = types.CopyBytes(code)
+			}
+			dump.Storage = make(map[types.Hash]types.Hash)
+			err := WalkAsOfStorage(tx, dump.Address, acc.Incarnation, types.Hash{}, blockNr+1, func(kAddr, kLoc, v []byte) (bool, error) {
+				if !bytes.Equal(kAddr, k) {
+					return false, nil
+				}
+				if len(v) > 0 {
+					dump.Storage[types.BytesToHash(kLoc)] = new(uint256.Int).SetBytes(v).Bytes32()
+				}
+				return true, nil
+			})
+			if err != nil {
+				return false, err
+			}
+		}
+		return true, fn(dump)
+	})
+}
@@ -0,0 +1,108 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"context"
+	"testing"
+
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/modules"
+	"github.com/amazechain/amc/params"
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+)
+
+// Tests that dumps of historical blocks hold the balances, nonces, code and
+// storage of that block rather than of the head.
+func TestDumpState(t *testing.T) {
+	modules.AmcInit()
+	kv.ChaindataTablesCfg = modules.AmcTableCfg
+	db := memdb.New(t.TempDir())
+	defer db.Close()
+	tx, err := db.BeginRw(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	var (
+		rules    = &params.Rules{IsSpuriousDragon: true}
+		alice    = types.HexToAddress("0x1000000000000000000000000000000000000001")
+		bob      = types.HexToAddress("0x2000000000000000000000000000000000000002")
+		contract = types.HexToAddress("0x3000000000000000000000000000000000000003")
+		slot1    = types.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000001")
+		slot2    = types.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000002")
+		code     = []byte{0x60, 0x00, 0x60, 0x00}
+	)
+	runBlock := func(number uint64, fn func(ibs *IntraBlockState)) {
+		ibs := New(NewPlainStateReader(tx))
+		fn(ibs)
+		if err := ibs.FinalizeTx(rules, NewNoopWriter()); err != nil {
+			t.Fatal(err)
+		}
+		w := NewPlainStateWriter(tx, tx, number)
+		if err := ibs.CommitBlock(rules, w); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.WriteChangeSets(); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.WriteHistory(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	runBlock(1, func(ibs *IntraBlockState) {
+		ibs.AddBalance(alice, uint256.NewInt(1))
+		ibs.SetNonce(alice, 1)
+		ibs.CreateAccount(contract, true)
+		ibs.SetCode(contract, code)
+		ibs.SetState(contract, &slot1, *uint256.NewInt(1))
+	})
+	runBlock(2, func(ibs *IntraBlockState) {
+		ibs.AddBalance(alice, uint256.NewInt(2))
+		ibs.AddBalance(bob, uint256.NewInt(3))
+		ibs.SetState(contract, &slot1, *uint256.NewInt(2))
+		ibs.SetState(contract, &slot2, *uint256.NewInt(5))
+	})
+
+	dump := func(number uint64) map[types.Address]*DumpAccount {
+		accounts := make(map[types.Address]*DumpAccount)
+		if err := DumpState(tx, number, func(acc *DumpAccount) error {
+			accounts[acc.Address] = acc
+			return nil
+		}); err != nil {
+			t.Fatalf("dump of block %d: %v", number, err)
+		}
+		return accounts
+	}
+	old, head := dump(1), dump(2)
+
+	if len(old) != 2 || old[alice] == nil || old[alice].Balance.Uint64() != 1 || old[alice].Nonce != 1 {
+		t.Errorf("block 1: have %d accounts, alice %+v", len(old), old[alice])
+	}
+	if c := old[contract]; c == nil || string(c.Code) != string(code) || len(c.Storage) != 1 || c.Storage[slot1] != types.Hash(uint256.NewInt(1).Bytes32()) {
+		t.Errorf("block 1: contract %+v", c)
+	}
+	if len(head) != 3 || head[alice].Balance.Uint64() != 3 || head[bob].Balance.Uint64() != 3 {
+		t.Errorf("block 2: have %d accounts, alice %+v, bob %+v", len(head), head[alice], head[bob])
+	}
+	if c := head[contract]; c == nil || string(c.Code) != string(code) || len(c.Storage) != 2 || c.Storage[slot1] != types.Hash(uint256.NewInt(2).Bytes32()) || c.Storage[slot2] != types.Hash(uint256.NewInt(5).Bytes32()) {
+		t.Errorf("block 2: contract %+v", c)
+	}
+}
@@ -36,6 +36,11 @@ type EntireCode struct {
 	Codes    []*HashCode     `json:"codes"`
 	Headers  []*block.Header `json:"headers"`
 	Rewards  []*block.Reward `json:"rewards"`
+
+	// From the state commitment fork on, the root of the state trie the block is
+	// executed on and the trie nodes it reads (see StateCommitment.Witness).
+	StateRoot types.Hash `json:"stateRoot"`
+	TrieNodes [][]byte   `json:"trieNodes"`
 }
 
 type HashCode struct {
@@ -97,6 +97,8 @@ type IntraBlockState struct {
 	snap    *Snapshot
 	codeMap map[types.Hash][]byte
 	height  uint64
+
+	commitment *StateCommitment
 }
 
 // Create a new state from a given trie
@@ -1029,8 +1031,30 @@ func (s *IntraBlockState) GenerateRootHash() types.Hash {
 	return root
 }
 
+// SetStateCommitment makes IntermediateRoot return the root of the state commitment
+// updated with the changes of the block, instead of the hash of the dirty accounts.
+func (s *IntraBlockState) SetStateCommitment(c *StateCommitment) {
+	s.commitment = c
+}
+
+// StateCommitment returns the state commitment of the block, or nil if there is none.
+func (s *IntraBlockState) StateCommitment() *StateCommitment {
+	return s.commitment
+}
+
 // IntermediateRoot root
 func (s *IntraBlockState) IntermediateRoot() types.Hash {
+	if s.commitment != nil {
+		root, err := s.commitment.apply(s)
+		if err != nil {
+			log.Error("Failed to update the state commitment", "err", err)
+			if s.savedErr == nil {
+				s.savedErr = err
+			}
+			return types.Hash{}
+		}
+		return root
+	}
 	return s.GenerateRootHash()
 }
 
@@ -0,0 +1,421 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sort"
+
+	"github.com/amazechain/amc/common/account"
+	"github.com/amazechain/amc/common/crypto"
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/internal/avm/rlp"
+	"github.com/amazechain/amc/modules"
+	"github.com/amazechain/amc/modules/rawdb"
+	"github.com/amazechain/amc/modules/trie"
+	"github.com/amazechain/amc/params"
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+)
+
+// trieAccount is the RLP layout of an account in the account trie.
+type trieAccount struct {
+	Nonce    uint64
+	Balance  *uint256.Int
+	Root     types.Hash // root of the storage trie
+	CodeHash types.Hash
+}
+
+// StateCommitment maintains the Merkle Patricia trie committing to all accounts and
+// storage from the state commitment fork on. As in Ethereum, the account trie is keyed
+// by keccak(address) and holds rlp([nonce, balance, storageRoot, codeHash]), and the
+// storage trie of an account is keyed by keccak(slot) and holds rlp(value).
+type StateCommitment struct {
+	db      trie.NodeReader
+	rules   *params.Rules
+	root    types.Hash // root the commitment was opened with
+	trie    *trie.Trie
+	storage map[types.Address]*trie.Trie // storage tries opened while applying blocks
+	witness *witnessReader               // records the nodes read, if requested
+}
+
+// NewStateCommitment opens the state commitment with the given root.
+func NewStateCommitment(db kv.Getter, root types.Hash, rules *params.Rules) (*StateCommitment, error) {
+	return newStateCommitment(rawdb.NewTrieNodeReader(db), root, rules)
+}
+
+// NewWitnessStateCommitment opens the state commitment with the given root and
+// records every trie node read while applying the block, so that the block can be
+// verified without the database (see Witness).
+func NewWitnessStateCommitment(db kv.Getter, root types.Hash, rules *params.Rules) (*StateCommitment, error) {
+	witness := &witnessReader{reader: rawdb.NewTrieNodeReader(db), nodes: make(map[types.Hash][]byte)}
+	c, err := newStateCommitment(witness, root, rules)
+	if err != nil {
+		return nil, err
+	}
+	c.witness = witness
+	return c, nil
+}
+
+// NewStatelessStateCommitment opens the state commitment with the given root on the
+// trie nodes of a witness only. Applying a block fails with a trie.MissingNodeError
+// if the witness lacks a node the block needs.
+func NewStatelessStateCommitment(nodes [][]byte, root types.Hash, rules *params.Rules) (*StateCommitment, error) {
+	reader := make(witnessNodes, len(nodes))
+	for _, enc := range nodes {
+		reader[crypto.Keccak256Hash(enc)] = enc
+	}
+	return newStateCommitment(reader, root, rules)
+}
+
+func newStateCommitment(reader trie.NodeReader, root types.Hash, rules *params.Rules) (*StateCommitment, error) {
+	t, err := trie.New(root, reader)
+	if err != nil {
+		return nil, err
+	}
+	return &StateCommitment{
+		db:      reader,
+		rules:   rules,
+		root:    root,
+		trie:    t,
+		storage: make(map[types.Address]*trie.Trie),
+	}, nil
+}
+
+// GenerateStateCommitment builds the state commitment of the whole plain state in
+// memory. It is used once, at the block activating the state commitment.
+func GenerateStateCommitment(tx kv.Tx, rules *params.Rules) (*StateCommitment, error) {
+	c, err := NewStateCommitment(tx, trie.EmptyRoot, rules)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.ForEach(modules.Account, nil, func(k, v []byte) error {
+		var acc account.StateAccount
+		if err := acc.DecodeForStorage(v); err != nil {
+			return fmt.Errorf("decoding account %x: %w", k, err)
+		}
+		addr := types.BytesToAddress(k)
+		st, _ := trie.New(trie.EmptyRoot, c.db)
+		prefix := make([]byte, types.AddressLength+types.IncarnationLength)
+		copy(prefix, addr[:])
+		binary.BigEndian.PutUint16(prefix[types.AddressLength:], acc.Incarnation)
+		if err := tx.ForPrefix(modules.Storage, prefix, func(sk, sv []byte) error {
+			_, _, slot := modules.PlainParseCompositeStorageKey(sk)
+			var value uint256.Int
+			value.SetBytes(sv)
+			return updateStorageTrie(st, slot, &value)
+		}); err != nil {
+			return err
+		}
+		c.storage[addr] = st
+		return c.updateAccount(addr, &acc, st.Hash())
+	}); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Root returns the root the state commitment was opened with, which is the state
+// root of the parent block.
+func (c *StateCommitment) Root() types.Hash {
+	return c.root
+}
+
+// Witness returns the trie nodes read since the commitment was opened with
+// NewWitnessStateCommitment, sorted by hash. Together with Root they are enough to
+// apply the same block with NewStatelessStateCommitment.
+func (c *StateCommitment) Witness() [][]byte {
+	if c.witness == nil {
+		return nil
+	}
+	hashes := make([]types.Hash, 0, len(c.witness.nodes))
+	for hash := range c.witness.nodes {
+		hashes = append(hashes, hash)
+	}
+	sort.Slice(hashes, func(i, j int) bool {
+		return bytes.Compare(hashes[i][:], hashes[j][:]) < 0
+	})
+	nodes := make([][]byte, len(hashes))
+	for i, hash := range hashes {
+		nodes[i] = c.witness.nodes[hash]
+	}
+	return nodes
+}
+
+// Hash returns the current root of the state commitment.
+func (c *StateCommitment) Hash() types.Hash {
+	return c.trie.Hash()
+}
+
+// Commit writes every new trie node to the database and returns the state root.
+func (c *StateCommitment) Commit(db kv.Putter) (types.Hash, error) {
+	put := func(hash types.Hash, enc []byte) error {
+		return rawdb.WriteTrieNode(db, hash, enc)
+	}
+	for addr, st := range c.storage {
+		if _, err := st.Commit(put); err != nil {
+			return types.Hash{}, fmt.Errorf("committing storage trie of %x: %w", addr, err)
+		}
+	}
+	return c.trie.Commit(put)
+}
+
+// apply updates the tries with the accounts and storage modified by the block and
+// returns the new root. It follows the same rules as MakeWriteSet and only writes the
+// final values, so calling it several times for the same block is harmless.
+//
+// Accounts and slots are applied in sorted order: the trie nodes a deletion has to
+// load depend on the order of the updates, and a verifier applying the block on a
+// witness has to load the same nodes as the miner that recorded it.
+func (c *StateCommitment) apply(sdb *IntraBlockState) (types.Hash, error) {
+	addrs := make(types.Addresses, 0, len(sdb.stateObjects))
+	for addr := range sdb.stateObjects {
+		addrs = append(addrs, addr)
+	}
+	sort.Sort(addrs)
+	for _, addr := range addrs {
+		so := sdb.stateObjects[addr]
+		_, isDirty := sdb.stateObjectsDirty[addr]
+		if !isDirty {
+			_, isDirty = sdb.journal.dirties[addr]
+		}
+		emptyRemoval := c.rules.IsSpuriousDragon && so.empty() && (!c.rules.IsAura || addr != SystemAddress)
+		if so.selfdestructed || (isDirty && emptyRemoval) {
+			if err := c.trie.Delete(crypto.Keccak256(addr[:])); err != nil {
+				return types.Hash{}, err
+			}
+			delete(c.storage, addr)
+		}
+		if isDirty && (so.created || !so.selfdestructed) && !emptyRemoval {
+			st, err := c.storageTrie(addr, so.created)
+			if err != nil {
+				return types.Hash{}, err
+			}
+			slots := make([]types.Hash, 0, len(so.dirtyStorage))
+			for slot := range so.dirtyStorage {
+				slots = append(slots, slot)
+			}
+			sort.Slice(slots, func(i, j int) bool {
+				return bytes.Compare(slots[i][:], slots[j][:]) < 0
+			})
+			for _, slot := range slots {
+				value := so.dirtyStorage[slot]
+				if err := updateStorageTrie(st, slot, &value); err != nil {
+					return types.Hash{}, err
+				}
+			}
+			if err := c.updateAccount(addr, &so.data, st.Hash()); err != nil {
+				return types.Hash{}, err
+			}
+		}
+	}
+	return c.trie.Hash(), nil
+}
+
+// storageTrie returns the storage trie of addr, which is empty for a newly created account.
+func (c *StateCommitment) storageTrie(addr types.Address, created bool) (*trie.Trie, error) {
+	if created {
+		st, _ := trie.New(trie.EmptyRoot, c.db)
+		c.storage[addr] = st
+		return st, nil
+	}
+	if st, ok := c.storage[addr]; ok {
+		return st, nil
+	}
+	acc, err := readTrieAccount(c.trie, addr)
+	if err != nil {
+		return nil, err
+	}
+	root := trie.EmptyRoot
+	if acc != nil {
+		root = acc.Root
+	}
+	st, err := trie.New(root, c.db)
+	if err != nil {
+		return nil, err
+	}
+	c.storage[addr] = st
+	return st, nil
+}
+
+func (c *StateCommitment) updateAccount(addr types.Address, acc *account.StateAccount, storageRoot types.Hash) error {
+	enc, err := rlp.EncodeToBytes(&trieAccount{
+		Nonce:    acc.Nonce,
+		Balance:  &acc.Balance,
+		Root:     storageRoot,
+		CodeHash: acc.CodeHash,
+	})
+	if err != nil {
+		return err
+	}
+	return c.trie.Update(crypto.Keccak256(addr[:]), enc)
+}
+
+// witnessReader records the trie nodes read through it.
+type witnessReader struct {
+	reader trie.NodeReader
+	nodes  map[types.Hash][]byte
+}
+
+func (r *witnessReader) Node(hash types.Hash) ([]byte, error) {
+	enc, err := r.reader.Node(hash)
+	if err == nil && len(enc) > 0 {
+		r.nodes[hash] = types.CopyBytes(enc)
+	}
+	return enc, err
+}
+
+// witnessNodes serves the trie nodes of a witness by their hash.
+type witnessNodes map[types.Hash][]byte
+
+func (w witnessNodes) Node(hash types.Hash) ([]byte, error) {
+	return w[hash], nil
+}
+
+func updateStorageTrie(st *trie.Trie, slot types.Hash, value *uint256.Int) error {
+	key := crypto.Keccak256(slot[:])
+	if value.IsZero() {
+		return st.Delete(key)
+	}
+	enc, err := rlp.EncodeToBytes(value.Bytes())
+	if err != nil {
+		return err
+	}
+	return st.Update(key, enc)
+}
+
+func readTrieAccount(t *trie.Trie, addr types.Address) (*trieAccount, error) {
+	enc, err := t.Get(crypto.Keccak256(addr[:]))
+	if err != nil || enc == nil {
+		return nil, err
+	}
+	acc := new(trieAccount)
+	if err := rlp.DecodeBytes(enc, acc); err != nil {
+		return nil, fmt.Errorf("decoding trie account %x: %w", addr, err)
+	}
+	return acc, nil
+}
+
+// VerifyAccounts checks the plain state of the given accounts against the stored
+// state commitment with the root. Their nonce, balance and code hash must match,
+// and accounts missing from the plain state must be missing from the trie too.
+func VerifyAccounts(tx kv.Tx, root types.Hash, addrs []types.Address) error {
+	t, err := trie.New(root, rawdb.NewTrieNodeReader(tx))
+	if err != nil {
+		return err
+	}
+	for _, addr := range addrs {
+		have, err := readTrieAccount(t, addr)
+		if err != nil {
+			return err
+		}
+		enc, err := tx.GetOne(modules.Account, addr[:])
+		if err != nil {
+			return err
+		}
+		if len(enc) == 0 {
+			if have != nil {
+				return fmt.Errorf("account %x is missing from the plain state", addr)
+			}
+			continue
+		}
+		var acc account.StateAccount
+		if err := acc.DecodeForStorage(enc); err != nil {
+			return fmt.Errorf("decoding account %x: %w", addr, err)
+		}
+		if have == nil || have.Nonce != acc.Nonce || !have.Balance.Eq(&acc.Balance) || have.CodeHash != acc.CodeHash {
+			return fmt.Errorf("account %x does not match the state commitment", addr)
+		}
+	}
+	return nil
+}
+
+// StorageProof is the Merkle proof of a storage slot.
+type StorageProof struct {
+	Key   types.Hash
+	Value *uint256.Int
+	Proof [][]byte
+}
+
+// AccountProof is the Merkle proof of an account and some of its storage slots.
+type AccountProof struct {
+	Address      types.Address
+	Nonce        uint64
+	Balance      *uint256.Int
+	CodeHash     types.Hash
+	StorageHash  types.Hash
+	Proof        [][]byte
+	StorageProof []StorageProof
+}
+
+// ProveAccount builds the proofs of an account and of the given storage slots against
+// the state root. Absent accounts and slots are proven by exclusion proofs.
+func ProveAccount(db kv.Getter, root types.Hash, addr types.Address, slots []types.Hash) (*AccountProof, error) {
+	reader := rawdb.NewTrieNodeReader(db)
+	t, err := trie.New(root, reader)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := t.Prove(crypto.Keccak256(addr[:]))
+	if err != nil {
+		return nil, err
+	}
+	acc, err := readTrieAccount(t, addr)
+	if err != nil {
+		return nil, err
+	}
+	if acc == nil {
+		acc = &trieAccount{Balance: new(uint256.Int), Root: trie.EmptyRoot, CodeHash: emptyCodeHashH}
+	}
+	st, err := trie.New(acc.Root, reader)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AccountProof{
+		Address:      addr,
+		Nonce:        acc.Nonce,
+		Balance:      acc.Balance,
+		CodeHash:     acc.CodeHash,
+		StorageHash:  acc.Root,
+		Proof:        proof,
+		StorageProof: make([]StorageProof, len(slots)),
+	}
+	for i, slot := range slots {
+		key := crypto.Keccak256(slot[:])
+		proof, err := st.Prove(key)
+		if err != nil {
+			return nil, err
+		}
+		value := new(uint256.Int)
+		enc, err := st.Get(key)
+		if err != nil {
+			return nil, err
+		}
+		if enc != nil {
+			var content []byte
+			if err := rlp.DecodeBytes(enc, &content); err != nil {
+				return nil, fmt.Errorf("decoding storage slot %x of %x: %w", slot, addr, err)
+			}
+			value.SetBytes(content)
+		}
+		result.StorageProof[i] = StorageProof{Key: slot, Value: value, Proof: proof}
+	}
+	return result, nil
+}
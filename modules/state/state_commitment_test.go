@@ -0,0 +1,152 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"context"
+	"testing"
+
+	"github.com/amazechain/amc/common/crypto"
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/internal/avm/rlp"
+	"github.com/amazechain/amc/modules"
+	"github.com/amazechain/amc/modules/trie"
+	"github.com/amazechain/amc/params"
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+)
+
+// Tests that the incrementally maintained state commitment matches the commitment
+// generated from the plain state, and that its proofs verify.
+func TestStateCommitment(t *testing.T) {
+	modules.AmcInit()
+	kv.ChaindataTablesCfg = modules.AmcTableCfg
+	db := memdb.New(t.TempDir())
+	defer db.Close()
+	tx, err := db.BeginRw(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	var (
+		rules    = &params.Rules{IsSpuriousDragon: true}
+		alice    = types.HexToAddress("0x1000000000000000000000000000000000000001")
+		bob      = types.HexToAddress("0x2000000000000000000000000000000000000002")
+		contract = types.HexToAddress("0x3000000000000000000000000000000000000003")
+		slot1    = types.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000001")
+		slot2    = types.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000002")
+	)
+	// runBlock executes fn on top of the committed state and returns the new root.
+	runBlock := func(number uint64, parent types.Hash, fn func(ibs *IntraBlockState)) types.Hash {
+		var c *StateCommitment
+		if parent == (types.Hash{}) {
+			c, err = GenerateStateCommitment(tx, rules)
+		} else {
+			c, err = NewStateCommitment(tx, parent, rules)
+		}
+		if err != nil {
+			t.Fatalf("block %d: opening state commitment failed: %v", number, err)
+		}
+		ibs := New(NewPlainStateReader(tx))
+		ibs.SetStateCommitment(c)
+		fn(ibs)
+		if err := ibs.FinalizeTx(rules, NewNoopWriter()); err != nil {
+			t.Fatal(err)
+		}
+		root := ibs.IntermediateRoot()
+		if err := ibs.CommitBlock(rules, NewPlainStateWriter(tx, tx, number)); err != nil {
+			t.Fatal(err)
+		}
+		if committed, err := c.Commit(tx); err != nil || committed != root {
+			t.Fatalf("block %d: commit mismatch: have %x (err %v), want %x", number, committed, err, root)
+		}
+		generated, err := GenerateStateCommitment(tx, rules)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if generated.Hash() != root {
+			t.Fatalf("block %d: root mismatch: incremental %x, generated %x", number, root, generated.Hash())
+		}
+		return root
+	}
+
+	root1 := runBlock(1, types.Hash{}, func(ibs *IntraBlockState) {
+		ibs.AddBalance(alice, uint256.NewInt(1000))
+		ibs.AddBalance(bob, uint256.NewInt(10))
+		ibs.CreateAccount(contract, true)
+		ibs.SetCode(contract, []byte{0x60, 0x00})
+		ibs.SetNonce(contract, 1)
+		ibs.SetState(contract, &slot1, *uint256.NewInt(0xaa))
+		ibs.SetState(contract, &slot2, *uint256.NewInt(0xbb))
+	})
+	if root1 == trie.EmptyRoot {
+		t.Fatalf("state root of a non-empty state is the empty root")
+	}
+	root2 := runBlock(2, root1, func(ibs *IntraBlockState) {
+		ibs.SubBalance(alice, uint256.NewInt(100))
+		ibs.AddBalance(bob, uint256.NewInt(100))
+		ibs.SetState(contract, &slot1, *uint256.NewInt(0))
+		ibs.SetState(contract, &slot2, *uint256.NewInt(0xcc))
+	})
+	if root2 == root1 {
+		t.Fatalf("state root unchanged by block 2")
+	}
+
+	proof, err := ProveAccount(tx, root2, contract, []types.Hash{slot1, slot2})
+	if err != nil {
+		t.Fatalf("ProveAccount failed: %v", err)
+	}
+	enc, err := trie.VerifyProof(root2, crypto.Keccak256(contract[:]), proof.Proof)
+	if err != nil || enc == nil {
+		t.Fatalf("account proof verification failed: %v", err)
+	}
+	var acc trieAccount
+	if err := rlp.DecodeBytes(enc, &acc); err != nil {
+		t.Fatal(err)
+	}
+	if acc.Nonce != 1 || acc.Root != proof.StorageHash {
+		t.Fatalf("proven account mismatch: nonce %d, storage root %x", acc.Nonce, acc.Root)
+	}
+	if !proof.StorageProof[0].Value.IsZero() || proof.StorageProof[1].Value.Uint64() != 0xcc {
+		t.Fatalf("storage values mismatch: %v, %v", proof.StorageProof[0].Value, proof.StorageProof[1].Value)
+	}
+	if enc, err := trie.VerifyProof(proof.StorageHash, crypto.Keccak256(slot1[:]), proof.StorageProof[0].Proof); err != nil || enc != nil {
+		t.Fatalf("exclusion proof of cleared slot failed: %x, %v", enc, err)
+	}
+	if enc, err := trie.VerifyProof(proof.StorageHash, crypto.Keccak256(slot2[:]), proof.StorageProof[1].Proof); err != nil || enc == nil {
+		t.Fatalf("storage proof failed: %v", err)
+	}
+
+	absent := types.HexToAddress("0x4000000000000000000000000000000000000004")
+	if err := VerifyAccounts(tx, root2, []types.Address{alice, bob, contract, absent}); err != nil {
+		t.Fatalf("plain state does not match the commitment: %v", err)
+	}
+	if err := VerifyAccounts(tx, root1, []types.Address{alice}); err == nil {
+		t.Fatal("plain state matched an outdated commitment")
+	}
+	if err := VerifyAccounts(tx, types.Hash{0x01}, []types.Address{alice}); err == nil {
+		t.Fatal("plain state matched a commitment not stored")
+	}
+
+	// The proofs of block 1 remain available.
+	old, err := ProveAccount(tx, root1, alice, nil)
+	if err != nil || old.Balance.Uint64() != 1000 {
+		t.Fatalf("historical proof mismatch: %v, %v", old, err)
+	}
+}
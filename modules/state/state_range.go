@@ -0,0 +1,84 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"bytes"
+
+	"github.com/amazechain/amc/common/account"
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/modules"
+	"github.com/ledgerwatch/erigon-lib/kv"
+)
+
+// AccountRange returns up to max accounts with an address in [origin, limit), as they
+// were after block blockNr. A nil limit means no upper bound. The values are in the
+// storage encoding of the Account table, and more reports whether the range holds
+// further accounts.
+func AccountRange(tx kv.Tx, blockNr uint64, origin, limit []byte, max int) (keys, values [][]byte, more bool, err error) {
+	err = WalkAsOfAccounts(tx, types.BytesToAddress(origin), blockNr+1, func(k, v []byte) (bool, error) {
+		if limit != nil && bytes.Compare(k, limit) >= 0 {
+			return false, nil
+		}
+		if len(keys) >= max {
+			more = true
+			return false, nil
+		}
+		var acc account.StateAccount
+		if err := acc.DecodeForStorage(v); err != nil {
+			return false, err
+		}
+		// Account change sets omit the code hash of contracts
+		if acc.Incarnation > 0 && acc.IsEmptyCodeHash() {
+			codeHash, err := tx.GetOne(modules.PlainContractCode, modules.PlainGenerateStoragePrefix(k, acc.Incarnation))
+			if err != nil {
+				return false, err
+			}
+			if len(codeHash) > 0 {
+				acc.CodeHash.SetBytes(codeHash)
+			}
+			v = make([]byte, acc.EncodingLengthForStorage())
+			acc.EncodeForStorage(v)
+		}
+		keys = append(keys, types.CopyBytes(k))
+		values = append(values, types.CopyBytes(v))
+		return true, nil
+	})
+	return keys, values, more, err
+}
+
+// StorageRange returns up to max storage slots of the given account incarnation from
+// origin on, as they were after block blockNr. The keys are composite keys of the
+// Storage table and more reports whether the account holds further slots.
+func StorageRange(tx kv.Tx, blockNr uint64, addr types.Address, incarnation uint16, origin types.Hash, max int) (keys, values [][]byte, more bool, err error) {
+	err = WalkAsOfStorage(tx, addr, incarnation, origin, blockNr+1, func(kAddr, kLoc, v []byte) (bool, error) {
+		if !bytes.Equal(kAddr, addr[:]) {
+			return false, nil
+		}
+		if len(v) == 0 {
+			return true, nil
+		}
+		if len(keys) >= max {
+			more = true
+			return false, nil
+		}
+		keys = append(keys, modules.PlainGenerateCompositeStorageKey(addr[:], incarnation, kLoc))
+		values = append(values, types.CopyBytes(v))
+		return true, nil
+	})
+	return keys, values, more, err
+}
@@ -0,0 +1,83 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/amazechain/amc/common/account"
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/modules"
+	"github.com/amazechain/amc/modules/changeset"
+	"github.com/amazechain/amc/modules/ethdb/bitmapdb"
+	"github.com/ledgerwatch/erigon-lib/etl"
+	"github.com/ledgerwatch/erigon-lib/kv"
+)
+
+// UnwindState reverts the plain state written by blocks (target, head] using the
+// account and storage changesets of those blocks, then drops the changesets and
+// the history index entries they produced.
+func UnwindState(tx kv.RwTx, head, target uint64, quit <-chan struct{}) error {
+	if target >= head {
+		return nil
+	}
+	// The oldest entry of every key holds its value before block target+1.
+	changes := etl.NewCollector("Unwind", os.TempDir(), etl.NewOldestEntryBuffer(etl.BufferOptimalSize))
+	defer changes.Close()
+	if err := changeset.RewindData(tx, head, target, changes, quit); err != nil {
+		return fmt.Errorf("collecting rewind data: %w", err)
+	}
+
+	writer := NewPlainStateWriterNoHistory(tx)
+	if err := changes.Load(tx, "", func(k, v []byte, _ etl.CurrentTableReader, _ etl.LoadNextFunc) error {
+		if len(k) == types.AddressLength {
+			if err := bitmapdb.TruncateRange64(tx, modules.AccountsHistory, k, target+1); err != nil {
+				return err
+			}
+			if len(v) == 0 {
+				return tx.Delete(modules.Account, k)
+			}
+			var acc account.StateAccount
+			if err := acc.DecodeForStorage(v); err != nil {
+				return fmt.Errorf("decoding account %x: %w", k, err)
+			}
+			// Changesets omit the code hash of contracts, recover it as the readers do
+			if acc.Incarnation > 0 && acc.IsEmptyCodeHash() {
+				codeHash, err := tx.GetOne(modules.PlainContractCode, modules.PlainGenerateStoragePrefix(k, acc.Incarnation))
+				if err != nil {
+					return err
+				}
+				if len(codeHash) > 0 {
+					acc.CodeHash = types.BytesToHash(codeHash)
+				}
+			}
+			return writer.UpdateAccountData(types.BytesToAddress(k), nil, &acc)
+		}
+		if err := bitmapdb.TruncateRange64(tx, modules.StorageHistory, modules.CompositeKeyWithoutIncarnation(k), target+1); err != nil {
+			return err
+		}
+		if len(v) == 0 {
+			return tx.Delete(modules.Storage, k)
+		}
+		return tx.Put(modules.Storage, k, v)
+	}, etl.TransformArgs{Quit: quit}); err != nil {
+		return fmt.Errorf("applying rewind data: %w", err)
+	}
+
+	return changeset.Truncate(tx, target+1)
+}
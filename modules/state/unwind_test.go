@@ -0,0 +1,118 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"context"
+	"testing"
+
+	"github.com/amazechain/amc/common/crypto"
+	"github.com/amazechain/amc/common/types"
+	"github.com/amazechain/amc/modules"
+	"github.com/amazechain/amc/modules/changeset"
+	"github.com/amazechain/amc/params"
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+)
+
+// Tests that unwinding restores the plain state of an earlier block from the
+// changesets and drops the changesets of the unwound blocks.
+func TestUnwindState(t *testing.T) {
+	modules.AmcInit()
+	kv.ChaindataTablesCfg = modules.AmcTableCfg
+	db := memdb.New(t.TempDir())
+	defer db.Close()
+	tx, err := db.BeginRw(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	var (
+		rules    = &params.Rules{IsSpuriousDragon: true}
+		alice    = types.HexToAddress("0x1000000000000000000000000000000000000001")
+		bob      = types.HexToAddress("0x2000000000000000000000000000000000000002")
+		contract = types.HexToAddress("0x3000000000000000000000000000000000000003")
+		slot1    = types.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000001")
+		slot2    = types.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000002")
+		code     = []byte{0x60, 0x00, 0x60, 0x00}
+	)
+	runBlock := func(number uint64, fn func(ibs *IntraBlockState)) {
+		ibs := New(NewPlainStateReader(tx))
+		fn(ibs)
+		if err := ibs.FinalizeTx(rules, NewNoopWriter()); err != nil {
+			t.Fatal(err)
+		}
+		w := NewPlainStateWriter(tx, tx, number)
+		if err := ibs.CommitBlock(rules, w); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.WriteChangeSets(); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.WriteHistory(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	runBlock(1, func(ibs *IntraBlockState) {
+		ibs.AddBalance(alice, uint256.NewInt(1))
+		ibs.CreateAccount(contract, true)
+		ibs.SetCode(contract, code)
+		ibs.SetState(contract, &slot1, *uint256.NewInt(1))
+	})
+	runBlock(2, func(ibs *IntraBlockState) {
+		ibs.AddBalance(alice, uint256.NewInt(2))
+		ibs.AddBalance(bob, uint256.NewInt(3))
+		ibs.SetState(contract, &slot1, *uint256.NewInt(2))
+		ibs.SetState(contract, &slot2, *uint256.NewInt(5))
+	})
+
+	if err := UnwindState(tx, 2, 1, nil); err != nil {
+		t.Fatalf("unwind failed: %v", err)
+	}
+
+	reader := NewPlainStateReader(tx)
+	acc, err := reader.ReadAccountData(alice)
+	if err != nil || acc == nil || acc.Balance.Uint64() != 1 {
+		t.Errorf("alice: have %v (err %v), want balance 1", acc, err)
+	}
+	if acc, err := reader.ReadAccountData(bob); err != nil || acc != nil {
+		t.Errorf("bob: have %v (err %v), want no account", acc, err)
+	}
+	acc, err = reader.ReadAccountData(contract)
+	if err != nil || acc == nil {
+		t.Fatalf("contract: have %v (err %v)", acc, err)
+	}
+	if want := crypto.Keccak256Hash(code); acc.CodeHash != want {
+		t.Errorf("contract code hash: have %x, want %x", acc.CodeHash, want)
+	}
+	if v, err := reader.ReadAccountStorage(contract, acc.Incarnation, &slot1); err != nil || uint256.NewInt(0).SetBytes(v).Uint64() != 1 {
+		t.Errorf("slot1: have %x (err %v), want 1", v, err)
+	}
+	if v, err := reader.ReadAccountStorage(contract, acc.Incarnation, &slot2); err != nil || v != nil {
+		t.Errorf("slot2: have %x (err %v), want empty", v, err)
+	}
+	for _, bucket := range []string{modules.AccountChangeSet, modules.StorageChangeSet} {
+		if err := changeset.ForRange(tx, bucket, 2, 3, func(n uint64, k, _ []byte) error {
+			t.Errorf("%s: changeset of block %d left for %x", bucket, n, k)
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -39,7 +39,7 @@ const (
 	Code    = "Code"    // contract code hash -> contract code
 	Account = "Account" // address(un hashed) -> account encoded
 	Storage = "Storage" // address (un hashed) + incarnation + storage key (un hashed) -> storage value(types.Hash)
-	Reward  = "Reward"  // ...
+	Reward  = "Reward"  // legacy "account:" and "epoch:" keyed rewards, migrated into the reward ledger
 	Deposit = "Deposit" // Deposit info
 
 	//key - addressHash+incarnation
@@ -52,6 +52,28 @@ const (
 	IncarnationMap = "IncarnationMap" // address -> incarnation of account when it was last deleted
 )
 
+// StateCommitment
+const (
+	// TrieNode stores the nodes of the account and storage tries committing to the state.
+	// Nodes are never deleted, so every root written since the state commitment fork can be proven.
+	TrieNode = "TrieNode" // node hash -> RLP encoded trie node
+)
+
+// Reward ledger
+const (
+	RewardAccrual   = "RewardAccrual"   // epoch_u64 + address -> blocks verified towards the payout of the epoch (u64)
+	RewardPaid      = "RewardPaid"      // epoch_u64 + address -> reward paid out by the reward block of the epoch
+	RewardHistory   = "RewardHistory"   // address + epoch_u64 -> reward paid out by the reward block of the epoch
+	RewardUnpaid    = "RewardUnpaid"    // address -> reward carried over while below the payout limit
+	RewardChangeSet = "RewardChangeSet" // block_num_u64 + table_id + key -> value before the block
+)
+
+// Deposit snapshots
+const (
+	DepositSnapshot = "DepositSnapshot" // block_num_u64 + hash -> hash of the deposit set after the block
+	DepositSet      = "DepositSet"      // deposit set hash -> address + public key + amount of every depositor
+)
+
 // HistoryState
 const (
 	AccountChangeSet = "AccountChangeSet" // blockNum_u64 ->  address + account(encoded)
@@ -98,10 +120,14 @@ const (
 	// shard number - it's biggest value in bitmap
 	LogTopicIndex   = "LogTopicIndex"
 	LogAddressIndex = "LogAddressIndex"
+	// LogIndexSet is the name of the DupSort-ed table that records, per canonical block, the keys added to
+	// LogAddressIndex and LogTopicIndex, so that the index can be unwound after the logs were replaced.
+	// 8-byte BE block number -> address (20 bytes) or topic (32 bytes)
+	LogIndexSet = "LogIndexSet"
 
-	// CallTraceSet is the name of the table that contain the mapping of block number to the set (sorted) of all accounts
+	// CallTraceSet is the name of the table that contain the mapping of block to the set (sorted) of all accounts
 	// touched by call traces. It is DupSort-ed table
-	// 8-byte BE block number -> account address -> two bits (one for "from", another for "to")
+	// 8-byte BE block number + block hash -> account address -> two bits (one for "from", another for "to")
 	CallTraceSet = "CallTraceSet"
 	// Indices for call traces - have the same format as LogTopicIndex and LogAddressIndex
 	// Store bitmap indices - in which block number we saw calls from (CallFromIndex) or to (CallToIndex) some addresses
@@ -125,6 +151,7 @@ var AmcTables = []string{
 	Storage,
 	PlainContractCode,
 	IncarnationMap,
+	TrieNode,
 
 	DatabaseInfo,
 	ChainConfig,
@@ -149,13 +176,26 @@ var AmcTables = []string{
 	Senders,
 	Receipts,
 	Log,
+	LogTopicIndex,
+	LogAddressIndex,
+	LogIndexSet,
+	CallTraceSet,
+	CallFromIndex,
+	CallToIndex,
 
 	SignersDB,
 	PoaSnapshot,
 	Sequence,
 
 	Reward,
+	RewardAccrual,
+	RewardPaid,
+	RewardHistory,
+	RewardUnpaid,
+	RewardChangeSet,
 	Deposit,
+	DepositSnapshot,
+	DepositSet,
 	BlockVerify,
 	BlockRewards,
 }
@@ -163,6 +203,8 @@ var AmcTables = []string{
 var AmcTableCfg = kv.TableCfg{
 	AccountChangeSet: {Flags: kv.DupSort},
 	StorageChangeSet: {Flags: kv.DupSort},
+	CallTraceSet:     {Flags: kv.DupSort},
+	LogIndexSet:      {Flags: kv.DupSort},
 	Storage: {
 		Flags:                     kv.DupSort,
 		AutoDupSortKeysConversion: true,
@@ -0,0 +1,175 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package trie
+
+import (
+	"fmt"
+
+	"github.com/amazechain/amc/internal/avm/rlp"
+)
+
+type node interface{}
+
+type (
+	// fullNode is a branch node with one child per nibble and a value slot.
+	fullNode struct {
+		Children [17]node
+		flags    nodeFlag
+	}
+	// shortNode is an extension node, or a leaf node if Key has the terminator.
+	shortNode struct {
+		Key   []byte
+		Val   node
+		flags nodeFlag
+	}
+	// hashNode references a node stored in the database by its hash.
+	hashNode []byte
+	// valueNode is the value of a leaf.
+	valueNode []byte
+)
+
+// nodeFlag contains caching-related metadata about a node.
+type nodeFlag struct {
+	hash  hashNode // cached hash of the node, nil if unknown or the node is embedded
+	dirty bool     // whether the node has changes that must be written to the database
+}
+
+func (n *fullNode) copy() *fullNode   { copy := *n; return &copy }
+func (n *shortNode) copy() *shortNode { copy := *n; return &copy }
+
+// encodeString returns the RLP encoding of b as a string.
+func encodeString(b []byte) []byte {
+	if len(b) == 1 && b[0] < 0x80 {
+		return []byte{b[0]}
+	}
+	return append(encodeHead(0x80, 0xB7, uint64(len(b))), b...)
+}
+
+// encodeList returns the RLP encoding of a list made of the already encoded items.
+func encodeList(items ...[]byte) []byte {
+	var size uint64
+	for _, item := range items {
+		size += uint64(len(item))
+	}
+	buf := encodeHead(0xC0, 0xF7, size)
+	for _, item := range items {
+		buf = append(buf, item...)
+	}
+	return buf
+}
+
+func encodeHead(smalltag, largetag byte, size uint64) []byte {
+	if size < 56 {
+		return []byte{smalltag + byte(size)}
+	}
+	var sizeBytes []byte
+	for ; size > 0; size >>= 8 {
+		sizeBytes = append([]byte{byte(size)}, sizeBytes...)
+	}
+	return append([]byte{largetag + byte(len(sizeBytes))}, sizeBytes...)
+}
+
+func decodeNode(hash, buf []byte) (node, error) {
+	if len(buf) == 0 {
+		return nil, fmt.Errorf("empty node")
+	}
+	elems, _, err := rlp.SplitList(buf)
+	if err != nil {
+		return nil, fmt.Errorf("decode error: %w", err)
+	}
+	switch c, _ := rlp.CountValues(elems); c {
+	case 2:
+		n, err := decodeShort(hash, elems)
+		if err != nil {
+			return nil, fmt.Errorf("short node: %w", err)
+		}
+		return n, nil
+	case 17:
+		n, err := decodeFull(hash, elems)
+		if err != nil {
+			return nil, fmt.Errorf("full node: %w", err)
+		}
+		return n, nil
+	default:
+		return nil, fmt.Errorf("invalid number of list elements: %v", c)
+	}
+}
+
+func decodeShort(hash, elems []byte) (node, error) {
+	kbuf, rest, err := rlp.SplitString(elems)
+	if err != nil {
+		return nil, err
+	}
+	flag := nodeFlag{hash: hash}
+	key := compactToHex(kbuf)
+	if hasTerm(key) {
+		// value node
+		val, _, err := rlp.SplitString(rest)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value node: %w", err)
+		}
+		return &shortNode{Key: key, Val: valueNode(val), flags: flag}, nil
+	}
+	r, _, err := decodeRef(rest)
+	if err != nil {
+		return nil, err
+	}
+	return &shortNode{Key: key, Val: r, flags: flag}, nil
+}
+
+func decodeFull(hash, elems []byte) (*fullNode, error) {
+	n := &fullNode{flags: nodeFlag{hash: hash}}
+	for i := 0; i < 16; i++ {
+		cld, rest, err := decodeRef(elems)
+		if err != nil {
+			return n, fmt.Errorf("child %d: %w", i, err)
+		}
+		n.Children[i], elems = cld, rest
+	}
+	val, _, err := rlp.SplitString(elems)
+	if err != nil {
+		return n, err
+	}
+	if len(val) > 0 {
+		n.Children[16] = valueNode(val)
+	}
+	return n, nil
+}
+
+func decodeRef(buf []byte) (node, []byte, error) {
+	kind, val, rest, err := rlp.Split(buf)
+	if err != nil {
+		return nil, buf, err
+	}
+	switch {
+	case kind == rlp.List:
+		// 'embedded' node reference. The encoding must be smaller
+		// than a hash in order to be valid.
+		if size := len(buf) - len(rest); size > hashLen {
+			return nil, buf, fmt.Errorf("oversized embedded node (size is %d bytes, want size < %d)", size, hashLen)
+		}
+		n, err := decodeNode(nil, buf)
+		return n, rest, err
+	case kind == rlp.String && len(val) == 0:
+		// empty node
+		return nil, rest, nil
+	case kind == rlp.String && len(val) == hashLen:
+		return hashNode(val), rest, nil
+	default:
+		return nil, nil, fmt.Errorf("invalid RLP string size %d (want 0 or 32)", len(val))
+	}
+}
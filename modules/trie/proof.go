@@ -0,0 +1,127 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package trie
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/amazechain/amc/common/crypto"
+	"github.com/amazechain/amc/common/types"
+)
+
+// Prove returns the Merkle proof for key: the encoded nodes on the path from the
+// root to the value, or to the point where the path ends if key is absent.
+// Nodes embedded in their parent are not included separately.
+func (t *Trie) Prove(key []byte) ([][]byte, error) {
+	k := keybytesToHex(key)
+	var nodes []node
+	tn := t.root
+	for len(k) > 0 && tn != nil {
+		switch n := tn.(type) {
+		case *shortNode:
+			if len(k) < len(n.Key) || !bytes.Equal(n.Key, k[:len(n.Key)]) {
+				// The trie doesn't contain the key.
+				tn = nil
+			} else {
+				tn, k = n.Val, k[len(n.Key):]
+			}
+			nodes = append(nodes, n)
+		case *fullNode:
+			tn, k = n.Children[k[0]], k[1:]
+			nodes = append(nodes, n)
+		case hashNode:
+			rn, err := t.resolveHash(n)
+			if err != nil {
+				return nil, err
+			}
+			tn = rn
+		default:
+			tn = nil
+		}
+	}
+	proof := make([][]byte, 0, len(nodes))
+	for i, n := range nodes {
+		enc := t.encode(n)
+		if i == 0 || len(enc) >= hashLen {
+			proof = append(proof, enc)
+		}
+	}
+	return proof, nil
+}
+
+// VerifyProof checks a proof produced by Prove against root and returns the value
+// proven for key, or nil if the proof shows that key is absent.
+func VerifyProof(root types.Hash, key []byte, proof [][]byte) ([]byte, error) {
+	nodes := make(map[types.Hash][]byte, len(proof))
+	for _, enc := range proof {
+		nodes[crypto.Keccak256Hash(enc)] = enc
+	}
+	k := keybytesToHex(key)
+	want := root
+	for i := 0; ; i++ {
+		enc, ok := nodes[want]
+		if !ok {
+			return nil, fmt.Errorf("proof node %d (hash %x) missing", i, want)
+		}
+		n, err := decodeNode(want[:], enc)
+		if err != nil {
+			return nil, fmt.Errorf("bad proof node %d: %w", i, err)
+		}
+		rest, child := walk(n, k)
+		switch child := child.(type) {
+		case nil:
+			// The trie doesn't contain the key.
+			return nil, nil
+		case hashNode:
+			k = rest
+			want = types.BytesToHash(child)
+		case valueNode:
+			return child, nil
+		default:
+			return nil, errors.New("invalid proof")
+		}
+	}
+}
+
+// walk follows key through n and its embedded children, returning the remaining
+// key and the node where the walk stopped.
+func walk(tn node, key []byte) ([]byte, node) {
+	for {
+		switch n := tn.(type) {
+		case *shortNode:
+			if len(key) < len(n.Key) || !bytes.Equal(n.Key, key[:len(n.Key)]) {
+				return nil, nil
+			}
+			tn, key = n.Val, key[len(n.Key):]
+		case *fullNode:
+			if len(key) == 0 {
+				return nil, nil
+			}
+			tn, key = n.Children[key[0]], key[1:]
+		case hashNode:
+			return key, n
+		case nil:
+			return key, nil
+		case valueNode:
+			return nil, n
+		default:
+			panic(fmt.Sprintf("%T: invalid node: %v", tn, tn))
+		}
+	}
+}
@@ -0,0 +1,423 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package trie implements the Merkle Patricia trie used to commit to the state.
+package trie
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/amazechain/amc/common/crypto"
+	"github.com/amazechain/amc/common/types"
+)
+
+const hashLen = types.HashLength
+
+// EmptyRoot is the known root hash of an empty trie.
+var EmptyRoot = types.HexToHash("56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
+
+// NodeReader retrieves encoded trie nodes by their hash.
+type NodeReader interface {
+	Node(hash types.Hash) ([]byte, error)
+}
+
+// MissingNodeError is returned when a node referenced by the trie is not in the database.
+type MissingNodeError struct {
+	NodeHash types.Hash
+}
+
+func (err *MissingNodeError) Error() string {
+	return fmt.Sprintf("missing trie node %x", err.NodeHash)
+}
+
+// Trie is a Merkle Patricia trie. Nodes are loaded lazily from a NodeReader and
+// modified nodes are kept in memory until Commit hands them to the caller.
+//
+// Trie is not safe for concurrent use.
+type Trie struct {
+	root node
+	db   NodeReader
+}
+
+// New opens the trie with the given root. An empty or zero root creates an empty trie.
+func New(root types.Hash, db NodeReader) (*Trie, error) {
+	t := &Trie{db: db}
+	if root != (types.Hash{}) && root != EmptyRoot {
+		n, err := t.resolveHash(root[:])
+		if err != nil {
+			return nil, err
+		}
+		t.root = n
+	}
+	return t, nil
+}
+
+// Get returns the value stored for key, or nil if it is not present.
+func (t *Trie) Get(key []byte) ([]byte, error) {
+	k := keybytesToHex(key)
+	n := t.root
+	for {
+		switch nn := n.(type) {
+		case nil:
+			return nil, nil
+		case valueNode:
+			return nn, nil
+		case *shortNode:
+			if len(k) < len(nn.Key) || !bytes.Equal(nn.Key, k[:len(nn.Key)]) {
+				return nil, nil
+			}
+			n, k = nn.Val, k[len(nn.Key):]
+		case *fullNode:
+			n, k = nn.Children[k[0]], k[1:]
+		case hashNode:
+			rn, err := t.resolveHash(nn)
+			if err != nil {
+				return nil, err
+			}
+			n = rn
+		default:
+			panic(fmt.Sprintf("%T: invalid node: %v", n, n))
+		}
+	}
+}
+
+// Update associates key with value. An empty value deletes the key.
+func (t *Trie) Update(key, value []byte) error {
+	k := keybytesToHex(key)
+	if len(value) == 0 {
+		_, n, err := t.delete(t.root, k)
+		if err != nil {
+			return err
+		}
+		t.root = n
+		return nil
+	}
+	_, n, err := t.insert(t.root, k, valueNode(value))
+	if err != nil {
+		return err
+	}
+	t.root = n
+	return nil
+}
+
+// Delete removes key from the trie.
+func (t *Trie) Delete(key []byte) error {
+	return t.Update(key, nil)
+}
+
+func (t *Trie) insert(n node, key []byte, value node) (bool, node, error) {
+	if len(key) == 0 {
+		if v, ok := n.(valueNode); ok {
+			return !bytes.Equal(v, value.(valueNode)), value, nil
+		}
+		return true, value, nil
+	}
+	switch n := n.(type) {
+	case *shortNode:
+		matchlen := prefixLen(key, n.Key)
+		// If the whole key matches, keep this short node as is
+		// and only update the value.
+		if matchlen == len(n.Key) {
+			dirty, nn, err := t.insert(n.Val, key[matchlen:], value)
+			if !dirty || err != nil {
+				return false, n, err
+			}
+			return true, &shortNode{Key: n.Key, Val: nn, flags: newFlag()}, nil
+		}
+		// Otherwise branch out at the index where they differ.
+		branch := &fullNode{flags: newFlag()}
+		var err error
+		_, branch.Children[n.Key[matchlen]], err = t.insert(nil, n.Key[matchlen+1:], n.Val)
+		if err != nil {
+			return false, nil, err
+		}
+		_, branch.Children[key[matchlen]], err = t.insert(nil, key[matchlen+1:], value)
+		if err != nil {
+			return false, nil, err
+		}
+		// Replace this shortNode with the branch if it occurs at index 0.
+		if matchlen == 0 {
+			return true, branch, nil
+		}
+		// Otherwise, replace it with a short node leading up to the branch.
+		return true, &shortNode{Key: key[:matchlen], Val: branch, flags: newFlag()}, nil
+
+	case *fullNode:
+		dirty, nn, err := t.insert(n.Children[key[0]], key[1:], value)
+		if !dirty || err != nil {
+			return false, n, err
+		}
+		n = n.copy()
+		n.flags = newFlag()
+		n.Children[key[0]] = nn
+		return true, n, nil
+
+	case nil:
+		return true, &shortNode{Key: key, Val: value, flags: newFlag()}, nil
+
+	case hashNode:
+		// We've hit a part of the trie that isn't loaded yet. Load
+		// the node and insert into it. This leaves all child nodes on
+		// the path to the value in the trie.
+		rn, err := t.resolveHash(n)
+		if err != nil {
+			return false, nil, err
+		}
+		dirty, nn, err := t.insert(rn, key, value)
+		if !dirty || err != nil {
+			return false, rn, err
+		}
+		return true, nn, nil
+
+	default:
+		panic(fmt.Sprintf("%T: invalid node: %v", n, n))
+	}
+}
+
+func (t *Trie) delete(n node, key []byte) (bool, node, error) {
+	switch n := n.(type) {
+	case *shortNode:
+		matchlen := prefixLen(key, n.Key)
+		if matchlen < len(n.Key) {
+			return false, n, nil // don't replace n on mismatch
+		}
+		if matchlen == len(key) {
+			return true, nil, nil // remove n entirely for whole matches
+		}
+		// The key is longer than n.Key. Remove the remaining suffix
+		// from the subtrie. Child can never be nil here since the
+		// subtrie must contain at least two other values with keys
+		// longer than n.Key.
+		dirty, child, err := t.delete(n.Val, key[len(n.Key):])
+		if !dirty || err != nil {
+			return false, n, err
+		}
+		switch child := child.(type) {
+		case *shortNode:
+			// Deleting from the subtrie reduced it to another
+			// short node. Merge the nodes to avoid creating a
+			// shortNode{..., shortNode{...}}.
+			return true, &shortNode{Key: concat(n.Key, child.Key...), Val: child.Val, flags: newFlag()}, nil
+		default:
+			return true, &shortNode{Key: n.Key, Val: child, flags: newFlag()}, nil
+		}
+
+	case *fullNode:
+		dirty, nn, err := t.delete(n.Children[key[0]], key[1:])
+		if !dirty || err != nil {
+			return false, n, err
+		}
+		n = n.copy()
+		n.flags = newFlag()
+		n.Children[key[0]] = nn
+
+		// Because n is a full node, it must've contained at least two children
+		// before the delete operation. If the new child value is non-nil, n still
+		// has at least two children after the deletion, and cannot be reduced to
+		// a short node.
+		if nn != nil {
+			return true, n, nil
+		}
+		// Check how many non-nil entries are left after deleting and
+		// reduce the full node to a short node if only one entry is
+		// left.
+		pos := -1
+		for i, cld := range &n.Children {
+			if cld != nil {
+				if pos == -1 {
+					pos = i
+				} else {
+					pos = -2
+					break
+				}
+			}
+		}
+		if pos >= 0 {
+			if pos != 16 {
+				// If the remaining entry is a short node, it replaces
+				// n and its key gets the missing nibble tacked to the
+				// front.
+				cnode, err := t.resolve(n.Children[pos])
+				if err != nil {
+					return false, nil, err
+				}
+				if cnode, ok := cnode.(*shortNode); ok {
+					k := concat([]byte{byte(pos)}, cnode.Key...)
+					return true, &shortNode{Key: k, Val: cnode.Val, flags: newFlag()}, nil
+				}
+			}
+			// Otherwise, n is replaced by a one-nibble short node
+			// containing the child.
+			return true, &shortNode{Key: []byte{byte(pos)}, Val: n.Children[pos], flags: newFlag()}, nil
+		}
+		// n still contains at least two values and cannot be reduced.
+		return true, n, nil
+
+	case valueNode:
+		return true, nil, nil
+
+	case nil:
+		return false, nil, nil
+
+	case hashNode:
+		rn, err := t.resolveHash(n)
+		if err != nil {
+			return false, nil, err
+		}
+		dirty, nn, err := t.delete(rn, key)
+		if !dirty || err != nil {
+			return false, rn, err
+		}
+		return true, nn, nil
+
+	default:
+		panic(fmt.Sprintf("%T: invalid node: %v (%v)", n, n, key))
+	}
+}
+
+func concat(s1 []byte, s2 ...byte) []byte {
+	r := make([]byte, len(s1)+len(s2))
+	copy(r, s1)
+	copy(r[len(s1):], s2)
+	return r
+}
+
+func newFlag() nodeFlag {
+	return nodeFlag{dirty: true}
+}
+
+func (t *Trie) resolve(n node) (node, error) {
+	if n, ok := n.(hashNode); ok {
+		return t.resolveHash(n)
+	}
+	return n, nil
+}
+
+func (t *Trie) resolveHash(n hashNode) (node, error) {
+	hash := types.BytesToHash(n)
+	if t.db == nil {
+		return nil, &MissingNodeError{NodeHash: hash}
+	}
+	enc, err := t.db.Node(hash)
+	if err != nil {
+		return nil, err
+	}
+	if len(enc) == 0 {
+		return nil, &MissingNodeError{NodeHash: hash}
+	}
+	return decodeNode(types.CopyBytes(hash[:]), types.CopyBytes(enc))
+}
+
+// Hash returns the root hash of the trie.
+func (t *Trie) Hash() types.Hash {
+	if t.root == nil {
+		return EmptyRoot
+	}
+	if hn, ok := t.root.(hashNode); ok {
+		return types.BytesToHash(hn)
+	}
+	return crypto.Keccak256Hash(t.encode(t.root))
+}
+
+// Commit hands every node modified since the trie was opened to put, and returns
+// the root hash. The root node is always stored, even if its encoding is shorter
+// than a hash.
+func (t *Trie) Commit(put func(hash types.Hash, enc []byte) error) (types.Hash, error) {
+	root := t.Hash()
+	if err := t.commit(t.root, true, put); err != nil {
+		return types.Hash{}, err
+	}
+	return root, nil
+}
+
+func (t *Trie) commit(n node, isRoot bool, put func(hash types.Hash, enc []byte) error) error {
+	var flags *nodeFlag
+	switch n := n.(type) {
+	case *shortNode:
+		if !n.flags.dirty {
+			return nil
+		}
+		if err := t.commit(n.Val, false, put); err != nil {
+			return err
+		}
+		flags = &n.flags
+	case *fullNode:
+		if !n.flags.dirty {
+			return nil
+		}
+		for _, child := range n.Children[:16] {
+			if err := t.commit(child, false, put); err != nil {
+				return err
+			}
+		}
+		flags = &n.flags
+	default:
+		return nil
+	}
+	enc := t.encode(n)
+	if len(enc) >= hashLen || isRoot {
+		if err := put(crypto.Keccak256Hash(enc), enc); err != nil {
+			return err
+		}
+	}
+	flags.dirty = false
+	return nil
+}
+
+// encode returns the RLP encoding of a short or full node, with its children
+// replaced by their references.
+func (t *Trie) encode(n node) []byte {
+	switch n := n.(type) {
+	case *shortNode:
+		return encodeList(encodeString(hexToCompact(n.Key)), t.ref(n.Val))
+	case *fullNode:
+		items := make([][]byte, 17)
+		for i, child := range &n.Children {
+			items[i] = t.ref(child)
+		}
+		return encodeList(items...)
+	default:
+		panic(fmt.Sprintf("%T: invalid node: %v", n, n))
+	}
+}
+
+// ref returns the reference to n embedded in its parent: the node itself if its
+// encoding is shorter than a hash, its hash otherwise.
+func (t *Trie) ref(n node) []byte {
+	var flags *nodeFlag
+	switch n := n.(type) {
+	case nil:
+		return encodeString(nil)
+	case hashNode:
+		return encodeString(n)
+	case valueNode:
+		return encodeString(n)
+	case *shortNode:
+		flags = &n.flags
+	case *fullNode:
+		flags = &n.flags
+	}
+	if flags.hash != nil {
+		return encodeString(flags.hash)
+	}
+	enc := t.encode(n)
+	if len(enc) < hashLen {
+		return enc
+	}
+	flags.hash = crypto.Keccak256(enc)
+	return encodeString(flags.hash)
+}
@@ -0,0 +1,141 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+
+package trie
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/amazechain/amc/common/crypto"
+	"github.com/amazechain/amc/common/types"
+)
+
+type memNodes map[types.Hash][]byte
+
+func (m memNodes) Node(hash types.Hash) ([]byte, error) {
+	return m[hash], nil
+}
+
+func (m memNodes) put(hash types.Hash, enc []byte) error {
+	m[hash] = enc
+	return nil
+}
+
+func TestEmptyTrie(t *testing.T) {
+	tr, _ := New(types.Hash{}, nil)
+	if have := tr.Hash(); have != EmptyRoot {
+		t.Errorf("empty root mismatch: have %x, want %x", have, EmptyRoot)
+	}
+}
+
+// Tests root hashes against the reference vectors of the Ethereum trie.
+func TestInsertDelete(t *testing.T) {
+	tr, _ := New(types.Hash{}, nil)
+	tr.Update([]byte("doe"), []byte("reindeer"))
+	tr.Update([]byte("dog"), []byte("puppy"))
+	tr.Update([]byte("dogglesworth"), []byte("cat"))
+	if have, want := tr.Hash(), types.HexToHash("8aad789dff2f538bca5d8ea56e8abe10f4c7ba3a5dea95fea4cd6e7c3a1168d3"); have != want {
+		t.Errorf("root mismatch: have %x, want %x", have, want)
+	}
+
+	tr, _ = New(types.Hash{}, nil)
+	tr.Update([]byte("A"), []byte(strings.Repeat("a", 50)))
+	if have, want := tr.Hash(), types.HexToHash("d23786fb4a010da3ce639d66d5e904a11dbc02746d1ce25029e53290cabf28ab"); have != want {
+		t.Errorf("root mismatch: have %x, want %x", have, want)
+	}
+
+	tr, _ = New(types.Hash{}, nil)
+	vals := []struct{ k, v string }{
+		{"do", "verb"},
+		{"ether", "wookiedoo"},
+		{"horse", "stallion"},
+		{"shaman", "horse"},
+		{"doge", "coin"},
+		{"ether", ""},
+		{"dog", "puppy"},
+		{"shaman", ""},
+	}
+	for _, val := range vals {
+		tr.Update([]byte(val.k), []byte(val.v))
+	}
+	if have, want := tr.Hash(), types.HexToHash("5991bb8c6514148a29db676a14ac506cd2cd5775ace63c30a4fe457715e9ac84"); have != want {
+		t.Errorf("root mismatch: have %x, want %x", have, want)
+	}
+}
+
+// Tests that committed tries can be reopened, updated incrementally and proven.
+func TestCommitAndProve(t *testing.T) {
+	db := make(memNodes)
+	tr, _ := New(types.Hash{}, db)
+	for i := 0; i < 500; i++ {
+		key := crypto.Keccak256([]byte(fmt.Sprint(i)))
+		tr.Update(key, []byte(fmt.Sprintf("value-%d", i)))
+	}
+	root, err := tr.Commit(db.put)
+	if err != nil {
+		t.Fatalf("commit failed: %v", err)
+	}
+
+	tr, err = New(root, db)
+	if err != nil {
+		t.Fatalf("reopen failed: %v", err)
+	}
+	for i := 0; i < 500; i += 2 {
+		tr.Delete(crypto.Keccak256([]byte(fmt.Sprint(i))))
+	}
+	updated, err := tr.Commit(db.put)
+	if err != nil {
+		t.Fatalf("commit failed: %v", err)
+	}
+
+	// A trie built from scratch with the remaining keys must have the same root.
+	fresh, _ := New(types.Hash{}, nil)
+	for i := 1; i < 500; i += 2 {
+		fresh.Update(crypto.Keccak256([]byte(fmt.Sprint(i))), []byte(fmt.Sprintf("value-%d", i)))
+	}
+	if fresh.Hash() != updated {
+		t.Fatalf("incremental root mismatch: have %x, want %x", updated, fresh.Hash())
+	}
+
+	tr, _ = New(updated, db)
+	for i := 0; i < 10; i++ {
+		key := crypto.Keccak256([]byte(fmt.Sprint(i)))
+		proof, err := tr.Prove(key)
+		if err != nil {
+			t.Fatalf("prove failed: %v", err)
+		}
+		val, err := VerifyProof(updated, key, proof)
+		if err != nil {
+			t.Fatalf("verify failed for %d: %v", i, err)
+		}
+		var want []byte
+		if i%2 == 1 {
+			want = []byte(fmt.Sprintf("value-%d", i))
+		}
+		if !bytes.Equal(val, want) {
+			t.Fatalf("proven value mismatch for %d: have %q, want %q", i, val, want)
+		}
+	}
+	// Proofs must not verify against another root.
+	key := crypto.Keccak256([]byte("1"))
+	proof, _ := tr.Prove(key)
+	if _, err := VerifyProof(root, key, proof); err == nil {
+		t.Fatalf("proof verified against a stale root")
+	}
+}
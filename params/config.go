@@ -308,6 +308,22 @@ type ChainConfig struct {
 	NanoBlock    *big.Int `json:"nanoBlock,omitempty" toml:",omitempty"`    // nanoBlock switch block (nil = no fork, 0 = already activated)
 	MoranBlock   *big.Int `json:"moranBlock,omitempty" toml:",omitempty"`   // moranBlock switch block (nil = no fork, 0 = already activated)
 	BeijingBlock *big.Int `json:"beijingBlock,omitempty" toml:",omitempty"` // beijingBlock switch block (nil = no fork, 0 = already activated)
+
+	// StateCommitmentBlock switches the header state root to the root of the Merkle Patricia trie
+	// over all accounts and storage (nil = no fork, 0 = already activated)
+	StateCommitmentBlock *big.Int `json:"stateCommitmentBlock,omitempty" toml:",omitempty"`
+
+	// VerifierCommitmentBlock adds the header hash over the verifiers and rewards of
+	// the body (nil = no fork, 0 = already activated)
+	VerifierCommitmentBlock *big.Int `json:"verifierCommitmentBlock,omitempty" toml:",omitempty"`
+
+	// VerifierQuorumBlock requires blocks to be signed by a quorum of the depositors
+	// with their deposited keys (nil = no fork, 0 = already activated)
+	VerifierQuorumBlock *big.Int `json:"verifierQuorumBlock,omitempty" toml:",omitempty"`
+
+	// DepositSchedules are the deposit tiers and rewards of the verifiers, each in
+	// force from its block on (empty = DefaultDepositSchedule)
+	DepositSchedules []*DepositSchedule `json:"depositSchedules,omitempty" toml:",omitempty"`
 	//Apos         *AposConfig `json:"apos,omitempty"`
 
 	// Gnosis Chain fork blocks
@@ -739,6 +755,21 @@ func (c *ChainConfig) IsBeijing(num uint64) bool {
 	return isForked(c.BeijingBlock, num)
 }
 
+// IsStateCommitment returns whether num is either equal to the state commitment fork block or greater.
+func (c *ChainConfig) IsStateCommitment(num uint64) bool {
+	return isForked(c.StateCommitmentBlock, num)
+}
+
+// IsVerifierCommitment returns whether num is either equal to the verifier commitment fork block or greater.
+func (c *ChainConfig) IsVerifierCommitment(num uint64) bool {
+	return isForked(c.VerifierCommitmentBlock, num)
+}
+
+// IsVerifierQuorum returns whether num is either equal to the verifier quorum fork block or greater.
+func (c *ChainConfig) IsVerifierQuorum(num uint64) bool {
+	return isForked(c.VerifierQuorumBlock, num)
+}
+
 func (c *ChainConfig) IsEip1559FeeCollector(num uint64) bool {
 	return c.Eip1559FeeCollector != nil && isForked(c.Eip1559FeeCollectorTransition, num)
 }
@@ -887,6 +918,18 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, head uint64) *ConfigC
 	if isForkIncompatible(c.CancunBlock, newcfg.CancunBlock, head) {
 		return newCompatError("Cancun fork block", c.CancunBlock, newcfg.CancunBlock)
 	}
+	if isForkIncompatible(c.StateCommitmentBlock, newcfg.StateCommitmentBlock, head) {
+		return newCompatError("State commitment fork block", c.StateCommitmentBlock, newcfg.StateCommitmentBlock)
+	}
+	if isForkIncompatible(c.VerifierCommitmentBlock, newcfg.VerifierCommitmentBlock, head) {
+		return newCompatError("Verifier commitment fork block", c.VerifierCommitmentBlock, newcfg.VerifierCommitmentBlock)
+	}
+	if isForkIncompatible(c.VerifierQuorumBlock, newcfg.VerifierQuorumBlock, head) {
+		return newCompatError("Verifier quorum fork block", c.VerifierQuorumBlock, newcfg.VerifierQuorumBlock)
+	}
+	if err := checkDepositSchedulesCompatible(c.DepositSchedules, newcfg.DepositSchedules, head); err != nil {
+		return err
+	}
 
 	// Parlia forks
 	//if isForkIncompatible(c.RamanujanBlock, newcfg.RamanujanBlock, head) {
@@ -984,6 +1027,7 @@ type Rules struct {
 	IsNano, IsMoran                                         bool
 	IsEip1559FeeCollector                                   bool
 	IsParlia, IsStarknet, IsAura, IsBeijing                 bool
+	IsStateCommitment                                       bool
 }
 
 // Rules ensures c's ChainID is not nil.
@@ -1012,6 +1056,7 @@ func (c *ChainConfig) Rules(num uint64) *Rules {
 		IsParlia:              c.Parlia != nil,
 		IsAura:                c.Aura != nil,
 		IsBeijing:             c.IsBeijing(num),
+		IsStateCommitment:     c.IsStateCommitment(num),
 	}
 }
 
@@ -0,0 +1,231 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+package params
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// DepositTier is the reward earned by the verifiers whose deposit reaches an amount.
+type DepositTier struct {
+	Deposit           *big.Int `json:"deposit"`           // least deposit of the tier, in wei
+	RewardPerBlock    *big.Int `json:"rewardPerBlock"`    // reward for every verified block, in wei
+	MaxRewardPerEpoch *big.Int `json:"maxRewardPerEpoch"` // most reward earned in a reward epoch, in wei
+}
+
+// DepositSchedule is the set of deposit tiers in force from a fork block on.
+type DepositSchedule struct {
+	Name       string         `json:"name"`       // fork name
+	Block      *big.Int       `json:"block"`      // first block the schedule applies to
+	MinDeposit *big.Int       `json:"minDeposit"` // least deposit earning rewards, in wei
+	Tiers      []*DepositTier `json:"tiers"`      // tiers ordered by deposit
+
+	// ExactDeposits only rewards the deposits matching a tier in whole AMT, as the
+	// tiers were paid before deposit schedules.
+	ExactDeposits bool `json:"exactDeposits,omitempty"`
+}
+
+// DefaultDepositSchedule holds the tiers used by chains configuring none: deposits
+// of 50, 100 and 500 AMT earning 0.375, 1 and 6.25 AMT over 30 days of 500, 100
+// and 100 verified blocks.
+var DefaultDepositSchedule = &DepositSchedule{
+	Name:          "default",
+	Block:         big.NewInt(0),
+	MinDeposit:    new(big.Int).Mul(big.NewInt(50), big.NewInt(AMT)),
+	ExactDeposits: true,
+	Tiers: []*DepositTier{
+		{
+			Deposit:           new(big.Int).Mul(big.NewInt(50), big.NewInt(AMT)),
+			RewardPerBlock:    big.NewInt(25000000000000),
+			MaxRewardPerEpoch: big.NewInt(12500000000000000),
+		},
+		{
+			Deposit:           new(big.Int).Mul(big.NewInt(100), big.NewInt(AMT)),
+			RewardPerBlock:    big.NewInt(333333333333334),
+			MaxRewardPerEpoch: big.NewInt(33333333333333400),
+		},
+		{
+			Deposit:           new(big.Int).Mul(big.NewInt(500), big.NewInt(AMT)),
+			RewardPerBlock:    big.NewInt(2083333333333334),
+			MaxRewardPerEpoch: big.NewInt(208333333333333400),
+		},
+	},
+}
+
+// DepositScheduleAt returns the deposit schedule in force at the given block, the
+// default one before the first configured schedule.
+func (c *ChainConfig) DepositScheduleAt(num uint64) *DepositSchedule {
+	active := DefaultDepositSchedule
+	if c == nil {
+		return active
+	}
+	for _, s := range c.DepositSchedules {
+		if !isForked(s.Block, num) {
+			break
+		}
+		active = s
+	}
+	return active
+}
+
+// Tier returns the highest tier reached by the deposit, nil if the deposit is
+// below the minimum of the schedule or of every tier. A schedule with exact
+// deposits returns the tier matching the deposit in whole AMT instead.
+func (s *DepositSchedule) Tier(deposit *big.Int) *DepositTier {
+	if deposit == nil || deposit.Cmp(s.MinDeposit) < 0 {
+		return nil
+	}
+	if s.ExactDeposits {
+		amt := big.NewInt(AMT)
+		whole := new(big.Int).Div(deposit, amt)
+		for _, t := range s.Tiers {
+			if new(big.Int).Div(t.Deposit, amt).Cmp(whole) == 0 {
+				return t
+			}
+		}
+		return nil
+	}
+	var tier *DepositTier
+	for _, t := range s.Tiers {
+		if deposit.Cmp(t.Deposit) < 0 {
+			break
+		}
+		tier = t
+	}
+	return tier
+}
+
+// CheckDepositSchedules checks that the deposit schedules activate at increasing
+// blocks and that their tiers are ordered and pay out amounts fitting 256 bits.
+func (c *ChainConfig) CheckDepositSchedules() error {
+	if c == nil {
+		return nil
+	}
+	names := make(map[string]struct{}, len(c.DepositSchedules))
+	var last *DepositSchedule
+	for i, s := range c.DepositSchedules {
+		if s == nil {
+			return fmt.Errorf("deposit schedule %d is empty", i)
+		}
+		if s.Name == "" {
+			return fmt.Errorf("deposit schedule %d has no name", i)
+		}
+		if _, ok := names[s.Name]; ok {
+			return fmt.Errorf("deposit schedule %q is declared twice", s.Name)
+		}
+		names[s.Name] = struct{}{}
+		if s.Block == nil || s.Block.Sign() < 0 {
+			return fmt.Errorf("deposit schedule %q has no activation block", s.Name)
+		}
+		if last != nil && last.Block.Cmp(s.Block) >= 0 {
+			return fmt.Errorf("deposit schedule %q activates at %v, not after %q at %v", s.Name, s.Block, last.Name, last.Block)
+		}
+		if err := s.check(); err != nil {
+			return fmt.Errorf("deposit schedule %q: %w", s.Name, err)
+		}
+		last = s
+	}
+	return nil
+}
+
+// checkDepositSchedulesCompatible checks that the deposit schedules activated by
+// the head are the same in both configs, matching them by their blocks.
+func checkDepositSchedulesCompatible(stored, newcfg []*DepositSchedule, head uint64) *ConfigCompatError {
+	activated := func(schedules []*DepositSchedule) map[uint64]*DepositSchedule {
+		m := make(map[uint64]*DepositSchedule)
+		for _, s := range schedules {
+			if s != nil && isForked(s.Block, head) {
+				m[s.Block.Uint64()] = s
+			}
+		}
+		return m
+	}
+	storedActive, newActive := activated(stored), activated(newcfg)
+	blocks := make([]uint64, 0, len(storedActive)+len(newActive))
+	for n := range storedActive {
+		blocks = append(blocks, n)
+	}
+	for n := range newActive {
+		if _, ok := storedActive[n]; !ok {
+			blocks = append(blocks, n)
+		}
+	}
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i] < blocks[j] })
+	for _, n := range blocks {
+		s, news := storedActive[n], newActive[n]
+		switch {
+		case s == nil:
+			return newCompatError(fmt.Sprintf("deposit schedule %q", news.Name), nil, news.Block)
+		case news == nil:
+			return newCompatError(fmt.Sprintf("deposit schedule %q", s.Name), s.Block, nil)
+		case !s.equal(news):
+			return newCompatError(fmt.Sprintf("deposit schedule %q", s.Name), s.Block, news.Block)
+		}
+	}
+	return nil
+}
+
+// equal returns whether both schedules pay the same rewards for the same deposits.
+func (s *DepositSchedule) equal(other *DepositSchedule) bool {
+	if !configNumEqual(s.MinDeposit, other.MinDeposit) || s.ExactDeposits != other.ExactDeposits || len(s.Tiers) != len(other.Tiers) {
+		return false
+	}
+	for i, t := range s.Tiers {
+		o := other.Tiers[i]
+		if t == nil || o == nil {
+			if t != o {
+				return false
+			}
+			continue
+		}
+		if !configNumEqual(t.Deposit, o.Deposit) || !configNumEqual(t.RewardPerBlock, o.RewardPerBlock) || !configNumEqual(t.MaxRewardPerEpoch, o.MaxRewardPerEpoch) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *DepositSchedule) check() error {
+	if !isUint256(s.MinDeposit) || s.MinDeposit.Sign() == 0 {
+		return errors.New("invalid minimum deposit")
+	}
+	if len(s.Tiers) == 0 {
+		return errors.New("no tiers")
+	}
+	for i, t := range s.Tiers {
+		if t == nil || !isUint256(t.Deposit) || !isUint256(t.RewardPerBlock) || !isUint256(t.MaxRewardPerEpoch) {
+			return fmt.Errorf("tier %d lacks its deposit or rewards", i)
+		}
+		if t.Deposit.Cmp(s.MinDeposit) < 0 {
+			return fmt.Errorf("tier %d deposit %v is below the minimum deposit %v", i, t.Deposit, s.MinDeposit)
+		}
+		if i > 0 && t.Deposit.Cmp(s.Tiers[i-1].Deposit) <= 0 {
+			return fmt.Errorf("tier %d deposit %v is not above the one of tier %d", i, t.Deposit, i-1)
+		}
+		if t.MaxRewardPerEpoch.Cmp(t.RewardPerBlock) < 0 {
+			return fmt.Errorf("tier %d pays at most %v an epoch, less than %v a block", i, t.MaxRewardPerEpoch, t.RewardPerBlock)
+		}
+	}
+	return nil
+}
+
+// isUint256 returns whether the number is set and fits an unsigned 256 bit integer.
+func isUint256(n *big.Int) bool {
+	return n != nil && n.Sign() >= 0 && n.BitLen() <= 256
+}
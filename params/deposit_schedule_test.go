@@ -0,0 +1,153 @@
+// Copyright 2023 The AmazeChain Authors
+// This file is part of the AmazeChain library.
+//
+// The AmazeChain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The AmazeChain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the AmazeChain library. If not, see <http://www.gnu.org/licenses/>.
+package params
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+// Tests that the schedule in force is the last one activated, and that deposits
+// reach the highest tier below them.
+func TestDepositScheduleAt(t *testing.T) {
+	var config ChainConfig
+	if err := json.Unmarshal([]byte(`{
+		"depositSchedules": [
+			{"name": "beijing", "block": 100, "minDeposit": 10, "tiers": [
+				{"deposit": 10, "rewardPerBlock": 1, "maxRewardPerEpoch": 5},
+				{"deposit": 50, "rewardPerBlock": 3, "maxRewardPerEpoch": 30}
+			]},
+			{"name": "shenzhen", "block": 200, "minDeposit": 20, "tiers": [
+				{"deposit": 20, "rewardPerBlock": 2, "maxRewardPerEpoch": 10}
+			]}
+		]
+	}`), &config); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.CheckDepositSchedules(); err != nil {
+		t.Fatalf("valid schedules rejected: %v", err)
+	}
+	for _, tt := range []struct {
+		number uint64
+		name   string
+	}{{0, "default"}, {99, "default"}, {100, "beijing"}, {199, "beijing"}, {200, "shenzhen"}, {1000, "shenzhen"}} {
+		if have := config.DepositScheduleAt(tt.number).Name; have != tt.name {
+			t.Errorf("schedule at %d: have %q, want %q", tt.number, have, tt.name)
+		}
+	}
+
+	beijing := config.DepositScheduleAt(100)
+	for _, tt := range []struct {
+		deposit int64
+		reward  int64 // reward per block, 0 without a tier
+	}{{9, 0}, {10, 1}, {49, 1}, {50, 3}, {1000, 3}} {
+		tier := beijing.Tier(big.NewInt(tt.deposit))
+		switch {
+		case tier == nil && tt.reward != 0:
+			t.Errorf("deposit %d: no tier, want reward %d", tt.deposit, tt.reward)
+		case tier != nil && tier.RewardPerBlock.Int64() != tt.reward:
+			t.Errorf("deposit %d: have reward %v, want %d", tt.deposit, tier.RewardPerBlock, tt.reward)
+		}
+	}
+	if err := DefaultDepositSchedule.check(); err != nil {
+		t.Fatalf("default schedule invalid: %v", err)
+	}
+	// The default schedule only pays deposits of a tier, as before schedules.
+	for _, tt := range []struct {
+		deposit int64 // AMT
+		tier    int   // -1 without a tier
+	}{{10, -1}, {50, 0}, {75, -1}, {100, 1}, {499, -1}, {500, 2}, {1000, -1}} {
+		tier := DefaultDepositSchedule.Tier(new(big.Int).Mul(big.NewInt(tt.deposit), big.NewInt(AMT)))
+		switch {
+		case tt.tier < 0 && tier != nil:
+			t.Errorf("default deposit %d: unexpected tier %v", tt.deposit, tier.Deposit)
+		case tt.tier >= 0 && tier != DefaultDepositSchedule.Tiers[tt.tier]:
+			t.Errorf("default deposit %d: have tier %v, want %d", tt.deposit, tier, tt.tier)
+		}
+	}
+}
+
+// Tests that malformed deposit schedules are rejected.
+func TestCheckDepositSchedules(t *testing.T) {
+	tier := func(deposit, perBlock, perEpoch int64) *DepositTier {
+		return &DepositTier{Deposit: big.NewInt(deposit), RewardPerBlock: big.NewInt(perBlock), MaxRewardPerEpoch: big.NewInt(perEpoch)}
+	}
+	schedule := func(name string, block int64, tiers ...*DepositTier) *DepositSchedule {
+		return &DepositSchedule{Name: name, Block: big.NewInt(block), MinDeposit: big.NewInt(10), Tiers: tiers}
+	}
+	tests := []struct {
+		schedules []*DepositSchedule
+		err       string
+	}{
+		{[]*DepositSchedule{schedule("a", 0, tier(10, 1, 5))}, ""},
+		{[]*DepositSchedule{schedule("", 0, tier(10, 1, 5))}, "has no name"},
+		{[]*DepositSchedule{schedule("a", 0, tier(10, 1, 5)), schedule("a", 10, tier(10, 1, 5))}, "declared twice"},
+		{[]*DepositSchedule{schedule("a", 10, tier(10, 1, 5)), schedule("b", 10, tier(10, 1, 5))}, "not after"},
+		{[]*DepositSchedule{schedule("a", 0)}, "no tiers"},
+		{[]*DepositSchedule{schedule("a", 0, tier(5, 1, 5))}, "below the minimum"},
+		{[]*DepositSchedule{schedule("a", 0, tier(20, 1, 5), tier(20, 2, 10))}, "not above"},
+		{[]*DepositSchedule{schedule("a", 0, tier(10, 5, 1))}, "at most"},
+		{[]*DepositSchedule{schedule("a", 0, tier(10, -1, 5))}, "lacks"},
+		{[]*DepositSchedule{{Name: "a", MinDeposit: big.NewInt(1), Tiers: []*DepositTier{tier(10, 1, 5)}}}, "no activation block"},
+	}
+	for i, tt := range tests {
+		err := (&ChainConfig{DepositSchedules: tt.schedules}).CheckDepositSchedules()
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("test %d: unexpected error %v", i, err)
+		case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+			t.Errorf("test %d: have error %v, want %q", i, err, tt.err)
+		}
+	}
+}
+
+// Tests that changing a deposit schedule activated by the head is incompatible,
+// and that the rewind goes back before the first schedule changed.
+func TestDepositSchedulesCompatible(t *testing.T) {
+	schedule := func(name string, block, perBlock int64) *DepositSchedule {
+		return &DepositSchedule{Name: name, Block: big.NewInt(block), MinDeposit: big.NewInt(10), Tiers: []*DepositTier{
+			{Deposit: big.NewInt(10), RewardPerBlock: big.NewInt(perBlock), MaxRewardPerEpoch: big.NewInt(100)},
+		}}
+	}
+	stored := []*DepositSchedule{schedule("a", 10, 1), schedule("b", 20, 2)}
+	tests := []struct {
+		newcfg   []*DepositSchedule
+		head     uint64
+		rewindTo uint64 // 0 if compatible
+	}{
+		{[]*DepositSchedule{schedule("a", 10, 1), schedule("b", 20, 2)}, 30, 0},
+		{[]*DepositSchedule{schedule("renamed", 10, 1), schedule("b", 20, 2)}, 30, 0},
+		{[]*DepositSchedule{schedule("a", 10, 1), schedule("b", 20, 3)}, 15, 0},
+		{[]*DepositSchedule{schedule("a", 10, 1), schedule("b", 20, 3)}, 30, 19},
+		{[]*DepositSchedule{schedule("a", 10, 1), schedule("b", 25, 2)}, 30, 19},
+		{[]*DepositSchedule{schedule("a", 10, 1)}, 30, 19},
+		{[]*DepositSchedule{schedule("z", 5, 1), schedule("a", 10, 1), schedule("b", 20, 2)}, 30, 4},
+		{nil, 30, 9},
+	}
+	for i, tt := range tests {
+		err := checkDepositSchedulesCompatible(stored, tt.newcfg, tt.head)
+		switch {
+		case tt.rewindTo == 0 && err != nil:
+			t.Errorf("test %d: unexpected error %v", i, err)
+		case tt.rewindTo != 0 && err == nil:
+			t.Errorf("test %d: changed schedule accepted", i)
+		case tt.rewindTo != 0 && err.RewindTo != tt.rewindTo:
+			t.Errorf("test %d: have rewind to %d, want %d", i, err.RewindTo, tt.rewindTo)
+		}
+	}
+}
@@ -32,11 +32,25 @@ func GetLatestBlockNumber(tx kv.Tx) (*uint256.Int, error) {
 }
 
 func GetFinalizedBlockNumber(tx kv.Tx) (*uint256.Int, error) {
-	//todo
-	return GetLatestBlockNumber(tx)
+	hash, err := rawdb.ReadFinalizedBlockHash(tx)
+	if err != nil {
+		return nil, err
+	}
+	number := rawdb.ReadHeaderNumber(tx, hash)
+	if number == nil {
+		return nil, fmt.Errorf("finalized block not found")
+	}
+	return uint256.NewInt(*number), nil
 }
 
 func GetSafeBlockNumber(tx kv.Tx) (*uint256.Int, error) {
-	//todo
-	return GetLatestBlockNumber(tx)
+	hash, err := rawdb.ReadSafeBlockHash(tx)
+	if err != nil {
+		return nil, err
+	}
+	number := rawdb.ReadHeaderNumber(tx, hash)
+	if number == nil {
+		return nil, fmt.Errorf("safe block not found")
+	}
+	return uint256.NewInt(*number), nil
 }